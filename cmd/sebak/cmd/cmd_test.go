@@ -326,3 +326,18 @@ func TestParseGenesisOption(t *testing.T) {
 		require.Equal(t, errors.NotPublicKey.Code, err.(*errors.Error).Code)
 	}
 }
+
+func TestParseFlagValidatorWeights(t *testing.T) {
+	weights, err := parseFlagValidatorWeights("GDPQ2LBYP3RL3O675H2N5IEYM6PRJNUA5QFMKXIHGTKEB5KS5T3KHFA2=10")
+	require.NoError(t, err)
+	require.Equal(t, map[string]uint64{"GDPQ2LBYP3RL3O675H2N5IEYM6PRJNUA5QFMKXIHGTKEB5KS5T3KHFA2": 10}, weights)
+
+	_, err = parseFlagValidatorWeights("GDPQ2LBYP3RL3O675H2N5IEYM6PRJNUA5QFMKXIHGTKEB5KS5T3KHFA2")
+	require.Error(t, err)
+
+	_, err = parseFlagValidatorWeights("GDPQ2LBYP3RL3O675H2N5IEYM6PRJNUA5QFMKXIHGTKEB5KS5T3KHFA2=-1")
+	require.Error(t, err)
+
+	_, err = parseFlagValidatorWeights("invalid-address=10")
+	require.Error(t, err)
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"os"
@@ -50,14 +51,22 @@ var (
 	flagPublishURL                 string = common.GetENVValue("SEBAK_PUBLISH", "")
 	flagSyncCheckInterval          string = common.GetENVValue("SEBAK_SYNC_CHECK_INTERVAL", "30s")
 	flagSyncFetchTimeout           string = common.GetENVValue("SEBAK_SYNC_FETCH_TIMEOUT", "1m")
-	flagSyncPoolSize               string = common.GetENVValue("SEBAK_SYNC_POOL_SIZE", "300")
+	flagSyncPoolSize               string = common.GetENVValue("SEBAK_SYNC_POOL_SIZE", "10")
+	flagSyncBatchSize              string = common.GetENVValue("SEBAK_SYNC_BATCH_SIZE", "100")
 	flagSyncRetryInterval          string = common.GetENVValue("SEBAK_SYNC_RETRY_INTERVAL", "10s")
 	flagSyncCheckPrevBlockInterval string = common.GetENVValue("SEBAK_SYNC_CHECK_PREVBLOCK", "30s")
+	flagSyncMode                   string = common.GetENVValue("SEBAK_SYNC_MODE", syncModeFull)
+	flagStateSnapshotInterval      string = common.GetENVValue("SEBAK_STATE_SNAPSHOT_INTERVAL", strconv.FormatUint(common.DefaultStateSnapshotInterval, 10))
+	flagStorageMode                string = common.GetENVValue("SEBAK_STORAGE_MODE", common.StorageModeArchive)
+	flagPruneBlocks                string = common.GetENVValue("SEBAK_PRUNE_BLOCKS", strconv.FormatUint(common.DefaultPruneBlocks, 10))
+	flagCertificateHeight          string = common.GetENVValue("SEBAK_CERTIFICATE_HEIGHT", "0")
 	flagThreshold                  string = common.GetENVValue("SEBAK_THRESHOLD", "67")
 	flagTimeoutACCEPT              string = common.GetENVValue("SEBAK_TIMEOUT_ACCEPT", "2s")
 	flagTimeoutALLCONFIRM          string = common.GetENVValue("SEBAK_TIMEOUT_ALLCONFIRM", "30s")
 	flagTimeoutINIT                string = common.GetENVValue("SEBAK_TIMEOUT_INIT", "2s")
 	flagTimeoutSIGN                string = common.GetENVValue("SEBAK_TIMEOUT_SIGN", "2s")
+	flagTimeoutBackoffFactor       string = common.GetENVValue("SEBAK_TIMEOUT_BACKOFF_FACTOR", "2")
+	flagTimeoutBackoffLimit        string = common.GetENVValue("SEBAK_TIMEOUT_BACKOFF_LIMIT", "1m")
 	flagTLSCertFile                string = common.GetENVValue("SEBAK_TLS_CERT", "sebak.crt")
 	flagTLSKeyFile                 string = common.GetENVValue("SEBAK_TLS_KEY", "sebak.key")
 	flagUnfreezingPeriod           string = common.GetENVValue("SEBAK_UNFREEZING_PERIOD", strconv.FormatUint(common.UnfreezingPeriod, 10))
@@ -78,6 +87,7 @@ var (
 	flagTransactionsLimit       string = common.GetENVValue("SEBAK_TRANSACTIONS_LIMIT", strconv.Itoa(common.DefaultTransactionsInBallotLimit))
 	flagOperationsInBallotLimit string = common.GetENVValue("SEBAK_OPERATIONS_IN_BALLOT_LIMIT", strconv.Itoa(common.DefaultOperationsInBallotLimit))
 	flagTxPoolLimit             string = common.GetENVValue("SEBAK_TX_POOL_LIMIT", strconv.Itoa(common.DefaultTxPoolLimit))
+	flagTxPoolSourceLimit       string = common.GetENVValue("SEBAK_TX_POOL_SOURCE_LIMIT", strconv.Itoa(common.DefaultTxPoolSourceLimit))
 
 	flagWatcherMode   bool   = common.GetENVValue("SEBAK_WATCHER_MODE", "0") == "1"
 	flagWatchInterval string = common.GetENVValue("SEBAK_WATCH_INTERVAL", "5s")
@@ -85,6 +95,18 @@ var (
 	flagDiscovery       cmdcommon.ListFlags // "SEBAK_DISCOVERY"
 	flagNTPServer       string              = common.GetENVValue("SEBAK_NTP_SERVER", "time.bora.net")
 	flagTimeSyncCommand string              = common.GetENVValue("SEBAK_TIME_SYNC_COMMAND", "")
+
+	flagProposerSelector string = common.GetENVValue("SEBAK_PROPOSER_SELECTOR", proposerSelectorSequential)
+	flagValidatorWeights string = common.GetENVValue("SEBAK_VALIDATOR_WEIGHTS", "")
+)
+
+const (
+	proposerSelectorSequential string = "sequential"
+	proposerSelectorWeighted   string = "weighted"
+	proposerSelectorFrozen     string = "frozen"
+
+	syncModeFull string = "full"
+	syncModeFast string = "fast"
 )
 
 var (
@@ -101,12 +123,15 @@ var (
 	syncCheckInterval       time.Duration
 	syncFetchTimeout        time.Duration
 	syncPoolSize            uint64
+	syncBatchSize           uint64
 	syncRetryInterval       time.Duration
 	threshold               int
 	timeoutACCEPT           time.Duration
 	timeoutALLCONFIRM       time.Duration
 	timeoutINIT             time.Duration
 	timeoutSIGN             time.Duration
+	timeoutBackoffFactor    float64
+	timeoutBackoffLimit     time.Duration
 	validators              []*node.Validator
 	httpCacheAdapter        string
 	httpCachePoolSize       int
@@ -116,10 +141,15 @@ var (
 	operationsInBallotLimit uint64
 	txPoolClientLimit       uint64
 	txPoolNodeLimit         uint64
+	txPoolSourceLimit       uint64
 	syncCheckPrevBlock      time.Duration
 	jsonrpcbindEndpoint     *common.Endpoint
 	watchInterval           time.Duration
 	discoveryEndpoints      []*common.Endpoint
+	validatorWeights        map[string]uint64
+	stateSnapshotInterval   uint64
+	pruneBlocks             uint64
+	certificateHeight       uint64
 
 	logLevel logging.Lvl
 	log      logging.Logger = logging.New("module", "main")
@@ -189,6 +219,8 @@ func init() {
 	nodeCmd.Flags().StringVar(&flagTimeoutSIGN, "timeout-sign", flagTimeoutSIGN, "timeout of the sign state")
 	nodeCmd.Flags().StringVar(&flagTimeoutACCEPT, "timeout-accept", flagTimeoutACCEPT, "timeout of the accept state")
 	nodeCmd.Flags().StringVar(&flagTimeoutALLCONFIRM, "timeout-allconfirm", flagTimeoutALLCONFIRM, "timeout of the allconfirm state")
+	nodeCmd.Flags().StringVar(&flagTimeoutBackoffFactor, "timeout-backoff-factor", flagTimeoutBackoffFactor, "timeouts are multiplied by the factor in each failed round (1 = static timeouts)")
+	nodeCmd.Flags().StringVar(&flagTimeoutBackoffLimit, "timeout-backoff-limit", flagTimeoutBackoffLimit, "maximum of the increased timeouts")
 	nodeCmd.Flags().StringVar(&flagBlockTime, "block-time", flagBlockTime, "block creation time")
 	nodeCmd.Flags().StringVar(&flagBlockTimeDelta, "block-time-delta", flagBlockTimeDelta, "variation period of block time")
 	nodeCmd.Flags().StringVar(&flagUnfreezingPeriod, "unfreezing-period", flagUnfreezingPeriod, "how long freezing must last")
@@ -196,6 +228,7 @@ func init() {
 	nodeCmd.Flags().StringVar(&flagTransactionsLimit, "transactions-limit", flagTransactionsLimit, "transactions limit in a ballot")
 	nodeCmd.Flags().StringVar(&flagOperationsInBallotLimit, "operations-in-ballot-limit", flagOperationsInBallotLimit, "operations limit in a ballot")
 	nodeCmd.Flags().StringVar(&flagTxPoolLimit, "txpool-limit", flagTxPoolLimit, "transaction pool limit: <client-side>[,<node-side>] (0= no limit)")
+	nodeCmd.Flags().StringVar(&flagTxPoolSourceLimit, "txpool-source-limit", flagTxPoolSourceLimit, "transactions limit of one source account in transaction pool (0= no limit)")
 	nodeCmd.Flags().Var(
 		&flagRateLimitAPI,
 		"rate-limit-api",
@@ -209,11 +242,17 @@ func init() {
 
 	nodeCmd.Flags().BoolVar(&flagDebugPProf, "debug-pprof", flagDebugPProf, "set debug pprof")
 
-	nodeCmd.Flags().StringVar(&flagSyncPoolSize, "sync-pool-size", flagSyncPoolSize, "sync pool size")
+	nodeCmd.Flags().StringVar(&flagSyncPoolSize, "sync-pool-size", flagSyncPoolSize, "sync pool size; number of block ranges synced in parallel")
+	nodeCmd.Flags().StringVar(&flagSyncBatchSize, "sync-batch-size", flagSyncBatchSize, "number of blocks fetched in one sync request")
 	nodeCmd.Flags().StringVar(&flagSyncFetchTimeout, "sync-fetch-timeout", flagSyncFetchTimeout, "sync fetch timeout")
 	nodeCmd.Flags().StringVar(&flagSyncRetryInterval, "sync-retry-interval", flagSyncRetryInterval, "sync retry interval")
 	nodeCmd.Flags().StringVar(&flagSyncCheckInterval, "sync-check-interval", flagSyncCheckInterval, "sync check interval")
 	nodeCmd.Flags().StringVar(&flagSyncCheckPrevBlockInterval, "sync-check-prevblock", flagSyncCheckPrevBlockInterval, "sync check interval for previous block")
+	nodeCmd.Flags().StringVar(&flagSyncMode, "sync-mode", flagSyncMode, "sync mode, {full, fast}; 'fast' starts from the state snapshot of validators")
+	nodeCmd.Flags().StringVar(&flagStateSnapshotInterval, "state-snapshot-interval", flagStateSnapshotInterval, "blocks between state snapshots for the fast sync; 0 disables the state snapshot")
+	nodeCmd.Flags().StringVar(&flagStorageMode, "storage-mode", flagStorageMode, "storage mode, {archive, pruned}; 'pruned' removes the history of the old blocks, like transaction bodies; the transactions of the old blocks are served without memo and time bounds")
+	nodeCmd.Flags().StringVar(&flagPruneBlocks, "prune-blocks", flagPruneBlocks, "number of the latest blocks, whose history is kept in the 'pruned' storage mode")
+	nodeCmd.Flags().StringVar(&flagCertificateHeight, "certificate-height", flagCertificateHeight, "block height from which the synced block must have the certificate")
 
 	nodeCmd.Flags().StringVar(&flagHTTPCacheAdapter, "http-cache-adapter", flagHTTPCacheAdapter, "http cache adapter: ex) 'mem'")
 	nodeCmd.Flags().StringVar(&flagHTTPCachePoolSize, "http-cache-pool-size", flagHTTPCachePoolSize, "http cache pool size")
@@ -225,6 +264,8 @@ func init() {
 	nodeCmd.Flags().Var(&flagDiscovery, "discovery", "initial endpoint for discovery")
 	nodeCmd.Flags().StringVar(&flagNTPServer, "ntp", flagNTPServer, "ntp server for time sync")
 	nodeCmd.Flags().StringVar(&flagTimeSyncCommand, "time-sync-command", flagTimeSyncCommand, "command for syncing local time")
+	nodeCmd.Flags().StringVar(&flagProposerSelector, "proposer-selector", flagProposerSelector, "proposer selector, {sequential, weighted, frozen}")
+	nodeCmd.Flags().StringVar(&flagValidatorWeights, "validator-weights", flagValidatorWeights, "weights of validators for '--proposer-selector weighted': <public address>=<weight> [ <public address>=<weight>...]")
 
 	rootCmd.AddCommand(nodeCmd)
 }
@@ -306,6 +347,29 @@ func parseFlagValidators(s string) (vs []*node.Validator, err error) {
 	return
 }
 
+func parseFlagValidatorWeights(s string) (weights map[string]uint64, err error) {
+	weights = map[string]uint64{}
+	for _, v := range strings.Fields(strings.TrimSpace(s)) {
+		splitted := strings.SplitN(v, "=", 2)
+		if len(splitted) != 2 {
+			err = fmt.Errorf("invalid weight, '%s'", v)
+			return
+		}
+
+		if _, err = keypair.Parse(splitted[0]); err != nil {
+			return
+		}
+
+		var weight uint64
+		if weight, err = strconv.ParseUint(splitted[1], 10, 64); err != nil {
+			return
+		}
+		weights[splitted[0]] = weight
+	}
+
+	return
+}
+
 func parseFlagDiscovery(l cmdcommon.ListFlags) (endpoints []*common.Endpoint, err error) {
 	if len(l) < 1 {
 		return
@@ -424,6 +488,12 @@ func parseFlagsNode() {
 	timeoutSIGN = getTimeDuration(flagTimeoutSIGN, common.DefaultTimeoutSIGN, "--timeout-sign")
 	timeoutACCEPT = getTimeDuration(flagTimeoutACCEPT, common.DefaultTimeoutACCEPT, "--timeout-accept")
 	timeoutALLCONFIRM = getTimeDuration(flagTimeoutALLCONFIRM, common.DefaultTimeoutALLCONFIRM, "--timeout-allconfirm")
+	timeoutBackoffLimit = getTimeDuration(flagTimeoutBackoffLimit, common.DefaultTimeoutBackoffLimit, "--timeout-backoff-limit")
+	if timeoutBackoffFactor, err = strconv.ParseFloat(flagTimeoutBackoffFactor, 64); err != nil {
+		cmdcommon.PrintFlagsError(nodeCmd, "--timeout-backoff-factor", err)
+	} else if timeoutBackoffFactor < 1 {
+		cmdcommon.PrintFlagsError(nodeCmd, "--timeout-backoff-factor", errors.New("must be greater than or equal to 1"))
+	}
 	blockTime = getTimeDuration(flagBlockTime, common.DefaultBlockTime, "--block-time")
 	blockTimeDelta = getTimeDuration(flagBlockTimeDelta, common.DefaultBlockTimeDelta, "--block-time-delta")
 
@@ -469,6 +539,10 @@ func parseFlagsNode() {
 		}
 	}
 
+	if txPoolSourceLimit, err = strconv.ParseUint(flagTxPoolSourceLimit, 10, 64); err != nil {
+		cmdcommon.PrintFlagsError(nodeCmd, "--txpool-source-limit", err)
+	}
+
 	if common.UnfreezingPeriod, err = strconv.ParseUint(flagUnfreezingPeriod, 10, 64); err != nil {
 		cmdcommon.PrintFlagsError(nodeCmd, "--unfreezing-period", err)
 	}
@@ -477,12 +551,44 @@ func parseFlagsNode() {
 		cmdcommon.PrintFlagsError(nodeCmd, "--sync-pool-size", err)
 	}
 
+	if syncBatchSize, err = strconv.ParseUint(flagSyncBatchSize, 10, 64); err != nil {
+		cmdcommon.PrintFlagsError(nodeCmd, "--sync-batch-size", err)
+	} else if syncBatchSize < 1 {
+		cmdcommon.PrintFlagsError(nodeCmd, "--sync-batch-size", errors.New("must be greater than 0"))
+	}
+
 	syncRetryInterval = getTimeDuration(flagSyncRetryInterval, sync.RetryInterval, "--sync-retry-interval")
 	syncFetchTimeout = getTimeDuration(flagSyncFetchTimeout, sync.FetchTimeout, "--sync-fetch-timeout")
 	syncCheckInterval = getTimeDuration(flagSyncCheckInterval, sync.CheckBlockHeightInterval, "--sync-check-interval")
 	syncCheckPrevBlock = getTimeDuration(flagSyncCheckPrevBlockInterval, sync.CheckPrevBlockInterval, "--sync-check-prevblock")
 	watchInterval = getTimeDuration(flagWatchInterval, sync.WatchInterval, "--watch-interval")
 
+	switch flagSyncMode {
+	case syncModeFull, syncModeFast:
+	default:
+		cmdcommon.PrintFlagsError(nodeCmd, "--sync-mode", fmt.Errorf("'%s'", flagSyncMode))
+	}
+
+	if stateSnapshotInterval, err = strconv.ParseUint(flagStateSnapshotInterval, 10, 64); err != nil {
+		cmdcommon.PrintFlagsError(nodeCmd, "--state-snapshot-interval", err)
+	}
+
+	if certificateHeight, err = strconv.ParseUint(flagCertificateHeight, 10, 64); err != nil {
+		cmdcommon.PrintFlagsError(nodeCmd, "--certificate-height", err)
+	}
+
+	switch flagStorageMode {
+	case common.StorageModeArchive, common.StorageModePruned:
+	default:
+		cmdcommon.PrintFlagsError(nodeCmd, "--storage-mode", fmt.Errorf("'%s'", flagStorageMode))
+	}
+
+	if pruneBlocks, err = strconv.ParseUint(flagPruneBlocks, 10, 64); err != nil {
+		cmdcommon.PrintFlagsError(nodeCmd, "--prune-blocks", err)
+	} else if pruneBlocks < 1 {
+		cmdcommon.PrintFlagsError(nodeCmd, "--prune-blocks", errors.New("must be greater than 0"))
+	}
+
 	{
 		if ok := common.HTTPCacheAdapterNames[flagHTTPCacheAdapter]; !ok {
 			cmdcommon.PrintFlagsError(nodeCmd, "--http-cache-adapter", err)
@@ -604,6 +710,18 @@ func parseFlagsNode() {
 		cmdcommon.PrintFlagsError(nodeCmd, "--rate-limit-node", err)
 	}
 
+	switch flagProposerSelector {
+	case proposerSelectorSequential, proposerSelectorFrozen:
+	case proposerSelectorWeighted:
+		if validatorWeights, err = parseFlagValidatorWeights(flagValidatorWeights); err != nil {
+			cmdcommon.PrintFlagsError(nodeCmd, "--validator-weights", err)
+		} else if len(validatorWeights) < 1 {
+			cmdcommon.PrintFlagsError(nodeCmd, "--validator-weights", errors.New("must be given"))
+		}
+	default:
+		cmdcommon.PrintFlagsError(nodeCmd, "--proposer-selector", fmt.Errorf("'%s'", flagProposerSelector))
+	}
+
 	{ // time sync
 		if len(flagNTPServer) < 1 {
 			cmdcommon.PrintFlagsError(nodeCmd, "--ntp", errors.New("must be given"))
@@ -646,20 +764,31 @@ func parseFlagsNode() {
 	parsedFlags = append(parsedFlags, "\n\ttimeout-sign", flagTimeoutSIGN)
 	parsedFlags = append(parsedFlags, "\n\ttimeout-accept", flagTimeoutACCEPT)
 	parsedFlags = append(parsedFlags, "\n\ttimeout-allconfirm", flagTimeoutALLCONFIRM)
+	parsedFlags = append(parsedFlags, "\n\ttimeout-backoff-factor", flagTimeoutBackoffFactor)
+	parsedFlags = append(parsedFlags, "\n\ttimeout-backoff-limit", flagTimeoutBackoffLimit)
 	parsedFlags = append(parsedFlags, "\n\tblock-time", flagBlockTime)
 	parsedFlags = append(parsedFlags, "\n\tblock-time-delta", flagBlockTimeDelta)
 	parsedFlags = append(parsedFlags, "\n\ttransactions-limit", flagTransactionsLimit)
 	parsedFlags = append(parsedFlags, "\n\toperations-limit", flagOperationsLimit)
 	parsedFlags = append(parsedFlags, "\n\toperations-in-ballot-limit", flagOperationsInBallotLimit)
 	parsedFlags = append(parsedFlags, "\n\ttxpool-limit", flagTxPoolLimit)
+	parsedFlags = append(parsedFlags, "\n\ttxpool-source-limit", flagTxPoolSourceLimit)
 	parsedFlags = append(parsedFlags, "\n\trate-limit-api", rateLimitRuleAPI)
 	parsedFlags = append(parsedFlags, "\n\trate-limit-node", rateLimitRuleNode)
 	parsedFlags = append(parsedFlags, "\n\thttp-cache-adapter", httpCacheAdapter)
 	parsedFlags = append(parsedFlags, "\n\thttp-cache-pool-size", httpCachePoolSize)
 	parsedFlags = append(parsedFlags, "\n\tdiscovery", discoveryEndpoints)
 	parsedFlags = append(parsedFlags, "\n\twatcher-mode", flagWatcherMode)
+	parsedFlags = append(parsedFlags, "\n\tsync-mode", flagSyncMode)
+	parsedFlags = append(parsedFlags, "\n\tsync-batch-size", syncBatchSize)
+	parsedFlags = append(parsedFlags, "\n\tstate-snapshot-interval", stateSnapshotInterval)
+	parsedFlags = append(parsedFlags, "\n\tstorage-mode", flagStorageMode)
+	parsedFlags = append(parsedFlags, "\n\tprune-blocks", pruneBlocks)
+	parsedFlags = append(parsedFlags, "\n\tcertificate-height", certificateHeight)
 	parsedFlags = append(parsedFlags, "\n\tntp", flagNTPServer)
 	parsedFlags = append(parsedFlags, "\n\ttime-sync-command", flagTimeSyncCommand)
+	parsedFlags = append(parsedFlags, "\n\tproposer-selector", flagProposerSelector)
+	parsedFlags = append(parsedFlags, "\n\tvalidator-weights", validatorWeights)
 
 	// create current Node
 	localNode, err = node.NewLocalNode(kp, bindEndpoint, "")
@@ -767,6 +896,8 @@ func runNode() error {
 		TimeoutSIGN:            timeoutSIGN,
 		TimeoutACCEPT:          timeoutACCEPT,
 		TimeoutALLCONFIRM:      timeoutALLCONFIRM,
+		TimeoutBackoffFactor:   timeoutBackoffFactor,
+		TimeoutBackoffLimit:    timeoutBackoffLimit,
 		NetworkID:              []byte(flagNetworkID),
 		InitialBalance:         initialBalance,
 		BlockTime:              blockTime,
@@ -782,9 +913,14 @@ func runNode() error {
 		CongressAccountAddress: flagCongressAddress,
 		TxPoolClientLimit:      int(txPoolClientLimit),
 		TxPoolNodeLimit:        int(txPoolNodeLimit),
+		TxPoolSourceLimit:      int(txPoolSourceLimit),
 		JSONRPCEndpoint:        jsonrpcbindEndpoint,
 		WatcherMode:            flagWatcherMode,
 		DiscoveryEndpoints:     discoveryEndpoints,
+		StateSnapshotInterval:  stateSnapshotInterval,
+		StorageMode:            flagStorageMode,
+		PruneBlocks:            pruneBlocks,
+		CertificateHeight:      certificateHeight,
 	}
 	connectionManager := network.NewValidatorConnectionManager(localNode, nt, policy, conf)
 
@@ -796,12 +932,17 @@ func runNode() error {
 	}
 	//Place setting config
 	c.SyncPoolSize = syncPoolSize
+	c.SyncBatchSize = syncBatchSize
 	c.FetchTimeout = syncFetchTimeout
 	c.RetryInterval = syncRetryInterval
 	c.CheckBlockHeightInterval = syncCheckInterval
 	c.CheckPrevBlockInterval = syncCheckPrevBlock
+	c.VotingThreshold = int(threshold)
 	c.WatchInterval = watchInterval
 
+	stateSnapshotter := runner.NewStateSnapshotter(st, localNode, conf)
+	c.SetStateSnapshotter(stateSnapshotter)
+
 	syncer := c.NewSyncer()
 
 	isaac, err := consensus.NewISAAC(localNode, policy, connectionManager, st, conf, syncer)
@@ -810,6 +951,28 @@ func runNode() error {
 		return err
 	}
 
+	switch flagProposerSelector {
+	case proposerSelectorWeighted:
+		isaac.SetProposerSelector(
+			consensus.NewWeightedSelector(connectionManager, st, consensus.FixedWeights(validatorWeights)),
+		)
+	case proposerSelectorFrozen:
+		isaac.SetProposerSelector(
+			consensus.NewWeightedSelector(connectionManager, st, consensus.FrozenBalanceWeights),
+		)
+	default:
+		isaac.SetProposerSelector(consensus.NewSequentialSelector(connectionManager))
+	}
+
+	// the state must be synced before `NodeRunner` loads it
+	if flagSyncMode == syncModeFast {
+		if blk, err := c.NewStateSyncer().Sync(context.Background()); err != nil {
+			log.Error("failed to sync the state snapshot; blocks will be synced from genesis", "error", err)
+		} else if blk != nil {
+			log.Info("state snapshot synced", "height", blk.Height, "block", blk.Hash)
+		}
+	}
+
 	// Execution group.
 	var g run.Group
 	{
@@ -819,6 +982,9 @@ func runNode() error {
 			fmt.Fprintf(os.Stderr, "%v\n", err)
 			return err
 		}
+		nr.SetStateSnapshotter(stateSnapshotter)
+		c.SetValidatorsUpdater(nr.UpdateValidators)
+		c.SetDelegatedUpdater(nr.UpdateDelegated)
 
 		g.Add(func() error {
 			if err := nr.Start(); err != nil {
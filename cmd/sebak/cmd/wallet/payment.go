@@ -29,6 +29,7 @@ var (
 	flagDry           bool
 	flagFreeze        bool
 	flagVerbose       bool
+	flagFee           string
 )
 
 func init() {
@@ -42,6 +43,7 @@ func init() {
 			var sender keypair.KP
 			var receiver keypair.KP
 			var endpoint *common.Endpoint
+			var fee common.Amount
 
 			// Receiver's public key
 			if receiver, err = keypair.Parse(args[0]); err != nil {
@@ -75,6 +77,22 @@ func init() {
 				cmdcommon.PrintFlagsError(c, "--endpoint", err)
 			}
 
+			// Fee; the transaction, which pays more fee is included in block first
+			if flagFreeze {
+				fee = common.FrozenFee
+			} else {
+				fee = common.BaseFee
+			}
+			if len(flagFee) > 0 {
+				var customFee common.Amount
+				if customFee, err = cmdcommon.ParseAmountFromString(flagFee); err != nil {
+					cmdcommon.PrintFlagsError(c, "--fee", err)
+				} else if customFee < fee {
+					cmdcommon.PrintFlagsError(c, "--fee", fmt.Errorf("Fee should be at least %v", fee))
+				}
+				fee = customFee
+			}
+
 			// TODO: Validate input transaction (does the sender have enough money?)
 
 			// At the moment this is a rather crude implementation: There is no support for pooling of transaction,
@@ -101,10 +119,6 @@ func init() {
 
 			// Check that account's balance is enough before sending the transaction
 			{
-				fee := common.BaseFee
-				if flagFreeze {
-					fee = common.FrozenFee
-				}
 				_, err = senderAccount.GetBalance().Sub(amount + fee)
 				if err != nil {
 					fmt.Printf("Attempting to draft %v GON (+ %v fees), but sender account only have %v GON\n",
@@ -121,6 +135,7 @@ func init() {
 			} else {
 				tx = MakeTransactionPayment(sender, receiver, amount, senderAccount.SequenceID)
 			}
+			tx.B.Fee = fee
 
 			tx.Sign(sender, []byte(flagNetworkID))
 
@@ -150,6 +165,7 @@ func init() {
 	PaymentCmd.Flags().BoolVar(&flagCreateAccount, "create", flagCreateAccount, "Whether or not the account should be created")
 	PaymentCmd.Flags().BoolVar(&flagFreeze, "freeze", flagFreeze, "When present, the payment is a frozen account creation. Imply --create.")
 	PaymentCmd.Flags().BoolVar(&flagDry, "dry-run", flagDry, "Print the transaction instead of sending it")
+	PaymentCmd.Flags().StringVar(&flagFee, "fee", flagFee, "Fee of the transaction; the default is the base fee")
 	PaymentCmd.Flags().BoolVar(&flagVerbose, "verbose", flagVerbose, "Print extra data (transaction sent, before/after balance...)")
 }
 
@@ -152,6 +152,15 @@ func (b Ballot) Transactions() []string {
 	return b.B.Proposed.Transactions
 }
 
+func (b Ballot) StateRoot() common.Hash {
+	return b.B.Proposed.StateRoot
+}
+
+// SetStateRoot should be called before signing by proposer.
+func (b *Ballot) SetStateRoot(root common.Hash) {
+	b.B.Proposed.StateRoot = root
+}
+
 func (b Ballot) Confirmed() string {
 	return b.B.Confirmed
 }
@@ -233,6 +242,41 @@ func (b Ballot) VerifySource(networkID []byte) (err error) {
 	return
 }
 
+// IsConflicted checks the ballot conflicts with the other ballot; the
+// conflicted ballots are signed by the same source for the same voting basis
+// and state, but they have the different votes or the different proposals.
+// The expired ballots does not conflict, because the node can expire the
+// round, which it already voted.
+func (b Ballot) IsConflicted(o Ballot) bool {
+	if b.Source() != o.Source() || b.State() != o.State() {
+		return false
+	}
+	if b.VotingBasis().Index() != o.VotingBasis().Index() {
+		return false
+	}
+	if b.GetHash() == o.GetHash() {
+		return false
+	}
+	if b.Vote() == voting.EXP || o.Vote() == voting.EXP {
+		return false
+	}
+
+	if b.Vote() != o.Vote() || b.Proposer() != o.Proposer() {
+		return true
+	}
+
+	if len(b.Transactions()) != len(o.Transactions()) {
+		return true
+	}
+	for i, hash := range b.Transactions() {
+		if o.Transactions()[i] != hash {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (b Ballot) IsFromProposer() bool {
 	return b.B.Source == b.B.Proposed.Proposer
 }
@@ -264,6 +308,7 @@ type BallotBodyProposed struct {
 	VotingBasis         voting.Basis        `json:"voting_basis"`
 	Transactions        []string            `json:"transactions"`
 	ProposerTransaction ProposerTransaction `json:"proposer_transaction"`
+	StateRoot           common.Hash         `json:"state_root"` // root of state trie after `Transactions` are applied
 }
 
 type BallotBody struct {
@@ -0,0 +1,134 @@
+package ballot
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/storage"
+	"boscoin.io/sebak/lib/voting"
+)
+
+// Certificate is the proof of the block; it keeps the signed ACCEPT ballots,
+// which agreed the block, so anyone can verify the block was confirmed by the
+// validators.
+type Certificate struct {
+	Block   string   `json:"block"` // hash of block
+	Height  uint64   `json:"height"`
+	Ballots []Ballot `json:"ballots"` // sorted by the source of ballot
+}
+
+func NewCertificate(blockHash string, height uint64, ballots ...Ballot) *Certificate {
+	c := &Certificate{
+		Block:  blockHash,
+		Height: height,
+	}
+	c.Ballots = append(c.Ballots, ballots...)
+	sort.Slice(c.Ballots, func(i, j int) bool {
+		return c.Ballots[i].Source() < c.Ballots[j].Source()
+	})
+
+	return c
+}
+
+func (c *Certificate) String() string {
+	return string(common.MustMarshalJSON(c))
+}
+
+// Sources returns the validators, which signed the ballots.
+func (c *Certificate) Sources() (sources []string) {
+	for _, b := range c.Ballots {
+		sources = append(sources, b.Source())
+	}
+
+	return
+}
+
+// Proposed returns the proposal, which the ballots agreed.
+func (c *Certificate) Proposed() BallotBodyProposed {
+	if len(c.Ballots) < 1 {
+		return BallotBodyProposed{}
+	}
+
+	return c.Ballots[0].B.Proposed
+}
+
+// VotingBasis returns the voting basis of the ballots.
+func (c *Certificate) VotingBasis() voting.Basis {
+	return c.Proposed().VotingBasis
+}
+
+// Verify checks the ballots are the signed ACCEPT ballots of the `validators`
+// for the same proposal and the number of them reaches the `threshold`.
+func (c *Certificate) Verify(networkID []byte, validators []string, threshold int) (err error) {
+	if len(c.Ballots) < 1 {
+		return errors.CertificateInvalid
+	}
+
+	if err = c.Ballots[0].VerifyProposer(networkID); err != nil {
+		return
+	}
+
+	known := map[string]bool{}
+	for _, address := range validators {
+		known[address] = true
+	}
+
+	proposed := common.MustMakeObjectHash(c.Proposed())
+	sources := map[string]bool{}
+	for _, b := range c.Ballots {
+		if b.State() != StateACCEPT || b.Vote() != voting.YES {
+			return errors.CertificateInvalid
+		}
+		if !known[b.Source()] || sources[b.Source()] {
+			return errors.CertificateInvalid
+		}
+		if !bytes.Equal(proposed, common.MustMakeObjectHash(b.B.Proposed)) {
+			return errors.CertificateInvalid
+		}
+		if b.GetHash() != b.B.MakeHashString() {
+			return errors.CertificateInvalid
+		}
+		if err = b.VerifySource(networkID); err != nil {
+			return
+		}
+		sources[b.Source()] = true
+	}
+
+	if len(sources) < threshold {
+		return errors.CertificateThresholdNotReached
+	}
+
+	return
+}
+
+// Save stores the certificate by the hash of block.
+func (c *Certificate) Save(st *storage.LevelDBBackend) (err error) {
+	key := GetCertificateKey(c.Block)
+
+	var exists bool
+	if exists, err = st.Has(key); err != nil {
+		return
+	}
+
+	if exists {
+		return st.Set(key, c)
+	}
+
+	return st.New(key, c)
+}
+
+func GetCertificateKey(blockHash string) string {
+	return fmt.Sprintf("%s%s", common.CertificatePrefixBlock, blockHash)
+}
+
+func ExistsCertificate(st *storage.LevelDBBackend, blockHash string) (bool, error) {
+	return st.Has(GetCertificateKey(blockHash))
+}
+
+func GetCertificate(st *storage.LevelDBBackend, blockHash string) (c *Certificate, err error) {
+	err = st.Get(GetCertificateKey(blockHash), &c)
+	return
+}
@@ -0,0 +1,98 @@
+package ballot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/storage"
+	"boscoin.io/sebak/lib/voting"
+)
+
+func TestCertificate(t *testing.T) {
+	st := storage.NewTestStorage()
+	defer st.Close()
+
+	conf := common.NewTestConfig()
+	commonKP := keypair.Random()
+	basis := voting.Basis{Round: 0, Height: 1, BlockHash: "hahaha", TotalTxs: 1}
+
+	var kps []*keypair.Full
+	var validators []string
+	for i := 0; i < 3; i++ {
+		kp := keypair.Random()
+		kps = append(kps, kp)
+		validators = append(validators, kp.Address())
+	}
+
+	propose := func(txs []string) *Ballot {
+		b := NewBallot(kps[0].Address(), kps[0].Address(), basis, txs)
+		opc, _ := NewCollectTxFeeFromBallot(*b, commonKP.Address())
+		opi, _ := NewInflationFromBallot(*b, commonKP.Address(), common.Amount(1))
+		ptx, _ := NewProposerTransactionFromBallot(*b, opc, opi)
+		b.SetProposerTransaction(ptx)
+		b.SetVote(StateINIT, voting.YES)
+		b.Sign(kps[0], conf.NetworkID)
+		return b
+	}
+	proposed := propose([]string{"tx0"})
+
+	accept := func(kp *keypair.Full, vote voting.Hole) Ballot {
+		b := *proposed
+		b.SetVote(StateACCEPT, vote)
+		b.Sign(kp, conf.NetworkID)
+		return b
+	}
+
+	var ballots []Ballot
+	for _, kp := range kps {
+		ballots = append(ballots, accept(kp, voting.YES))
+	}
+
+	c := NewCertificate("block-hash", 2, ballots...)
+	require.NoError(t, c.Verify(conf.NetworkID, validators, 3))
+	require.Equal(t, basis, c.VotingBasis())
+	require.Equal(t, 3, len(c.Sources()))
+
+	{ // not enough ballots
+		c := NewCertificate("block-hash", 2, ballots[:2]...)
+		require.Equal(t, errors.CertificateThresholdNotReached, c.Verify(conf.NetworkID, validators, 3))
+	}
+
+	{ // unknown validator
+		c := NewCertificate("block-hash", 2, append(ballots[:2], accept(keypair.Random(), voting.YES))...)
+		require.Equal(t, errors.CertificateInvalid, c.Verify(conf.NetworkID, validators, 2))
+	}
+
+	{ // duplicated validator
+		c := NewCertificate("block-hash", 2, ballots[0], ballots[0], ballots[1])
+		require.Equal(t, errors.CertificateInvalid, c.Verify(conf.NetworkID, validators, 3))
+	}
+
+	{ // not YES
+		c := NewCertificate("block-hash", 2, ballots[0], ballots[1], accept(kps[2], voting.NO))
+		require.Equal(t, errors.CertificateInvalid, c.Verify(conf.NetworkID, validators, 3))
+	}
+
+	{ // different proposal
+		b := *propose([]string{"tx1"})
+		b.SetVote(StateACCEPT, voting.YES)
+		b.Sign(kps[2], conf.NetworkID)
+		c := NewCertificate("block-hash", 2, ballots[0], ballots[1], b)
+		require.Equal(t, errors.CertificateInvalid, c.Verify(conf.NetworkID, validators, 3))
+	}
+
+	require.NoError(t, c.Save(st))
+
+	exists, err := ExistsCertificate(st, "block-hash")
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	found, err := GetCertificate(st, "block-hash")
+	require.NoError(t, err)
+	require.Equal(t, c.Sources(), found.Sources())
+	require.NoError(t, found.Verify(conf.NetworkID, validators, 3))
+}
@@ -0,0 +1,130 @@
+package ballot
+
+import (
+	"fmt"
+	"sort"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/storage"
+	"boscoin.io/sebak/lib/voting"
+)
+
+// Evidence is the proof of the equivocation; the validator of `Address`
+// signed the two conflicting ballots for the same voting basis and state.
+// The signed ballots are kept as they are received, so anyone can verify the
+// evidence.
+type Evidence struct {
+	Hash     string       `json:"hash"`
+	Address  string       `json:"address"`
+	Basis    voting.Basis `json:"basis"`
+	State    State        `json:"state"`
+	Ballots  []Ballot     `json:"ballots"` // sorted by the ballot hash
+	Detected string       `json:"detected"`
+}
+
+func NewEvidence(a, b Ballot) (e *Evidence, err error) {
+	if !a.IsConflicted(b) {
+		return nil, errors.EvidenceInvalid
+	}
+
+	ballots := []Ballot{a, b}
+	sort.Slice(ballots, func(i, j int) bool {
+		return ballots[i].GetHash() < ballots[j].GetHash()
+	})
+
+	e = &Evidence{
+		Hash:     common.MustMakeObjectHashString([]string{ballots[0].GetHash(), ballots[1].GetHash()}),
+		Address:  a.Source(),
+		Basis:    a.VotingBasis(),
+		State:    a.State(),
+		Ballots:  ballots,
+		Detected: common.NowISO8601(),
+	}
+
+	return
+}
+
+func (e *Evidence) String() string {
+	return string(common.MustMarshalJSON(e))
+}
+
+// Verify checks the ballots of evidence are conflicted and they are signed by
+// the validator.
+func (e *Evidence) Verify(networkID []byte) (err error) {
+	if len(e.Ballots) != 2 || !e.Ballots[0].IsConflicted(e.Ballots[1]) {
+		return errors.EvidenceInvalid
+	}
+
+	for _, b := range e.Ballots {
+		if b.Source() != e.Address || b.GetHash() != b.B.MakeHashString() {
+			return errors.EvidenceInvalid
+		}
+		if err = b.VerifySource(networkID); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// Save stores the evidence; the same evidence is stored only once.
+func (e *Evidence) Save(st *storage.LevelDBBackend) (err error) {
+	key := GetEvidenceKey(e.Hash)
+
+	var exists bool
+	if exists, err = st.Has(key); err != nil || exists {
+		return
+	}
+
+	if err = st.New(key, e); err != nil {
+		return
+	}
+
+	return st.New(GetEvidenceKeyByHeight(e.Basis.Height, e.Hash), e.Hash)
+}
+
+func GetEvidenceKey(hash string) string {
+	return fmt.Sprintf("%s%s", common.EvidencePrefixHash, hash)
+}
+
+func GetEvidenceKeyByHeight(height uint64, hash string) string {
+	return fmt.Sprintf("%s%020d%s", common.EvidencePrefixHeight, height, hash)
+}
+
+func ExistsEvidence(st *storage.LevelDBBackend, hash string) (bool, error) {
+	return st.Has(GetEvidenceKey(hash))
+}
+
+func GetEvidence(st *storage.LevelDBBackend, hash string) (e *Evidence, err error) {
+	err = st.Get(GetEvidenceKey(hash), &e)
+	return
+}
+
+// GetEvidences returns the `Evidence`s by the order of the block height of
+// voting basis.
+func GetEvidences(st *storage.LevelDBBackend, options storage.ListOptions) (
+	func() (*Evidence, bool, []byte),
+	func(),
+) {
+	iterFunc, closeFunc := st.GetIterator(common.EvidencePrefixHeight, options)
+
+	return (func() (*Evidence, bool, []byte) {
+			item, hasNext := iterFunc()
+			if !hasNext {
+				return nil, false, item.Key
+			}
+
+			var hash string
+			common.MustUnmarshalJSON(item.Value, &hash)
+
+			e, err := GetEvidence(st, hash)
+			if err != nil {
+				return nil, false, item.Key
+			}
+
+			return e, hasNext, item.Key
+		}), (func() {
+			closeFunc()
+		})
+}
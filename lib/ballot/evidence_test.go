@@ -0,0 +1,124 @@
+package ballot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/storage"
+	"boscoin.io/sebak/lib/voting"
+)
+
+func TestBallotIsConflicted(t *testing.T) {
+	conf := common.NewTestConfig()
+	kp := keypair.Random()
+	proposer := keypair.Random()
+	basis := voting.Basis{Round: 0, Height: 1, BlockHash: "hahaha", TotalTxs: 1}
+
+	newBallot := func(source *keypair.Full, basis voting.Basis, txs []string, vote voting.Hole) Ballot {
+		b := NewBallot(source.Address(), proposer.Address(), basis, txs)
+		b.SetVote(StateSIGN, vote)
+		b.Sign(source, conf.NetworkID)
+		return *b
+	}
+
+	a := newBallot(kp, basis, []string{"tx0"}, voting.YES)
+
+	// same ballot
+	require.False(t, a.IsConflicted(a))
+
+	// different transactions
+	require.True(t, a.IsConflicted(newBallot(kp, basis, []string{"tx1"}, voting.YES)))
+
+	// different vote
+	require.True(t, a.IsConflicted(newBallot(kp, basis, []string{"tx0"}, voting.NO)))
+
+	// expired
+	require.False(t, a.IsConflicted(newBallot(kp, basis, []string{"tx0"}, voting.EXP)))
+
+	// other source
+	require.False(t, a.IsConflicted(newBallot(keypair.Random(), basis, []string{"tx1"}, voting.YES)))
+
+	// other round
+	nextBasis := basis
+	nextBasis.Round++
+	require.False(t, a.IsConflicted(newBallot(kp, nextBasis, []string{"tx1"}, voting.YES)))
+
+	// other state
+	b := newBallot(kp, basis, []string{"tx1"}, voting.YES)
+	b.SetVote(StateACCEPT, voting.YES)
+	b.Sign(kp, conf.NetworkID)
+	require.False(t, a.IsConflicted(b))
+}
+
+func TestEvidence(t *testing.T) {
+	st := storage.NewTestStorage()
+	defer st.Close()
+
+	conf := common.NewTestConfig()
+	kp := keypair.Random()
+	commonKP := keypair.Random()
+	basis := voting.Basis{Round: 0, Height: 1, BlockHash: "hahaha", TotalTxs: 1}
+
+	newBallot := func(txs []string) Ballot {
+		b := NewBallot(kp.Address(), kp.Address(), basis, txs)
+		opc, _ := NewCollectTxFeeFromBallot(*b, commonKP.Address())
+		opi, _ := NewInflationFromBallot(*b, commonKP.Address(), common.Amount(1))
+		ptx, _ := NewProposerTransactionFromBallot(*b, opc, opi)
+		b.SetProposerTransaction(ptx)
+		b.SetVote(StateSIGN, voting.YES)
+		b.Sign(kp, conf.NetworkID)
+		return *b
+	}
+
+	a := newBallot([]string{"tx0"})
+	b := newBallot([]string{"tx1"})
+
+	{ // not conflicted
+		_, err := NewEvidence(a, a)
+		require.Equal(t, errors.EvidenceInvalid, err)
+	}
+
+	e, err := NewEvidence(a, b)
+	require.NoError(t, err)
+	require.Equal(t, kp.Address(), e.Address)
+	require.NoError(t, e.Verify(conf.NetworkID))
+
+	{ // the same evidence regardless of the order of ballots
+		other, err := NewEvidence(b, a)
+		require.NoError(t, err)
+		require.Equal(t, e.Hash, other.Hash)
+	}
+
+	require.NoError(t, e.Save(st))
+	require.NoError(t, e.Save(st))
+
+	exists, err := ExistsEvidence(st, e.Hash)
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	found, err := GetEvidence(st, e.Hash)
+	require.NoError(t, err)
+	require.Equal(t, e.Hash, found.Hash)
+	require.NoError(t, found.Verify(conf.NetworkID))
+
+	iterFunc, closeFunc := GetEvidences(st, nil)
+	var evidences []*Evidence
+	for {
+		found, hasNext, _ := iterFunc()
+		if !hasNext {
+			break
+		}
+		evidences = append(evidences, found)
+	}
+	closeFunc()
+	require.Equal(t, 1, len(evidences))
+
+	{ // signed by the other
+		found.Ballots[1].Sign(keypair.Random(), conf.NetworkID)
+		require.Error(t, found.Verify(conf.NetworkID))
+	}
+}
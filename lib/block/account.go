@@ -5,6 +5,7 @@ import (
 
 	"boscoin.io/sebak/lib/common"
 	"boscoin.io/sebak/lib/storage"
+	"boscoin.io/sebak/lib/transaction/operation"
 )
 
 // BlockAccount is account model in block. the storage should support,
@@ -26,6 +27,10 @@ type BlockAccount struct {
 	Linked   string      `json:"linked"`
 	CodeHash []byte      `json:"code_hash"`
 	RootHash common.Hash `json:"root_hash"`
+	// Signers and Thresholds are set by `SetOptions` operation; without
+	// signers, only the account itself can sign.
+	Signers    []operation.Signer   `json:"signers,omitempty"`
+	Thresholds operation.Thresholds `json:"thresholds"`
 }
 
 func NewBlockAccount(address string, balance common.Amount) *BlockAccount {
@@ -45,6 +50,37 @@ func (b *BlockAccount) IsFrozen() bool {
 	return b.Linked != ""
 }
 
+func (b *BlockAccount) IsMultiSig() bool {
+	return len(b.Signers) > 0
+}
+
+// SignatureWeight returns the sum of the weights of the given signers.
+func (b *BlockAccount) SignatureWeight(signers []string) (weight uint64) {
+	if !b.IsMultiSig() {
+		if _, found := common.InStringArray(signers, b.Address); found {
+			weight = 1
+		}
+		return
+	}
+
+	for _, s := range b.Signers {
+		if _, found := common.InStringArray(signers, s.Address); found {
+			weight += uint64(s.Weight)
+		}
+	}
+
+	return
+}
+
+// Threshold returns the minimum signature weight of the given level.
+func (b *BlockAccount) Threshold(level operation.ThresholdLevel) uint64 {
+	if t := b.Thresholds.Get(level); b.IsMultiSig() && t > 0 {
+		return uint64(t)
+	}
+
+	return 1
+}
+
 func (b *BlockAccount) IncreaseSequenceID() {
 	b.SequenceID += 1
 }
@@ -5,7 +5,9 @@ import (
 	"testing"
 
 	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
 	"boscoin.io/sebak/lib/storage"
+	"boscoin.io/sebak/lib/transaction/operation"
 
 	"github.com/stretchr/testify/require"
 )
@@ -129,3 +131,33 @@ func TestBlockAccountSaveBlockAccountSequenceIDs(t *testing.T) {
 		require.Equal(t, b.SequenceID, fetched[i].SequenceID)
 	}
 }
+
+func TestBlockAccountSignatureWeight(t *testing.T) {
+	b := TestMakeBlockAccount()
+	signer0 := keypair.Random().Address()
+	signer1 := keypair.Random().Address()
+
+	{ // without signers
+		require.False(t, b.IsMultiSig())
+		require.Equal(t, uint64(1), b.SignatureWeight([]string{b.Address}))
+		require.Equal(t, uint64(0), b.SignatureWeight([]string{signer0}))
+		require.Equal(t, uint64(1), b.Threshold(operation.ThresholdHigh))
+	}
+
+	b.Signers = []operation.Signer{
+		operation.Signer{Address: signer0, Weight: 1},
+		operation.Signer{Address: signer1, Weight: 2},
+	}
+	b.Thresholds = operation.Thresholds{Low: 0, Medium: 2, High: 3}
+
+	{ // with signers
+		require.True(t, b.IsMultiSig())
+		require.Equal(t, uint64(0), b.SignatureWeight([]string{b.Address}))
+		require.Equal(t, uint64(1), b.SignatureWeight([]string{b.Address, signer0}))
+		require.Equal(t, uint64(3), b.SignatureWeight([]string{signer0, signer1}))
+
+		require.Equal(t, uint64(1), b.Threshold(operation.ThresholdLow))
+		require.Equal(t, uint64(2), b.Threshold(operation.ThresholdMedium))
+		require.Equal(t, uint64(3), b.Threshold(operation.ThresholdHigh))
+	}
+}
@@ -34,10 +34,11 @@ func (bck Block) IsEmpty() bool {
 }
 
 // NewBlock creates new block; `ptx` represents the
-// `ProposerTransaction.GetHash()`.
-func NewBlock(proposer string, basis voting.Basis, ptx string, transactions []string, proposedTime string) *Block {
+// `ProposerTransaction.GetHash()` and `stateRoot` is the root of state trie
+// after the transactions of block are applied.
+func NewBlock(proposer string, basis voting.Basis, ptx string, transactions []string, stateRoot common.Hash, proposedTime string) *Block {
 	b := &Block{
-		Header:              *NewBlockHeader(basis, getTransactionRoot(append([]string{ptx}, transactions...)), proposedTime),
+		Header:              *NewBlockHeader(basis, getTransactionRoot(append([]string{ptx}, transactions...)), stateRoot, proposedTime),
 		Transactions:        transactions,
 		ProposerTransaction: ptx,
 		Proposer:            proposer,
@@ -48,8 +49,46 @@ func NewBlock(proposer string, basis voting.Basis, ptx string, transactions []st
 	return b
 }
 
+// getTransactionRoot returns the merkle root of transaction hashes; the first
+// one is the hash of `ProposerTransaction`.
 func getTransactionRoot(txs []string) string {
-	return common.MustMakeObjectHashString(txs) // TODO make root
+	return base58.Encode(common.MakeMerkleRoot(transactionRootItems(txs)))
+}
+
+func transactionRootItems(txs []string) [][]byte {
+	items := make([][]byte, len(txs))
+	for i, tx := range txs {
+		items[i] = []byte(tx)
+	}
+	return items
+}
+
+// TransactionProof returns the inclusion path of transaction to
+// `Header.TransactionsRoot`.
+func (b Block) TransactionProof(txHash string) (proof []common.MerkleProofNode, err error) {
+	txs := append([]string{b.ProposerTransaction}, b.Transactions...)
+
+	index := -1
+	for i, tx := range txs {
+		if tx == txHash {
+			index = i
+			break
+		}
+	}
+
+	var ok bool
+	if proof, ok = common.MakeMerkleProof(transactionRootItems(txs), index); !ok {
+		err = errors.BlockTransactionDoesNotExists
+		return
+	}
+
+	return
+}
+
+// VerifyTransactionProof checks the proof from `Block.TransactionProof()`
+// against `Header.TransactionsRoot`.
+func VerifyTransactionProof(transactionsRoot string, txHash string, proof []common.MerkleProofNode) bool {
+	return common.VerifyMerkleProof(base58.Decode(transactionsRoot), []byte(txHash), proof)
 }
 
 func getBlockKey(hash string) string {
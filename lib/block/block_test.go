@@ -287,3 +287,21 @@ func TestMakeGenesisBlockFindCommonAccount(t *testing.T) {
 		require.Equal(t, commonAccount.SequenceID, ac.SequenceID)
 	}
 }
+
+func TestBlockTransactionProof(t *testing.T) {
+	txs := []string{"tx-0", "tx-1", "tx-2", "tx-3", "tx-4"}
+	prev := TestMakeNewBlock([]string{})
+	blk := TestMakeNewBlockWithPrevBlock(prev, txs)
+
+	for _, tx := range append([]string{blk.ProposerTransaction}, txs...) {
+		proof, err := blk.TransactionProof(tx)
+		require.NoError(t, err)
+		require.True(t, VerifyTransactionProof(blk.TransactionsRoot, tx, proof))
+
+		// with the transactions root of the other block
+		require.False(t, VerifyTransactionProof(prev.TransactionsRoot, tx, proof))
+	}
+
+	_, err := blk.TransactionProof("unknown")
+	require.Equal(t, errors.BlockTransactionDoesNotExists, err)
+}
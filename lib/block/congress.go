@@ -0,0 +1,349 @@
+package block
+
+import (
+	"fmt"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/storage"
+	"boscoin.io/sebak/lib/transaction/operation"
+)
+
+// CongressVotingTally is the number of votes by answer.
+type CongressVotingTally struct {
+	Count uint64 `json:"count"`
+	Yes   uint64 `json:"yes"`
+	No    uint64 `json:"no"`
+	ABS   uint64 `json:"abs"`
+}
+
+func (t *CongressVotingTally) Add(answer operation.VoteAnswer) {
+	t.Count++
+	switch answer {
+	case operation.VoteYes:
+		t.Yes++
+	case operation.VoteNo:
+		t.No++
+	case operation.VoteABS:
+		t.ABS++
+	}
+}
+
+func (t *CongressVotingTally) Remove(answer operation.VoteAnswer) {
+	t.Count--
+	switch answer {
+	case operation.VoteYes:
+		t.Yes--
+	case operation.VoteNo:
+		t.No--
+	case operation.VoteABS:
+		t.ABS--
+	}
+}
+
+// CongressVoting is the `operation.CongressVoting` included in block. The
+// votes of `operation.CongressVote` are tallied by node until `Voting.End`;
+// at the block of `Voting.End`, the voting is closed and the result is
+// decided. The voting passes when the yes votes are more than half of the
+// votes.
+//
+// `Results` are the `operation.CongressVotingResult`s of the voting and
+// `Funding` is the `operation.InflationPF`, which funds the voting; they are
+// `<transaction hash>-<operation index>` of the operations.
+type CongressVoting struct {
+	Hash     string                   `json:"hash"` // <transaction hash>-<operation index>
+	TxHash   string                   `json:"tx_hash"`
+	Source   string                   `json:"source"`
+	Height   uint64                   `json:"block_height"`
+	Proposal operation.CongressVoting `json:"proposal"`
+
+	Tally  CongressVotingTally `json:"tally"`
+	Closed bool                `json:"closed"`
+	Passed bool                `json:"passed"`
+
+	Results []string `json:"results"`
+	Funding string   `json:"funding"`
+}
+
+func NewCongressVoting(txHash string, index int, source string, blockHeight uint64, opb operation.CongressVoting) *CongressVoting {
+	return &CongressVoting{
+		Hash:     fmt.Sprintf("%s-%d", txHash, index),
+		TxHash:   txHash,
+		Source:   source,
+		Height:   blockHeight,
+		Proposal: opb,
+	}
+}
+
+func (cv *CongressVoting) String() string {
+	return string(common.MustMarshalJSON(cv))
+}
+
+// IsVotable checks the vote can be included in the block of `height`.
+func (cv *CongressVoting) IsVotable(height uint64) bool {
+	if cv.Closed {
+		return false
+	}
+
+	return height >= cv.Proposal.Voting.Start && height <= cv.Proposal.Voting.End
+}
+
+// Close decides the result by the tally.
+func (cv *CongressVoting) Close() {
+	cv.Closed = true
+	cv.Passed = cv.Tally.Yes*2 > cv.Tally.Count
+}
+
+func (cv *CongressVoting) Save(st *storage.LevelDBBackend) (err error) {
+	key := GetCongressVotingKey(cv.Hash)
+
+	var exists bool
+	if exists, err = st.Has(key); err != nil {
+		return
+	}
+
+	if exists {
+		return st.Set(key, cv)
+	}
+
+	if err = st.New(key, cv); err != nil {
+		return
+	}
+	if err = st.New(GetCongressVotingKeyByHeight(cv.Height, cv.Hash), cv.Hash); err != nil {
+		return
+	}
+
+	return st.New(GetCongressVotingKeyByEnd(cv.Proposal.Voting.End, cv.Hash), cv.Hash)
+}
+
+// AddResult adds the `operation.CongressVotingResult` of the voting;
+// `GetCongressVotingByResult()` finds the voting by it.
+func (cv *CongressVoting) AddResult(st *storage.LevelDBBackend, resultHash string) (err error) {
+	if err = st.New(GetCongressVotingKeyByResult(resultHash), cv.Hash); err != nil {
+		return
+	}
+
+	cv.Results = append(cv.Results, resultHash)
+
+	return cv.Save(st)
+}
+
+func GetCongressVotingKey(hash string) string {
+	return fmt.Sprintf("%s%s", common.CongressVotingPrefixHash, hash)
+}
+
+func GetCongressVotingKeyByHeight(height uint64, hash string) string {
+	return fmt.Sprintf("%s%020d%s", common.CongressVotingPrefixHeight, height, hash)
+}
+
+func GetCongressVotingKeyByResult(resultHash string) string {
+	return fmt.Sprintf("%s%s", common.CongressVotingPrefixResult, resultHash)
+}
+
+func GetCongressVotingKeyPrefixEnd(end uint64) string {
+	return fmt.Sprintf("%s%020d", common.CongressVotingPrefixEnd, end)
+}
+
+func GetCongressVotingKeyByEnd(end uint64, hash string) string {
+	return fmt.Sprintf("%s%s", GetCongressVotingKeyPrefixEnd(end), hash)
+}
+
+func ExistsCongressVoting(st *storage.LevelDBBackend, hash string) (bool, error) {
+	return st.Has(GetCongressVotingKey(hash))
+}
+
+func GetCongressVoting(st *storage.LevelDBBackend, hash string) (cv *CongressVoting, err error) {
+	if err = st.Get(GetCongressVotingKey(hash), &cv); err != nil {
+		return
+	}
+
+	return
+}
+
+// GetCongressVotingByResult returns the `CongressVoting` of the
+// `operation.CongressVotingResult`.
+func GetCongressVotingByResult(st *storage.LevelDBBackend, resultHash string) (cv *CongressVoting, err error) {
+	var hash string
+	if err = st.Get(GetCongressVotingKeyByResult(resultHash), &hash); err != nil {
+		return
+	}
+
+	return GetCongressVoting(st, hash)
+}
+
+func loadCongressVotingsInsideIterator(
+	st *storage.LevelDBBackend,
+	iterFunc func() (storage.IterItem, bool),
+	closeFunc func(),
+) (
+	func() (*CongressVoting, bool, []byte),
+	func(),
+) {
+	return (func() (*CongressVoting, bool, []byte) {
+			item, hasNext := iterFunc()
+			if !hasNext {
+				return nil, false, item.Key
+			}
+
+			var hash string
+			common.MustUnmarshalJSON(item.Value, &hash)
+
+			cv, err := GetCongressVoting(st, hash)
+			if err != nil {
+				return nil, false, item.Key
+			}
+
+			return cv, hasNext, item.Key
+		}), (func() {
+			closeFunc()
+		})
+}
+
+// GetCongressVotings returns the `CongressVoting`s by the order of the block
+// height, which includes them.
+func GetCongressVotings(st *storage.LevelDBBackend, options storage.ListOptions) (
+	func() (*CongressVoting, bool, []byte),
+	func(),
+) {
+	iterFunc, closeFunc := st.GetIterator(common.CongressVotingPrefixHeight, options)
+
+	return loadCongressVotingsInsideIterator(st, iterFunc, closeFunc)
+}
+
+// GetCongressVotingsByEnd returns the `CongressVoting`s, which end at the
+// block of `end`.
+func GetCongressVotingsByEnd(st *storage.LevelDBBackend, end uint64, options storage.ListOptions) (
+	func() (*CongressVoting, bool, []byte),
+	func(),
+) {
+	iterFunc, closeFunc := st.GetIterator(GetCongressVotingKeyPrefixEnd(end), options)
+
+	return loadCongressVotingsInsideIterator(st, iterFunc, closeFunc)
+}
+
+// CongressVotingFunding records the `operation.InflationPF`, which consumes the
+// `operation.CongressVotingResult`; the voting result can be funded only once.
+type CongressVotingFunding struct {
+	VotingResult string `json:"voting_result"`
+	Funding      string `json:"funding"` // <transaction hash>-<operation index>
+	Height       uint64 `json:"block_height"`
+}
+
+func NewCongressVotingFunding(votingResult, funding string, blockHeight uint64) *CongressVotingFunding {
+	return &CongressVotingFunding{
+		VotingResult: votingResult,
+		Funding:      funding,
+		Height:       blockHeight,
+	}
+}
+
+func (f *CongressVotingFunding) String() string {
+	return string(common.MustMarshalJSON(f))
+}
+
+// Save stores the funding; if the voting result is already funded, it returns
+// error.
+func (f *CongressVotingFunding) Save(st *storage.LevelDBBackend) error {
+	return st.New(GetCongressVotingFundingKey(f.VotingResult), f)
+}
+
+func GetCongressVotingFundingKey(votingResult string) string {
+	return fmt.Sprintf("%s%s", common.CongressVotingPrefixFunding, votingResult)
+}
+
+func ExistsCongressVotingFunding(st *storage.LevelDBBackend, votingResult string) (bool, error) {
+	return st.Has(GetCongressVotingFundingKey(votingResult))
+}
+
+func GetCongressVotingFunding(st *storage.LevelDBBackend, votingResult string) (f *CongressVotingFunding, err error) {
+	if err = st.Get(GetCongressVotingFundingKey(votingResult), &f); err != nil {
+		return
+	}
+
+	return
+}
+
+// CongressVote is the `operation.CongressVote` included in block. Only the
+// last vote of voter is counted for the `CongressVoting`. `Voter` is the
+// general account, which owns the frozen accounts, and `Account` is the frozen
+// account, which sends the vote; one voter has one vote, even though it has
+// several frozen accounts.
+type CongressVote struct {
+	CongressVotingHash string               `json:"congress_voting_hash"`
+	Voter              string               `json:"voter"`
+	Account            string               `json:"account"`
+	Answer             operation.VoteAnswer `json:"answer"`
+	TxHash             string               `json:"tx_hash"`
+	Height             uint64               `json:"block_height"`
+}
+
+func NewCongressVote(txHash string, voter, account string, blockHeight uint64, opb operation.CongressVote) *CongressVote {
+	return &CongressVote{
+		CongressVotingHash: opb.CongressVotingHash,
+		Voter:              voter,
+		Account:            account,
+		Answer:             opb.Answer,
+		TxHash:             txHash,
+		Height:             blockHeight,
+	}
+}
+
+func (v *CongressVote) String() string {
+	return string(common.MustMarshalJSON(v))
+}
+
+func (v *CongressVote) Save(st *storage.LevelDBBackend) (err error) {
+	key := GetCongressVoteKey(v.CongressVotingHash, v.Voter)
+
+	var exists bool
+	if exists, err = st.Has(key); err != nil {
+		return
+	}
+
+	if exists {
+		return st.Set(key, v)
+	}
+
+	return st.New(key, v)
+}
+
+func GetCongressVoteKeyPrefixVoting(congressVotingHash string) string {
+	return fmt.Sprintf("%s%s-", common.CongressVotePrefixVoting, congressVotingHash)
+}
+
+func GetCongressVoteKey(congressVotingHash, voter string) string {
+	return fmt.Sprintf("%s%s", GetCongressVoteKeyPrefixVoting(congressVotingHash), voter)
+}
+
+func ExistsCongressVote(st *storage.LevelDBBackend, congressVotingHash, voter string) (bool, error) {
+	return st.Has(GetCongressVoteKey(congressVotingHash, voter))
+}
+
+func GetCongressVote(st *storage.LevelDBBackend, congressVotingHash, voter string) (v *CongressVote, err error) {
+	if err = st.Get(GetCongressVoteKey(congressVotingHash, voter), &v); err != nil {
+		return
+	}
+
+	return
+}
+
+// GetCongressVotesByVoting returns the votes of `CongressVoting`.
+func GetCongressVotesByVoting(st *storage.LevelDBBackend, congressVotingHash string, options storage.ListOptions) (
+	func() (*CongressVote, bool, []byte),
+	func(),
+) {
+	iterFunc, closeFunc := st.GetIterator(GetCongressVoteKeyPrefixVoting(congressVotingHash), options)
+
+	return (func() (*CongressVote, bool, []byte) {
+			item, hasNext := iterFunc()
+			if !hasNext {
+				return nil, false, item.Key
+			}
+
+			var v *CongressVote
+			common.MustUnmarshalJSON(item.Value, &v)
+
+			return v, hasNext, item.Key
+		}), (func() {
+			closeFunc()
+		})
+}
@@ -0,0 +1,128 @@
+package block
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/storage"
+	"boscoin.io/sebak/lib/transaction/operation"
+)
+
+func TestCongressVoting(t *testing.T) {
+	st := storage.NewTestStorage()
+	defer st.Close()
+
+	txHash := common.MustMakeObjectHashString("voting")
+	opb := operation.NewCongressVoting("dummy contract", 3, 5, common.Amount(1000000), keypair.Random().Address())
+	cv := NewCongressVoting(txHash, 1, keypair.Random().Address(), 2, opb)
+	require.Equal(t, txHash+"-1", cv.Hash)
+	require.NoError(t, cv.Save(st))
+
+	require.False(t, cv.IsVotable(2))
+	require.True(t, cv.IsVotable(3))
+	require.True(t, cv.IsVotable(5))
+	require.False(t, cv.IsVotable(6))
+
+	{ // by end
+		iterFunc, closeFunc := GetCongressVotingsByEnd(st, 5, nil)
+		found, hasNext, _ := iterFunc()
+		require.True(t, hasNext)
+		require.Equal(t, cv.Hash, found.Hash)
+		_, hasNext, _ = iterFunc()
+		require.False(t, hasNext)
+		closeFunc()
+
+		iterFunc, closeFunc = GetCongressVotingsByEnd(st, 4, nil)
+		_, hasNext, _ = iterFunc()
+		require.False(t, hasNext)
+		closeFunc()
+	}
+
+	{ // by height
+		other := NewCongressVoting(common.MustMakeObjectHashString("other"), 0, keypair.Random().Address(), 1, opb)
+		require.NoError(t, other.Save(st))
+
+		var found []string
+		iterFunc, closeFunc := GetCongressVotings(st, nil)
+		for {
+			v, hasNext, _ := iterFunc()
+			if !hasNext {
+				break
+			}
+			found = append(found, v.Hash)
+		}
+		closeFunc()
+		require.Equal(t, []string{other.Hash, cv.Hash}, found)
+	}
+
+	{ // by result
+		resultHash := common.MustMakeObjectHashString("result") + "-0"
+		require.NoError(t, cv.AddResult(st, resultHash))
+		require.Equal(t, []string{resultHash}, cv.Results)
+
+		found, err := GetCongressVotingByResult(st, resultHash)
+		require.NoError(t, err)
+		require.Equal(t, cv, found)
+	}
+
+	{ // tally
+		cv.Tally.Add(operation.VoteYes)
+		cv.Tally.Add(operation.VoteNo)
+		cv.Tally.Add(operation.VoteNo)
+		cv.Tally.Remove(operation.VoteNo)
+		cv.Tally.Add(operation.VoteABS)
+		require.Equal(t, CongressVotingTally{Count: 3, Yes: 1, No: 1, ABS: 1}, cv.Tally)
+
+		cv.Close()
+		require.True(t, cv.Closed)
+		require.False(t, cv.Passed)
+		require.False(t, cv.IsVotable(4))
+		require.NoError(t, cv.Save(st))
+
+		saved, err := GetCongressVoting(st, cv.Hash)
+		require.NoError(t, err)
+		require.Equal(t, cv, saved)
+	}
+}
+
+func TestCongressVote(t *testing.T) {
+	st := storage.NewTestStorage()
+	defer st.Close()
+
+	votingHash := common.MustMakeObjectHashString("voting") + "-0"
+	voters := []string{keypair.Random().Address(), keypair.Random().Address()}
+
+	for _, voter := range voters {
+		v := NewCongressVote(common.MustMakeObjectHashString(voter), voter, voter, 3, operation.NewCongressVote(votingHash, operation.VoteYes))
+		require.NoError(t, v.Save(st))
+	}
+
+	{ // vote again
+		v := NewCongressVote(common.MustMakeObjectHashString("again"), voters[0], voters[0], 4, operation.NewCongressVote(votingHash, operation.VoteNo))
+		require.NoError(t, v.Save(st))
+
+		saved, err := GetCongressVote(st, votingHash, voters[0])
+		require.NoError(t, err)
+		require.Equal(t, operation.VoteNo, saved.Answer)
+		require.Equal(t, uint64(4), saved.Height)
+	}
+
+	// the votes of other voting are not listed
+	other := NewCongressVote(common.MustMakeObjectHashString("other"), voters[0], voters[0], 3, operation.NewCongressVote(votingHash+"0", operation.VoteYes))
+	require.NoError(t, other.Save(st))
+
+	var found []string
+	iterFunc, closeFunc := GetCongressVotesByVoting(st, votingHash, nil)
+	for {
+		v, hasNext, _ := iterFunc()
+		if !hasNext {
+			break
+		}
+		found = append(found, v.Voter)
+	}
+	closeFunc()
+	require.ElementsMatch(t, voters, found)
+}
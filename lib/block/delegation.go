@@ -0,0 +1,160 @@
+package block
+
+import (
+	"fmt"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/storage"
+)
+
+// Delegation is the `operation.Delegate` included in block. The frozen
+// account, `Delegator` binds its balance to the `Validator`; one frozen
+// account can delegate to only one validator, so the later delegation
+// replaces the previous one.
+type Delegation struct {
+	Delegator string `json:"delegator"`
+	Validator string `json:"validator"`
+	TxHash    string `json:"tx_hash"`
+	Height    uint64 `json:"block_height"`
+}
+
+func NewDelegation(delegator, validator, txHash string, blockHeight uint64) *Delegation {
+	return &Delegation{
+		Delegator: delegator,
+		Validator: validator,
+		TxHash:    txHash,
+		Height:    blockHeight,
+	}
+}
+
+func (d *Delegation) String() string {
+	return string(common.MustMarshalJSON(d))
+}
+
+func (d *Delegation) Save(st *storage.LevelDBBackend) (err error) {
+	key := GetDelegationKey(d.Delegator)
+
+	var exists bool
+	if exists, err = st.Has(key); err != nil {
+		return
+	}
+
+	if !exists {
+		if err = st.New(key, d); err != nil {
+			return
+		}
+		return st.New(GetDelegationKeyByValidator(d.Validator, d.Delegator), d.Delegator)
+	}
+
+	var previous Delegation
+	if err = st.Get(key, &previous); err != nil {
+		return
+	}
+	if err = st.Set(key, d); err != nil {
+		return
+	}
+	if previous.Validator == d.Validator {
+		return
+	}
+
+	if err = st.Remove(GetDelegationKeyByValidator(previous.Validator, d.Delegator)); err != nil {
+		return
+	}
+
+	return st.New(GetDelegationKeyByValidator(d.Validator, d.Delegator), d.Delegator)
+}
+
+func GetDelegationKey(delegator string) string {
+	return fmt.Sprintf("%s%s", common.DelegationPrefixDelegator, delegator)
+}
+
+func GetDelegationKeyPrefixValidator(validator string) string {
+	return fmt.Sprintf("%s%s-", common.DelegationPrefixValidator, validator)
+}
+
+func GetDelegationKeyByValidator(validator, delegator string) string {
+	return fmt.Sprintf("%s%s", GetDelegationKeyPrefixValidator(validator), delegator)
+}
+
+func ExistsDelegation(st *storage.LevelDBBackend, delegator string) (bool, error) {
+	return st.Has(GetDelegationKey(delegator))
+}
+
+func GetDelegation(st *storage.LevelDBBackend, delegator string) (d *Delegation, err error) {
+	if err = st.Get(GetDelegationKey(delegator), &d); err != nil {
+		return
+	}
+
+	return
+}
+
+// GetDelegationsByValidator returns the `Delegation`s to the validator by the
+// order of the delegator address.
+func GetDelegationsByValidator(st *storage.LevelDBBackend, validator string, options storage.ListOptions) (
+	func() (*Delegation, bool, []byte),
+	func(),
+) {
+	iterFunc, closeFunc := st.GetIterator(GetDelegationKeyPrefixValidator(validator), options)
+
+	return (func() (*Delegation, bool, []byte) {
+			item, hasNext := iterFunc()
+			if !hasNext {
+				return nil, false, item.Key
+			}
+
+			var delegator string
+			common.MustUnmarshalJSON(item.Value, &delegator)
+
+			d, err := GetDelegation(st, delegator)
+			if err != nil {
+				return nil, false, item.Key
+			}
+
+			return d, hasNext, item.Key
+		}), (func() {
+			closeFunc()
+		})
+}
+
+// GetDelegatedAmount returns the sum of the balances of the frozen accounts,
+// which are delegated to the validator.
+func GetDelegatedAmount(st *storage.LevelDBBackend, validator string) (amount common.Amount, err error) {
+	iterFunc, closeFunc := GetDelegationsByValidator(st, validator, nil)
+	defer closeFunc()
+
+	for {
+		d, hasNext, _ := iterFunc()
+		if !hasNext {
+			break
+		}
+
+		var ba *BlockAccount
+		if ba, err = GetBlockAccount(st, d.Delegator); err != nil {
+			if err == errors.StorageRecordDoesNotExist {
+				err = nil
+				continue
+			}
+			return
+		}
+		if amount, err = amount.Add(ba.Balance); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// GetDelegatedAmounts returns the delegated amounts of the validators.
+func GetDelegatedAmounts(st *storage.LevelDBBackend, validators ...string) (amounts map[string]common.Amount, err error) {
+	amounts = map[string]common.Amount{}
+	for _, validator := range validators {
+		var amount common.Amount
+		if amount, err = GetDelegatedAmount(st, validator); err != nil {
+			return
+		}
+		amounts[validator] = amount
+	}
+
+	return
+}
@@ -0,0 +1,67 @@
+package block
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/storage"
+)
+
+func TestDelegation(t *testing.T) {
+	st := storage.NewTestStorage()
+	defer st.Close()
+
+	validator0 := keypair.Random().Address()
+	validator1 := keypair.Random().Address()
+
+	delegator0 := NewBlockAccount(keypair.Random().Address(), common.Unit*2)
+	delegator0.MustSave(st)
+	delegator1 := NewBlockAccount(keypair.Random().Address(), common.Unit*3)
+	delegator1.MustSave(st)
+
+	d0 := NewDelegation(delegator0.Address, validator0, common.MustMakeObjectHashString("tx0"), 2)
+	require.NoError(t, d0.Save(st))
+	d1 := NewDelegation(delegator1.Address, validator0, common.MustMakeObjectHashString("tx1"), 3)
+	require.NoError(t, d1.Save(st))
+
+	found, err := GetDelegation(st, delegator0.Address)
+	require.NoError(t, err)
+	require.Equal(t, d0, found)
+
+	amounts, err := GetDelegatedAmounts(st, validator0, validator1)
+	require.NoError(t, err)
+	require.Equal(t, common.Unit*5, amounts[validator0])
+	require.Equal(t, common.Amount(0), amounts[validator1])
+
+	// the later delegation replaces the previous one
+	d1 = NewDelegation(delegator1.Address, validator1, common.MustMakeObjectHashString("tx2"), 4)
+	require.NoError(t, d1.Save(st))
+
+	amounts, err = GetDelegatedAmounts(st, validator0, validator1)
+	require.NoError(t, err)
+	require.Equal(t, common.Unit*2, amounts[validator0])
+	require.Equal(t, common.Unit*3, amounts[validator1])
+
+	var delegations []*Delegation
+	iterFunc, closeFunc := GetDelegationsByValidator(st, validator1, nil)
+	for {
+		d, hasNext, _ := iterFunc()
+		if !hasNext {
+			break
+		}
+		delegations = append(delegations, d)
+	}
+	closeFunc()
+	require.Equal(t, []*Delegation{d1}, delegations)
+
+	// the delegated amount follows the balance of delegator
+	delegator0.Balance = common.Unit
+	delegator0.MustSave(st)
+
+	amount, err := GetDelegatedAmount(st, validator0)
+	require.NoError(t, err)
+	require.Equal(t, common.Unit, amount)
+}
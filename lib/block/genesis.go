@@ -33,6 +33,8 @@ func GetGenesis(st *storage.LevelDBBackend) Block {
 // * `Block.Transaction` is empty
 // * `Block.ProposedTime` is `common.GenesisBlockConfirmedTime`
 // * has only one `Transaction`
+// * `Block.StateRoot` is the root of state trie, which has the genesis account
+//   and common account
 //
 // This Transaction is different from other normal Transaction;
 // * signed by `keypair.Master(string(networkID))`
@@ -102,6 +104,12 @@ func MakeGenesisBlock(st *storage.LevelDBBackend, genesisAccount BlockAccount, c
 	kp := keypair.Master(string(networkID))
 	tx.Sign(kp, []byte(networkID))
 
+	// the genesis accounts are the initial state
+	var stateRoot common.Hash
+	if stateRoot, err = PutStateAccounts(st, common.Hash{}, genesisAccount, commonAccount); err != nil {
+		return
+	}
+
 	blk = NewBlock(
 		"",
 		voting.Basis{
@@ -111,6 +119,7 @@ func MakeGenesisBlock(st *storage.LevelDBBackend, genesisAccount BlockAccount, c
 		},
 		"",
 		[]string{tx.GetHash()},
+		stateRoot,
 		common.GenesisBlockConfirmedTime,
 	)
 	if err = blk.Save(st); err != nil {
@@ -3,29 +3,32 @@ package block
 import (
 	"encoding/json"
 
+	"boscoin.io/sebak/lib/common"
 	"boscoin.io/sebak/lib/voting"
 )
 
 type Header struct {
 	// TODO rename `Header` to `BlockHeader`
-	Version          uint32 `json:"version"`
-	PrevBlockHash    string `json:"prev_block_hash"`   // TODO Uint256 type
-	TransactionsRoot string `json:"transactions_root"` // Merkle root of Txs // TODO Uint256 type
-	ProposedTime     string `json:"proposed_time"`
-	Height           uint64 `json:"height"`
-	TotalTxs         uint64 `json:"total-txs"`
-	TotalOps         uint64 `json:"total-ops"`
+	Version          uint32      `json:"version"`
+	PrevBlockHash    string      `json:"prev_block_hash"`   // TODO Uint256 type
+	TransactionsRoot string      `json:"transactions_root"` // Merkle root of Txs // TODO Uint256 type
+	StateRoot        common.Hash `json:"state_root"`        // root of state trie after the block is applied
+	ProposedTime     string      `json:"proposed_time"`
+	Height           uint64      `json:"height"`
+	TotalTxs         uint64      `json:"total-txs"`
+	TotalOps         uint64      `json:"total-ops"`
 
 	// TODO smart contract fields
 }
 
-func NewBlockHeader(basis voting.Basis, txRoot string, proposedTime string) *Header {
+func NewBlockHeader(basis voting.Basis, txRoot string, stateRoot common.Hash, proposedTime string) *Header {
 	return &Header{
 		PrevBlockHash:    basis.BlockHash,
 		Height:           basis.Height,
 		TotalTxs:         basis.TotalTxs,
 		TotalOps:         basis.TotalOps,
 		TransactionsRoot: txRoot,
+		StateRoot:        stateRoot,
 		ProposedTime:     proposedTime,
 	}
 }
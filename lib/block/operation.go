@@ -162,6 +162,10 @@ func key(hash string) string {
 	return fmt.Sprintf("%s%s", common.BlockOperationPrefixHash, hash)
 }
 
+func GetBlockOperationKey(hash string) string {
+	return key(hash)
+}
+
 func GetBlockOperationCreateFrozenKey(hash string, height uint64) string {
 	return fmt.Sprintf(
 		"%s%s%s",
@@ -0,0 +1,62 @@
+package block
+
+import (
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/storage"
+	"boscoin.io/sebak/lib/storage/statedb/trie"
+)
+
+// The accounts, which were stored before the state trie, are put into the
+// state trie at boot. The header of the stored block can not be changed, so the
+// new state root of the block is stored separately and it is used instead of
+// `Header.StateRoot` to apply the next block.
+
+func getStateRootKey(hash string) string {
+	return common.StateRootPrefixBlock + hash
+}
+
+// GetStateRoot returns the root of state trie, which the next block of `blk`
+// is applied to.
+func GetStateRoot(st *storage.LevelDBBackend, blk Block) (root common.Hash, err error) {
+	if err = st.Get(getStateRootKey(blk.Hash), &root); err == errors.StorageRecordDoesNotExist {
+		return blk.StateRoot, nil
+	}
+
+	return
+}
+
+// SaveStateRoot stores the state root of block, which replaces the state root
+// of block header.
+func SaveStateRoot(st *storage.LevelDBBackend, hash string, root common.Hash) (err error) {
+	key := getStateRootKey(hash)
+
+	var exists bool
+	if exists, err = st.Has(key); err != nil {
+		return
+	}
+
+	if exists {
+		return st.Set(key, root)
+	}
+
+	return st.New(key, root)
+}
+
+// PutStateAccounts puts the accounts into the state trie of `root` and returns
+// the new root. The accounts are encoded like `statedb.StateDB` does.
+func PutStateAccounts(st *storage.LevelDBBackend, root common.Hash, accounts ...BlockAccount) (newRoot common.Hash, err error) {
+	t := trie.NewTrie(root, trie.NewEthDatabase(st))
+	for _, ba := range accounts {
+		if err = t.TryUpdate([]byte(ba.Address), common.MustMarshalJSON(ba)); err != nil {
+			return
+		}
+	}
+
+	if newRoot, err = t.Commit(nil); err != nil {
+		return
+	}
+	err = t.CommitDB(newRoot)
+
+	return
+}
@@ -0,0 +1,93 @@
+package block
+
+import (
+	"github.com/btcsuite/btcutil/base58"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/errors"
+)
+
+// StateSnapshot is the manifest of the state at the block of `Height`. The
+// accounts are in the state trie of `StateRoot`, and the other records, which
+// are not in the state trie, like the congress votings and the delegations,
+// are exported in the `StateSnapshotChunk`s of `Chunks`.
+//
+// The snapshot is signed by the validator, which made it. The validators make
+// the same snapshot for the same block, so the snapshot can be trusted when
+// enough validators signed the same `StateSnapshotBody`.
+type StateSnapshot struct {
+	H StateSnapshotHeader `json:"H"`
+	B StateSnapshotBody   `json:"B"`
+}
+
+type StateSnapshotHeader struct {
+	Hash      string `json:"hash"` // hash of `StateSnapshotBody`
+	Source    string `json:"source"`
+	Signature string `json:"signature"`
+}
+
+type StateSnapshotBody struct {
+	Height    uint64      `json:"height"`
+	Block     string      `json:"block"` // hash of block
+	StateRoot common.Hash `json:"state_root"`
+	Chunks    []string    `json:"chunks"` // hashes of `StateSnapshotChunk`s
+}
+
+func (sb StateSnapshotBody) MakeHashString() string {
+	return common.MustMakeObjectHashString(sb)
+}
+
+func NewStateSnapshot(blk Block, chunks ...StateSnapshotChunk) *StateSnapshot {
+	s := &StateSnapshot{
+		B: StateSnapshotBody{
+			Height:    blk.Height,
+			Block:     blk.Hash,
+			StateRoot: blk.StateRoot,
+			Chunks:    []string{},
+		},
+	}
+	for _, c := range chunks {
+		s.B.Chunks = append(s.B.Chunks, c.MakeHashString())
+	}
+
+	return s
+}
+
+func (s StateSnapshot) String() string {
+	return string(common.MustMarshalJSON(s))
+}
+
+func (s *StateSnapshot) Sign(kp keypair.KP, networkID []byte) {
+	s.H.Hash = s.B.MakeHashString()
+	s.H.Source = kp.Address()
+	signature, _ := keypair.MakeSignature(kp, networkID, s.H.Hash)
+	s.H.Signature = base58.Encode(signature)
+}
+
+// Verify checks the hash of body and the signature of source.
+func (s StateSnapshot) Verify(networkID []byte) (err error) {
+	if s.H.Hash != s.B.MakeHashString() {
+		return errors.StateSnapshotInvalid
+	}
+
+	var kp keypair.KP
+	if kp, err = keypair.Parse(s.H.Source); err != nil {
+		return
+	}
+
+	return kp.Verify(append(networkID, []byte(s.H.Hash)...), base58.Decode(s.H.Signature))
+}
+
+// StateSnapshotRecord is the raw record of storage.
+type StateSnapshotRecord struct {
+	Key   []byte `json:"key"`
+	Value []byte `json:"value"`
+}
+
+// StateSnapshotChunk is the part of records of `StateSnapshot`.
+type StateSnapshotChunk []StateSnapshotRecord
+
+func (c StateSnapshotChunk) MakeHashString() string {
+	return common.MustMakeObjectHashString(c)
+}
@@ -0,0 +1,51 @@
+package block
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/errors"
+)
+
+func TestStateSnapshotSign(t *testing.T) {
+	conf := common.NewTestConfig()
+	kp := keypair.Random()
+
+	st := InitTestBlockchain()
+	defer st.Close()
+
+	blk := GetLatestBlock(st)
+	chunk := StateSnapshotChunk{
+		{Key: []byte("key0"), Value: []byte("value0")},
+		{Key: []byte("key1"), Value: []byte("value1")},
+	}
+
+	s := NewStateSnapshot(blk, chunk)
+	s.Sign(kp, conf.NetworkID)
+	require.Equal(t, kp.Address(), s.H.Source)
+	require.Equal(t, blk.Hash, s.B.Block)
+	require.Equal(t, blk.StateRoot, s.B.StateRoot)
+	require.Equal(t, []string{chunk.MakeHashString()}, s.B.Chunks)
+	require.NoError(t, s.Verify(conf.NetworkID))
+
+	// same body is signed by the other node
+	other := NewStateSnapshot(blk, chunk)
+	other.Sign(keypair.Random(), conf.NetworkID)
+	require.Equal(t, s.H.Hash, other.H.Hash)
+
+	{ // wrong network id
+		require.Error(t, s.Verify([]byte("wrong")))
+	}
+
+	{ // modified body
+		modified := *s
+		modified.B.Height++
+		require.Equal(t, errors.StateSnapshotInvalid, modified.Verify(conf.NetworkID))
+
+		modified.H.Hash = modified.B.MakeHashString()
+		require.Error(t, modified.Verify(conf.NetworkID))
+	}
+}
@@ -75,10 +75,31 @@ func (b *Block) MustSave(st *storage.LevelDBBackend) {
 }
 
 /// Version of `BlockAccount.Save` that panics on error, usable only in tests
+///
+/// The account is also put into the state of the latest block, so the next
+/// block can be applied to it.
 func (b *BlockAccount) MustSave(st *storage.LevelDBBackend) {
 	if err := b.Save(st); err != nil {
 		panic(err)
 	}
+
+	if exists, err := ExistsBlockByHeight(st, common.GenesisBlockHeight); err != nil {
+		panic(err)
+	} else if !exists {
+		return
+	}
+
+	latest := GetLatestBlock(st)
+	root, err := GetStateRoot(st, latest)
+	if err != nil {
+		panic(err)
+	}
+	if root, err = PutStateAccounts(st, root, *b); err != nil {
+		panic(err)
+	}
+	if err = SaveStateRoot(st, latest.Hash, root); err != nil {
+		panic(err)
+	}
 }
 
 /// Version of `BlockTransaction.Save` that panics on error, usable only in tests
@@ -108,6 +129,7 @@ func TestMakeNewBlock(transactions []string) Block {
 		},
 		"",
 		transactions,
+		common.Hash{},
 		common.NowISO8601(),
 	)
 }
@@ -125,6 +147,7 @@ func TestMakeNewBlockWithPrevBlock(prevBlock Block, txs []string) Block {
 		},
 		"",
 		txs,
+		common.Hash{},
 		common.NowISO8601(),
 	)
 }
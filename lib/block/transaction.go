@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/btcsuite/btcutil/base58"
+
 	"boscoin.io/sebak/lib/common"
 	"boscoin.io/sebak/lib/errors"
 	"boscoin.io/sebak/lib/storage"
@@ -19,6 +21,7 @@ import (
 //  * get list by `Confirmed` order
 //  * get list by `Account` and created order
 //  * get list by `Block` and created order
+//  * get list by `Account` and `Memo`, and created order
 
 // TODO(BlockTransaction): support counting
 
@@ -93,6 +96,23 @@ func (bt BlockTransaction) NewBlockTransactionKeyByAccount(accountAddress string
 	)
 }
 
+func (bt BlockTransaction) NewBlockTransactionKeyByAccountMemo(accountAddress string, memo transaction.Memo) string {
+	return fmt.Sprintf(
+		"%s%s",
+		bt.getBlockTransactionKeyPrefixAccountMemoSequence(accountAddress, memo),
+		common.GetUniqueIDFromUUID(),
+	)
+}
+
+func (bt BlockTransaction) getBlockTransactionKeyPrefixAccountMemoSequence(accountAddress string, memo transaction.Memo) string {
+	return fmt.Sprintf(
+		"%s%s%s",
+		GetBlockTransactionKeyPrefixAccountMemo(accountAddress, memo),
+		common.EncodeUint64ToByteSlice(bt.blockHeight),
+		common.EncodeUint64ToByteSlice(bt.SequenceID),
+	)
+}
+
 func (bt BlockTransaction) NewBlockTransactionKeyByBlock(hash string) string {
 	return fmt.Sprintf(
 		"%s%s%s%s",
@@ -133,6 +153,12 @@ func (bt *BlockTransaction) Save(st *storage.LevelDBBackend) (err error) {
 	if err = st.New(bt.NewBlockTransactionKeyByBlock(bt.Block), bt.Hash); err != nil {
 		return
 	}
+	if err = bt.saveAccountMemo(st, bt.Source); err != nil {
+		return
+	}
+	if err = RemoveUnconfirmedTransactionPool(st, bt.Hash); err != nil {
+		return
+	}
 
 	bt.isSaved = true
 
@@ -210,11 +236,42 @@ func (bt *BlockTransaction) SaveBlockOperation(st *storage.LevelDBBackend, op op
 		if err != nil {
 			return
 		}
+		if err = bt.saveAccountMemo(st, pop.TargetAddress()); err != nil {
+			return
+		}
 	}
 
 	return nil
 }
 
+// saveAccountMemo indexes the transaction by the account and memo; the
+// account, which is the target of several operations, is indexed only once.
+func (bt BlockTransaction) saveAccountMemo(st *storage.LevelDBBackend, accountAddress string) (err error) {
+	memo := bt.Transaction().B.Memo
+	if memo == nil {
+		return
+	}
+
+	iterFunc, closeFunc := st.GetIterator(bt.getBlockTransactionKeyPrefixAccountMemoSequence(accountAddress, *memo), nil)
+	defer closeFunc()
+	for {
+		item, hasNext := iterFunc()
+		if !hasNext {
+			break
+		}
+
+		var hash string
+		if err = json.Unmarshal(item.Value, &hash); err != nil {
+			return
+		}
+		if hash == bt.Hash {
+			return
+		}
+	}
+
+	return st.New(bt.NewBlockTransactionKeyByAccountMemo(accountAddress, *memo), bt.Hash)
+}
+
 //TODO: This function is no longer required when Index for operation is applied
 func (bt *BlockTransaction) GetOperationIndex(opHash string) (opIndex int, err error) {
 	opIndex = -1
@@ -241,6 +298,19 @@ func GetBlockTransactionKeyPrefixAccount(accountAddress string) string {
 	return fmt.Sprintf("%s%s-", common.BlockTransactionPrefixAccount, accountAddress)
 }
 
+// GetBlockTransactionKeyPrefixAccountMemo uses the hash of memo value, because
+// the text memo can have any character. The memo type is also in the key, so
+// the memos, which have same value and different type, are not mixed.
+func GetBlockTransactionKeyPrefixAccountMemo(accountAddress string, memo transaction.Memo) string {
+	return fmt.Sprintf(
+		"%s%s-%s-%s-",
+		common.BlockTransactionPrefixAccountMemo,
+		accountAddress,
+		memo.Type,
+		base58.Encode(common.MakeHash([]byte(memo.Value))),
+	)
+}
+
 func GetBlockTransactionKeyPrefixBlock(hash string) string {
 	return fmt.Sprintf("%s%s-", common.BlockTransactionPrefixBlock, hash)
 }
@@ -262,6 +332,55 @@ func ExistsBlockTransaction(st *storage.LevelDBBackend, hash string) (bool, erro
 	return st.Has(GetBlockTransactionKey(hash))
 }
 
+// GetTransactionInBlock returns the transaction in block from the
+// `TransactionPool`; when the `TransactionPool` is pruned in
+// `common.StorageModePruned`, the transaction is made from the
+// `BlockTransaction` and `BlockOperation`s and `pruned` is true. The made
+// transaction has no memo and time bounds, because they are kept only in the
+// `TransactionPool`.
+func GetTransactionInBlock(st *storage.LevelDBBackend, hash string) (tx transaction.Transaction, pruned bool, err error) {
+	var exists bool
+	if exists, err = ExistsTransactionPool(st, hash); err != nil {
+		return
+	} else if exists {
+		var tp TransactionPool
+		if tp, err = GetTransactionPool(st, hash); err != nil {
+			return
+		}
+		return tp.Transaction(), false, nil
+	}
+
+	var bt BlockTransaction
+	if bt, err = GetBlockTransaction(st, hash); err != nil {
+		return
+	}
+	pruned = true
+
+	tx.H.Hash = bt.Hash
+	tx.H.Signature = bt.Signature
+	tx.H.Created = bt.Created
+	tx.B.Source = bt.Source
+	tx.B.Fee = bt.Fee
+	tx.B.SequenceID = bt.SequenceID
+	for _, opHash := range bt.Operations {
+		var bo BlockOperation
+		if bo, err = GetBlockOperation(st, opHash); err != nil {
+			return
+		}
+
+		var opb operation.Body
+		if opb, err = operation.UnmarshalBodyJSON(bo.Type, bo.Body); err != nil {
+			return
+		}
+		tx.B.Operations = append(tx.B.Operations, operation.Operation{
+			H: operation.Header{Type: bo.Type},
+			B: opb,
+		})
+	}
+
+	return
+}
+
 func LoadBlockTransactionsInsideIterator(
 	st *storage.LevelDBBackend,
 	iterFunc func() (storage.IterItem, bool),
@@ -317,6 +436,14 @@ func GetBlockTransactionsByAccount(st *storage.LevelDBBackend, accountAddress st
 	return LoadBlockTransactionsInsideIterator(st, iterFunc, closeFunc)
 }
 
+func GetBlockTransactionsByAccountMemo(st *storage.LevelDBBackend, accountAddress string, memo transaction.Memo, options storage.ListOptions) (
+	func() (BlockTransaction, bool, []byte),
+	func(),
+) {
+	iterFunc, closeFunc := st.GetIterator(GetBlockTransactionKeyPrefixAccountMemo(accountAddress, memo), options)
+	return LoadBlockTransactionsInsideIterator(st, iterFunc, closeFunc)
+}
+
 func GetBlockTransactionsByBlock(st *storage.LevelDBBackend, hash string, options storage.ListOptions) (
 	func() (BlockTransaction, bool, []byte),
 	func(),
@@ -39,6 +39,10 @@ func GetTransactionPoolKey(hash string) string {
 	return fmt.Sprintf("%s%s", common.TransactionPoolPrefix, hash)
 }
 
+func GetTransactionPoolUnconfirmedKey(hash string) string {
+	return fmt.Sprintf("%s%s", common.TransactionPoolPrefixUnconfirmed, hash)
+}
+
 func (tp TransactionPool) Save(st *storage.LevelDBBackend) (err error) {
 	key := GetTransactionPoolKey(tp.Hash)
 
@@ -54,6 +58,17 @@ func (tp TransactionPool) Save(st *storage.LevelDBBackend) (err error) {
 		return
 	}
 
+	// the transaction, which is not yet in block, is also indexed as
+	// unconfirmed; the index is removed when the transaction is confirmed.
+	var confirmed bool
+	if confirmed, err = ExistsBlockTransaction(st, tp.Hash); err != nil {
+		return
+	} else if !confirmed {
+		if err = st.New(GetTransactionPoolUnconfirmedKey(tp.Hash), tp.Hash); err != nil {
+			return
+		}
+	}
+
 	event := observer.NewCondition(observer.TxPool, observer.Identifier, tp.Hash).String()
 	go observer.ResourceObserver.Trigger(event, &tp)
 
@@ -94,10 +109,80 @@ func GetTransactionPool(st *storage.LevelDBBackend, hash string) (tp Transaction
 	return
 }
 
-func DeleteTransactionPool(st *storage.LevelDBBackend, hash string) error {
+// GetTransactionPools returns all the `TransactionPool` including the
+// confirmed transactions.
+func GetTransactionPools(st *storage.LevelDBBackend, options storage.ListOptions) (
+	func() (TransactionPool, bool, []byte),
+	func(),
+) {
+	iterFunc, closeFunc := st.GetIterator(common.TransactionPoolPrefix, options)
+
+	return (func() (TransactionPool, bool, []byte) {
+			item, hasNext := iterFunc()
+			if !hasNext {
+				return TransactionPool{}, false, item.Key
+			}
+
+			var tp TransactionPool
+			common.MustUnmarshalJSON(item.Value, &tp)
+
+			return tp, hasNext, item.Key
+		}), (func() {
+			closeFunc()
+		})
+}
+
+// GetUnconfirmedTransactionPools returns the `TransactionPool`, which is not
+// yet confirmed.
+func GetUnconfirmedTransactionPools(st *storage.LevelDBBackend, options storage.ListOptions) (
+	func() (TransactionPool, bool, []byte),
+	func(),
+) {
+	iterFunc, closeFunc := st.GetIterator(common.TransactionPoolPrefixUnconfirmed, options)
+
+	return (func() (TransactionPool, bool, []byte) {
+			for {
+				item, hasNext := iterFunc()
+				if !hasNext {
+					return TransactionPool{}, false, item.Key
+				}
+
+				var hash string
+				common.MustUnmarshalJSON(item.Value, &hash)
+
+				tp, err := GetTransactionPool(st, hash)
+				if err != nil {
+					continue
+				}
+
+				return tp, hasNext, item.Key
+			}
+		}), (func() {
+			closeFunc()
+		})
+}
+
+func DeleteTransactionPool(st *storage.LevelDBBackend, hash string) (err error) {
+	if err = RemoveUnconfirmedTransactionPool(st, hash); err != nil {
+		return
+	}
+
 	return st.Remove(GetTransactionPoolKey(hash))
 }
 
+// RemoveUnconfirmedTransactionPool removes the unconfirmed index of
+// `TransactionPool`, if exists.
+func RemoveUnconfirmedTransactionPool(st *storage.LevelDBBackend, hash string) (err error) {
+	key := GetTransactionPoolUnconfirmedKey(hash)
+
+	var exists bool
+	if exists, err = st.Has(key); !exists || err != nil {
+		return
+	}
+
+	return st.Remove(key)
+}
+
 func SaveTransactionPool(st *storage.LevelDBBackend, tx transaction.Transaction) (tp TransactionPool, err error) {
 	if tp, err = NewTransactionPool(tx); err != nil {
 		return
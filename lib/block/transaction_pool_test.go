@@ -46,3 +46,56 @@ func TestTransactionPool(t *testing.T) {
 		require.Error(t, err, errors.StorageRecordDoesNotExist)
 	}
 }
+
+func TestUnconfirmedTransactionPools(t *testing.T) {
+	conf := common.NewTestConfig()
+	st := storage.NewTestStorage()
+
+	getUnconfirmed := func() (hashes []string) {
+		iterFunc, closeFunc := GetUnconfirmedTransactionPools(st, nil)
+		defer closeFunc()
+		for {
+			tp, hasNext, _ := iterFunc()
+			if !hasNext {
+				break
+			}
+			hashes = append(hashes, tp.Hash)
+		}
+		return
+	}
+
+	_, tx0 := transaction.TestMakeTransaction(conf.NetworkID, 1)
+	_, tx1 := transaction.TestMakeTransaction(conf.NetworkID, 1)
+	for _, tx := range []transaction.Transaction{tx0, tx1} {
+		_, err := SaveTransactionPool(st, tx)
+		require.NoError(t, err)
+	}
+	require.ElementsMatch(t, []string{tx0.GetHash(), tx1.GetHash()}, getUnconfirmed())
+
+	{ // confirmed; the `TransactionPool` is kept, but it is not unconfirmed
+		blk := TestMakeNewBlock([]string{tx0.GetHash()})
+		bt := NewBlockTransactionFromTransaction(blk.Hash, blk.Height, blk.ProposedTime, tx0)
+		bt.MustSave(st)
+
+		require.Equal(t, []string{tx1.GetHash()}, getUnconfirmed())
+		exists, err := ExistsTransactionPool(st, tx0.GetHash())
+		require.NoError(t, err)
+		require.True(t, exists)
+	}
+
+	{ // the confirmed transaction is not unconfirmed
+		_, tx := transaction.TestMakeTransaction(conf.NetworkID, 1)
+		blk := TestMakeNewBlock([]string{tx.GetHash()})
+		bt := NewBlockTransactionFromTransaction(blk.Hash, blk.Height, blk.ProposedTime, tx)
+		bt.MustSave(st)
+		_, err := SaveTransactionPool(st, tx)
+		require.NoError(t, err)
+
+		require.Equal(t, []string{tx1.GetHash()}, getUnconfirmed())
+	}
+
+	{ // deleted
+		require.NoError(t, DeleteTransactionPool(st, tx1.GetHash()))
+		require.Equal(t, 0, len(getUnconfirmed()))
+	}
+}
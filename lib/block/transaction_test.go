@@ -325,6 +325,64 @@ func TestMultipleBlockTransactionGetByAccount(t *testing.T) {
 	}
 }
 
+func TestMultipleBlockTransactionGetByAccountMemo(t *testing.T) {
+	conf := common.NewTestConfig()
+	kpSource := keypair.Random()
+	kpTarget := keypair.Random()
+	st := storage.NewTestStorage()
+
+	memos := []string{"1", "2", "1", ""}
+
+	var txs []transaction.Transaction
+	var txHashes []string
+	for _, memo := range memos {
+		// the target has several operations in one transaction
+		tx := transaction.TestMakeTransactionWithKeypair(conf.NetworkID, 2, kpSource, kpTarget)
+		if len(memo) > 0 {
+			tx.B.Memo = transaction.NewMemo(transaction.MemoID, memo)
+		}
+		tx.Sign(kpSource, conf.NetworkID)
+
+		txs = append(txs, tx)
+		txHashes = append(txHashes, tx.GetHash())
+	}
+
+	blk := TestMakeNewBlock(txHashes)
+	for _, tx := range txs {
+		bt := NewBlockTransactionFromTransaction(blk.Hash, blk.Height, blk.ProposedTime, tx)
+		bt.MustSave(st)
+		require.NoError(t, bt.SaveBlockOperations(st))
+	}
+
+	getByMemo := func(address, value string, memoTypes ...transaction.MemoType) (hashes []string) {
+		memo := transaction.Memo{Type: transaction.MemoID, Value: value}
+		if len(memoTypes) > 0 {
+			memo.Type = memoTypes[0]
+		}
+		iterFunc, closeFunc := GetBlockTransactionsByAccountMemo(st, address, memo, nil)
+		defer closeFunc()
+		for {
+			bt, hasNext, _ := iterFunc()
+			if !hasNext {
+				break
+			}
+			hashes = append(hashes, bt.Hash)
+		}
+		return
+	}
+
+	// both of source and target can be filtered by memo
+	for _, address := range []string{kpSource.Address(), kpTarget.Address()} {
+		require.Equal(t, []string{txHashes[0], txHashes[2]}, getByMemo(address, "1"))
+		require.Equal(t, []string{txHashes[1]}, getByMemo(address, "2"))
+		require.Equal(t, 0, len(getByMemo(address, "3")))
+
+		// same value, but different type
+		require.Equal(t, 0, len(getByMemo(address, "1", transaction.MemoText)))
+	}
+	require.Equal(t, 0, len(getByMemo(keypair.Random().Address(), "1")))
+}
+
 func TestMultipleBlockTransactionGetByBlock(t *testing.T) {
 	conf := common.NewTestConfig()
 	kp := keypair.Random()
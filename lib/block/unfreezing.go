@@ -0,0 +1,175 @@
+package block
+
+import (
+	"fmt"
+	"sort"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/storage"
+	"boscoin.io/sebak/lib/transaction/operation"
+)
+
+// UnfreezingRequest is the `operation.UnfreezeRequest` included in block. After
+// `common.UnfreezingPeriod` from `Height`, the `Amount` of request is matured
+// and it can be withdrawn from the frozen account; if the request is for the
+// whole balance, `Amount` is zero and the frozen account can withdraw
+// without limit.
+type UnfreezingRequest struct {
+	Address   string        `json:"address"`
+	OpHash    string        `json:"op_hash"`
+	TxHash    string        `json:"tx_hash"`
+	Height    uint64        `json:"block_height"`
+	Amount    common.Amount `json:"amount"`
+	Withdrawn common.Amount `json:"withdrawn"`
+}
+
+func NewUnfreezingRequest(address, opHash, txHash string, blockHeight uint64, opb operation.UnfreezeRequest) *UnfreezingRequest {
+	return &UnfreezingRequest{
+		Address: address,
+		OpHash:  opHash,
+		TxHash:  txHash,
+		Height:  blockHeight,
+		Amount:  opb.Amount,
+	}
+}
+
+func (r *UnfreezingRequest) String() string {
+	return string(common.MustMarshalJSON(r))
+}
+
+// IsWhole returns true when the whole balance is requested.
+func (r *UnfreezingRequest) IsWhole() bool {
+	return r.Amount == 0
+}
+
+// IsMatured checks the request passes `common.UnfreezingPeriod` at the block
+// of `height`.
+func (r *UnfreezingRequest) IsMatured(height uint64) bool {
+	return height >= r.Height && height-r.Height >= common.UnfreezingPeriod
+}
+
+// Remaining returns the amount, which is not yet withdrawn.
+func (r *UnfreezingRequest) Remaining() common.Amount {
+	return r.Amount - r.Withdrawn
+}
+
+// Withdraw withdraws the `amount` from the request and returns the rest of
+// `amount`, which exceeds the remaining of request.
+func (r *UnfreezingRequest) Withdraw(amount common.Amount) common.Amount {
+	if r.IsWhole() {
+		r.Withdrawn = r.Withdrawn.MustAdd(amount)
+		return 0
+	}
+
+	withdrawn := amount
+	if remaining := r.Remaining(); withdrawn > remaining {
+		withdrawn = remaining
+	}
+	r.Withdrawn = r.Withdrawn.MustAdd(withdrawn)
+
+	return amount - withdrawn
+}
+
+func (r *UnfreezingRequest) Save(st *storage.LevelDBBackend) (err error) {
+	key := GetUnfreezingRequestKey(r.Address, r.Height, r.OpHash)
+
+	var exists bool
+	if exists, err = st.Has(key); err != nil {
+		return
+	}
+
+	if exists {
+		return st.Set(key, r)
+	}
+
+	return st.New(key, r)
+}
+
+func GetUnfreezingRequestKeyPrefixAddress(address string) string {
+	return fmt.Sprintf("%s%s", common.UnfreezingRequestPrefixAddress, address)
+}
+
+func GetUnfreezingRequestKey(address string, height uint64, opHash string) string {
+	return fmt.Sprintf("%s%020d%s", GetUnfreezingRequestKeyPrefixAddress(address), height, opHash)
+}
+
+// GetUnfreezingRequestsByAddress returns the `UnfreezingRequest`s of the
+// frozen account by the order of the block height.
+func GetUnfreezingRequestsByAddress(st *storage.LevelDBBackend, address string, options storage.ListOptions) (
+	func() (*UnfreezingRequest, bool, []byte),
+	func(),
+) {
+	iterFunc, closeFunc := st.GetIterator(GetUnfreezingRequestKeyPrefixAddress(address), options)
+
+	return (func() (*UnfreezingRequest, bool, []byte) {
+			item, hasNext := iterFunc()
+			if !hasNext {
+				return nil, false, item.Key
+			}
+
+			var r *UnfreezingRequest
+			common.MustUnmarshalJSON(item.Value, &r)
+
+			return r, hasNext, item.Key
+		}), (func() {
+			closeFunc()
+		})
+}
+
+// GetAllUnfreezingRequests returns all the `UnfreezingRequest`s of the frozen
+// account by the order of the block height. The stored `UnfreezingRequest`s
+// are merged with the requests made from the `BlockOperation`s of
+// `operation.UnfreezeRequest`, which are included in block before the
+// `UnfreezingRequest`s are stored; the stored one is used for the same
+// operation, because it keeps the withdrawn amount.
+func GetAllUnfreezingRequests(st *storage.LevelDBBackend, address string) (requests []*UnfreezingRequest) {
+	stored := map[string]bool{}
+
+	iterFunc, closeFunc := GetUnfreezingRequestsByAddress(st, address, nil)
+	for {
+		r, hasNext, _ := iterFunc()
+		if !hasNext {
+			break
+		}
+		requests = append(requests, r)
+		stored[r.OpHash] = true
+	}
+	closeFunc()
+
+	for _, r := range getUnfreezingRequestsFromBlockOperations(st, address) {
+		if stored[r.OpHash] {
+			continue
+		}
+		requests = append(requests, r)
+	}
+
+	sort.SliceStable(requests, func(i, j int) bool {
+		return requests[i].Height < requests[j].Height
+	})
+
+	return
+}
+
+func getUnfreezingRequestsFromBlockOperations(st *storage.LevelDBBackend, address string) (requests []*UnfreezingRequest) {
+	iterFunc, closeFunc := GetBlockOperationsBySourceAndType(st, address, operation.TypeUnfreezingRequest, nil)
+	defer closeFunc()
+
+	for {
+		bo, hasNext, _ := iterFunc()
+		if !hasNext {
+			break
+		}
+
+		body, err := operation.UnmarshalBodyJSON(bo.Type, bo.Body)
+		if err != nil {
+			continue
+		}
+		opb, ok := body.(operation.UnfreezeRequest)
+		if !ok {
+			continue
+		}
+		requests = append(requests, NewUnfreezingRequest(address, bo.OpHash, bo.TxHash, bo.Height, opb))
+	}
+
+	return
+}
@@ -0,0 +1,134 @@
+package block
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/storage"
+	"boscoin.io/sebak/lib/transaction"
+	"boscoin.io/sebak/lib/transaction/operation"
+)
+
+func TestUnfreezingRequest(t *testing.T) {
+	st := storage.NewTestStorage()
+	defer st.Close()
+
+	address := keypair.Random().Address()
+
+	partial := NewUnfreezingRequest(address, common.MustMakeObjectHashString("partial"), common.MustMakeObjectHashString("tx0"), 3, operation.NewPartialUnfreezeRequest(common.Unit))
+	require.NoError(t, partial.Save(st))
+	whole := NewUnfreezingRequest(address, common.MustMakeObjectHashString("whole"), common.MustMakeObjectHashString("tx1"), 2, operation.NewUnfreezeRequest())
+	require.NoError(t, whole.Save(st))
+
+	// the requests of the other account are not listed
+	other := NewUnfreezingRequest(keypair.Random().Address(), common.MustMakeObjectHashString("other"), common.MustMakeObjectHashString("tx2"), 1, operation.NewUnfreezeRequest())
+	require.NoError(t, other.Save(st))
+
+	requests := GetAllUnfreezingRequests(st, address)
+	require.Equal(t, []*UnfreezingRequest{whole, partial}, requests)
+
+	{ // maturity
+		require.False(t, partial.IsMatured(2))
+		require.False(t, partial.IsMatured(common.UnfreezingPeriod+2))
+		require.True(t, partial.IsMatured(common.UnfreezingPeriod+3))
+	}
+
+	{ // withdraw
+		require.Equal(t, common.Amount(0), partial.Withdraw(common.Unit/2))
+		require.Equal(t, common.Unit/2, partial.Remaining())
+		require.Equal(t, common.Amount(100), partial.Withdraw(common.Unit/2+100))
+		require.Equal(t, common.Amount(0), partial.Remaining())
+		require.NoError(t, partial.Save(st))
+
+		// the whole request can be withdrawn without limit
+		require.True(t, whole.IsWhole())
+		require.Equal(t, common.Amount(0), whole.Withdraw(common.Unit*10))
+		require.Equal(t, common.Unit*10, whole.Withdrawn)
+
+		requests = GetAllUnfreezingRequests(st, address)
+		require.Equal(t, common.Unit, requests[1].Withdrawn)
+	}
+}
+
+func TestUnfreezingRequestFromBlockOperations(t *testing.T) {
+	conf := common.NewTestConfig()
+	st := storage.NewTestStorage()
+	defer st.Close()
+
+	kp := keypair.Random()
+
+	// the unfreeze request, which is stored only as `BlockOperation`
+	op, err := operation.NewOperation(operation.NewUnfreezeRequest())
+	require.NoError(t, err)
+	tx, err := transaction.NewTransaction(kp.Address(), 0, op)
+	require.NoError(t, err)
+	tx.Sign(kp, conf.NetworkID)
+
+	bo, err := NewBlockOperationFromOperation(op, tx, 3)
+	require.NoError(t, err)
+	bo.MustSave(st)
+
+	requests := GetAllUnfreezingRequests(st, kp.Address())
+	require.Equal(t, 1, len(requests))
+	require.Equal(t, bo.OpHash, requests[0].OpHash)
+	require.Equal(t, tx.GetHash(), requests[0].TxHash)
+	require.Equal(t, uint64(3), requests[0].Height)
+	require.True(t, requests[0].IsWhole())
+
+	{ // withdrawn request is stored and it is not made again
+		requests[0].Withdraw(common.Unit)
+		require.NoError(t, requests[0].Save(st))
+
+		requests = GetAllUnfreezingRequests(st, kp.Address())
+		require.Equal(t, 1, len(requests))
+		require.Equal(t, common.Unit, requests[0].Withdrawn)
+	}
+}
+
+func TestUnfreezingRequestMerged(t *testing.T) {
+	conf := common.NewTestConfig()
+	st := storage.NewTestStorage()
+	defer st.Close()
+
+	kp := keypair.Random()
+
+	// the legacy request, which is stored only as `BlockOperation`
+	legacyOp, err := operation.NewOperation(operation.NewPartialUnfreezeRequest(common.Unit))
+	require.NoError(t, err)
+	legacyTx, err := transaction.NewTransaction(kp.Address(), 0, legacyOp)
+	require.NoError(t, err)
+	legacyTx.Sign(kp, conf.NetworkID)
+
+	legacyBo, err := NewBlockOperationFromOperation(legacyOp, legacyTx, 2)
+	require.NoError(t, err)
+	legacyBo.MustSave(st)
+
+	// the later request, which is stored as both
+	op, err := operation.NewOperation(operation.NewUnfreezeRequest())
+	require.NoError(t, err)
+	tx, err := transaction.NewTransaction(kp.Address(), 1, op)
+	require.NoError(t, err)
+	tx.Sign(kp, conf.NetworkID)
+
+	bo, err := NewBlockOperationFromOperation(op, tx, 5)
+	require.NoError(t, err)
+	bo.MustSave(st)
+
+	r := NewUnfreezingRequest(kp.Address(), bo.OpHash, tx.GetHash(), 5, operation.NewUnfreezeRequest())
+	r.Withdraw(common.Unit)
+	require.NoError(t, r.Save(st))
+
+	requests := GetAllUnfreezingRequests(st, kp.Address())
+	require.Equal(t, 2, len(requests))
+
+	require.Equal(t, legacyBo.OpHash, requests[0].OpHash)
+	require.Equal(t, common.Unit, requests[0].Amount)
+	require.Equal(t, uint64(2), requests[0].Height)
+
+	// the stored request is not duplicated and keeps the withdrawn amount
+	require.Equal(t, bo.OpHash, requests[1].OpHash)
+	require.Equal(t, common.Unit, requests[1].Withdrawn)
+}
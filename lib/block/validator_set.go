@@ -0,0 +1,253 @@
+package block
+
+import (
+	"fmt"
+	"sort"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/storage"
+	"boscoin.io/sebak/lib/transaction/operation"
+)
+
+// ValidatorSetItem is the validator of `ValidatorSet`.
+type ValidatorSetItem struct {
+	Address  string `json:"address"`
+	Endpoint string `json:"endpoint"`
+	Alias    string `json:"alias"`
+}
+
+// ValidatorSet is the validators, which vote from the block of `Height`. The
+// initial validator set is given by node at boot and it is changed by
+// `ValidatorSetChange`s.
+type ValidatorSet struct {
+	Height     uint64             `json:"height"`
+	Validators []ValidatorSetItem `json:"validators"` // sorted by address
+}
+
+func NewValidatorSet(height uint64, validators ...ValidatorSetItem) ValidatorSet {
+	vs := ValidatorSet{Height: height}
+	vs.Validators = append(vs.Validators, validators...)
+	sort.Slice(vs.Validators, func(i, j int) bool {
+		return vs.Validators[i].Address < vs.Validators[j].Address
+	})
+
+	return vs
+}
+
+func (vs ValidatorSet) String() string {
+	return string(common.MustMarshalJSON(vs))
+}
+
+func (vs ValidatorSet) Addresses() (addresses []string) {
+	for _, v := range vs.Validators {
+		addresses = append(addresses, v.Address)
+	}
+
+	return
+}
+
+func (vs ValidatorSet) Has(address string) bool {
+	for _, v := range vs.Validators {
+		if v.Address == address {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Apply returns the new `ValidatorSet`, which the change is applied to. The
+// last validator can not be removed.
+func (vs ValidatorSet) Apply(c *ValidatorSetChange) ValidatorSet {
+	var validators []ValidatorSetItem
+	for _, v := range vs.Validators {
+		if v.Address == c.Validator.Address {
+			continue
+		}
+		validators = append(validators, v)
+	}
+
+	switch c.Type {
+	case operation.TypeAddValidator:
+		validators = append(validators, c.Validator)
+	case operation.TypeRemoveValidator:
+		if len(validators) < 1 {
+			validators = vs.Validators
+		}
+	}
+
+	return NewValidatorSet(c.Height, validators...)
+}
+
+func GetInitialValidatorSetKey() string {
+	return common.ValidatorSetPrefixInitial
+}
+
+// SaveInitialValidatorSet stores the validators given by node at the first
+// boot; once stored, it is not overwritten, because the validators given at
+// the next boot can be different with the initial validator set of the
+// stored blocks.
+func SaveInitialValidatorSet(st *storage.LevelDBBackend, vs ValidatorSet) (err error) {
+	key := GetInitialValidatorSetKey()
+
+	var exists bool
+	if exists, err = st.Has(key); err != nil || exists {
+		return
+	}
+
+	return st.New(key, vs)
+}
+
+func GetInitialValidatorSet(st *storage.LevelDBBackend) (vs ValidatorSet, err error) {
+	err = st.Get(GetInitialValidatorSetKey(), &vs)
+	return
+}
+
+// ValidatorSetChange is the `operation.AddValidator` or
+// `operation.RemoveValidator` included in block; it is applied to the
+// validator set from the block of `Height`.
+type ValidatorSetChange struct {
+	Hash        string                  `json:"hash"` // <transaction hash>-<operation index>
+	TxHash      string                  `json:"tx_hash"`
+	BlockHeight uint64                  `json:"block_height"`
+	Type        operation.OperationType `json:"type"`
+	Height      uint64                  `json:"height"`
+	Validator   ValidatorSetItem        `json:"validator"`
+}
+
+func NewValidatorSetChange(txHash string, index int, blockHeight uint64, op operation.Operation) (c *ValidatorSetChange, err error) {
+	c = &ValidatorSetChange{
+		Hash:        fmt.Sprintf("%s-%d", txHash, index),
+		TxHash:      txHash,
+		BlockHeight: blockHeight,
+		Type:        op.H.Type,
+	}
+
+	switch opb := op.B.(type) {
+	case operation.AddValidator:
+		c.Height = opb.Height
+		c.Validator = ValidatorSetItem{Address: opb.Address, Endpoint: opb.Endpoint, Alias: opb.Alias}
+	case operation.RemoveValidator:
+		c.Height = opb.Height
+		c.Validator = ValidatorSetItem{Address: opb.Address}
+	default:
+		return nil, errors.TypeOperationBodyNotMatched
+	}
+
+	return
+}
+
+func (c *ValidatorSetChange) String() string {
+	return string(common.MustMarshalJSON(c))
+}
+
+func (c *ValidatorSetChange) Save(st *storage.LevelDBBackend) error {
+	return st.New(GetValidatorSetChangeKey(c.Height, c.Hash), c)
+}
+
+func GetValidatorSetChangeKeyPrefixHeight(height uint64) string {
+	return fmt.Sprintf("%s%020d", common.ValidatorSetChangePrefixHeight, height)
+}
+
+func GetValidatorSetChangeKey(height uint64, hash string) string {
+	return fmt.Sprintf("%s%s", GetValidatorSetChangeKeyPrefixHeight(height), hash)
+}
+
+func loadValidatorSetChangesInsideIterator(
+	iterFunc func() (storage.IterItem, bool),
+	closeFunc func(),
+) (
+	func() (*ValidatorSetChange, bool, []byte),
+	func(),
+) {
+	return (func() (*ValidatorSetChange, bool, []byte) {
+			item, hasNext := iterFunc()
+			if !hasNext {
+				return nil, false, item.Key
+			}
+
+			var c *ValidatorSetChange
+			common.MustUnmarshalJSON(item.Value, &c)
+
+			return c, hasNext, item.Key
+		}), (func() {
+			closeFunc()
+		})
+}
+
+// GetValidatorSetChanges returns the `ValidatorSetChange`s by the order of
+// `Height`.
+func GetValidatorSetChanges(st *storage.LevelDBBackend, options storage.ListOptions) (
+	func() (*ValidatorSetChange, bool, []byte),
+	func(),
+) {
+	return loadValidatorSetChangesInsideIterator(st.GetIterator(common.ValidatorSetChangePrefixHeight, options))
+}
+
+// GetValidatorSetChangesByHeight returns the `ValidatorSetChange`s, which are
+// applied from the block of `height`.
+func GetValidatorSetChangesByHeight(st *storage.LevelDBBackend, height uint64) (changes []*ValidatorSetChange) {
+	iterFunc, closeFunc := loadValidatorSetChangesInsideIterator(
+		st.GetIterator(GetValidatorSetChangeKeyPrefixHeight(height), nil),
+	)
+	for {
+		c, hasNext, _ := iterFunc()
+		if !hasNext {
+			break
+		}
+		changes = append(changes, c)
+	}
+	closeFunc()
+
+	return
+}
+
+// GetValidatorSetHistory returns the `ValidatorSet`s from the initial
+// validator set by the order of `Height`; the validator set is returned for
+// each height, which has the changes.
+func GetValidatorSetHistory(st *storage.LevelDBBackend) (history []ValidatorSet, err error) {
+	var vs ValidatorSet
+	if vs, err = GetInitialValidatorSet(st); err != nil {
+		return
+	}
+	history = append(history, vs)
+
+	iterFunc, closeFunc := GetValidatorSetChanges(st, nil)
+	defer closeFunc()
+
+	for {
+		c, hasNext, _ := iterFunc()
+		if !hasNext {
+			break
+		}
+
+		vs = vs.Apply(c)
+		if last := history[len(history)-1]; last.Height == vs.Height {
+			history[len(history)-1] = vs
+		} else {
+			history = append(history, vs)
+		}
+	}
+
+	return
+}
+
+// GetValidatorSet returns the `ValidatorSet`, which votes for the block of
+// `height`.
+func GetValidatorSet(st *storage.LevelDBBackend, height uint64) (vs ValidatorSet, err error) {
+	var history []ValidatorSet
+	if history, err = GetValidatorSetHistory(st); err != nil {
+		return
+	}
+
+	vs = history[0]
+	for _, h := range history[1:] {
+		if h.Height > height {
+			break
+		}
+		vs = h
+	}
+
+	return
+}
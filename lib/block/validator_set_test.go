@@ -0,0 +1,75 @@
+package block
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/storage"
+	"boscoin.io/sebak/lib/transaction/operation"
+)
+
+func TestValidatorSet(t *testing.T) {
+	st := storage.NewTestStorage()
+	defer st.Close()
+
+	v0 := ValidatorSetItem{Address: keypair.Random().Address(), Alias: "v0"}
+	v1 := ValidatorSetItem{Address: keypair.Random().Address(), Alias: "v1"}
+	v2 := ValidatorSetItem{Address: keypair.Random().Address(), Endpoint: "https://localhost:12345", Alias: "v2"}
+
+	initial := NewValidatorSet(common.GenesisBlockHeight, v0, v1)
+	require.NoError(t, SaveInitialValidatorSet(st, initial))
+
+	{ // the stored initial validator set is not overwritten
+		require.NoError(t, SaveInitialValidatorSet(st, NewValidatorSet(common.GenesisBlockHeight, v0)))
+		vs, err := GetInitialValidatorSet(st)
+		require.NoError(t, err)
+		require.Equal(t, initial, vs)
+	}
+
+	saveChange := func(txHash string, blockHeight uint64, opb operation.Body) *ValidatorSetChange {
+		op, err := operation.NewOperation(opb)
+		require.NoError(t, err)
+		c, err := NewValidatorSetChange(txHash, 0, blockHeight, op)
+		require.NoError(t, err)
+		require.NoError(t, c.Save(st))
+		return c
+	}
+
+	added := saveChange("tx0", 2, operation.NewAddValidator(v2.Address, v2.Endpoint, v2.Alias, 5))
+	require.Equal(t, v2, added.Validator)
+	saveChange("tx1", 3, operation.NewRemoveValidator(v0.Address, 8))
+
+	require.Equal(t, 1, len(GetValidatorSetChangesByHeight(st, 5)))
+	require.Equal(t, 0, len(GetValidatorSetChangesByHeight(st, 6)))
+
+	history, err := GetValidatorSetHistory(st)
+	require.NoError(t, err)
+	require.Equal(t, 3, len(history))
+	require.Equal(t, initial, history[0])
+	require.Equal(t, NewValidatorSet(5, v0, v1, v2), history[1])
+	require.Equal(t, NewValidatorSet(8, v1, v2), history[2])
+
+	{ // by height
+		vs, err := GetValidatorSet(st, 4)
+		require.NoError(t, err)
+		require.Equal(t, initial, vs)
+
+		vs, err = GetValidatorSet(st, 5)
+		require.NoError(t, err)
+		require.True(t, vs.Has(v2.Address))
+
+		vs, err = GetValidatorSet(st, 100)
+		require.NoError(t, err)
+		require.False(t, vs.Has(v0.Address))
+		require.Equal(t, NewValidatorSet(8, v1, v2).Addresses(), vs.Addresses())
+	}
+
+	{ // the last validator can not be removed
+		vs := NewValidatorSet(common.GenesisBlockHeight, v0)
+		c := &ValidatorSetChange{Type: operation.TypeRemoveValidator, Height: 10, Validator: v0}
+		require.Equal(t, []string{v0.Address}, vs.Apply(c).Addresses())
+	}
+}
@@ -22,10 +22,12 @@ const (
 	UrlAccount               = "/accounts/{id}"
 	UrlAccountOperations     = "/accounts/{id}/operations"
 	UrlAccountFrozenAccounts = "/accounts/{id}/frozen-accounts"
+	UrlAccountProof          = "/accounts/{id}/proof"
 	UrlFrozenAccounts        = "/frozen-accounts"
 	UrlTransactions          = "/transactions"
 	UrlTransactionByHash     = "/transactions/{id}"
 	UrlTransactionStatus     = "/transactions/{id}/status"
+	UrlTransactionProof      = "/transactions/{id}/proof"
 	UrlTransactionOperations = "/transactions/{id}/operations"
 	UrlSubscribe             = "/subscribe"
 )
@@ -41,6 +43,8 @@ const (
 	QueryOrder  QueryKey = "reverse"
 	QueryCursor QueryKey = "cursor"
 	QueryType   QueryKey = "type"
+	QueryHeight QueryKey = "height"
+	QueryMemo   QueryKey = "memo"
 )
 
 type Q struct {
@@ -65,6 +69,10 @@ func (qs Queries) toQueryString() string {
 			urlValues.Add(QueryCursor.String(), q.Value)
 		case QueryType:
 			urlValues.Add(QueryType.String(), q.Value)
+		case QueryHeight:
+			urlValues.Add(QueryHeight.String(), q.Value)
+		case QueryMemo:
+			urlValues.Add(QueryMemo.String(), q.Value)
 
 		}
 	}
@@ -187,6 +195,17 @@ func (c *Client) LoadAccount(id string, queries ...Q) (account Account, err erro
 	return
 }
 
+// LoadAccountProof returns the merkle proof of account against the state root
+// of the latest block; with `QueryHeight`, the block at that height is used.
+// `BlockHeight` and `BlockHash` of the proof are of the used block, and the
+// proof can be checked by `statedb.VerifyAccountProof()`.
+func (c *Client) LoadAccountProof(id string, queries ...Q) (proof AccountProof, err error) {
+	url := strings.Replace(UrlAccountProof, "{id}", id, -1)
+	url += Queries(queries).toQueryString()
+	err = c.getResponse(url, http.Header{}, &proof)
+	return
+}
+
 func (c *Client) LoadFrozenAccountsByLinked(id string, queries ...Q) (fPage FrozenAccountsPage, err error) {
 	url := strings.Replace(UrlAccountFrozenAccounts, "{id}", id, -1)
 	url += Queries(queries).toQueryString()
@@ -215,6 +234,16 @@ func (c *Client) LoadTransactionStatus(id string, queries ...Q) (transactionHist
 	return
 }
 
+// LoadTransactionProof returns the inclusion path of transaction to the
+// transactions root of block; it can be checked by
+// `block.VerifyTransactionProof()`.
+func (c *Client) LoadTransactionProof(id string, queries ...Q) (proof TransactionProof, err error) {
+	url := strings.Replace(UrlTransactionProof, "{id}", id, -1)
+	url += Queries(queries).toQueryString()
+	err = c.getResponse(url, http.Header{}, &proof)
+	return
+}
+
 func (c *Client) LoadTransactions(queries ...Q) (tPage TransactionsPage, err error) {
 	url := UrlTransactions
 	url += Queries(queries).toQueryString()
@@ -5,6 +5,9 @@ import (
 
 	"boscoin.io/sebak/lib/common"
 	"boscoin.io/sebak/lib/node/runner/api/resource"
+	"boscoin.io/sebak/lib/storage/statedb/trie"
+	"boscoin.io/sebak/lib/transaction"
+	"boscoin.io/sebak/lib/transaction/operation"
 )
 
 type Problem struct {
@@ -21,12 +24,43 @@ type Account struct {
 		Self         Link `json:"self"`
 		Transactions Link `json:"transactions"`
 		Operations   Link `json:"operations"`
+		Proof        Link `json:"proof"`
 	} `json:"_links"`
 
-	Address    string `json:"address"`
-	SequenceID uint64 `json:"sequence_id"`
-	Balance    string `json:"balance"`
-	Linked     string `json:"linked"`
+	Address    string               `json:"address"`
+	SequenceID uint64               `json:"sequence_id"`
+	Balance    string               `json:"balance"`
+	Linked     string               `json:"linked"`
+	Signers    []operation.Signer   `json:"signers"`
+	Thresholds operation.Thresholds `json:"thresholds"`
+}
+
+type AccountProof struct {
+	Links struct {
+		Self    Link `json:"self"`
+		Account Link `json:"account"`
+		Block   Link `json:"block"`
+	} `json:"_links"`
+
+	Address     string         `json:"address"`
+	BlockHash   string         `json:"block_hash"`
+	BlockHeight uint64         `json:"block_height"`
+	StateRoot   common.Hash    `json:"state_root"`
+	Proof       trie.ProofList `json:"proof"`
+}
+
+type TransactionProof struct {
+	Links struct {
+		Self        Link `json:"self"`
+		Transaction Link `json:"transaction"`
+		Block       Link `json:"block"`
+	} `json:"_links"`
+
+	Hash             string                   `json:"hash"`
+	BlockHash        string                   `json:"block_hash"`
+	BlockHeight      uint64                   `json:"block_height"`
+	TransactionsRoot string                   `json:"transactions_root"`
+	Proof            []common.MerkleProofNode `json:"proof"`
 }
 
 type FrozenAccount struct {
@@ -44,9 +78,18 @@ type FrozenAccount struct {
 	UnfreezingBlockHeight      uint64                      `json:"unfreezing_block_height"`
 	UnfreezingOpHash           string                      `json:"unfreezing_op_hash"`
 	UnfreezingRemainingBlockes uint64                      `json:"unfreezing_remaining_blockheight"`
+	UnfreezingRequests         []UnfreezingRequest         `json:"unfreezing_requests"`
 	PaymentOpHash              string                      `json:"payment_op_hash"`
 }
 
+type UnfreezingRequest struct {
+	OpHash          string        `json:"op_hash"`
+	BlockHeight     uint64        `json:"block_height"`
+	Amount          common.Amount `json:"amount"`
+	Withdrawn       common.Amount `json:"withdrawn"`
+	RemainingBlocks uint64        `json:"remaining_blocks"`
+}
+
 type FrozenAccountsPage struct {
 	Links struct {
 		Self Link `json:"self"`
@@ -67,12 +110,14 @@ type Transaction struct {
 		Account    Link `json:"account"`
 		Operations Link `json:"operations"`
 	} `json:"_links"`
-	Hash           string `json:"hash"`
-	Source         string `json:"source"`
-	Fee            string `json:"fee"`
-	SequenceID     uint64 `json:"sequence_id"`
-	Created        string `json:"created"`
-	OperationCount uint64 `json:"operation_count"`
+	Hash           string                  `json:"hash"`
+	Source         string                  `json:"source"`
+	Fee            string                  `json:"fee"`
+	SequenceID     uint64                  `json:"sequence_id"`
+	Created        string                  `json:"created"`
+	OperationCount uint64                  `json:"operation_count"`
+	TimeBounds     *transaction.TimeBounds `json:"time_bounds,omitempty"`
+	Memo           *transaction.Memo       `json:"memo,omitempty"`
 }
 
 type TransactionPost struct {
@@ -163,6 +208,11 @@ type CongressVotingResult struct {
 	CongressVotingHash string `json:"congress_voting_hash"`
 }
 
+type CongressVote struct {
+	CongressVotingHash string `json:"congress_voting_hash"`
+	Answer             string `json:"answer"`
+}
+
 type CreateAccount struct {
 	Target string `json:"target"`
 	Amount []byte `json:"amount"`
@@ -14,15 +14,28 @@ type Config struct {
 	BlockTime         time.Duration
 	BlockTimeDelta    time.Duration
 
+	// TimeoutBackoffFactor multiplies the timeouts by each failed round in
+	// the same height; 1 keeps the timeouts static.
+	TimeoutBackoffFactor float64
+	// TimeoutBackoffLimit is the maximum of the timeouts, which is increased
+	// by `TimeoutBackoffFactor`.
+	TimeoutBackoffLimit time.Duration
+
 	TxsLimit          int
 	OpsLimit          int
 	OpsInBallotLimit  int
 	TxPoolClientLimit int
 	TxPoolNodeLimit   int
+	TxPoolSourceLimit int
 
 	NetworkID      []byte
 	InitialBalance Amount
 
+	// CertificateHeight is the block height from which the block must have
+	// the certificate; the blocks below it were made before the certificate
+	// was stored, so they are synced without it.
+	CertificateHeight uint64
+
 	// Those fields are not consensus-related
 	RateLimitRuleAPI  RateLimitRule
 	RateLimitRuleNode RateLimitRule
@@ -38,5 +51,15 @@ type Config struct {
 
 	WatcherMode bool
 
+	// StateSnapshotInterval is the interval of blocks to make the state snapshot
+	// for the fast sync of the other nodes; 0 does not make snapshot.
+	StateSnapshotInterval uint64
+
+	// StorageMode is `StorageModeArchive` or `StorageModePruned`.
+	StorageMode string
+	// PruneBlocks is the number of the latest blocks, whose history is kept
+	// in `StorageModePruned`.
+	PruneBlocks uint64
+
 	DiscoveryEndpoints []*Endpoint
 }
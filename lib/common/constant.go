@@ -44,6 +44,10 @@ const (
 	// DefaultTxPoolLimit is the default tx pool limit.
 	DefaultTxPoolLimit int = 1000000
 
+	// DefaultTxPoolSourceLimit is the default number of transactions of one
+	// source account in tx pool.
+	DefaultTxPoolSourceLimit int = 16
+
 	// DefaultOperationsInTransactionLimit is the default maximum number of
 	// operations in one transaction.
 	DefaultOperationsInTransactionLimit int = 1000
@@ -57,6 +61,12 @@ const (
 	// `ProposerTransaction`.
 	DefaultOperationsInBallotLimit int = 10000
 
+	// MaxSignersInAccount is the maximum number of signers of one account.
+	MaxSignersInAccount int = 20
+
+	// MaxMemoTextLength is the maximum length of text memo of transaction.
+	MaxMemoTextLength int = 28
+
 	DefaultTimeoutINIT       = 2 * time.Second
 	DefaultTimeoutSIGN       = 2 * time.Second
 	DefaultTimeoutACCEPT     = 2 * time.Second
@@ -64,6 +74,29 @@ const (
 	DefaultBlockTime         = 5 * time.Second
 	DefaultBlockTimeDelta    = 1 * time.Second
 
+	DefaultTimeoutBackoffFactor float64 = 2
+	DefaultTimeoutBackoffLimit          = 1 * time.Minute
+
+	// DefaultStateSnapshotInterval is the default interval of blocks to make the
+	// state snapshot.
+	DefaultStateSnapshotInterval uint64 = 1000
+
+	// StateSnapshotChunkSize is the maximum number of records in one chunk of
+	// state snapshot.
+	StateSnapshotChunkSize int = 1000
+
+	// StorageModeArchive keeps the all the history of blocks.
+	StorageModeArchive = "archive"
+	// StorageModePruned removes the history of the old blocks, like the
+	// `TransactionPool` records; the blocks and the current state are kept.
+	// The transactions of the old blocks are served without memo and time
+	// bounds, and they are marked as `pruned`.
+	StorageModePruned = "pruned"
+
+	// DefaultPruneBlocks is the default number of the latest blocks, whose
+	// history is kept in `StorageModePruned`.
+	DefaultPruneBlocks uint64 = 10000
+
 	// DiscoveryMessageCreatedAllowDuration limit the `DiscoveryMessage.Created`
 	// is allowed or not.
 	DiscoveryMessageCreatedAllowDuration time.Duration = time.Second * 10
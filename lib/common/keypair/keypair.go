@@ -17,6 +17,7 @@ type KP = stellar.KP
 // Aliases to stellar functions
 var Master = stellar.Master
 var Parse = stellar.Parse
+var FromRawSeed = stellar.FromRawSeed
 var RandomCanFail = stellar.Random
 
 // MakeSignature makes signature from given hash string
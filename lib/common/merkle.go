@@ -0,0 +1,98 @@
+package common
+
+import (
+	"bytes"
+)
+
+const (
+	merkleLeafPrefix byte = 0x00
+	merkleNodePrefix byte = 0x01
+)
+
+// MerkleProofNode is the sibling hash in the inclusion path of merkle tree;
+// `Left` is true when the sibling is placed on the left side.
+type MerkleProofNode struct {
+	Hash []byte `json:"hash"`
+	Left bool   `json:"left"`
+}
+
+func makeMerkleLeaf(data []byte) []byte {
+	return MakeHash(append([]byte{merkleLeafPrefix}, data...))
+}
+
+func makeMerkleNode(left, right []byte) []byte {
+	b := make([]byte, 0, 1+len(left)+len(right))
+	b = append(b, merkleNodePrefix)
+	b = append(b, left...)
+	b = append(b, right...)
+	return MakeHash(b)
+}
+
+func makeMerkleLeaves(items [][]byte) [][]byte {
+	leaves := make([][]byte, len(items))
+	for i, item := range items {
+		leaves[i] = makeMerkleLeaf(item)
+	}
+	return leaves
+}
+
+// nextMerkleLevel hashes the pairs of nodes; the last node without pair is
+// promoted to the next level as it is, so the tree can not be forged by
+// duplicating the last item.
+func nextMerkleLevel(level [][]byte) [][]byte {
+	next := make([][]byte, 0, (len(level)+1)/2)
+	for i := 0; i < len(level); i += 2 {
+		if i+1 == len(level) {
+			next = append(next, level[i])
+			continue
+		}
+		next = append(next, makeMerkleNode(level[i], level[i+1]))
+	}
+	return next
+}
+
+// MakeMerkleRoot returns the root of binary merkle tree of items. The leaves
+// and the internal nodes are hashed with different prefixes.
+func MakeMerkleRoot(items [][]byte) []byte {
+	if len(items) < 1 {
+		return MakeHash([]byte{})
+	}
+
+	level := makeMerkleLeaves(items)
+	for len(level) > 1 {
+		level = nextMerkleLevel(level)
+	}
+	return level[0]
+}
+
+// MakeMerkleProof returns the inclusion path of the item at `index` from the
+// leaf to the root.
+func MakeMerkleProof(items [][]byte, index int) (proof []MerkleProofNode, ok bool) {
+	if index < 0 || index >= len(items) {
+		return
+	}
+
+	level := makeMerkleLeaves(items)
+	for len(level) > 1 {
+		if sibling := index ^ 1; sibling < len(level) {
+			proof = append(proof, MerkleProofNode{Hash: level[sibling], Left: sibling < index})
+		}
+		level = nextMerkleLevel(level)
+		index /= 2
+	}
+	return proof, true
+}
+
+// VerifyMerkleProof checks that the item is included in the merkle tree of
+// the given root.
+func VerifyMerkleProof(root []byte, item []byte, proof []MerkleProofNode) bool {
+	h := makeMerkleLeaf(item)
+	for _, node := range proof {
+		if node.Left {
+			h = makeMerkleNode(node.Hash, h)
+		} else {
+			h = makeMerkleNode(h, node.Hash)
+		}
+	}
+	return bytes.Equal(root, h)
+}
@@ -0,0 +1,58 @@
+package common
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func makeMerkleTestItems(n int) (items [][]byte) {
+	for i := 0; i < n; i++ {
+		items = append(items, []byte(fmt.Sprintf("item-%d", i)))
+	}
+	return
+}
+
+func TestMerkleRoot(t *testing.T) {
+	items := makeMerkleTestItems(3)
+
+	// the leaves and the nodes are hashed with different prefix
+	expected := makeMerkleNode(
+		makeMerkleNode(makeMerkleLeaf(items[0]), makeMerkleLeaf(items[1])),
+		makeMerkleLeaf(items[2]),
+	)
+	require.Equal(t, expected, MakeMerkleRoot(items))
+
+	// order matters
+	require.NotEqual(t, expected, MakeMerkleRoot([][]byte{items[1], items[0], items[2]}))
+
+	// duplicating the last item makes different root
+	require.NotEqual(t, expected, MakeMerkleRoot(append(items, items[2])))
+
+	require.Equal(t, makeMerkleLeaf(items[0]), MakeMerkleRoot(items[:1]))
+	require.Equal(t, MakeHash([]byte{}), MakeMerkleRoot(nil))
+}
+
+func TestMerkleProof(t *testing.T) {
+	for n := 1; n < 18; n++ {
+		items := makeMerkleTestItems(n)
+		root := MakeMerkleRoot(items)
+		for i, item := range items {
+			proof, ok := MakeMerkleProof(items, i)
+			require.True(t, ok)
+			require.True(t, VerifyMerkleProof(root, item, proof), "n=%d index=%d", n, i)
+
+			require.False(t, VerifyMerkleProof(root, []byte("unknown"), proof))
+			if len(proof) > 0 {
+				proof[0].Left = !proof[0].Left
+				require.False(t, VerifyMerkleProof(root, item, proof))
+			}
+		}
+
+		_, ok := MakeMerkleProof(items, n)
+		require.False(t, ok)
+		_, ok = MakeMerkleProof(items, -1)
+		require.False(t, ok)
+	}
+}
@@ -9,6 +9,7 @@ const (
 	BlockTransactionPrefixConfirmed       = string(0x12)
 	BlockTransactionPrefixAccount         = string(0x13)
 	BlockTransactionPrefixBlock           = string(0x14)
+	BlockTransactionPrefixAccountMemo     = string(0x15)
 	BlockOperationPrefixHash              = string(0x20)
 	BlockOperationPrefixTxHash            = string(0x21)
 	BlockOperationPrefixSource            = string(0x22)
@@ -25,5 +26,26 @@ const (
 	BlockAccountSequenceIDPrefix          = string(0x32)
 	BlockAccountSequenceIDByAddressPrefix = string(0x33)
 	TransactionPoolPrefix                 = string(0x40)
+	TransactionPoolPrefixUnconfirmed      = string(0x41)
 	InternalPrefix                        = string(0x50) // internal data
+	StateTriePrefix                       = string(0x60) // nodes of state trie
+	StateRootPrefixBlock                  = string(0x61)
+	CongressVotingPrefixHash              = string(0x70)
+	CongressVotingPrefixEnd               = string(0x71)
+	CongressVotePrefixVoting              = string(0x72)
+	CongressVotingPrefixHeight            = string(0x73)
+	CongressVotingPrefixResult            = string(0x74)
+	CongressVotingPrefixFunding           = string(0x75)
+	UnfreezingRequestPrefixAddress        = string(0x80)
+	DelegationPrefixDelegator             = string(0x90)
+	DelegationPrefixValidator             = string(0x91)
+	ValidatorSetPrefixInitial             = string(0xA0)
+	ValidatorSetChangePrefixHeight        = string(0xA1)
+	EvidencePrefixHash                    = string(0xB0)
+	EvidencePrefixHeight                  = string(0xB1)
+	CertificatePrefixBlock                = string(0xC0)
+	JournalPrefixBallot                   = string(0xD0)
+	JournalPrefixState                    = string(0xD1)
+	SyncPrefixTarget                      = string(0xE0)
+	SyncPrefixHeader                      = string(0xE1)
 )
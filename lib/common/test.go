@@ -17,6 +17,8 @@ func NewTestConfig() Config {
 	p.TimeoutSIGN = DefaultTimeoutSIGN
 	p.TimeoutACCEPT = DefaultTimeoutACCEPT
 	p.TimeoutALLCONFIRM = DefaultTimeoutALLCONFIRM
+	p.TimeoutBackoffFactor = DefaultTimeoutBackoffFactor
+	p.TimeoutBackoffLimit = DefaultTimeoutBackoffLimit
 	p.BlockTime = 0
 	p.BlockTimeDelta = DefaultBlockTimeDelta
 
@@ -29,12 +31,16 @@ func NewTestConfig() Config {
 
 	p.TxPoolClientLimit = DefaultTxPoolLimit
 	p.TxPoolNodeLimit = 0 // unlimited
+	p.TxPoolSourceLimit = DefaultTxPoolSourceLimit
 
 	p.RateLimitRuleAPI = NewRateLimitRule(RateLimitAPI)
 	p.RateLimitRuleNode = NewRateLimitRule(RateLimitNode)
 
 	p.HTTPCachePoolSize = HTTPCachePoolSize
 
+	p.StorageMode = StorageModeArchive
+	p.PruneBlocks = DefaultPruneBlocks
+
 	return p
 }
 
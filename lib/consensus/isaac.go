@@ -1,6 +1,7 @@
 package consensus
 
 import (
+	"bytes"
 	"context"
 	"sync"
 
@@ -211,6 +212,53 @@ func (is *ISAAC) IsVoted(b ballot.Ballot) bool {
 	return runningRound.IsVoted(b)
 }
 
+// AcceptedBallots returns the ACCEPT ballots, which vote YES for the same
+// proposal with the given ballot.
+func (is *ISAAC) AcceptedBallots(b ballot.Ballot) (ballots []ballot.Ballot) {
+	is.RLock()
+	defer is.RUnlock()
+
+	runningRound, found := is.RunningRounds[b.VotingBasis().Index()]
+	if !found {
+		return
+	}
+
+	runningRound.RLock()
+	defer runningRound.RUnlock()
+
+	roundVote, found := runningRound.Voted[b.Proposer()]
+	if !found {
+		return
+	}
+
+	proposed := common.MustMakeObjectHash(b.B.Proposed)
+	for _, voted := range roundVote.GetResult(ballot.StateACCEPT) {
+		if voted.Vote() != voting.YES {
+			continue
+		}
+		if !bytes.Equal(proposed, common.MustMakeObjectHash(voted.B.Proposed)) {
+			continue
+		}
+		ballots = append(ballots, voted)
+	}
+
+	return
+}
+
+// Conflicted returns the ballot, which is already voted by the source of the
+// given ballot and conflicts with the given ballot.
+func (is *ISAAC) Conflicted(b ballot.Ballot) (ballot.Ballot, bool) {
+	is.RLock()
+	defer is.RUnlock()
+
+	runningRound, found := is.RunningRounds[b.VotingBasis().Index()]
+	if !found {
+		return ballot.Ballot{}, false
+	}
+
+	return runningRound.Conflicted(b)
+}
+
 func (is *ISAAC) Vote(b ballot.Ballot) (isNew bool, err error) {
 	is.Lock()
 	defer is.Unlock()
@@ -0,0 +1,158 @@
+package consensus
+
+import (
+	"fmt"
+	"sync"
+
+	logging "github.com/inconshreveable/log15"
+
+	"boscoin.io/sebak/lib/ballot"
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/storage"
+)
+
+// Journal is the write-ahead log of consensus. The ballots sent by the node
+// and the transitions of `ISAACState` are stored by `voting.Basis` before they
+// take effect, so after the node is restarted, it can recover them and does
+// not vote differently in the same round.
+type Journal struct {
+	sync.Mutex
+
+	st  *storage.LevelDBBackend
+	log logging.Logger
+}
+
+func NewJournal(st *storage.LevelDBBackend, nodeAlias string) *Journal {
+	return &Journal{
+		st:  st,
+		log: log.New(logging.Ctx{"node": nodeAlias}),
+	}
+}
+
+func getJournalKeyPrefixHeight(prefix string, height uint64) string {
+	return fmt.Sprintf("%s%020d", prefix, height)
+}
+
+func GetJournalBallotKey(state ISAACState) string {
+	return fmt.Sprintf(
+		"%s%020d%02d",
+		getJournalKeyPrefixHeight(common.JournalPrefixBallot, state.Height),
+		state.Round,
+		state.BallotState,
+	)
+}
+
+func GetJournalStateKey(height, round uint64) string {
+	return fmt.Sprintf("%s%020d", getJournalKeyPrefixHeight(common.JournalPrefixState, height), round)
+}
+
+// WriteBallot records the ballot, which will be sent. The renewed ballot in
+// the same `ISAACState` replaces the previous one.
+func (j *Journal) WriteBallot(b ballot.Ballot) error {
+	j.Lock()
+	defer j.Unlock()
+
+	state := ISAACState{
+		Height:      b.VotingBasis().Height,
+		Round:       b.VotingBasis().Round,
+		BallotState: b.State(),
+	}
+	j.log.Debug("Journal.WriteBallot()", "state", state, "ballot", b.GetHash())
+
+	return j.set(GetJournalBallotKey(state), b)
+}
+
+// WriteState records the transition of `ISAACState`. Only the latest state of
+// each round is kept.
+func (j *Journal) WriteState(state ISAACState) error {
+	j.Lock()
+	defer j.Unlock()
+
+	j.log.Debug("Journal.WriteState()", "state", state)
+
+	return j.set(GetJournalStateKey(state.Height, state.Round), state)
+}
+
+func (j *Journal) set(key string, v interface{}) (err error) {
+	var exists bool
+	if exists, err = j.st.Has(key); err != nil {
+		return
+	}
+
+	if exists {
+		return j.st.Set(key, v)
+	}
+
+	return j.st.New(key, v)
+}
+
+// Ballots returns the sent ballots of `height` by the order of round and
+// ballot state.
+func (j *Journal) Ballots(height uint64) (ballots []ballot.Ballot) {
+	iterFunc, closeFunc := j.st.GetIterator(getJournalKeyPrefixHeight(common.JournalPrefixBallot, height), nil)
+	defer closeFunc()
+
+	for {
+		item, hasNext := iterFunc()
+		if !hasNext {
+			break
+		}
+
+		var b ballot.Ballot
+		common.MustUnmarshalJSON(item.Value, &b)
+		ballots = append(ballots, b)
+	}
+
+	return
+}
+
+// LatestState returns the latest recorded `ISAACState` of `height`.
+func (j *Journal) LatestState(height uint64) (state ISAACState, found bool) {
+	iterFunc, closeFunc := j.st.GetIterator(
+		getJournalKeyPrefixHeight(common.JournalPrefixState, height),
+		storage.NewDefaultListOptions(true, nil, 1),
+	)
+	defer closeFunc()
+
+	item, hasNext := iterFunc()
+	if !hasNext {
+		return
+	}
+	common.MustUnmarshalJSON(item.Value, &state)
+
+	return state, true
+}
+
+// RemoveLowerThanOrEqualHeight removes the records of the finished heights.
+func (j *Journal) RemoveLowerThanOrEqualHeight(height uint64) (err error) {
+	j.Lock()
+	defer j.Unlock()
+
+	j.log.Debug("Journal.RemoveLowerThanOrEqualHeight()", "height", height)
+
+	for _, prefix := range []string{common.JournalPrefixBallot, common.JournalPrefixState} {
+		var keys []string
+		iterFunc, closeFunc := j.st.GetIterator(prefix, nil)
+		for {
+			item, hasNext := iterFunc()
+			if !hasNext {
+				break
+			}
+
+			key := string(item.Key)
+			if key[len(prefix):len(prefix)+20] > fmt.Sprintf("%020d", height) {
+				break
+			}
+			keys = append(keys, key)
+		}
+		closeFunc()
+
+		for _, key := range keys {
+			if err = j.st.Remove(key); err != nil {
+				return
+			}
+		}
+	}
+
+	return
+}
@@ -0,0 +1,81 @@
+package consensus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/ballot"
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/storage"
+	"boscoin.io/sebak/lib/voting"
+)
+
+func TestJournal(t *testing.T) {
+	st := storage.NewTestStorage()
+	defer st.Close()
+
+	conf := common.NewTestConfig()
+	kp := keypair.Random()
+	j := NewJournal(st, "n1")
+
+	newBallot := func(height, round uint64, state ballot.State, vote voting.Hole) ballot.Ballot {
+		basis := voting.Basis{Round: round, Height: height, BlockHash: "hahaha"}
+		b := ballot.NewBallot(kp.Address(), kp.Address(), basis, []string{})
+		b.SetVote(state, vote)
+		b.Sign(kp, conf.NetworkID)
+		return *b
+	}
+
+	{ // nothing recorded
+		require.Equal(t, 0, len(j.Ballots(1)))
+		_, found := j.LatestState(1)
+		require.False(t, found)
+	}
+
+	require.NoError(t, j.WriteBallot(newBallot(1, 0, ballot.StateSIGN, voting.YES)))
+	require.NoError(t, j.WriteBallot(newBallot(1, 0, ballot.StateACCEPT, voting.YES)))
+	require.NoError(t, j.WriteBallot(newBallot(1, 1, ballot.StateSIGN, voting.NO)))
+	require.NoError(t, j.WriteBallot(newBallot(2, 0, ballot.StateSIGN, voting.YES)))
+
+	pushed := []ISAACState{}
+	pushISAACState(&pushed, 1, 0, ballot.StateINIT)
+	pushISAACState(&pushed, 1, 0, ballot.StateSIGN)
+	pushISAACState(&pushed, 1, 1, ballot.StateINIT)
+	pushISAACState(&pushed, 1, 1, ballot.StateSIGN)
+	pushISAACState(&pushed, 2, 0, ballot.StateINIT)
+	for _, state := range pushed {
+		require.NoError(t, j.WriteState(state))
+	}
+
+	ballots := j.Ballots(1)
+	require.Equal(t, 3, len(ballots))
+	require.Equal(t, uint64(0), ballots[0].VotingBasis().Round)
+	require.Equal(t, ballot.StateSIGN, ballots[0].State())
+	require.Equal(t, ballot.StateACCEPT, ballots[1].State())
+	require.Equal(t, uint64(1), ballots[2].VotingBasis().Round)
+	require.Equal(t, voting.NO, ballots[2].Vote())
+	require.NoError(t, ballots[2].VerifySource(conf.NetworkID))
+
+	state, found := j.LatestState(1)
+	require.True(t, found)
+	require.Equal(t, pushed[3], state)
+
+	{ // the renewed ballot replaces the previous one
+		require.NoError(t, j.WriteBallot(newBallot(1, 1, ballot.StateSIGN, voting.EXP)))
+		ballots := j.Ballots(1)
+		require.Equal(t, 3, len(ballots))
+		require.Equal(t, voting.EXP, ballots[2].Vote())
+	}
+
+	require.NoError(t, j.RemoveLowerThanOrEqualHeight(1))
+	require.Equal(t, 0, len(j.Ballots(1)))
+	_, found = j.LatestState(1)
+	require.False(t, found)
+
+	require.Equal(t, 1, len(j.Ballots(2)))
+	state, found = j.LatestState(2)
+	require.True(t, found)
+	require.Equal(t, pushed[4], state)
+}
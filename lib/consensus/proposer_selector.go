@@ -1,9 +1,17 @@
 package consensus
 
 import (
+	"encoding/binary"
 	"sort"
+	"sync"
 
+	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/common"
 	"boscoin.io/sebak/lib/network"
+	"boscoin.io/sebak/lib/storage"
+	"boscoin.io/sebak/lib/storage/statedb"
+	"boscoin.io/sebak/lib/storage/statedb/trie"
+	"boscoin.io/sebak/lib/transaction/operation"
 )
 
 type ProposerSelector interface {
@@ -14,8 +22,175 @@ type SequentialSelector struct {
 	cm network.ConnectionManager
 }
 
+func NewSequentialSelector(cm network.ConnectionManager) SequentialSelector {
+	return SequentialSelector{cm: cm}
+}
+
 func (s SequentialSelector) Select(blockHeight uint64, round uint64) string {
 	candidates := sort.StringSlice(s.cm.AllValidators())
 	candidates.Sort()
 	return candidates[(blockHeight+round)%uint64(len(candidates))]
 }
+
+// ValidatorWeights returns the weight of each validator at the given block
+// height. The validator without weight or with zero weight can not be
+// selected unless all the validators have zero weight.
+type ValidatorWeights func(st *storage.LevelDBBackend, validators []string, blockHeight uint64) map[string]uint64
+
+// FixedWeights returns the `ValidatorWeights`, which always returns the given
+// weights, like the weights declared in config.
+func FixedWeights(weights map[string]uint64) ValidatorWeights {
+	return func(_ *storage.LevelDBBackend, _ []string, _ uint64) map[string]uint64 {
+		return weights
+	}
+}
+
+// FrozenBalanceWeights derives the weight of validator from the balance of the
+// frozen accounts linked to the validator. The balances are read from the state
+// of the block at `blockHeight`, so every node gets the same weights of the
+// height regardless of the blocks stored after it.
+func FrozenBalanceWeights(st *storage.LevelDBBackend, validators []string, blockHeight uint64) map[string]uint64 {
+	weights := map[string]uint64{}
+
+	// NOTE if the block is not yet stored, like while syncing, every validator
+	// has same weight; the ballots of that height can not be checked anyway.
+	blk, err := block.GetBlockByHeight(st, blockHeight)
+	if err != nil {
+		return weights
+	}
+	root, err := block.GetStateRoot(st, blk)
+	if err != nil {
+		return weights
+	}
+	sdb := statedb.New(root, trie.NewEthDatabase(st))
+
+	for _, address := range validators {
+		weights[address] = uint64(getFrozenBalanceByLinked(st, sdb, address, blockHeight))
+	}
+
+	return weights
+}
+
+func getFrozenBalanceByLinked(st *storage.LevelDBBackend, sdb *statedb.StateDB, linked string, blockHeight uint64) (balance common.Amount) {
+	iterFunc, closeFunc := block.GetBlockOperationsByLinked(st, linked, nil)
+	defer closeFunc()
+
+	for {
+		bo, hasNext, _ := iterFunc()
+		if !hasNext {
+			break
+		}
+		if bo.Height > blockHeight {
+			continue
+		}
+
+		body, err := operation.UnmarshalBodyJSON(bo.Type, bo.Body)
+		if err != nil {
+			continue
+		}
+		casted, ok := body.(operation.CreateAccount)
+		if !ok {
+			continue
+		}
+
+		ba, err := sdb.GetBlockAccount(casted.Target)
+		if err != nil || ba.Linked != linked {
+			continue
+		}
+		if balance, err = balance.Add(ba.Balance); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// WeightedSelector selects the proposer by the weight of validators. The
+// selection is seeded by the hash of the block at `blockHeight`, which is the
+// previous block of new ballot, so the proposer of next height can not be
+// known before the previous block is confirmed.
+type WeightedSelector struct {
+	sync.Mutex
+
+	cm      network.ConnectionManager
+	st      *storage.LevelDBBackend
+	weights ValidatorWeights
+
+	cachedHeight  uint64
+	cachedSeed    []byte
+	cachedWeights map[string]uint64
+}
+
+func NewWeightedSelector(cm network.ConnectionManager, st *storage.LevelDBBackend, weights ValidatorWeights) *WeightedSelector {
+	return &WeightedSelector{
+		cm:      cm,
+		st:      st,
+		weights: weights,
+	}
+}
+
+// load returns the seed and the weights of `blockHeight`; they are cached
+// until the next height, because the proposer is checked for every ballot.
+func (s *WeightedSelector) load(blockHeight uint64, candidates []string) ([]byte, map[string]uint64) {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.cachedWeights != nil && s.cachedHeight == blockHeight {
+		return s.cachedSeed, s.cachedWeights
+	}
+
+	// NOTE if the block is not yet stored, like while syncing, the seed is
+	// empty; the ballots of that height can not be checked anyway.
+	var seed []byte
+	if blk, err := block.GetBlockByHeight(s.st, blockHeight); err == nil {
+		seed = []byte(blk.Hash)
+	}
+
+	weights := s.weights(s.st, candidates, blockHeight)
+	if seed == nil {
+		return seed, weights
+	}
+
+	s.cachedHeight = blockHeight
+	s.cachedSeed = seed
+	s.cachedWeights = weights
+
+	return seed, weights
+}
+
+func (s *WeightedSelector) Select(blockHeight uint64, round uint64) string {
+	candidates := append(sort.StringSlice{}, s.cm.AllValidators()...)
+	candidates.Sort()
+
+	seed, weights := s.load(blockHeight, candidates)
+
+	var total uint64
+	for _, address := range candidates {
+		total += weights[address]
+	}
+
+	encodedHeight := common.EncodeUint64ToByteSlice(blockHeight)
+	encodedRound := common.EncodeUint64ToByteSlice(round)
+
+	b := make([]byte, 0, len(seed)+len(encodedHeight)+len(encodedRound))
+	b = append(b, seed...)
+	b = append(b, encodedHeight[:]...)
+	b = append(b, encodedRound[:]...)
+	r := binary.BigEndian.Uint64(common.MakeHash(b)[:8])
+
+	// all the validators have same weight
+	if total < 1 {
+		return candidates[r%uint64(len(candidates))]
+	}
+
+	r %= total
+	for _, address := range candidates {
+		w := weights[address]
+		if r < w {
+			return address
+		}
+		r -= w
+	}
+
+	return candidates[len(candidates)-1]
+}
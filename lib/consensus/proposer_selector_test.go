@@ -0,0 +1,151 @@
+package consensus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/network"
+	"boscoin.io/sebak/lib/transaction"
+	"boscoin.io/sebak/lib/transaction/operation"
+)
+
+type selectorTestConnectionManager struct {
+	network.ConnectionManager
+	validators []string
+}
+
+func (c selectorTestConnectionManager) AllValidators() []string {
+	return c.validators
+}
+
+func makeSelectorTestValidators(n int) (validators []string) {
+	for i := 0; i < n; i++ {
+		validators = append(validators, keypair.Random().Address())
+	}
+	return
+}
+
+func TestWeightedSelector(t *testing.T) {
+	st := block.InitTestBlockchain()
+	defer st.Close()
+
+	validators := makeSelectorTestValidators(3)
+	cm := selectorTestConnectionManager{validators: validators}
+	weights := map[string]uint64{
+		validators[0]: 1,
+		validators[1]: 3,
+		validators[2]: 0,
+	}
+
+	selector := NewWeightedSelector(cm, st, FixedWeights(weights))
+	latest := block.GetLatestBlock(st)
+
+	selected := map[string]int{}
+	for round := uint64(0); round < 1000; round++ {
+		proposer := selector.Select(latest.Height, round)
+		selected[proposer]++
+
+		// same proposer is selected by the other node
+		other := NewWeightedSelector(cm, st, FixedWeights(weights))
+		require.Equal(t, proposer, other.Select(latest.Height, round))
+	}
+
+	require.Equal(t, 0, selected[validators[2]])
+	require.True(t, selected[validators[0]] > 0)
+	require.True(t, selected[validators[1]] > selected[validators[0]])
+}
+
+func TestWeightedSelectorWithoutWeights(t *testing.T) {
+	st := block.InitTestBlockchain()
+	defer st.Close()
+
+	validators := makeSelectorTestValidators(3)
+	cm := selectorTestConnectionManager{validators: validators}
+	selector := NewWeightedSelector(cm, st, FixedWeights(map[string]uint64{}))
+	latest := block.GetLatestBlock(st)
+
+	selected := map[string]int{}
+	for round := uint64(0); round < 300; round++ {
+		selected[selector.Select(latest.Height, round)]++
+	}
+
+	// without weights, every validator can be selected
+	for _, v := range validators {
+		require.True(t, selected[v] > 0)
+	}
+}
+
+func TestFrozenBalanceWeights(t *testing.T) {
+	st := block.InitTestBlockchain()
+	defer st.Close()
+
+	validators := makeSelectorTestValidators(2)
+	genesis := block.GetLatestBlock(st)
+
+	// every block creates the frozen account linked to the validator
+	source := keypair.Random()
+	linked := []string{validators[0], validators[0], validators[1]}
+	amounts := []common.Amount{common.Amount(1000), common.Amount(2000), common.Amount(5000)}
+	var blocks []block.Block
+	var frozens []string
+	for i, amount := range amounts {
+		frozen := keypair.Random()
+		frozens = append(frozens, frozen.Address())
+		op, err := operation.NewOperation(operation.NewCreateAccount(frozen.Address(), amount, linked[i]))
+		require.NoError(t, err)
+		tx, err := transaction.NewTransaction(source.Address(), uint64(i), op)
+		require.NoError(t, err)
+
+		latest := block.GetLatestBlock(st)
+		root, err := block.GetStateRoot(st, latest)
+		require.NoError(t, err)
+
+		blk := block.TestMakeNewBlockWithPrevBlock(latest, []string{tx.GetHash()})
+		ba := block.NewBlockAccountLinked(frozen.Address(), amount, linked[i])
+		blk.StateRoot, err = block.PutStateAccounts(st, root, *ba)
+		require.NoError(t, err)
+		blk.MustSave(st)
+		require.NoError(t, ba.Save(st))
+
+		bo, err := block.NewBlockOperationFromOperation(op, tx, blk.Height)
+		require.NoError(t, err)
+		require.NoError(t, bo.Save(st))
+
+		blocks = append(blocks, blk)
+	}
+
+	{ // the latest block
+		weights := FrozenBalanceWeights(st, validators, blocks[2].Height)
+		require.Equal(t, uint64(amounts[0]+amounts[1]), weights[validators[0]])
+		require.Equal(t, uint64(amounts[2]), weights[validators[1]])
+	}
+
+	{ // the frozen accounts created after the height are not counted
+		weights := FrozenBalanceWeights(st, validators, blocks[0].Height)
+		require.Equal(t, uint64(amounts[0]), weights[validators[0]])
+		require.Equal(t, uint64(0), weights[validators[1]])
+
+		weights = FrozenBalanceWeights(st, validators, genesis.Height)
+		require.Equal(t, uint64(0), weights[validators[0]])
+		require.Equal(t, uint64(0), weights[validators[1]])
+	}
+
+	{ // the balance is read from the state of the height, not from the latest
+		ba, err := block.GetBlockAccount(st, frozens[2])
+		require.NoError(t, err)
+		ba.Balance = common.Amount(1)
+		require.NoError(t, ba.Save(st))
+
+		weights := FrozenBalanceWeights(st, validators, blocks[2].Height)
+		require.Equal(t, uint64(amounts[2]), weights[validators[1]])
+	}
+
+	{ // unknown height; every validator has same weight
+		weights := FrozenBalanceWeights(st, validators, 100)
+		require.Equal(t, 0, len(weights))
+	}
+}
@@ -4,6 +4,8 @@ import (
 	logging "github.com/inconshreveable/log15"
 
 	"boscoin.io/sebak/lib/ballot"
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/errors"
 	"boscoin.io/sebak/lib/voting"
 )
 
@@ -39,11 +41,32 @@ func (rv *RoundVote) IsVotedByNode(state ballot.State, node string) bool {
 	return found
 }
 
+// Conflicted returns the voted ballot, which conflicts with the given ballot.
+func (rv *RoundVote) Conflicted(b ballot.Ballot) (ballot.Ballot, bool) {
+	if !b.State().IsValidForVote() {
+		return ballot.Ballot{}, false
+	}
+
+	voted, found := rv.GetResult(b.State())[b.Source()]
+	if !found || !voted.IsConflicted(b) {
+		return ballot.Ballot{}, false
+	}
+
+	return voted, true
+}
+
+// Vote stores the ballot; the ballot, which conflicts with the voted ballot
+// of the same source, does not overwrite the voted one.
 func (rv *RoundVote) Vote(b ballot.Ballot) (isNew bool, err error) {
 	if !b.State().IsValidForVote() {
 		return
 	}
 
+	if _, found := rv.Conflicted(b); found {
+		err = errors.BallotEquivocation
+		return
+	}
+
 	result := rv.GetResult(b.State())
 
 	_, isNew = result[b.Source()]
@@ -75,16 +98,25 @@ func (rv *RoundVote) CanGetVotingResult(policy voting.ThresholdPolicy, state bal
 
 	result := rv.GetResult(state)
 
+	// besides the number of votes, the votes must reach the delegated
+	// threshold; the delegated amount of validator is the weight of vote.
+	delegatedThreshold := policy.DelegatedThreshold()
+
 	var yes, no, expired int
-	for _, blt := range result {
+	var yesDelegated, noDelegated, votedDelegated common.Amount
+	for source, blt := range result {
+		delegated := policy.Delegated(source)
 		switch blt.Vote() {
 		case voting.YES:
 			yes++
+			yesDelegated = yesDelegated.MustAdd(delegated)
 		case voting.NO:
 			no++
+			noDelegated = noDelegated.MustAdd(delegated)
 		case voting.EXP:
 			expired++
 		}
+		votedDelegated = votedDelegated.MustAdd(delegated)
 	}
 
 	log.Debug(
@@ -93,12 +125,15 @@ func (rv *RoundVote) CanGetVotingResult(policy voting.ThresholdPolicy, state bal
 		"yes", yes,
 		"no", no,
 		"expired", expired,
+		"delegated-threshold", delegatedThreshold,
+		"yes-delegated", yesDelegated,
+		"no-delegated", noDelegated,
 		"state", state,
 	)
 
-	if yes >= threshold {
+	if yes >= threshold && yesDelegated >= delegatedThreshold {
 		return result, voting.YES, true
-	} else if no >= threshold {
+	} else if no >= threshold && noDelegated >= delegatedThreshold {
 		return result, voting.NO, true
 	} else {
 		// do nothing
@@ -107,13 +142,20 @@ func (rv *RoundVote) CanGetVotingResult(policy voting.ThresholdPolicy, state bal
 	// check draw!
 	total := policy.Validators()
 	voted := yes + no + expired
-	if cannotBeOver(total-voted, threshold, yes, no) { // draw
+	remainDelegated := policy.TotalDelegated() - votedDelegated
+	yesPossible := canBeOver(total-voted, threshold, yes) && canBeOverDelegated(remainDelegated, delegatedThreshold, yesDelegated)
+	noPossible := canBeOver(total-voted, threshold, no) && canBeOverDelegated(remainDelegated, delegatedThreshold, noDelegated)
+	if !yesPossible && !noPossible { // draw
 		return result, voting.EXP, true
 	}
 
 	return result, voting.NOTYET, false
 }
 
-func cannotBeOver(remain, threshold, yes, no int) bool {
-	return remain+yes < threshold && remain+no < threshold
+func canBeOver(remain, threshold, voted int) bool {
+	return remain+voted >= threshold
+}
+
+func canBeOverDelegated(remain, threshold, voted common.Amount) bool {
+	return remain+voted >= threshold
 }
@@ -0,0 +1,140 @@
+package consensus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/ballot"
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/voting"
+)
+
+func TestRoundVoteWithDelegated(t *testing.T) {
+	nodes := []string{"node1", "node2", "node3", "node4", "node5", "node6"}
+
+	vote := func(rv *RoundVote, source string, hole voting.Hole) {
+		b := *ballot.NewBallot(source, nodes[0], voting.Basis{Height: 10}, []string{})
+		b.SetVote(ballot.StateSIGN, hole)
+		rv.Vote(b)
+	}
+
+	newRoundVote := func(source string, hole voting.Hole) *RoundVote {
+		b := *ballot.NewBallot(source, nodes[0], voting.Basis{Height: 10}, []string{})
+		b.SetVote(ballot.StateSIGN, hole)
+		return NewRoundVote(b)
+	}
+
+	policy, err := NewDefaultVotingThresholdPolicy(66)
+	require.NoError(t, err)
+	policy.SetValidators(len(nodes))
+
+	{ // without delegation, the number of votes is enough
+		rv := newRoundVote(nodes[0], voting.YES)
+		vote(rv, nodes[1], voting.YES)
+		vote(rv, nodes[2], voting.YES)
+		vote(rv, nodes[3], voting.YES)
+
+		_, hole, finished := rv.CanGetVotingResult(policy, ballot.StateSIGN, log)
+		require.True(t, finished)
+		require.Equal(t, voting.YES, hole)
+	}
+
+	policy.SetDelegated(map[string]common.Amount{
+		nodes[0]: common.Amount(10),
+		nodes[1]: common.Amount(10),
+		nodes[2]: common.Amount(10),
+		nodes[3]: common.Amount(10),
+		nodes[4]: common.Amount(30),
+		nodes[5]: common.Amount(30),
+	})
+
+	{ // the votes does not reach the delegated threshold
+		rv := newRoundVote(nodes[0], voting.YES)
+		vote(rv, nodes[1], voting.YES)
+		vote(rv, nodes[2], voting.YES)
+		vote(rv, nodes[3], voting.YES)
+
+		_, hole, finished := rv.CanGetVotingResult(policy, ballot.StateSIGN, log)
+		require.False(t, finished)
+		require.Equal(t, voting.NOTYET, hole)
+
+		vote(rv, nodes[4], voting.NO)
+		_, hole, finished = rv.CanGetVotingResult(policy, ballot.StateSIGN, log)
+		require.False(t, finished)
+		require.Equal(t, voting.NOTYET, hole)
+
+		// the delegated threshold can not be reached
+		vote(rv, nodes[5], voting.NO)
+		_, hole, finished = rv.CanGetVotingResult(policy, ballot.StateSIGN, log)
+		require.True(t, finished)
+		require.Equal(t, voting.EXP, hole)
+	}
+
+	{ // the votes reach both thresholds
+		rv := newRoundVote(nodes[0], voting.YES)
+		vote(rv, nodes[1], voting.YES)
+		vote(rv, nodes[4], voting.YES)
+		vote(rv, nodes[5], voting.YES)
+
+		_, hole, finished := rv.CanGetVotingResult(policy, ballot.StateSIGN, log)
+		require.True(t, finished)
+		require.Equal(t, voting.YES, hole)
+	}
+
+	// the delegated amount of nodes[5] is capped
+	policy.SetDelegated(map[string]common.Amount{
+		nodes[0]: common.Amount(10),
+		nodes[1]: common.Amount(10),
+		nodes[2]: common.Amount(10),
+		nodes[3]: common.Amount(10),
+		nodes[4]: common.Amount(10),
+		nodes[5]: common.Amount(200),
+	})
+
+	{ // the others reach both thresholds without nodes[5]
+		rv := newRoundVote(nodes[0], voting.YES)
+		vote(rv, nodes[1], voting.YES)
+		vote(rv, nodes[2], voting.YES)
+		vote(rv, nodes[3], voting.YES)
+		vote(rv, nodes[4], voting.YES)
+
+		_, hole, finished := rv.CanGetVotingResult(policy, ballot.StateSIGN, log)
+		require.True(t, finished)
+		require.Equal(t, voting.YES, hole)
+	}
+}
+
+func TestRoundVoteConflicted(t *testing.T) {
+	conf := common.NewTestConfig()
+	kp := keypair.Random()
+	proposer := keypair.Random()
+
+	newBallot := func(txs []string) ballot.Ballot {
+		b := *ballot.NewBallot(kp.Address(), proposer.Address(), voting.Basis{Height: 10}, txs)
+		b.SetVote(ballot.StateSIGN, voting.YES)
+		b.Sign(kp, conf.NetworkID)
+		return b
+	}
+
+	voted := newBallot([]string{"tx0"})
+	rv := NewRoundVote(voted)
+
+	// same ballot
+	_, found := rv.Conflicted(voted)
+	require.False(t, found)
+	_, err := rv.Vote(voted)
+	require.NoError(t, err)
+
+	// conflicted ballot does not overwrite the voted one
+	conflicted := newBallot([]string{"tx1"})
+	b, found := rv.Conflicted(conflicted)
+	require.True(t, found)
+	require.Equal(t, voted.GetHash(), b.GetHash())
+
+	_, err = rv.Vote(conflicted)
+	require.Equal(t, errors.BallotEquivocation, err)
+	require.Equal(t, voted.GetHash(), rv.GetResult(ballot.StateSIGN)[kp.Address()].GetHash())
+}
@@ -57,6 +57,21 @@ func (rr *RunningRound) IsVoted(ballot ballot.Ballot) bool {
 	}
 }
 
+// Conflicted returns the voted ballot, which conflicts with the given ballot;
+// the conflicted ballot can be voted for the other proposer.
+func (rr *RunningRound) Conflicted(b ballot.Ballot) (ballot.Ballot, bool) {
+	rr.RLock()
+	defer rr.RUnlock()
+
+	for _, roundVote := range rr.Voted {
+		if voted, found := roundVote.Conflicted(b); found {
+			return voted, true
+		}
+	}
+
+	return ballot.Ballot{}, false
+}
+
 func (rr *RunningRound) Vote(ballot ballot.Ballot) {
 	rr.Lock()
 	defer rr.Unlock()
@@ -3,8 +3,10 @@ package consensus
 import (
 	"encoding/json"
 	"math"
+	"sort"
 	"sync"
 
+	"boscoin.io/sebak/lib/common"
 	"boscoin.io/sebak/lib/errors"
 )
 
@@ -14,9 +16,14 @@ type ISAACVotingThresholdPolicy struct {
 	threshold  int
 	validators int
 	connected  int
+	delegated  map[string]common.Amount
+	weights    map[string]common.Amount // capped `delegated`
 }
 
 func (vt *ISAACVotingThresholdPolicy) Validators() int {
+	vt.RLock()
+	defer vt.RUnlock()
+
 	return vt.validators
 }
 
@@ -24,6 +31,10 @@ func (vt *ISAACVotingThresholdPolicy) SetValidators(n int) {
 	if n < 1 {
 		panic(errors.VotingThresholdInvalidValidators)
 	}
+
+	vt.Lock()
+	defer vt.Unlock()
+
 	vt.validators = n
 }
 
@@ -46,6 +57,9 @@ func (vt *ISAACVotingThresholdPolicy) SetConnected(n int) {
 }
 
 func (vt *ISAACVotingThresholdPolicy) Threshold() int {
+	vt.RLock()
+	defer vt.RUnlock()
+
 	v := float64(vt.validators) * (float64(vt.threshold) / float64(100))
 	threshold := int(math.Ceil(v))
 
@@ -56,6 +70,107 @@ func (vt *ISAACVotingThresholdPolicy) Threshold() int {
 	return threshold
 }
 
+// Delegated returns the weight of validator; it is the delegated amount, but
+// it is capped by `capDelegated()`.
+func (vt *ISAACVotingThresholdPolicy) Delegated(address string) common.Amount {
+	vt.RLock()
+	defer vt.RUnlock()
+
+	return vt.weights[address]
+}
+
+func (vt *ISAACVotingThresholdPolicy) TotalDelegated() common.Amount {
+	vt.RLock()
+	defer vt.RUnlock()
+
+	return vt.totalDelegated()
+}
+
+func (vt *ISAACVotingThresholdPolicy) totalDelegated() (total common.Amount) {
+	for _, amount := range vt.weights {
+		total = total.MustAdd(amount)
+	}
+
+	return
+}
+
+func (vt *ISAACVotingThresholdPolicy) SetDelegated(delegated map[string]common.Amount) {
+	vt.Lock()
+	defer vt.Unlock()
+
+	vt.delegated = map[string]common.Amount{}
+	for address, amount := range delegated {
+		vt.delegated[address] = amount
+	}
+
+	vt.weights = vt.capDelegated()
+}
+
+// capDelegated caps the delegated amount of each validator by `100 -
+// threshold` percent of the total; without cap, the validator, which has too
+// much delegated amount, can halt the consensus by going offline, because the
+// others can not reach `DelegatedThreshold()`.
+func (vt *ISAACVotingThresholdPolicy) capDelegated() map[string]common.Amount {
+	weights := map[string]common.Amount{}
+	if len(vt.delegated) < 1 {
+		return weights
+	}
+
+	// the maximum share of one validator in the total of the capped amounts
+	share := float64(100-vt.threshold) / float64(100)
+
+	var amounts []float64
+	var total float64
+	for _, amount := range vt.delegated {
+		amounts = append(amounts, float64(amount))
+		total += float64(amount)
+	}
+	sort.Sort(sort.Reverse(sort.Float64Slice(amounts)))
+
+	// the largest `k` amounts are capped to `limit`, which is `share` of the
+	// capped total; `rest` is the total of the others.
+	limit := math.Inf(1)
+	rest := total
+	for k := 0; k < len(amounts); k++ {
+		cappedTotal := rest
+		if k > 0 {
+			cappedTotal += float64(k) * limit
+		}
+		if amounts[k] <= share*cappedTotal {
+			break
+		}
+
+		// the same amounts can not exceed `share`
+		if float64(k+1)*share >= 1 {
+			limit = math.Min(limit, amounts[k])
+			break
+		}
+
+		rest -= amounts[k]
+		limit = math.Floor(share * rest / (1 - float64(k+1)*share))
+	}
+
+	for address, amount := range vt.delegated {
+		if float64(amount) > limit {
+			amount = common.Amount(limit)
+		}
+		weights[address] = amount
+	}
+
+	return weights
+}
+
+// DelegatedThreshold returns the delegated amount, which the votes must
+// reach with `Threshold()`.
+func (vt *ISAACVotingThresholdPolicy) DelegatedThreshold() common.Amount {
+	vt.RLock()
+	defer vt.RUnlock()
+
+	v := float64(vt.totalDelegated()) * (float64(vt.threshold) / float64(100))
+
+	return common.Amount(math.Ceil(v))
+}
+
 func (vt *ISAACVotingThresholdPolicy) MarshalJSON() ([]byte, error) {
 	vt.RLock()
 	defer vt.RUnlock()
@@ -64,6 +179,7 @@ func (vt *ISAACVotingThresholdPolicy) MarshalJSON() ([]byte, error) {
 		"threshold":  vt.threshold,
 		"validators": vt.validators,
 		"connected":  vt.connected,
+		"delegated":  vt.totalDelegated(),
 	})
 }
 
@@ -80,6 +196,8 @@ func NewDefaultVotingThresholdPolicy(threshold int) (vt *ISAACVotingThresholdPol
 	vt = &ISAACVotingThresholdPolicy{
 		threshold:  threshold,
 		validators: 0,
+		delegated:  map[string]common.Amount{},
+		weights:    map[string]common.Amount{},
 	}
 
 	return
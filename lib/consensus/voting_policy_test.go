@@ -1,9 +1,12 @@
 package consensus
 
 import (
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/common"
 )
 
 func TestThreshold(t *testing.T) {
@@ -14,3 +17,99 @@ func TestThreshold(t *testing.T) {
 	require.Equal(t, 660, vt.Threshold())
 
 }
+
+// TestThresholdConcurrent checks the validators can be changed while the
+// threshold is read; run it with `-race`.
+func TestThresholdConcurrent(t *testing.T) {
+	vt, err := NewDefaultVotingThresholdPolicy(66)
+	require.NoError(t, err)
+	vt.SetValidators(1)
+
+	var wg sync.WaitGroup
+	for i := 1; i <= 100; i++ {
+		wg.Add(2)
+		go func(n int) {
+			defer wg.Done()
+			vt.SetValidators(n)
+		}(i)
+		go func() {
+			defer wg.Done()
+			require.True(t, vt.Threshold() <= 66)
+			require.True(t, vt.Validators() <= 100)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestDelegatedThreshold(t *testing.T) {
+	vt, err := NewDefaultVotingThresholdPolicy(66)
+	require.NoError(t, err)
+
+	vt.SetValidators(3)
+	require.Equal(t, common.Amount(0), vt.DelegatedThreshold())
+
+	vt.SetDelegated(map[string]common.Amount{
+		"node1": common.Amount(100),
+		"node2": common.Amount(300),
+		"node3": common.Amount(600),
+	})
+	// node2 and node3 are capped
+	require.Equal(t, common.Amount(312), vt.TotalDelegated())
+	require.Equal(t, common.Amount(100), vt.Delegated("node1"))
+	require.Equal(t, common.Amount(106), vt.Delegated("node2"))
+	require.Equal(t, common.Amount(106), vt.Delegated("node3"))
+	require.Equal(t, common.Amount(0), vt.Delegated("node4"))
+	require.Equal(t, common.Amount(206), vt.DelegatedThreshold())
+}
+
+func TestDelegatedCapped(t *testing.T) {
+	vt, err := NewDefaultVotingThresholdPolicy(66)
+	require.NoError(t, err)
+	vt.SetValidators(4)
+
+	{ // one validator has too much delegated amount
+		vt.SetDelegated(map[string]common.Amount{
+			"node1": common.Amount(10),
+			"node2": common.Amount(10),
+			"node3": common.Amount(10),
+			"node4": common.Amount(70),
+		})
+		require.Equal(t, common.Amount(15), vt.Delegated("node4"))
+		require.Equal(t, common.Amount(10), vt.Delegated("node1"))
+		require.Equal(t, common.Amount(45), vt.TotalDelegated())
+
+		// the others can reach the delegated threshold without it
+		require.True(t, vt.TotalDelegated()-vt.Delegated("node4") >= vt.DelegatedThreshold())
+	}
+
+	{ // two validators have too much delegated amount
+		vt.SetDelegated(map[string]common.Amount{
+			"node1": common.Amount(10),
+			"node2": common.Amount(10),
+			"node3": common.Amount(40),
+			"node4": common.Amount(40),
+		})
+		require.Equal(t, common.Amount(21), vt.Delegated("node3"))
+		require.Equal(t, common.Amount(21), vt.Delegated("node4"))
+		require.Equal(t, common.Amount(62), vt.TotalDelegated())
+	}
+
+	{ // only one validator has delegated amount
+		vt.SetDelegated(map[string]common.Amount{
+			"node4": common.Amount(70),
+		})
+		require.Equal(t, common.Amount(0), vt.Delegated("node4"))
+		require.Equal(t, common.Amount(0), vt.DelegatedThreshold())
+	}
+
+	{ // not capped
+		vt.SetDelegated(map[string]common.Amount{
+			"node1": common.Amount(20),
+			"node2": common.Amount(20),
+			"node3": common.Amount(30),
+			"node4": common.Amount(30),
+		})
+		require.Equal(t, common.Amount(30), vt.Delegated("node4"))
+		require.Equal(t, common.Amount(100), vt.TotalDelegated())
+	}
+}
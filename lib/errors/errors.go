@@ -115,4 +115,40 @@ var (
 	SnapshotNotFound                          = NewError(197, "snapshot not found")
 	SnapshotLimitReached                      = NewError(198, "snapshots over limit")
 	BallotsNotFound                           = NewError(199, "ballots not found")
+	StateRootDoesNotMatch                     = NewError(200, "state root does not match")
+	InvalidAccountProof                       = NewError(201, "account proof is invalid")
+	InvalidSigners                            = NewError(202, "signers are invalid")
+	InvalidThresholds                         = NewError(203, "thresholds are invalid")
+	DuplicatedSignature                       = NewError(204, "duplicated signature found")
+	NotEnoughSignatureWeight                  = NewError(205, "signature weight is lower than threshold")
+	InvalidTimeBounds                         = NewError(206, "time bounds are invalid")
+	TransactionNotYetValid                    = NewError(207, "transaction is not yet valid")
+	TransactionExpired                        = NewError(208, "transaction is expired")
+	InvalidMemo                               = NewError(209, "memo is invalid")
+	TransactionReplacementFeeTooLow           = NewError(210, "fee is too low to replace the transaction in pool")
+	TransactionSourceQueueFull                = NewError(211, "too many transactions of source in pool")
+	CongressVoteFromInvalidAccount            = NewError(212, "congress vote should be done from a frozen account")
+	CongressVotingNotFound                    = NewError(213, "congress voting not found")
+	CongressVotingNotInPeriod                 = NewError(214, "not in the period of congress voting")
+	InflationPFAlreadyFunded                  = NewError(215, "voting result is already funded")
+	UnfreezingAmountWholeUnit                 = NewError(216, "unfreezing amount must be a whole number of units (10k)")
+	UnfreezingAmountExceeded                  = NewError(217, "unfreezing amount exceeds the frozen balance")
+	UnfreezingAmountNotMatured                = NewError(218, "payment exceeds the matured unfreezing amount")
+	DelegateFromInvalidAccount                = NewError(219, "only frozen account can delegate")
+	ValidatorSetChangeNotInFuture             = NewError(220, "validator set change must take effect at the future block")
+	ValidatorSetChangeAlreadyExists           = NewError(221, "validator set change for the validator already exists at the height")
+	BallotEquivocation                        = NewError(222, "ballot conflicts with the ballot of the same validator")
+	EvidenceInvalid                           = NewError(223, "invalid evidence")
+	EvidenceNotFound                          = NewError(224, "evidence not found")
+	CertificateInvalid                        = NewError(225, "invalid certificate")
+	CertificateNotFound                       = NewError(226, "certificate not found")
+	CertificateThresholdNotReached            = NewError(227, "ballots of certificate does not reach threshold")
+	StateSnapshotNotFound                     = NewError(228, "state snapshot not found")
+	StateSnapshotInvalid                      = NewError(229, "invalid state snapshot")
+	SyncInvalidData                           = NewError(230, "node sent invalid data for sync")
+	CongressVotingNotClosed                   = NewError(231, "congress voting is not yet closed")
+	CongressVotingNotPassed                   = NewError(232, "congress voting is not passed")
+	CongressVotingResultMissMatched           = NewError(233, "congress voting result does not match with the votes")
+	CongressVotingResultAlreadyExists         = NewError(234, "congress voting already has the result")
+	SyncBlocksPruned                          = NewError(235, "node can not serve the pruned blocks for sync")
 )
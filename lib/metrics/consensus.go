@@ -20,6 +20,8 @@ type ConsensusMetrics struct {
 
 	Validators        metrics.Gauge
 	MissingValidators metrics.Gauge
+
+	EquivocationTotal metrics.Counter
 }
 
 func (c *ConsensusMetrics) SetBlockIntervalSeconds(t time.Time) time.Time {
@@ -46,6 +48,9 @@ func (c *ConsensusMetrics) SetValidators(num int) {
 func (c *ConsensusMetrics) SetMissingValidators(num int) {
 	c.MissingValidators.Set(float64(num))
 }
+func (c *ConsensusMetrics) AddEquivocation(validator string) {
+	c.EquivocationTotal.With(ConsensusValidator, validator).Add(1)
+}
 
 func PromConsensusMetrics() *ConsensusMetrics {
 	return &ConsensusMetrics{
@@ -91,6 +96,12 @@ func PromConsensusMetrics() *ConsensusMetrics {
 			Name:      "missing_validators",
 			Help:      "Number of missing validators.",
 		}, []string{}),
+		EquivocationTotal: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: ConsensusSubsystem,
+			Name:      "equivocation_total",
+			Help:      "Number of detected equivocations.",
+		}, []string{ConsensusValidator}),
 	}
 }
 
@@ -106,5 +117,7 @@ func NopConsensusMetrics() *ConsensusMetrics {
 
 		Validators:        discard.NewGauge(),
 		MissingValidators: discard.NewGauge(),
+
+		EquivocationTotal: discard.NewCounter(),
 	}
 }
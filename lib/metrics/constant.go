@@ -6,11 +6,24 @@ const (
 	SyncSubsystem      = "sync"
 	TxPoolSubsystem    = "txpool"
 	APISubsystem       = "api"
+	StorageSubsystem   = "storage"
+)
+
+const (
+	ConsensusValidator = "validator"
 )
 
 const (
 	SyncComponent = "component"
 	SyncFetcher   = "fetcher"
+	SyncHeader    = "header"
 	SyncValidator = "validator"
+	SyncPeer      = "peer"
 	SyncAll       = "all"
 )
+
+const (
+	StoragePruneType            = "type"
+	StoragePruneSequenceID      = "sequence_id"
+	StoragePruneTransactionPool = "transaction_pool"
+)
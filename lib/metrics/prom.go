@@ -6,4 +6,5 @@ func InitPrometheusMetrics() {
 	Sync = PromSyncMetrics()
 	TxPool = PromTxPoolMetrics()
 	API = PromAPIMetrics()
+	Storage = PromStorageMetrics()
 }
@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	prometheus "github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+type StorageMetrics struct {
+	PrunedHeight         metrics.Gauge
+	PrunedTotal          metrics.Counter
+	PruneDurationSeconds metrics.Histogram
+}
+
+func (s *StorageMetrics) SetPrunedHeight(height uint64) {
+	s.PrunedHeight.Set(float64(height))
+}
+
+// AddPruned counts the removed records by the type of record.
+func (s *StorageMetrics) AddPruned(recordType string, n int) {
+	s.PrunedTotal.With(StoragePruneType, recordType).Add(float64(n))
+}
+
+func (s *StorageMetrics) ObservePruneDurationSeconds(begin time.Time) {
+	s.PruneDurationSeconds.Observe(time.Since(begin).Seconds())
+}
+
+func PromStorageMetrics() *StorageMetrics {
+	return &StorageMetrics{
+		PrunedHeight: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: StorageSubsystem,
+			Name:      "pruned_height",
+			Help:      "Height of the last pruned block.",
+		}, []string{}),
+		PrunedTotal: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: StorageSubsystem,
+			Name:      "pruned_total",
+			Help:      "Number of pruned records.",
+		}, []string{StoragePruneType}),
+		PruneDurationSeconds: prometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
+			Namespace: Namespace,
+			Subsystem: StorageSubsystem,
+			Name:      "prune_duration_seconds",
+			Help:      "Time pruning the blocks at once.",
+		}, []string{}),
+	}
+}
+
+func NopStorageMetrics() *StorageMetrics {
+	return &StorageMetrics{
+		PrunedHeight:         discard.NewGauge(),
+		PrunedTotal:          discard.NewCounter(),
+		PruneDurationSeconds: discard.NewHistogram(),
+	}
+}
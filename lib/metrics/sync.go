@@ -33,6 +33,11 @@ func (s *SyncMetrics) AddValidateError() {
 	s.ErrorTotal.With(SyncComponent, SyncValidator).Add(1)
 }
 
+// AddInvalidData counts the invalid data sent by the other nodes.
+func (s *SyncMetrics) AddInvalidData() {
+	s.ErrorTotal.With(SyncComponent, SyncPeer).Add(1)
+}
+
 func PromSyncMetrics() *SyncMetrics {
 	return &SyncMetrics{
 		Height: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
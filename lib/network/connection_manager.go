@@ -13,4 +13,6 @@ type ConnectionManager interface {
 	CountConnected() int
 	IsReady() bool
 	Discovery(DiscoveryMessage) error
+	// Follow the changed validators of local node
+	UpdateValidators()
 }
@@ -23,6 +23,8 @@ var (
 		errors.TooManyRequests.Code:               http.StatusTooManyRequests,
 		errors.BlockTransactionDoesNotExists.Code: http.StatusNotFound,
 		errors.BlockAccountDoesNotExists.Code:     http.StatusNotFound,
+		errors.CongressVotingNotFound.Code:        http.StatusNotFound,
+		errors.EvidenceNotFound.Code:              http.StatusNotFound,
 		errors.TransactionPoolFull.Code:           http.StatusLocked,
 		errors.BadRequestParameter.Code:           http.StatusBadRequest,
 	}
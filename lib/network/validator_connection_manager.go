@@ -26,6 +26,7 @@ type ValidatorConnectionManager struct {
 
 	clients                       map[ /* hash of node.Endpoint() */ string]NetworkClient
 	connected                     map[ /* node.Address() */ string]bool
+	connecting                    map[ /* node.Address() */ string]*node.Validator
 	config                        common.Config
 	discoveryChannel              chan DiscoveryMessage
 	connectedEqualOrOverThreshold bool
@@ -43,13 +44,14 @@ func NewValidatorConnectionManager(
 		panic("empty validators")
 	}
 	cm := &ValidatorConnectionManager{
-		localNode: localNode,
-		network:   network,
-		policy:    policy,
-		config:    config,
-		clients:   map[string]NetworkClient{},
-		connected: map[string]bool{},
-		log:       log.New(logging.Ctx{"node": localNode.Alias()}),
+		localNode:  localNode,
+		network:    network,
+		policy:     policy,
+		config:     config,
+		clients:    map[string]NetworkClient{},
+		connected:  map[string]bool{},
+		connecting: map[string]*node.Validator{},
+		log:        log.New(logging.Ctx{"node": localNode.Alias()}),
 	}
 	cm.connected[localNode.Address()] = true
 	cm.discoveryChannel = make(chan DiscoveryMessage, 100)
@@ -96,13 +98,55 @@ func (c *ValidatorConnectionManager) Start() {
 	}
 
 	c.log.Debug("starting to connect to validators", "validators", c.localNode.GetValidators())
-	for _, v := range c.localNode.GetValidators() {
-		if v.Address() == c.localNode.Address() {
+	c.UpdateValidators()
+	go c.watchForMetrics()
+}
+
+// UpdateValidators starts to connect to the validators, which are newly added
+// to the local node, and stops to connect to the removed validators.
+func (c *ValidatorConnectionManager) UpdateValidators() {
+	validators := c.localNode.GetValidators()
+
+	c.Lock()
+	var added []*node.Validator
+	for address := range c.connecting {
+		if _, found := validators[address]; !found {
+			delete(c.connecting, address)
+		}
+	}
+	for address := range c.connected {
+		if _, found := validators[address]; !found {
+			delete(c.connected, address)
+		}
+	}
+	for address, v := range validators {
+		if address == c.localNode.Address() {
 			continue
 		}
+		if _, found := c.connecting[address]; found {
+			continue
+		}
+		c.connecting[address] = v
+		added = append(added, v)
+	}
+
+	if connected := c.countConnectedUnlocked(); connected > 0 {
+		c.policy.SetConnected(connected)
+	}
+	c.Unlock()
+
+	for _, v := range added {
 		go c.connectingValidator(v)
 	}
-	go c.watchForMetrics()
+}
+
+// isConnecting checks the validator is still connected by
+// `connectingValidator()`.
+func (c *ValidatorConnectionManager) isConnecting(v *node.Validator) bool {
+	c.RLock()
+	defer c.RUnlock()
+
+	return c.connecting[v.Address()] == v
 }
 
 // setConnected returns `true` when the validator is newly connected or
@@ -111,6 +155,11 @@ func (c *ValidatorConnectionManager) setConnected(v *node.Validator, connected b
 	c.Lock()
 	defer c.Unlock()
 
+	// the validator is already removed
+	if c.connecting[v.Address()] != v {
+		return false
+	}
+
 	old, found := c.connected[v.Address()]
 	c.connected[v.Address()] = connected
 
@@ -165,6 +214,12 @@ func (c *ValidatorConnectionManager) countConnectedUnlocked() int {
 func (c *ValidatorConnectionManager) connectingValidator(v *node.Validator) {
 	ticker := time.NewTicker(time.Second * 1)
 	for _ = range ticker.C {
+		if !c.isConnecting(v) {
+			ticker.Stop()
+			c.log.Debug("validator is removed", "validator", v.Address())
+			return
+		}
+
 		if v.Endpoint() == nil {
 			continue
 		}
@@ -360,6 +415,10 @@ func (c *ValidatorConnectionManager) watchForMetrics() {
 
 	ticker := time.NewTicker(time.Second * 60)
 	for _ = range ticker.C {
+		// the validators can be changed by `UpdateValidators()`
+		numValidators = len(c.localNode.GetValidators())
+		metrics.Consensus.SetValidators(numValidators)
+
 		numConnected := c.CountConnected()
 		metrics.Consensus.SetMissingValidators(numValidators - numConnected)
 	}
@@ -14,13 +14,14 @@ type NodeInfo struct {
 }
 
 type NodeInfoNode struct {
-	Version    NodeVersion           `json:"version"`
-	Started    string                `json:"started"`
-	State      State                 `json:"state"`
-	Alias      string                `json:"alias"`
-	Address    string                `json:"address"`
-	Endpoint   *common.Endpoint      `json:"endpoint"`
-	Validators map[string]*Validator `json:"validators"`
+	Version    NodeVersion              `json:"version"`
+	Started    string                   `json:"started"`
+	State      State                    `json:"state"`
+	Alias      string                   `json:"alias"`
+	Address    string                   `json:"address"`
+	Endpoint   *common.Endpoint         `json:"endpoint"`
+	Validators map[string]*Validator    `json:"validators"`
+	Delegated  map[string]common.Amount `json:"delegated"` // delegated amount by validator
 }
 
 type NodePolicy struct {
@@ -34,6 +35,8 @@ type NodePolicy struct {
 	TimeoutSIGN               time.Duration `json:"timeout-sign"`
 	TimeoutACCEPT             time.Duration `json:"timeout-accept"`
 	TimeoutALLCONFIRM         time.Duration `json:"timeout-allconfirm"`
+	TimeoutBackoffFactor      float64       `json:"timeout-backoff-factor"`
+	TimeoutBackoffLimit       time.Duration `json:"timeout-backoff-limit"`
 	RateLimitRuleAPI          string        `json:"rate-limit-api"`
 	RateLimitRuleNode         string        `json:"rate-limit-node"`
 	TransactionsLimit         int           `json:"transactions-limit"`            // transactions limit in a ballot
@@ -157,6 +157,17 @@ func (n *LocalNode) AddValidators(validators ...*Validator) error {
 	return nil
 }
 
+// SetValidators replaces the validators with the given validators.
+func (n *LocalNode) SetValidators(validators ...*Validator) {
+	n.Lock()
+	defer n.Unlock()
+
+	n.validators = map[string]*Validator{}
+	for _, va := range validators {
+		n.validators[va.Address()] = va
+	}
+}
+
 func (n *LocalNode) ClearValidators() {
 	n.Lock()
 	defer n.Unlock()
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
+	"strconv"
 
 	"github.com/gorilla/mux"
 
@@ -12,6 +13,8 @@ import (
 	"boscoin.io/sebak/lib/errors"
 	"boscoin.io/sebak/lib/network/httputils"
 	"boscoin.io/sebak/lib/node/runner/api/resource"
+	"boscoin.io/sebak/lib/storage/statedb"
+	"boscoin.io/sebak/lib/storage/statedb/trie"
 	"boscoin.io/sebak/lib/transaction/operation"
 )
 
@@ -44,6 +47,48 @@ func (api NetworkHandlerAPI) GetAccountHandler(w http.ResponseWriter, r *http.Re
 	httputils.MustWriteJSON(w, 200, payload)
 }
 
+// GetAccountProofHandler returns the merkle proof of account against the
+// state root in the header of block. Without `height`, the latest block is
+// used.
+func (api NetworkHandlerAPI) GetAccountProofHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	address := vars["id"]
+
+	readFunc := func() (payload interface{}, err error) {
+		var blk block.Block
+		if s := r.URL.Query().Get("height"); len(s) > 0 {
+			var height uint64
+			if height, err = strconv.ParseUint(s, 10, 64); err != nil {
+				return nil, errors.BadRequestParameter.Clone().SetData("error", err.Error())
+			}
+			if blk, err = block.GetBlockByHeight(api.storage, height); err != nil {
+				return nil, err
+			}
+		} else {
+			blk = block.GetLatestBlock(api.storage)
+		}
+
+		// NOTE the proof is made against `Header.StateRoot`, not the state
+		// root replaced by `block.SaveStateRoot()`, so it can be verified with
+		// the block header.
+		sdb := statedb.New(blk.StateRoot, trie.NewEthDatabase(api.storage))
+		proof, err := sdb.GetAccountProof(address)
+		if err != nil {
+			return nil, err
+		}
+		payload = resource.NewAccountProof(address, &blk, proof)
+		return payload, nil
+	}
+
+	payload, err := readFunc()
+	if err != nil {
+		httputils.WriteJSONError(w, err)
+		return
+	}
+
+	httputils.MustWriteJSON(w, 200, payload)
+}
+
 func (api NetworkHandlerAPI) GetAccountsHandler(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 
@@ -179,6 +224,7 @@ func (api NetworkHandlerAPI) GetFrozenAccountsByAccountHandler(w http.ResponseWr
 				UnfreezingRequestBlockHeight: unfreezingBlockHeight,
 				UnfreezingRequestOpHash:      unfreezingOpHash,
 				UnfreezingRemainingBlocks:    unfreezingRemainingBlocks,
+				UnfreezingRequests:           api.getUnfreezingRequests(casted.Target),
 				PaymentOpHash:                paymentOpHash,
 			}
 			var ba *block.BlockAccount
@@ -288,6 +334,7 @@ func (api NetworkHandlerAPI) GetFrozenAccountsHandler(w http.ResponseWriter, r *
 				UnfreezingRequestBlockHeight: unfreezingBlockHeight,
 				UnfreezingRequestOpHash:      unfreezingOpHash,
 				UnfreezingRemainingBlocks:    unfreezingRemainingBlocks,
+				UnfreezingRequests:           api.getUnfreezingRequests(casted.Target),
 				PaymentOpHash:                paymentOpHash,
 			}
 			var ba *block.BlockAccount
@@ -306,3 +353,23 @@ func (api NetworkHandlerAPI) GetFrozenAccountsHandler(w http.ResponseWriter, r *
 	list := p.ResourceList(txs, firstCursor, cursor)
 	httputils.MustWriteJSON(w, 200, list)
 }
+
+// getUnfreezingRequests returns the unfreezing requests of the frozen account
+// with the remaining blocks to be matured.
+func (api NetworkHandlerAPI) getUnfreezingRequests(address string) (infos []resource.UnfreezingRequestInfo) {
+	lastblock := block.GetLatestBlock(api.storage)
+	for _, r := range block.GetAllUnfreezingRequests(api.storage, address) {
+		info := resource.UnfreezingRequestInfo{
+			OpHash:      r.OpHash,
+			BlockHeight: r.Height,
+			Amount:      r.Amount,
+			Withdrawn:   r.Withdrawn,
+		}
+		if !r.IsMatured(lastblock.Height) {
+			info.RemainingBlocks = r.Height + common.UnfreezingPeriod - lastblock.Height
+		}
+		infos = append(infos, info)
+	}
+
+	return
+}
@@ -13,6 +13,9 @@ import (
 	"boscoin.io/sebak/lib/common/keypair"
 	"boscoin.io/sebak/lib/errors"
 	"boscoin.io/sebak/lib/network/httputils"
+	"boscoin.io/sebak/lib/storage/statedb"
+	"boscoin.io/sebak/lib/storage/statedb/trie"
+	"boscoin.io/sebak/lib/voting"
 
 	"github.com/stretchr/testify/require"
 )
@@ -51,6 +54,114 @@ func TestGetAccountHandler(t *testing.T) {
 	}
 }
 
+func TestGetAccountProofHandler(t *testing.T) {
+	ts, storage := prepareAPIServer()
+	defer storage.Close()
+	defer ts.Close()
+
+	// Put the account in state trie and make new block with the state root
+	ba := block.TestMakeBlockAccount()
+	require.NoError(t, ba.Save(storage))
+
+	genesis := block.GetLatestBlock(storage)
+	sdb := statedb.New(genesis.StateRoot, trie.NewEthDatabase(storage))
+	sdb.SetBlockAccount(ba)
+	root, err := sdb.CommitTrie()
+	require.NoError(t, err)
+	require.NoError(t, sdb.CommitDB(root))
+
+	blk := block.NewBlock(
+		keypair.Random().Address(),
+		voting.Basis{Height: genesis.Height + 1, BlockHash: genesis.Hash},
+		"",
+		[]string{},
+		root,
+		common.NowISO8601(),
+	)
+	require.NoError(t, blk.Save(storage))
+
+	getProof := func(address string, height uint64) (*http.Response, []byte) {
+		url := strings.Replace(GetAccountProofHandlerPattern, "{id}", address, -1)
+		url += "?height=" + strconv.FormatUint(height, 10)
+		resp, err := ts.Client().Get(ts.URL + url)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp, body
+	}
+
+	{ // proof against the state root of new block
+		resp, body := getProof(ba.Address, blk.Height)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var recv struct {
+			Address     string         `json:"address"`
+			BlockHash   string         `json:"block_hash"`
+			BlockHeight uint64         `json:"block_height"`
+			StateRoot   common.Hash    `json:"state_root"`
+			Proof       trie.ProofList `json:"proof"`
+		}
+		common.MustUnmarshalJSON(body, &recv)
+		require.Equal(t, ba.Address, recv.Address)
+		require.Equal(t, blk.Hash, recv.BlockHash)
+		require.Equal(t, blk.Height, recv.BlockHeight)
+		require.Equal(t, root, recv.StateRoot)
+
+		proved, err := statedb.VerifyAccountProof(blk.StateRoot, ba.Address, recv.Proof)
+		require.NoError(t, err)
+		require.Equal(t, ba.Balance, proved.Balance)
+	}
+
+	{ // without height, the proof is made against the latest block
+		url := strings.Replace(GetAccountProofHandlerPattern, "{id}", ba.Address, -1)
+		resp, err := ts.Client().Get(ts.URL + url)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		body, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		var recv struct {
+			BlockHash   string         `json:"block_hash"`
+			BlockHeight uint64         `json:"block_height"`
+			StateRoot   common.Hash    `json:"state_root"`
+			Proof       trie.ProofList `json:"proof"`
+		}
+		common.MustUnmarshalJSON(body, &recv)
+		require.Equal(t, blk.Hash, recv.BlockHash)
+		require.Equal(t, blk.Height, recv.BlockHeight)
+		require.Equal(t, blk.StateRoot, recv.StateRoot)
+
+		proved, err := statedb.VerifyAccountProof(blk.StateRoot, ba.Address, recv.Proof)
+		require.NoError(t, err)
+		require.Equal(t, ba.Balance, proved.Balance)
+	}
+
+	{ // the account, which is put into the live state after the latest block,
+		// is not in the state root of block header
+		other := block.TestMakeBlockAccount()
+		other.MustSave(storage)
+
+		replaced, err := block.GetStateRoot(storage, *blk)
+		require.NoError(t, err)
+		require.NotEqual(t, blk.StateRoot, replaced)
+
+		resp, _ := getProof(other.Address, blk.Height)
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	}
+
+	{ // genesis block does not have the account in state trie
+		resp, _ := getProof(ba.Address, genesis.Height)
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	}
+
+	{ // unknown height
+		resp, _ := getProof(ba.Address, blk.Height+1)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	}
+}
+
 // Test that getting an inexisting account returns an error
 func TestGetNonExistentAccountHandler(t *testing.T) {
 
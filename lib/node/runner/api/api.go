@@ -21,15 +21,24 @@ const (
 	GetAccountsHandlerPattern              = "/accounts"
 	GetAccountOperationsHandlerPattern     = "/accounts/{id}/operations"
 	GetAccountFrozenAccountHandlerPattern  = "/accounts/{id}/frozen-accounts"
+	GetAccountProofHandlerPattern          = "/accounts/{id}/proof"
 	GetFrozenAccountHandlerPattern         = "/frozen-accounts"
 	GetTransactionsHandlerPattern          = "/transactions"
 	GetTransactionByHashHandlerPattern     = "/transactions/{id}"
 	GetTransactionOperationsHandlerPattern = "/transactions/{id}/operations"
 	GetTransactionOperationHandlerPattern  = "/transactions/{id}/operations/{opindex}"
 	GetTransactionStatusHandlerPattern     = "/transactions/{id}/status"
+	GetTransactionProofHandlerPattern      = "/transactions/{id}/proof"
 	PostTransactionPattern                 = "/transactions"
 	GetBlocksHandlerPattern                = "/blocks"
 	GetBlockHandlerPattern                 = "/blocks/{hashOrHeight}"
+	GetCongressProposalsHandlerPattern     = "/congress/proposals"
+	GetCongressProposalHandlerPattern      = "/congress/proposals/{id}"
+	GetCongressResultHandlerPattern        = "/congress/proposals/{id}/result"
+	GetValidatorSetsHandlerPattern         = "/validators"
+	GetValidatorSetHandlerPattern          = "/validators/{height}"
+	GetEvidencesHandlerPattern             = "/evidence"
+	GetEvidenceHandlerPattern              = "/evidence/{id}"
 	GetNodeInfoPattern                     = "/"
 	PostSubscribePattern                   = "/subscribe"
 )
@@ -26,16 +26,25 @@ func prepareAPIServer() (*httptest.Server, *storage.LevelDBBackend) {
 
 	router := mux.NewRouter()
 	router.HandleFunc(GetAccountHandlerPattern, apiHandler.GetAccountHandler).Methods("GET")
+	router.HandleFunc(GetAccountProofHandlerPattern, apiHandler.GetAccountProofHandler).Methods("GET")
 	router.HandleFunc(GetAccountsHandlerPattern, apiHandler.GetAccountsHandler).Methods("POST")
 	router.HandleFunc(GetAccountTransactionsHandlerPattern, apiHandler.GetTransactionsByAccountHandler).Methods("GET")
 	router.HandleFunc(GetAccountOperationsHandlerPattern, apiHandler.GetOperationsByAccountHandler).Methods("GET")
 	router.HandleFunc(GetTransactionOperationHandlerPattern, apiHandler.GetOperationsByTxHashOpIndexHandler).Methods("GET")
 	router.HandleFunc(GetTransactionsHandlerPattern, apiHandler.GetTransactionsHandler).Methods("GET")
 	router.HandleFunc(GetTransactionByHashHandlerPattern, apiHandler.GetTransactionByHashHandler).Methods("GET")
+	router.HandleFunc(GetTransactionProofHandlerPattern, apiHandler.GetTransactionProofHandler).Methods("GET")
 	router.HandleFunc(GetTransactionStatusHandlerPattern, apiHandler.GetTransactionStatusByHashHandler).Methods("GET")
 	router.HandleFunc(GetTransactionOperationsHandlerPattern, apiHandler.GetOperationsByTxHandler).Methods("GET")
 	router.HandleFunc(GetBlocksHandlerPattern, apiHandler.GetBlocksHandler).Methods("GET")
 	router.HandleFunc(GetBlockHandlerPattern, apiHandler.GetBlockHandler).Methods("GET")
+	router.HandleFunc(GetCongressProposalsHandlerPattern, apiHandler.GetCongressProposalsHandler).Methods("GET")
+	router.HandleFunc(GetCongressProposalHandlerPattern, apiHandler.GetCongressProposalHandler).Methods("GET")
+	router.HandleFunc(GetCongressResultHandlerPattern, apiHandler.GetCongressResultHandler).Methods("GET")
+	router.HandleFunc(GetValidatorSetsHandlerPattern, apiHandler.GetValidatorSetsHandler).Methods("GET")
+	router.HandleFunc(GetValidatorSetHandlerPattern, apiHandler.GetValidatorSetHandler).Methods("GET")
+	router.HandleFunc(GetEvidencesHandlerPattern, apiHandler.GetEvidencesHandler).Methods("GET")
+	router.HandleFunc(GetEvidenceHandlerPattern, apiHandler.GetEvidenceHandler).Methods("GET")
 	router.HandleFunc(PostSubscribePattern, apiHandler.PostSubscribeHandler).Methods("POST")
 	ts := httptest.NewServer(router)
 	return ts, storage
@@ -0,0 +1,126 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/network/httputils"
+	"boscoin.io/sebak/lib/node/runner/api/resource"
+)
+
+func (api NetworkHandlerAPI) GetCongressProposalsHandler(w http.ResponseWriter, r *http.Request) {
+	p, err := NewPageQuery(r)
+	if err != nil {
+		httputils.WriteJSONError(w, err)
+		return
+	}
+
+	var options = p.ListOptions()
+	var firstCursor []byte
+	var cursor []byte
+
+	latestHeight := block.GetLatestBlock(api.storage).Height
+
+	readFunc := func() []resource.Resource {
+		var rs []resource.Resource
+		iterFunc, closeFunc := block.GetCongressVotings(api.storage, options)
+		for {
+			cv, hasNext, c := iterFunc()
+			if !hasNext {
+				break
+			}
+			cursor = append([]byte{}, c...)
+			if len(firstCursor) == 0 {
+				firstCursor = append(firstCursor, c...)
+			}
+			rs = append(rs, resource.NewCongressProposal(cv, resource.NewCongressProposalState(cv, latestHeight)))
+		}
+		closeFunc()
+		return rs
+	}
+
+	rs := readFunc()
+	list := p.ResourceList(rs, firstCursor, cursor)
+	httputils.MustWriteJSON(w, 200, list)
+}
+
+func (api NetworkHandlerAPI) GetCongressProposalHandler(w http.ResponseWriter, r *http.Request) {
+	cv, err := api.getCongressVoting(mux.Vars(r)["id"])
+	if err != nil {
+		httputils.WriteJSONError(w, err)
+		return
+	}
+
+	state := resource.NewCongressProposalState(cv, block.GetLatestBlock(api.storage).Height)
+	httputils.MustWriteJSON(w, 200, resource.NewCongressProposal(cv, state))
+}
+
+func (api NetworkHandlerAPI) GetCongressResultHandler(w http.ResponseWriter, r *http.Request) {
+	cv, err := api.getCongressVoting(mux.Vars(r)["id"])
+	if err != nil {
+		httputils.WriteJSONError(w, err)
+		return
+	}
+
+	var results []*resource.Operation
+	for _, hash := range cv.Results {
+		var o *resource.Operation
+		if o, err = api.getOperationByHash(hash); err != nil {
+			httputils.WriteJSONError(w, err)
+			return
+		}
+		results = append(results, o)
+	}
+
+	var funding *resource.Operation
+	if len(cv.Funding) > 0 {
+		if funding, err = api.getOperationByHash(cv.Funding); err != nil {
+			httputils.WriteJSONError(w, err)
+			return
+		}
+	}
+
+	state := resource.NewCongressProposalState(cv, block.GetLatestBlock(api.storage).Height)
+	httputils.MustWriteJSON(w, 200, resource.NewCongressResult(cv, state, results, funding))
+}
+
+func (api NetworkHandlerAPI) getCongressVoting(hash string) (cv *block.CongressVoting, err error) {
+	if len(hash) < 1 {
+		return nil, errors.BadRequestParameter
+	}
+
+	var exists bool
+	if exists, err = block.ExistsCongressVoting(api.storage, hash); err != nil {
+		return
+	} else if !exists {
+		return nil, errors.CongressVotingNotFound
+	}
+
+	return block.GetCongressVoting(api.storage, hash)
+}
+
+// getOperationByHash returns the operation by `<transaction hash>-<operation
+// index>`.
+func (api NetworkHandlerAPI) getOperationByHash(hash string) (*resource.Operation, error) {
+	i := strings.LastIndex(hash, "-")
+	if i < 0 {
+		return nil, errors.InvalidOperation
+	}
+
+	opIndex, err := strconv.Atoi(hash[i+1:])
+	if err != nil {
+		return nil, errors.InvalidOperation
+	}
+
+	bo, err := block.GetBlockOperationByIndex(api.storage, hash[:i], opIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	return resource.NewOperation(&bo, opIndex), nil
+}
@@ -0,0 +1,146 @@
+package api
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/storage"
+	"boscoin.io/sebak/lib/transaction"
+	"boscoin.io/sebak/lib/transaction/operation"
+)
+
+func prepareCongressTx(st *storage.LevelDBBackend, kp *keypair.Full, opb operation.Body) (block.Block, transaction.Transaction) {
+	op, err := operation.NewOperation(opb)
+	if err != nil {
+		panic(err)
+	}
+	tx, err := transaction.NewTransaction(kp.Address(), 0, op)
+	if err != nil {
+		panic(err)
+	}
+	tx.Sign(kp, networkID)
+
+	theBlock := block.TestMakeNewBlockWithPrevBlock(block.GetLatestBlock(st), []string{tx.GetHash()})
+	theBlock.MustSave(st)
+	bt := block.NewBlockTransactionFromTransaction(theBlock.Hash, theBlock.Height, theBlock.ProposedTime, tx)
+	bt.MustSave(st)
+	if err := bt.SaveBlockOperations(st); err != nil {
+		panic(err)
+	}
+
+	return theBlock, tx
+}
+
+func TestGetCongressProposalHandler(t *testing.T) {
+	ts, st := prepareAPIServer()
+	defer st.Close()
+	defer ts.Close()
+
+	kp := keypair.Random()
+
+	reqFunc := func(url string) map[string]interface{} {
+		respBody := request(ts, url, false)
+		defer respBody.Close()
+		bs, err := ioutil.ReadAll(bufio.NewReader(respBody))
+		require.NoError(t, err)
+
+		result := make(map[string]interface{})
+		common.MustUnmarshalJSON(bs, &result)
+		return result
+	}
+
+	// the votes can be included from the block 4
+	opb := operation.NewCongressVoting("dummy contract", 4, 5, common.Amount(1000000), kp.Address())
+	blk, tx := prepareCongressTx(st, kp, opb) // 2
+	cv := block.NewCongressVoting(tx.GetHash(), 0, kp.Address(), blk.Height, opb)
+	require.NoError(t, cv.Save(st))
+
+	proposalURL := strings.Replace(GetCongressProposalHandlerPattern, "{id}", cv.Hash, -1)
+	resultURL := strings.Replace(GetCongressResultHandlerPattern, "{id}", cv.Hash, -1)
+
+	{ // pending
+		res := reqFunc(proposalURL)
+		require.Equal(t, cv.Hash, res["hash"])
+		require.Equal(t, "dummy contract", res["contract"])
+		require.Equal(t, "pending", res["state"])
+	}
+
+	{ // list
+		res := reqFunc(GetCongressProposalsHandlerPattern)
+		records := res["_embedded"].(map[string]interface{})["records"].([]interface{})
+		require.Equal(t, 1, len(records))
+		require.Equal(t, cv.Hash, records[0].(map[string]interface{})["hash"])
+	}
+
+	emptyBlock := block.TestMakeNewBlockWithPrevBlock(block.GetLatestBlock(st), []string{})
+	emptyBlock.MustSave(st) // 3
+
+	{ // voting
+		res := reqFunc(proposalURL)
+		require.Equal(t, "voting", res["state"])
+	}
+
+	cv.Tally.Add(operation.VoteYes)
+	cv.Close()
+	require.NoError(t, cv.Save(st))
+
+	resultOpb := operation.NewCongressVotingResult(
+		common.MustMakeObjectHashString("ballots"), []string{"http://www.boscoin.io/1"},
+		common.MustMakeObjectHashString("voters"), []string{"http://www.boscoin.io/2"},
+		common.MustMakeObjectHashString("membership"), []string{"http://www.boscoin.io/3"},
+		1, 1, 0, 0,
+		cv.Hash,
+	)
+	_, resultTx := prepareCongressTx(st, kp, resultOpb) // 4
+	resultHash := fmt.Sprintf("%s-0", resultTx.GetHash())
+	require.NoError(t, cv.AddResult(st, resultHash))
+
+	{ // closed
+		res := reqFunc(proposalURL)
+		require.Equal(t, "closed", res["state"])
+
+		res = reqFunc(resultURL)
+		require.Equal(t, "closed", res["state"])
+		require.Equal(t, true, res["passed"])
+		results := res["_embedded"].(map[string]interface{})["results"].([]interface{})
+		require.Equal(t, 1, len(results))
+		require.Equal(t, resultTx.GetHash(), results[0].(map[string]interface{})["tx_hash"])
+	}
+
+	_, fundingTx := prepareCongressTx(st, kp, operation.NewInflationPF(kp.Address(), common.Amount(1000000), resultHash)) // 5
+	found, err := block.GetCongressVotingByResult(st, resultHash)
+	require.NoError(t, err)
+	found.Funding = fmt.Sprintf("%s-0", fundingTx.GetHash())
+	require.NoError(t, found.Save(st))
+
+	{ // funded
+		res := reqFunc(proposalURL)
+		require.Equal(t, "funded", res["state"])
+		require.Equal(t, found.Funding, res["funding"])
+
+		res = reqFunc(resultURL)
+		require.Equal(t, "funded", res["state"])
+		funding := res["_embedded"].(map[string]interface{})["funding"].(map[string]interface{})
+		require.Equal(t, fundingTx.GetHash(), funding["tx_hash"])
+		require.Equal(t, "inflation-pf", funding["type"])
+	}
+
+	{ // unknown
+		url := strings.Replace(GetCongressProposalHandlerPattern, "{id}", resultHash, -1)
+		req, _ := http.NewRequest("GET", ts.URL+url, nil)
+		resp, err := ts.Client().Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	}
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"boscoin.io/sebak/lib/ballot"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/network/httputils"
+	"boscoin.io/sebak/lib/node/runner/api/resource"
+)
+
+// GetEvidencesHandler returns the evidences of the equivocation, which this
+// node found, by the order of the block height.
+func (api NetworkHandlerAPI) GetEvidencesHandler(w http.ResponseWriter, r *http.Request) {
+	p, err := NewPageQuery(r)
+	if err != nil {
+		httputils.WriteJSONError(w, err)
+		return
+	}
+
+	var options = p.ListOptions()
+	var firstCursor []byte
+	var cursor []byte
+
+	readFunc := func() []resource.Resource {
+		var rs []resource.Resource
+		iterFunc, closeFunc := ballot.GetEvidences(api.storage, options)
+		for {
+			e, hasNext, c := iterFunc()
+			if !hasNext {
+				break
+			}
+			cursor = append([]byte{}, c...)
+			if len(firstCursor) == 0 {
+				firstCursor = append(firstCursor, c...)
+			}
+			rs = append(rs, resource.NewEvidence(e))
+		}
+		closeFunc()
+		return rs
+	}
+
+	rs := readFunc()
+	list := p.ResourceList(rs, firstCursor, cursor)
+	httputils.MustWriteJSON(w, 200, list)
+}
+
+func (api NetworkHandlerAPI) GetEvidenceHandler(w http.ResponseWriter, r *http.Request) {
+	hash := mux.Vars(r)["id"]
+
+	exists, err := ballot.ExistsEvidence(api.storage, hash)
+	if err != nil {
+		httputils.WriteJSONError(w, err)
+		return
+	} else if !exists {
+		httputils.WriteJSONError(w, errors.EvidenceNotFound)
+		return
+	}
+
+	e, err := ballot.GetEvidence(api.storage, hash)
+	if err != nil {
+		httputils.WriteJSONError(w, err)
+		return
+	}
+
+	httputils.MustWriteJSON(w, 200, resource.NewEvidence(e))
+}
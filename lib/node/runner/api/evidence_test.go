@@ -0,0 +1,79 @@
+package api
+
+import (
+	"bufio"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/ballot"
+	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/voting"
+)
+
+func TestGetEvidenceHandler(t *testing.T) {
+	ts, st := prepareAPIServer()
+	defer st.Close()
+	defer ts.Close()
+
+	reqFunc := func(url string) map[string]interface{} {
+		respBody := request(ts, url, false)
+		defer respBody.Close()
+		bs, err := ioutil.ReadAll(bufio.NewReader(respBody))
+		require.NoError(t, err)
+
+		result := make(map[string]interface{})
+		common.MustUnmarshalJSON(bs, &result)
+		return result
+	}
+
+	kp := keypair.Random()
+	latest := block.GetLatestBlock(st)
+	basis := voting.Basis{Round: 0, Height: latest.Height, BlockHash: latest.Hash, TotalTxs: latest.TotalTxs}
+
+	newBallot := func(txs []string) ballot.Ballot {
+		b := ballot.NewBallot(kp.Address(), kp.Address(), basis, txs)
+		opc, _ := ballot.NewCollectTxFeeFromBallot(*b, block.CommonKP.Address())
+		opi, _ := ballot.NewInflationFromBallot(*b, block.CommonKP.Address(), common.Amount(1))
+		ptx, _ := ballot.NewProposerTransactionFromBallot(*b, opc, opi)
+		b.SetProposerTransaction(ptx)
+		b.SetVote(ballot.StateSIGN, voting.YES)
+		b.Sign(kp, networkID)
+		return *b
+	}
+
+	e, err := ballot.NewEvidence(newBallot([]string{"tx0"}), newBallot([]string{"tx1"}))
+	require.NoError(t, err)
+	require.NoError(t, e.Save(st))
+
+	{ // list
+		res := reqFunc(GetEvidencesHandlerPattern)
+		records := res["_embedded"].(map[string]interface{})["records"].([]interface{})
+		require.Equal(t, 1, len(records))
+		record := records[0].(map[string]interface{})
+		require.Equal(t, e.Hash, record["hash"])
+		require.Equal(t, kp.Address(), record["address"])
+		require.Equal(t, "SIGN", record["state"])
+		require.Equal(t, 2, len(record["ballots"].([]interface{})))
+	}
+
+	{ // evidence
+		res := reqFunc(strings.Replace(GetEvidenceHandlerPattern, "{id}", e.Hash, -1))
+		require.Equal(t, e.Hash, res["hash"])
+	}
+
+	{ // unknown
+		url := strings.Replace(GetEvidenceHandlerPattern, "{id}", "unknown", -1)
+		req, _ := http.NewRequest("GET", ts.URL+url, nil)
+		resp, err := ts.Client().Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	}
+}
@@ -3,6 +3,7 @@ package api
 import (
 	"net/http"
 
+	"boscoin.io/sebak/lib/block"
 	"boscoin.io/sebak/lib/common"
 	"boscoin.io/sebak/lib/node"
 )
@@ -21,6 +22,20 @@ func (api NetworkHandlerAPI) GetNodeInfoHandler(w http.ResponseWriter, r *http.R
 		nodeInfo.Node.Endpoint = common.NewEndpointFromURL(rUrl)
 	}
 
+	if api.storage != nil {
+		var validators []string
+		for address := range nodeInfo.Node.Validators {
+			validators = append(validators, address)
+		}
+
+		delegated, err := block.GetDelegatedAmounts(api.storage, validators...)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		nodeInfo.Node.Delegated = delegated
+	}
+
 	if api.GetLatestBlock != nil {
 		latestBlock := api.GetLatestBlock()
 		nodeInfo.Block = node.NodeBlockInfo{
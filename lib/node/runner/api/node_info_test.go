@@ -106,10 +106,28 @@ func TestAPIGetNodeInfoHandler(t *testing.T) {
 	require.Equal(t, latestBlock.TotalTxs, receivedNodeInfo.Block.TotalTxs)
 	require.Equal(t, latestBlock.TotalOps, receivedNodeInfo.Block.TotalOps)
 
+	require.Equal(t, common.Amount(0), receivedNodeInfo.Node.Delegated[localNode.Address()])
+
 	js, _ := json.Marshal(policy)
 	rjs, _ := json.Marshal(receivedNodeInfo.Policy)
 	require.Equal(t, js, rjs)
 
+	{ // delegated to the validator
+		kpFrozen := keypair.Random()
+		block.NewBlockAccountLinked(kpFrozen.Address(), common.Unit, keypair.Random().Address()).MustSave(st)
+		d := block.NewDelegation(kpFrozen.Address(), localNode.Address(), common.MustMakeObjectHashString("tx"), 2)
+		require.NoError(t, d.Save(st))
+
+		body := request(ts, GetNodeInfoPattern, false)
+		data, err := ioutil.ReadAll(bufio.NewReader(body))
+		body.Close()
+		require.NoError(t, err)
+
+		receivedNodeInfo, err := node.NewNodeInfoFromJSON(data)
+		require.NoError(t, err)
+		require.Equal(t, common.Unit, receivedNodeInfo.Node.Delegated[localNode.Address()])
+	}
+
 	// udpate localNode state
 	localNode.SetBooting()
 
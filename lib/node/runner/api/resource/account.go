@@ -25,6 +25,8 @@ func (a Account) GetMap() hal.Entry {
 		"sequence_id": a.ba.SequenceID,
 		"balance":     a.ba.Balance,
 		"linked":      a.ba.Linked,
+		"signers":     a.ba.Signers,
+		"thresholds":  a.ba.Thresholds,
 	}
 }
 
@@ -33,8 +35,9 @@ func (a Account) Resource() *hal.Resource {
 	accountID := a.ba.Address
 
 	r := hal.NewResource(a, a.LinkSelf())
-	r.AddLink("transactions", hal.NewLink(strings.Replace(URLAccountTransactions, "{id}", address, -1)+"{?cursor,limit,order}", hal.LinkAttr{"templated": true}))
+	r.AddLink("transactions", hal.NewLink(strings.Replace(URLAccountTransactions, "{id}", address, -1)+"{?cursor,limit,order,memo,memo_type}", hal.LinkAttr{"templated": true}))
 	r.AddLink("operations", hal.NewLink(strings.Replace(URLAccountOperations, "{id}", accountID, -1)+"{?cursor,limit,order}", hal.LinkAttr{"templated": true}))
+	r.AddLink("proof", hal.NewLink(strings.Replace(URLAccountProof, "{id}", accountID, -1)+"{?height}", hal.LinkAttr{"templated": true}))
 	return r
 }
 
@@ -0,0 +1,47 @@
+package resource
+
+import (
+	"strings"
+
+	"github.com/nvellon/hal"
+
+	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/storage/statedb/trie"
+)
+
+type AccountProof struct {
+	address string
+	b       *block.Block
+	proof   trie.ProofList
+}
+
+// NewAccountProof makes the resource of proof against `Header.StateRoot` of
+// block.
+func NewAccountProof(address string, b *block.Block, proof trie.ProofList) *AccountProof {
+	return &AccountProof{
+		address: address,
+		b:       b,
+		proof:   proof,
+	}
+}
+
+func (ap AccountProof) GetMap() hal.Entry {
+	return hal.Entry{
+		"address":      ap.address,
+		"block_hash":   ap.b.Hash,
+		"block_height": ap.b.Height,
+		"state_root":   ap.b.StateRoot,
+		"proof":        ap.proof,
+	}
+}
+
+func (ap AccountProof) Resource() *hal.Resource {
+	r := hal.NewResource(ap, ap.LinkSelf())
+	r.AddLink("account", hal.NewLink(strings.Replace(URLAccounts, "{id}", ap.address, -1)))
+	r.AddLink("block", hal.NewLink(strings.Replace(URLBlocks, "{id}", ap.b.Hash, -1)))
+	return r
+}
+
+func (ap AccountProof) LinkSelf() string {
+	return strings.Replace(URLAccountProof, "{id}", ap.address, -1)
+}
@@ -26,6 +26,7 @@ func (blk Block) GetMap() hal.Entry {
 		"height":               b.Height,
 		"prev_block_hash":      b.PrevBlockHash,
 		"transactions_root":    b.TransactionsRoot,
+		"state_root":           b.StateRoot,
 		"confirmed":            b.Confirmed,
 		"proposer":             b.Proposer,
 		"proposed_time":        b.ProposedTime,
@@ -0,0 +1,136 @@
+package resource
+
+import (
+	"strings"
+
+	"github.com/nvellon/hal"
+
+	"boscoin.io/sebak/lib/block"
+)
+
+type CongressProposalState string
+
+const (
+	PendingProposalState CongressProposalState = "pending"
+	VotingProposalState  CongressProposalState = "voting"
+	ClosedProposalState  CongressProposalState = "closed"
+	FundedProposalState  CongressProposalState = "funded"
+)
+
+// NewCongressProposalState decides the state of the congress voting at the
+// latest block height; the votes can be included from the next block.
+func NewCongressProposalState(cv *block.CongressVoting, latestHeight uint64) CongressProposalState {
+	switch {
+	case len(cv.Funding) > 0:
+		return FundedProposalState
+	case cv.Closed || latestHeight >= cv.Proposal.Voting.End:
+		return ClosedProposalState
+	case latestHeight+1 >= cv.Proposal.Voting.Start:
+		return VotingProposalState
+	default:
+		return PendingProposalState
+	}
+}
+
+type CongressProposal struct {
+	cv    *block.CongressVoting
+	state CongressProposalState
+}
+
+func NewCongressProposal(cv *block.CongressVoting, state CongressProposalState) *CongressProposal {
+	return &CongressProposal{
+		cv:    cv,
+		state: state,
+	}
+}
+
+func (cp CongressProposal) GetMap() hal.Entry {
+	return hal.Entry{
+		"hash":            cp.cv.Hash,
+		"tx_hash":         cp.cv.TxHash,
+		"source":          cp.cv.Source,
+		"block_height":    cp.cv.Height,
+		"contract":        cp.cv.Proposal.Contract,
+		"voting":          cp.cv.Proposal.Voting,
+		"funding_address": cp.cv.Proposal.FundingAddress,
+		"amount":          cp.cv.Proposal.Amount,
+		"state":           cp.state,
+		"funding":         cp.cv.Funding,
+	}
+}
+
+func (cp CongressProposal) Resource() *hal.Resource {
+	r := hal.NewResource(cp, cp.LinkSelf())
+	r.AddLink("result", hal.NewLink(strings.Replace(URLCongressResult, "{id}", cp.cv.Hash, -1)))
+	r.AddLink("operation", hal.NewLink(LinkOperationByHash(cp.cv.Hash)))
+	if len(cp.cv.Funding) > 0 {
+		r.AddLink("funding", hal.NewLink(LinkOperationByHash(cp.cv.Funding)))
+	}
+	return r
+}
+
+func (cp CongressProposal) LinkSelf() string {
+	return strings.Replace(URLCongressProposal, "{id}", cp.cv.Hash, -1)
+}
+
+// CongressResult shows the tally of congress voting with the
+// `operation.CongressVotingResult`s and the `operation.InflationPF`, which
+// funds it.
+type CongressResult struct {
+	cv      *block.CongressVoting
+	state   CongressProposalState
+	results []*Operation
+	funding *Operation
+}
+
+func NewCongressResult(cv *block.CongressVoting, state CongressProposalState, results []*Operation, funding *Operation) *CongressResult {
+	return &CongressResult{
+		cv:      cv,
+		state:   state,
+		results: results,
+		funding: funding,
+	}
+}
+
+func (cr CongressResult) GetMap() hal.Entry {
+	return hal.Entry{
+		"hash":   cr.cv.Hash,
+		"state":  cr.state,
+		"tally":  cr.cv.Tally,
+		"closed": cr.cv.Closed,
+		"passed": cr.cv.Passed,
+	}
+}
+
+func (cr CongressResult) Resource() *hal.Resource {
+	r := hal.NewResource(cr, cr.LinkSelf())
+	r.AddLink("proposal", hal.NewLink(strings.Replace(URLCongressProposal, "{id}", cr.cv.Hash, -1)))
+
+	var rCollection hal.ResourceCollection
+	for _, o := range cr.results {
+		rCollection = append(rCollection, o.Resource())
+	}
+	r.EmbedCollection("results", rCollection)
+
+	if cr.funding != nil {
+		r.AddLink("funding", hal.NewLink(cr.funding.LinkSelf()))
+		r.Embed("funding", cr.funding.Resource())
+	}
+	return r
+}
+
+func (cr CongressResult) LinkSelf() string {
+	return strings.Replace(URLCongressResult, "{id}", cr.cv.Hash, -1)
+}
+
+// LinkOperationByHash returns the link of operation from
+// `<transaction hash>-<operation index>`.
+func LinkOperationByHash(hash string) string {
+	i := strings.LastIndex(hash, "-")
+	if i < 0 {
+		return strings.Replace(URLTransactionByHash, "{id}", hash, -1)
+	}
+
+	self := strings.Replace(URLTransactionOperation, "{id}", hash[:i], -1)
+	return strings.Replace(self, "{opindex}", hash[i+1:], -1)
+}
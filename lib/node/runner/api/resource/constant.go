@@ -8,12 +8,21 @@ const (
 	URLAccountTransactions   = APIPrefix + APIVersionV1 + "/accounts/{id}/transactions"
 	URLAccountOperations     = APIPrefix + APIVersionV1 + "/accounts/{id}/operations"
 	URLAccountFrozenAccounts = APIPrefix + APIVersionV1 + "/accounts/{id}/frozen-accounts"
+	URLAccountProof          = APIPrefix + APIVersionV1 + "/accounts/{id}/proof"
 	URLFrozenAccounts        = APIPrefix + APIVersionV1 + "/frozen-accounts"
 	URLTransactions          = APIPrefix + APIVersionV1 + "/transactions"
 	URLTransactionByHash     = APIPrefix + APIVersionV1 + "/transactions/{id}"
 	URLTransactionOperations = APIPrefix + APIVersionV1 + "/transactions/{id}/operations"
 	URLTransactionOperation  = APIPrefix + APIVersionV1 + "/transactions/{id}/operations/{opindex}"
 	URLTransactionStatus     = APIPrefix + APIVersionV1 + "/transactions/{id}/status"
+	URLTransactionProof      = APIPrefix + APIVersionV1 + "/transactions/{id}/proof"
 	URLOperations            = APIPrefix + APIVersionV1 + "/operations/{id}"
 	URLBlocks                = APIPrefix + APIVersionV1 + "/blocks/{id}"
+	URLCongressProposals     = APIPrefix + APIVersionV1 + "/congress/proposals"
+	URLCongressProposal      = APIPrefix + APIVersionV1 + "/congress/proposals/{id}"
+	URLCongressResult        = APIPrefix + APIVersionV1 + "/congress/proposals/{id}/result"
+	URLValidatorSets         = APIPrefix + APIVersionV1 + "/validators"
+	URLValidatorSet          = APIPrefix + APIVersionV1 + "/validators/{height}"
+	URLEvidences             = APIPrefix + APIVersionV1 + "/evidence"
+	URLEvidence              = APIPrefix + APIVersionV1 + "/evidence/{id}"
 )
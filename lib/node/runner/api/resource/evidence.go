@@ -0,0 +1,36 @@
+package resource
+
+import (
+	"strings"
+
+	"github.com/nvellon/hal"
+
+	"boscoin.io/sebak/lib/ballot"
+)
+
+type Evidence struct {
+	e *ballot.Evidence
+}
+
+func NewEvidence(e *ballot.Evidence) *Evidence {
+	return &Evidence{e: e}
+}
+
+func (e Evidence) GetMap() hal.Entry {
+	return hal.Entry{
+		"hash":     e.e.Hash,
+		"address":  e.e.Address,
+		"basis":    e.e.Basis,
+		"state":    e.e.State.String(),
+		"ballots":  e.e.Ballots,
+		"detected": e.e.Detected,
+	}
+}
+
+func (e Evidence) Resource() *hal.Resource {
+	return hal.NewResource(e, e.LinkSelf())
+}
+
+func (e Evidence) LinkSelf() string {
+	return strings.Replace(URLEvidence, "{id}", e.e.Hash, -1)
+}
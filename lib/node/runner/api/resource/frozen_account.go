@@ -39,9 +39,20 @@ type FrozenAccountInfo struct {
 	UnfreezingRequestBlockHeight uint64
 	UnfreezingRequestOpHash      string
 	UnfreezingRemainingBlocks    uint64
+	UnfreezingRequests           []UnfreezingRequestInfo
 	PaymentOpHash                string
 }
 
+// UnfreezingRequestInfo is the unfreezing request of frozen account; if
+// `Amount` is zero, the whole balance is requested.
+type UnfreezingRequestInfo struct {
+	OpHash          string        `json:"op_hash"`
+	BlockHeight     uint64        `json:"block_height"`
+	Amount          common.Amount `json:"amount"`
+	Withdrawn       common.Amount `json:"withdrawn"`
+	RemainingBlocks uint64        `json:"remaining_blocks"`
+}
+
 func (fa FrozenAccount) GetMap() hal.Entry {
 	return hal.Entry{
 		"address":                     fa.ba.Address,
@@ -54,6 +65,7 @@ func (fa FrozenAccount) GetMap() hal.Entry {
 		"unfreezing_block_height":     fa.info.UnfreezingRequestBlockHeight,
 		"unfreezing_op_hash":          fa.info.UnfreezingRequestOpHash,
 		"unfreezing_remaining_blocks": fa.info.UnfreezingRemainingBlocks,
+		"unfreezing_requests":         fa.info.UnfreezingRequests,
 		"payment_op_hash":             fa.info.PaymentOpHash,
 	}
 }
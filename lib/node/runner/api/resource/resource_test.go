@@ -50,7 +50,7 @@ func TestResourceAccount(t *testing.T) {
 		bt := block.NewBlockTransactionFromTransaction("dummy", 0, common.NowISO8601(), tx)
 		bt.MustSave(storage)
 
-		rt := NewTransaction(&bt, tx)
+		rt := NewTransaction(&bt, tx, false)
 		r := rt.Resource()
 		j, _ := json.MarshalIndent(r, "", " ")
 
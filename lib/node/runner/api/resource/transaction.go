@@ -10,18 +10,23 @@ import (
 type Transaction struct {
 	bt *block.BlockTransaction
 	tx transaction.Transaction
+
+	// pruned is true when the body of transaction is pruned; the memo and
+	// time bounds are not served.
+	pruned bool
 }
 
-func NewTransaction(bt *block.BlockTransaction, tx transaction.Transaction) *Transaction {
+func NewTransaction(bt *block.BlockTransaction, tx transaction.Transaction, pruned bool) *Transaction {
 	t := &Transaction{
-		bt: bt,
-		tx: tx,
+		bt:     bt,
+		tx:     tx,
+		pruned: pruned,
 	}
 	return t
 }
 
 func (t Transaction) GetMap() hal.Entry {
-	return hal.Entry{
+	entry := hal.Entry{
 		"hash":            t.bt.Hash,
 		"block":           t.bt.Block,
 		"source":          t.bt.Source,
@@ -31,6 +36,17 @@ func (t Transaction) GetMap() hal.Entry {
 		"operation_count": len(t.bt.Operations),
 		"operations":      t.tx.B.Operations,
 	}
+	if t.tx.B.TimeBounds != nil {
+		entry["time_bounds"] = t.tx.B.TimeBounds
+	}
+	if t.tx.B.Memo != nil {
+		entry["memo"] = t.tx.B.Memo
+	}
+	if t.pruned {
+		entry["pruned"] = true
+	}
+
+	return entry
 }
 func (t Transaction) Resource() *hal.Resource {
 
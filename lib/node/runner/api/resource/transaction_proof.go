@@ -0,0 +1,45 @@
+package resource
+
+import (
+	"strings"
+
+	"github.com/nvellon/hal"
+
+	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/common"
+)
+
+type TransactionProof struct {
+	hash  string
+	b     *block.Block
+	proof []common.MerkleProofNode
+}
+
+func NewTransactionProof(hash string, b *block.Block, proof []common.MerkleProofNode) *TransactionProof {
+	return &TransactionProof{
+		hash:  hash,
+		b:     b,
+		proof: proof,
+	}
+}
+
+func (tp TransactionProof) GetMap() hal.Entry {
+	return hal.Entry{
+		"hash":              tp.hash,
+		"block_hash":        tp.b.Hash,
+		"block_height":      tp.b.Height,
+		"transactions_root": tp.b.TransactionsRoot,
+		"proof":             tp.proof,
+	}
+}
+
+func (tp TransactionProof) Resource() *hal.Resource {
+	r := hal.NewResource(tp, tp.LinkSelf())
+	r.AddLink("transaction", hal.NewLink(strings.Replace(URLTransactionByHash, "{id}", tp.hash, -1)))
+	r.AddLink("block", hal.NewLink(strings.Replace(URLBlocks, "{id}", tp.b.Hash, -1)))
+	return r
+}
+
+func (tp TransactionProof) LinkSelf() string {
+	return strings.Replace(URLTransactionProof, "{id}", tp.hash, -1)
+}
@@ -0,0 +1,33 @@
+package resource
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/nvellon/hal"
+
+	"boscoin.io/sebak/lib/block"
+)
+
+type ValidatorSet struct {
+	vs block.ValidatorSet
+}
+
+func NewValidatorSet(vs block.ValidatorSet) *ValidatorSet {
+	return &ValidatorSet{vs: vs}
+}
+
+func (v ValidatorSet) GetMap() hal.Entry {
+	return hal.Entry{
+		"height":     v.vs.Height,
+		"validators": v.vs.Validators,
+	}
+}
+
+func (v ValidatorSet) Resource() *hal.Resource {
+	return hal.NewResource(v, v.LinkSelf())
+}
+
+func (v ValidatorSet) LinkSelf() string {
+	return strings.Replace(URLValidatorSet, "{height}", strconv.FormatUint(v.vs.Height, 10), -1)
+}
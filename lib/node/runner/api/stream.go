@@ -57,11 +57,11 @@ func (api NetworkHandlerAPI) PostSubscribeHandler(w http.ResponseWriter, r *http
 			r := resource.NewAccount(v)
 			return json.Marshal(r.Resource())
 		case *block.BlockTransaction:
-			tp, err := block.GetTransactionPool(api.storage, v.Hash)
+			tx, pruned, err := block.GetTransactionInBlock(api.storage, v.Hash)
 			if err != nil {
 				return nil, err
 			}
-			r := resource.NewTransaction(v, tp.Transaction())
+			r := resource.NewTransaction(v, tx, pruned)
 			return json.Marshal(r.Resource())
 		}
 
@@ -8,10 +8,12 @@ import (
 	"github.com/gorilla/mux"
 
 	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/common"
 	o "boscoin.io/sebak/lib/common/observer"
 	"boscoin.io/sebak/lib/errors"
 	"boscoin.io/sebak/lib/network/httputils"
 	"boscoin.io/sebak/lib/node/runner/api/resource"
+	"boscoin.io/sebak/lib/transaction"
 )
 
 func (api NetworkHandlerAPI) GetTransactionsHandler(w http.ResponseWriter, r *http.Request) {
@@ -35,12 +37,12 @@ func (api NetworkHandlerAPI) GetTransactionsHandler(w http.ResponseWriter, r *ht
 		if len(firstCursor) == 0 {
 			firstCursor = append(firstCursor, c...)
 		}
-		tp, err := block.GetTransactionPool(api.storage, t.Hash)
+		tx, pruned, err := block.GetTransactionInBlock(api.storage, t.Hash)
 		if err != nil {
 			httputils.WriteJSONError(w, err)
 			return
 		}
-		txs = append(txs, resource.NewTransaction(&t, tp.Transaction()))
+		txs = append(txs, resource.NewTransaction(&t, tx, pruned))
 	}
 	closeFunc()
 
@@ -66,16 +68,50 @@ func (api NetworkHandlerAPI) GetTransactionByHashHandler(w http.ResponseWriter,
 		httputils.WriteJSONError(w, err)
 		return
 	}
-	tp, err := block.GetTransactionPool(api.storage, bt.Hash)
+	body, pruned, err := block.GetTransactionInBlock(api.storage, bt.Hash)
 	if err != nil {
 		httputils.WriteJSONError(w, err)
 		return
 	}
-	tx := resource.NewTransaction(&bt, tp.Transaction())
+	tx := resource.NewTransaction(&bt, body, pruned)
 
 	httputils.MustWriteJSON(w, 200, tx)
 }
 
+// GetTransactionProofHandler returns the inclusion path of transaction to the
+// transactions root of the block which includes the transaction.
+func (api NetworkHandlerAPI) GetTransactionProofHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	key := vars["id"]
+
+	found, err := block.ExistsBlockTransaction(api.storage, key)
+	if err != nil {
+		httputils.WriteJSONError(w, err)
+		return
+	}
+	if !found {
+		httputils.WriteJSONError(w, errors.BlockTransactionDoesNotExists)
+		return
+	}
+	bt, err := block.GetBlockTransaction(api.storage, key)
+	if err != nil {
+		httputils.WriteJSONError(w, err)
+		return
+	}
+	blk, err := block.GetBlock(api.storage, bt.Block)
+	if err != nil {
+		httputils.WriteJSONError(w, err)
+		return
+	}
+	proof, err := blk.TransactionProof(bt.Hash)
+	if err != nil {
+		httputils.WriteJSONError(w, err)
+		return
+	}
+
+	httputils.MustWriteJSON(w, 200, resource.NewTransactionProof(bt.Hash, &blk, proof))
+}
+
 func (api NetworkHandlerAPI) GetTransactionsByAccountHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	address := vars["id"]
@@ -90,7 +126,23 @@ func (api NetworkHandlerAPI) GetTransactionsByAccountHandler(w http.ResponseWrit
 	var firstCursor []byte
 	var cursor []byte
 	var txs []resource.Resource
-	iterFunc, closeFunc := block.GetBlockTransactionsByAccount(api.storage, address, options)
+
+	var iterFunc func() (block.BlockTransaction, bool, []byte)
+	var closeFunc func()
+	if value := r.URL.Query().Get("memo"); len(value) > 0 {
+		// without `memo_type`, the memo is text memo
+		memo := transaction.Memo{Type: transaction.MemoText, Value: value}
+		if memoType := r.URL.Query().Get("memo_type"); len(memoType) > 0 {
+			memo.Type = transaction.MemoType(memoType)
+		}
+		if err := memo.IsWellFormed(); err != nil {
+			httputils.WriteJSONError(w, errors.BadRequestParameter.Clone().SetData("error", err.Error()))
+			return
+		}
+		iterFunc, closeFunc = block.GetBlockTransactionsByAccountMemo(api.storage, address, memo, options)
+	} else {
+		iterFunc, closeFunc = block.GetBlockTransactionsByAccount(api.storage, address, options)
+	}
 	for {
 		t, hasNext, c := iterFunc()
 		if !hasNext {
@@ -100,12 +152,12 @@ func (api NetworkHandlerAPI) GetTransactionsByAccountHandler(w http.ResponseWrit
 		if len(firstCursor) == 0 {
 			firstCursor = append(firstCursor, c...)
 		}
-		tp, err := block.GetTransactionPool(api.storage, t.Hash)
+		tx, pruned, err := block.GetTransactionInBlock(api.storage, t.Hash)
 		if err != nil {
 			httputils.WriteJSONError(w, err)
 			return
 		}
-		txs = append(txs, resource.NewTransaction(&t, tp.Transaction()))
+		txs = append(txs, resource.NewTransaction(&t, tx, pruned))
 	}
 	closeFunc()
 	list := p.ResourceList(txs, firstCursor, cursor)
@@ -117,8 +169,11 @@ func (api NetworkHandlerAPI) GetTransactionStatusByHashHandler(w http.ResponseWr
 	key := vars["id"]
 
 	status := "notfound"
-	if found, _ := block.ExistsTransactionPool(api.storage, key); found {
+	if tp, err := block.GetTransactionPool(api.storage, key); err == nil {
 		status = "submitted"
+		if api.isExpiredTransaction(tp.Transaction()) {
+			status = "expired"
+		}
 	}
 	if found, _ := block.ExistsBlockTransaction(api.storage, key); found {
 		status = "confirmed"
@@ -126,7 +181,7 @@ func (api NetworkHandlerAPI) GetTransactionStatusByHashHandler(w http.ResponseWr
 
 	payload := resource.NewTransactionStatus(key, status)
 
-	if httputils.IsEventStream(r) && status != "confirmed" {
+	if httputils.IsEventStream(r) && status != "confirmed" && status != "expired" {
 
 		txStatusRenderFunc := func(args ...interface{}) ([]byte, error) {
 			if len(args) <= 1 {
@@ -166,3 +221,19 @@ func (api NetworkHandlerAPI) GetTransactionStatusByHashHandler(w http.ResponseWr
 		httputils.MustWriteJSON(w, 200, payload)
 	}
 }
+
+// isExpiredTransaction checks the submitted transaction can not be included in
+// the next blocks by its time bounds.
+func (api NetworkHandlerAPI) isExpiredTransaction(tx transaction.Transaction) bool {
+	if tx.B.TimeBounds == nil {
+		return false
+	}
+
+	latest := block.GetLatestBlock(api.storage)
+	latestTime, err := common.ParseISO8601(latest.ProposedTime)
+	if err != nil {
+		return false
+	}
+
+	return tx.IsExpired(latest.Height+1, latestTime)
+}
@@ -11,7 +11,9 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
 	"boscoin.io/sebak/lib/node/runner/api/resource"
+	"boscoin.io/sebak/lib/transaction"
 )
 
 func TestGetTransactionByHashHandler(t *testing.T) {
@@ -49,6 +51,71 @@ func TestGetTransactionByHashHandler(t *testing.T) {
 
 		require.Equal(t, bt.Hash, recv["hash"], "hash is not the same")
 		require.Equal(t, bt.Block, recv["block"], "block is not the same")
+		require.Nil(t, recv["pruned"])
+	}
+}
+
+// TestGetTransactionByHashHandlerPruned checks the transaction, whose
+// `TransactionPool` is pruned, has the operations from the `BlockOperation`s
+// and it is marked as pruned.
+func TestGetTransactionByHashHandlerPruned(t *testing.T) {
+	ts, storage := prepareAPIServer()
+	defer storage.Close()
+	defer ts.Close()
+
+	_, tx, bt := prepareTxWithoutSave(storage)
+	bt.MustSave(storage)
+	require.NoError(t, bt.SaveBlockOperations(storage))
+
+	respBody := request(ts, GetTransactionsHandlerPattern+"/"+bt.Hash, false)
+	defer respBody.Close()
+	readByte, err := ioutil.ReadAll(respBody)
+	require.NoError(t, err)
+
+	recv := make(map[string]interface{})
+	common.MustUnmarshalJSON(readByte, &recv)
+	require.Equal(t, bt.Hash, recv["hash"])
+	require.Equal(t, len(tx.B.Operations), len(recv["operations"].([]interface{})))
+	require.Equal(t, true, recv["pruned"])
+}
+
+func TestGetTransactionProofHandler(t *testing.T) {
+	ts, storage := prepareAPIServer()
+	defer storage.Close()
+	defer ts.Close()
+
+	_, _, btList := prepareTxs(storage, 5)
+
+	{ // unknown transaction
+		url := strings.Replace(GetTransactionProofHandlerPattern, "{id}", "findme", -1)
+		resp, err := ts.Client().Get(ts.URL + url)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	}
+
+	for _, bt := range btList {
+		url := strings.Replace(GetTransactionProofHandlerPattern, "{id}", bt.Hash, -1)
+		respBody := request(ts, url, false)
+		readByte, err := ioutil.ReadAll(respBody)
+		respBody.Close()
+		require.NoError(t, err)
+
+		var recv struct {
+			Hash             string                   `json:"hash"`
+			BlockHash        string                   `json:"block_hash"`
+			TransactionsRoot string                   `json:"transactions_root"`
+			Proof            []common.MerkleProofNode `json:"proof"`
+		}
+		common.MustUnmarshalJSON(readByte, &recv)
+		require.Equal(t, bt.Hash, recv.Hash)
+		require.Equal(t, bt.Block, recv.BlockHash)
+
+		blk, err := block.GetBlock(storage, bt.Block)
+		require.NoError(t, err)
+		require.Equal(t, blk.TransactionsRoot, recv.TransactionsRoot)
+		require.True(t, block.VerifyTransactionProof(blk.TransactionsRoot, bt.Hash, recv.Proof))
 	}
 }
 
@@ -94,6 +161,33 @@ func TestGetTransactionStatusByHashHandler(t *testing.T) {
 	}
 }
 
+func TestGetTransactionStatusExpired(t *testing.T) {
+	ts, storage := prepareAPIServer()
+	defer storage.Close()
+	defer ts.Close()
+
+	latest := block.GetLatestBlock(storage)
+	getStatus := func(tb transaction.TimeBounds) string {
+		kp, tx, _ := prepareTxWithoutSave(storage)
+		tx.B.TimeBounds = &tb
+		tx.Sign(kp, networkID)
+		block.SaveTransactionPool(storage, *tx)
+
+		respBody := request(ts, strings.Replace(GetTransactionStatusHandlerPattern, "{id}", tx.GetHash(), -1), false)
+		defer respBody.Close()
+		readByte, err := ioutil.ReadAll(bufio.NewReader(respBody))
+		require.NoError(t, err)
+		var status resource.TransactionStatus
+		common.MustUnmarshalJSON(readByte, &status)
+		require.Equal(t, tx.GetHash(), status.Hash)
+
+		return status.Status
+	}
+
+	require.Equal(t, "submitted", getStatus(transaction.TimeBounds{MaxHeight: latest.Height + 1}))
+	require.Equal(t, "expired", getStatus(transaction.TimeBounds{MaxHeight: latest.Height}))
+}
+
 func TestGetTransactionsHandler(t *testing.T) {
 	ts, storage := prepareAPIServer()
 	defer storage.Close()
@@ -169,6 +263,62 @@ func TestGetTransactionsByAccountHandler(t *testing.T) {
 	}
 }
 
+func TestGetTransactionsByAccountHandlerWithMemo(t *testing.T) {
+	ts, storage := prepareAPIServer()
+	defer storage.Close()
+	defer ts.Close()
+
+	kp := keypair.Random()
+	var txs []transaction.Transaction
+	var txHashes []string
+	for _, memo := range []string{"deposit-1", "deposit-2", "deposit-1"} {
+		tx := transaction.TestMakeTransactionWithKeypair(networkID, 1, kp)
+		tx.B.Memo = transaction.NewMemo(transaction.MemoText, memo)
+		tx.Sign(kp, networkID)
+		txs = append(txs, tx)
+		txHashes = append(txHashes, tx.GetHash())
+	}
+
+	theBlock := block.TestMakeNewBlockWithPrevBlock(block.GetLatestBlock(storage), txHashes)
+	theBlock.MustSave(storage)
+	for _, tx := range txs {
+		bt := block.NewBlockTransactionFromTransaction(theBlock.Hash, theBlock.Height, theBlock.ProposedTime, tx)
+		bt.MustSave(storage)
+		block.SaveTransactionPool(storage, tx)
+	}
+
+	url := strings.Replace(GetAccountTransactionsHandlerPattern, "{id}", kp.Address(), -1) + "?memo=deposit-1"
+	respBody := request(ts, url, false)
+	defer respBody.Close()
+	readByte, err := ioutil.ReadAll(bufio.NewReader(respBody))
+	require.NoError(t, err)
+
+	recv := make(map[string]interface{})
+	common.MustUnmarshalJSON(readByte, &recv)
+	records := recv["_embedded"].(map[string]interface{})["records"].([]interface{})
+
+	require.Equal(t, 2, len(records))
+	for i, index := range []int{0, 2} {
+		bt := records[i].(map[string]interface{})
+		require.Equal(t, txHashes[index], bt["hash"].(string))
+
+		memo := bt["memo"].(map[string]interface{})
+		require.Equal(t, string(transaction.MemoText), memo["type"])
+		require.Equal(t, "deposit-1", memo["value"])
+	}
+
+	{ // the value is not well-formed for the memo type
+		respBody := request(ts, url+"&memo_type=hash", false)
+		defer respBody.Close()
+		readByte, err := ioutil.ReadAll(bufio.NewReader(respBody))
+		require.NoError(t, err)
+
+		recv := make(map[string]interface{})
+		common.MustUnmarshalJSON(readByte, &recv)
+		require.Equal(t, http.StatusBadRequest, int(recv["status"].(float64)))
+	}
+}
+
 func TestGetTransactionsHandlerPage(t *testing.T) {
 	ts, storage := prepareAPIServer()
 	defer storage.Close()
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/network/httputils"
+	"boscoin.io/sebak/lib/node/runner/api/resource"
+)
+
+// GetValidatorSetsHandler returns the history of validator set; the validator
+// set is listed for each height, which it is changed.
+func (api NetworkHandlerAPI) GetValidatorSetsHandler(w http.ResponseWriter, r *http.Request) {
+	history, err := block.GetValidatorSetHistory(api.storage)
+	if err != nil {
+		httputils.WriteJSONError(w, err)
+		return
+	}
+
+	var rs []resource.Resource
+	for _, vs := range history {
+		rs = append(rs, resource.NewValidatorSet(vs))
+	}
+
+	list := resource.NewResourceList(rs, resource.URLValidatorSets, "", "")
+	httputils.MustWriteJSON(w, 200, list)
+}
+
+// GetValidatorSetHandler returns the validator set, which votes for the block
+// of height.
+func (api NetworkHandlerAPI) GetValidatorSetHandler(w http.ResponseWriter, r *http.Request) {
+	height, err := strconv.ParseUint(mux.Vars(r)["height"], 10, 64)
+	if err != nil {
+		httputils.WriteJSONError(w, errors.BadRequestParameter)
+		return
+	}
+
+	vs, err := block.GetValidatorSet(api.storage, height)
+	if err != nil {
+		httputils.WriteJSONError(w, err)
+		return
+	}
+
+	httputils.MustWriteJSON(w, 200, resource.NewValidatorSet(vs))
+}
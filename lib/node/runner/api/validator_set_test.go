@@ -0,0 +1,61 @@
+package api
+
+import (
+	"bufio"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/transaction/operation"
+)
+
+func TestGetValidatorSetHandler(t *testing.T) {
+	ts, st := prepareAPIServer()
+	defer st.Close()
+	defer ts.Close()
+
+	reqFunc := func(url string) map[string]interface{} {
+		respBody := request(ts, url, false)
+		defer respBody.Close()
+		bs, err := ioutil.ReadAll(bufio.NewReader(respBody))
+		require.NoError(t, err)
+
+		result := make(map[string]interface{})
+		common.MustUnmarshalJSON(bs, &result)
+		return result
+	}
+
+	v0 := block.ValidatorSetItem{Address: keypair.Random().Address(), Alias: "v0"}
+	v1 := block.ValidatorSetItem{Address: keypair.Random().Address(), Alias: "v1"}
+	require.NoError(t, block.SaveInitialValidatorSet(st, block.NewValidatorSet(common.GenesisBlockHeight, v0)))
+
+	op, err := operation.NewOperation(operation.NewAddValidator(v1.Address, "", v1.Alias, 5))
+	require.NoError(t, err)
+	c, err := block.NewValidatorSetChange(common.MustMakeObjectHashString("tx"), 0, 2, op)
+	require.NoError(t, err)
+	require.NoError(t, c.Save(st))
+
+	{ // history
+		res := reqFunc(GetValidatorSetsHandlerPattern)
+		records := res["_embedded"].(map[string]interface{})["records"].([]interface{})
+		require.Equal(t, 2, len(records))
+		require.Equal(t, float64(1), records[0].(map[string]interface{})["height"])
+		require.Equal(t, float64(5), records[1].(map[string]interface{})["height"])
+		require.Equal(t, 2, len(records[1].(map[string]interface{})["validators"].([]interface{})))
+	}
+
+	{ // by height
+		res := reqFunc(strings.Replace(GetValidatorSetHandlerPattern, "{height}", "4", -1))
+		require.Equal(t, float64(1), res["height"])
+		require.Equal(t, 1, len(res["validators"].([]interface{})))
+
+		res = reqFunc(strings.Replace(GetValidatorSetHandlerPattern, "{height}", "10", -1))
+		require.Equal(t, float64(5), res["height"])
+		require.Equal(t, 2, len(res["validators"].([]interface{})))
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"boscoin.io/sebak/lib/ballot"
 	"boscoin.io/sebak/lib/block"
 	"boscoin.io/sebak/lib/errors"
 	api "boscoin.io/sebak/lib/node/runner/node_api"
@@ -111,9 +112,24 @@ func (nh NetworkHandlerNode) GetBlocksHandler(w http.ResponseWriter, r *http.Req
 			var tx block.BlockTransaction
 			var tp block.TransactionPool
 
+			var exists bool
+			var certificate *ballot.Certificate
+			if exists, err = ballot.ExistsCertificate(nh.storage, b.Hash); err != nil {
+				nh.renderNodeItem(w, api.NodeItemError, err)
+			} else if exists {
+				if certificate, err = ballot.GetCertificate(nh.storage, b.Hash); err != nil {
+					nh.renderNodeItem(w, api.NodeItemError, err)
+				} else {
+					nh.renderNodeItem(w, api.NodeItemCertificate, certificate)
+				}
+			}
+
+			// the `TransactionPool` of the old block is removed in
+			// `common.StorageModePruned`, so the transaction is rendered
+			// without message.
 			if tx, err = block.GetBlockTransaction(nh.storage, b.ProposerTransaction); err != nil {
 				nh.renderNodeItem(w, api.NodeItemError, err)
-			} else if tp, err = block.GetTransactionPool(nh.storage, tx.Hash); err != nil {
+			} else if tp, err = block.GetTransactionPool(nh.storage, tx.Hash); err != nil && err != errors.StorageRecordDoesNotExist {
 				nh.renderNodeItem(w, api.NodeItemError, err)
 			} else {
 				tx.Message = tp.Message
@@ -124,7 +140,7 @@ func (nh NetworkHandlerNode) GetBlocksHandler(w http.ResponseWriter, r *http.Req
 				if tx, err = block.GetBlockTransaction(nh.storage, t); err != nil {
 					nh.renderNodeItem(w, api.NodeItemError, err)
 					continue
-				} else if tp, err = block.GetTransactionPool(nh.storage, tx.Hash); err != nil {
+				} else if tp, err = block.GetTransactionPool(nh.storage, tx.Hash); err != nil && err != errors.StorageRecordDoesNotExist {
 					nh.renderNodeItem(w, api.NodeItemError, err)
 				} else {
 					tx.Message = tp.Message
@@ -15,6 +15,7 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/require"
 
+	"boscoin.io/sebak/lib/ballot"
 	"boscoin.io/sebak/lib/block"
 	"boscoin.io/sebak/lib/errors"
 	api "boscoin.io/sebak/lib/node/runner/node_api"
@@ -573,6 +574,10 @@ func TestGetBlocksHandlerWithModeFull(t *testing.T) {
 	p.Prepare()
 	defer p.Done()
 
+	latest := p.blocks[len(p.blocks)-1]
+	certificate := ballot.NewCertificate(latest.Hash, latest.Height)
+	require.NoError(t, certificate.Save(p.st))
+
 	{ // by default, mode will be `GetBlocksOptionsModeFull`
 		u := p.URL(nil)
 		u.RawQuery = fmt.Sprintf("mode=%s", GetBlocksOptionsModeFull)
@@ -601,6 +606,39 @@ func TestGetBlocksHandlerWithModeFull(t *testing.T) {
 			expectedNumberOfTransactions += len(b.Transactions)
 		}
 		require.Equal(t, expectedNumberOfTransactions, len(rbs[api.NodeItemBlockTransaction]))
+
+		// only the latest block has the certificate
+		require.Equal(t, 1, len(rbs[api.NodeItemCertificate]))
+		require.Equal(t, latest.Hash, rbs[api.NodeItemCertificate][0].(ballot.Certificate).Block)
+	}
+
+	{ // the transaction, whose `TransactionPool` is pruned, has no message
+		pruned := p.blocks[0].Transactions[0]
+		require.NoError(t, block.DeleteTransactionPool(p.st, pruned))
+
+		u := p.URL(nil)
+		u.RawQuery = fmt.Sprintf("mode=%s", GetBlocksOptionsModeFull)
+
+		req, err := http.NewRequest("GET", u.String(), nil)
+		require.NoError(t, err)
+		resp, err := p.server.Client().Do(req)
+		require.NoError(t, err)
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		rbs, err := unmarshalFromNodeItemResponseBody(resp.Body)
+		require.NoError(t, err)
+
+		var found bool
+		for _, item := range rbs[api.NodeItemBlockTransaction] {
+			bt := item.(block.BlockTransaction)
+			if bt.Hash != pruned {
+				require.False(t, bt.Transaction().IsEmpty())
+				continue
+			}
+			found = true
+			require.True(t, bt.Transaction().IsEmpty())
+		}
+		require.True(t, found)
 	}
 }
 
@@ -34,6 +34,8 @@ type NetworkHandlerNode struct {
 	transactionPool *transaction.Pool
 	urlPrefix       string
 	conf            common.Config
+
+	stateSnapshotter *StateSnapshotter
 }
 
 func NewNetworkHandlerNode(localNode *node.LocalNode, network network.Network, storage *storage.LevelDBBackend, consensus *consensus.ISAAC, transactionPool *transaction.Pool, urlPrefix string, conf common.Config) *NetworkHandlerNode {
@@ -48,6 +50,12 @@ func NewNetworkHandlerNode(localNode *node.LocalNode, network network.Network, s
 	}
 }
 
+// SetStateSnapshotter sets the `StateSnapshotter`, which serves the state
+// snapshots; it must be set before the handlers are added.
+func (api *NetworkHandlerNode) SetStateSnapshotter(s *StateSnapshotter) {
+	api.stateSnapshotter = s
+}
+
 func (api NetworkHandlerNode) HandlerURLPattern(pattern string) string {
 	return fmt.Sprintf("%s%s", api.urlPrefix, pattern)
 }
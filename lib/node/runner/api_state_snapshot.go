@@ -0,0 +1,106 @@
+package runner
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/errors"
+	api "boscoin.io/sebak/lib/node/runner/node_api"
+)
+
+const (
+	GetStateSnapshotPattern      = "/state-snapshot"
+	GetStateSnapshotChunkPattern = "/state-snapshot/chunk"
+	GetStateTrieNodesPattern     = "/state-snapshot/trie"
+
+	// MaxStateTrieNodesRequest is the maximum number of trie nodes, which can
+	// be requested at once.
+	MaxStateTrieNodesRequest = 500
+)
+
+// StateTrieNodesRequest is the request body of `GetStateTrieNodesHandler`.
+type StateTrieNodesRequest struct {
+	Height uint64        `json:"height"`
+	Hashes []common.Hash `json:"hashes"`
+}
+
+// GetStateSnapshotHandler returns the latest `block.StateSnapshot` of node;
+// with `height` query, the snapshot at the height is returned.
+func (nh NetworkHandlerNode) GetStateSnapshotHandler(w http.ResponseWriter, r *http.Request) {
+	var snapshot *block.StateSnapshot
+	var err error
+	if h := r.URL.Query().Get("height"); len(h) > 0 {
+		var height uint64
+		if height, err = strconv.ParseUint(h, 10, 64); err != nil {
+			http.Error(w, errors.InvalidQueryString.Error(), http.StatusBadRequest)
+			return
+		}
+		snapshot, err = nh.stateSnapshotter.Snapshot(height)
+	} else {
+		snapshot, err = nh.stateSnapshotter.Latest()
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	nh.renderNodeItem(w, api.NodeItemStateSnapshot, snapshot)
+}
+
+// GetStateSnapshotChunkHandler returns the chunk of snapshot by `height` and
+// `index` query.
+func (nh NetworkHandlerNode) GetStateSnapshotChunkHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	height, err := strconv.ParseUint(query.Get("height"), 10, 64)
+	if err != nil {
+		http.Error(w, errors.InvalidQueryString.Error(), http.StatusBadRequest)
+		return
+	}
+	index, err := strconv.Atoi(query.Get("index"))
+	if err != nil {
+		http.Error(w, errors.InvalidQueryString.Error(), http.StatusBadRequest)
+		return
+	}
+
+	chunk, err := nh.stateSnapshotter.Chunk(height, index)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	nh.renderNodeItem(w, api.NodeItemStateChunk, chunk)
+}
+
+// GetStateTrieNodesHandler returns the nodes of state trie of the snapshot;
+// the nodes are returned by the order of requested hashes.
+func (nh NetworkHandlerNode) GetStateTrieNodesHandler(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Error reading request body", http.StatusInternalServerError)
+		return
+	}
+
+	var request StateTrieNodesRequest
+	if err = json.Unmarshal(body, &request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(request.Hashes) < 1 || len(request.Hashes) > MaxStateTrieNodesRequest {
+		http.Error(w, errors.InvalidQueryString.Error(), http.StatusBadRequest)
+		return
+	}
+
+	nodes, err := nh.stateSnapshotter.TrieNodes(request.Height, request.Hashes)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	for _, n := range nodes {
+		nh.renderNodeItem(w, api.NodeItemStateTrieNode, n)
+	}
+}
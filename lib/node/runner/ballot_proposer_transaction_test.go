@@ -89,6 +89,17 @@ func (p *ballotCheckerProposedTransaction) MakeBallot(numberOfTxs int) (blt *bal
 	}
 
 	blt.SetProposerTransaction(ptx)
+
+	var txs []*transaction.Transaction
+	for i := range p.txs {
+		txs = append(txs, &p.txs[i])
+	}
+	stateRoot, err := MakeStateRoot(p.nr.Storage(), txs, ptx)
+	if err != nil {
+		panic(err)
+	}
+	blt.SetStateRoot(stateRoot)
+
 	blt.SetVote(ballot.StateINIT, voting.YES)
 	blt.Sign(p.proposerNode.Keypair(), networkID)
 
@@ -216,6 +227,52 @@ func TestProposedTransactionWithTransactions(t *testing.T) {
 	require.Equal(t, voting.YES, checker.VotingHole)
 }
 
+// TestProposedTransactionWithWrongStateRoot checks the ballot, which has
+// different state root with the computed one, is voted as NO.
+func TestProposedTransactionWithWrongStateRoot(t *testing.T) {
+	p := &ballotCheckerProposedTransaction{}
+	p.Prepare()
+
+	blt := p.MakeBallot(3)
+	blt.SetStateRoot(common.BytesToHash(common.MakeHash([]byte("wrong"))))
+	blt.Sign(p.proposerNode.Keypair(), networkID)
+
+	var ballotMessage common.NetworkMessage
+	{
+		b, _ := blt.Serialize()
+		ballotMessage = common.NetworkMessage{
+			Type: common.BallotMessage,
+			Data: b,
+		}
+	}
+
+	baseChecker := &BallotChecker{
+		DefaultChecker: common.DefaultChecker{Funcs: DefaultHandleBaseBallotCheckerFuncs},
+		NodeRunner:     p.nr,
+		Conf:           p.nr.Conf,
+		LocalNode:      p.nr.Node(),
+		Message:        ballotMessage,
+		Log:            p.nr.Log(),
+		VotingHole:     voting.NOTYET,
+	}
+	err := common.RunChecker(baseChecker, common.DefaultDeferFunc)
+	require.NoError(t, err)
+
+	checker := &BallotChecker{
+		DefaultChecker: common.DefaultChecker{Funcs: DefaultHandleINITBallotCheckerFuncs},
+		NodeRunner:     p.nr,
+		Conf:           p.nr.Conf,
+		LocalNode:      p.nr.Node(),
+		Message:        ballotMessage,
+		Ballot:         baseChecker.Ballot,
+		VotingHole:     voting.NOTYET,
+		Log:            p.nr.Log(),
+	}
+	err = common.RunChecker(checker, common.DefaultDeferFunc)
+	require.NoError(t, err)
+	require.Equal(t, voting.NO, checker.VotingHole)
+}
+
 // TestProposedTransactionDifferentSigning checks this rule,
 // `ProposerTransaction.Source()` must be same with `Ballot.Proposer()`, it
 // means, `ProposerTransaction` must be signed by same KP of ballot
@@ -629,9 +686,12 @@ func TestProposedTransactionStoreWithZeroAmount(t *testing.T) {
 	previousCommonAccount, _ := block.GetBlockAccount(p.nr.Storage(), p.commonAccount.Address)
 
 	{
-		_, _, err := finishBallot(
+		accepted, err := acceptBallot(p.nr, *blt, p.proposerNode)
+		require.NoError(t, err)
+
+		_, _, err = finishBallot(
 			p.nr,
-			*blt,
+			accepted,
 			p.nr.Log(),
 		)
 		require.NoError(t, err)
@@ -714,9 +774,12 @@ func TestProposedTransactionStoreWithAmount(t *testing.T) {
 	previousCommonAccount, _ := block.GetBlockAccount(p.nr.Storage(), p.commonAccount.Address)
 
 	{
-		_, _, err := finishBallot(
+		accepted, err := acceptBallot(p.nr, *blt, p.proposerNode)
+		require.NoError(t, err)
+
+		_, _, err = finishBallot(
 			p.nr,
-			*blt,
+			accepted,
 			p.nr.Log(),
 		)
 		require.NoError(t, err)
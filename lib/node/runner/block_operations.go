@@ -39,9 +39,26 @@ func NewSavingBlockOperations(st *storage.LevelDBBackend, logger logging.Logger)
 }
 
 func (sb *SavingBlockOperations) getCheckedBlockKey() string {
+	return getCheckedBlockKey()
+}
+
+func getCheckedBlockKey() string {
 	return fmt.Sprintf("%s-last-checked-block", common.InternalPrefix)
 }
 
+// SetBlockOperationsCheckedHeight marks the `BlockOperation`s of the blocks
+// until `height` are saved; it is used when the blocks before `height` are
+// not stored, like the state snapshot sync.
+func SetBlockOperationsCheckedHeight(st *storage.LevelDBBackend, height uint64) error {
+	if found, err := st.Has(getCheckedBlockKey()); err != nil {
+		return err
+	} else if found {
+		return st.Set(getCheckedBlockKey(), height)
+	}
+
+	return st.New(getCheckedBlockKey(), height)
+}
+
 func (sb *SavingBlockOperations) getCheckedBlockHeight() uint64 {
 	var checked uint64
 	if err := sb.st.Get(sb.getCheckedBlockKey(), &checked); err != nil {
@@ -0,0 +1,87 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/ballot"
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/storage"
+)
+
+func TestByzantineScenarioIsWellFormed(t *testing.T) {
+	sc, err := NewByzantineScenarioFromYAML([]byte(`
+validators: 4
+height: 3
+limit: 1m
+byzantine:
+  - node: 3
+    vote-no: 0.5
+`))
+	require.NoError(t, err)
+	require.Equal(t, 66, sc.Threshold)
+	require.Equal(t, 1, sc.Tolerance())
+
+	{ // unknown node
+		_, err := NewByzantineScenarioFromYAML([]byte("validators: 4\nheight: 3\nlimit: 1m\nbyzantine:\n  - node: 4\n"))
+		require.Error(t, err)
+	}
+	{ // duplicated node
+		_, err := NewByzantineScenarioFromYAML([]byte("validators: 4\nheight: 3\nlimit: 1m\nbyzantine:\n  - node: 1\n  - node: 1\n"))
+		require.Error(t, err)
+	}
+	{ // invalid probability
+		_, err := NewByzantineScenarioFromYAML([]byte("validators: 4\nheight: 3\nlimit: 1m\nbyzantine:\n  - node: 1\n    withhold: 2\n"))
+		require.Error(t, err)
+	}
+	{ // unknown field
+		_, err := NewByzantineScenarioFromYAML([]byte("validators: 4\nheight: 3\nlimit: 1m\nbyzantine:\n  - node: 1\n    vote-yes: 1\n"))
+		require.Error(t, err)
+	}
+}
+
+// TestByzantineTolerable checks the honest validators make the same blocks
+// with the faulty validators as many as the voting threshold policy
+// tolerates.
+func TestByzantineTolerable(t *testing.T) {
+	sc, err := LoadByzantineScenario("testdata/byzantine/tolerable.yml")
+	require.NoError(t, err)
+	require.Equal(t, sc.Tolerance(), len(sc.Byzantine))
+
+	s, reached := sc.Run(common.NewTestConfig())
+	require.True(t, reached)
+	requireSameChain(t, s.Honest())
+
+	// the double votes are detected by the honest validators
+	doubleVoter := s.Node(1).Node().Address()
+	var found bool
+	for _, n := range s.Honest() {
+		iterFunc, closeFunc := ballot.GetEvidences(n.Storage(), storage.NewDefaultListOptions(false, nil, 100))
+		for {
+			e, hasNext, _ := iterFunc()
+			if !hasNext {
+				break
+			}
+			require.Equal(t, doubleVoter, e.Address)
+			require.NoError(t, e.Verify(n.Conf.NetworkID))
+			found = true
+		}
+		closeFunc()
+	}
+	require.True(t, found)
+}
+
+// TestByzantineIntolerable checks the honest validators can not make block
+// with the faulty validators more than the voting threshold policy tolerates.
+func TestByzantineIntolerable(t *testing.T) {
+	sc, err := LoadByzantineScenario("testdata/byzantine/intolerable.yml")
+	require.NoError(t, err)
+	require.True(t, len(sc.Byzantine) > sc.Tolerance())
+
+	s, reached := sc.Run(common.NewTestConfig())
+	require.False(t, reached)
+	for _, n := range s.Nodes() {
+		require.Equal(t, common.GenesisBlockHeight, n.Consensus().LatestBlock().Height)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"boscoin.io/sebak/lib/common"
 	"boscoin.io/sebak/lib/consensus"
 	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/metrics"
 	"boscoin.io/sebak/lib/node"
 	"boscoin.io/sebak/lib/node/runner/api"
 	node_api "boscoin.io/sebak/lib/node/runner/node_api"
@@ -331,6 +332,43 @@ func BallotCheckBasis(c common.Checker, args ...interface{}) (err error) {
 	return
 }
 
+// BallotCheckEquivocation checks the source of ballot already voted the
+// conflicting ballot for the same voting basis and state; the evidence of
+// the equivocation is stored.
+func BallotCheckEquivocation(c common.Checker, args ...interface{}) (err error) {
+	checker := c.(*BallotChecker)
+
+	voted, found := checker.NodeRunner.Consensus().Conflicted(checker.Ballot)
+	if !found {
+		return
+	}
+
+	var evidence *ballot.Evidence
+	if evidence, err = ballot.NewEvidence(voted, checker.Ballot); err != nil {
+		return
+	}
+
+	var exists bool
+	if exists, err = ballot.ExistsEvidence(checker.NodeRunner.Storage(), evidence.Hash); err != nil {
+		return
+	}
+	if !exists {
+		if err = evidence.Save(checker.NodeRunner.Storage()); err != nil {
+			return
+		}
+		metrics.Consensus.AddEquivocation(evidence.Address)
+	}
+
+	checker.Log.Error(
+		"equivocation found",
+		"validator", evidence.Address,
+		"evidence", evidence.Hash,
+		"ballots", []string{voted.GetHash(), checker.Ballot.GetHash()},
+	)
+
+	return errors.BallotEquivocation
+}
+
 // BallotAlreadyVoted checks the node of ballot voted.
 func BallotAlreadyVoted(c common.Checker, args ...interface{}) (err error) {
 	checker := c.(*BallotChecker)
@@ -581,10 +619,13 @@ func BallotGetMissingTransaction(c common.Checker, args ...interface{}) (err err
 var INITBallotTransactionCheckerFuncs = []common.CheckerFunc{
 	IsNew,
 	CheckMissingTransaction,
+	BallotTransactionsTimeBounds,
 	BallotTransactionsOperationLimit,
 	BallotTransactionsSameSource,
+	BallotTransactionsInflationPF,
 	BallotTransactionsOperationBodyCollectTxFee,
 	BallotTransactionsAllValid,
+	BallotTransactionsStateRoot,
 }
 
 // INITBallotValidateTransactions validates the
@@ -738,7 +779,21 @@ func FinishedBallotStore(c common.Checker, args ...interface{}) error {
 		defer checker.NodeRunner.NextHeight()
 		checker.NodeRunner.Consensus().SetLatestVotingBasis(basis)
 
-		checker.NodeRunner.TransactionPool.RemoveFromSources(checker.LatestBlockSources...)
+		promoted := checker.NodeRunner.TransactionPool.RemoveFromSources(checker.LatestBlockSources...)
+		if invalids := RemoveInvalidPromotedTransactions(
+			checker.NodeRunner.Storage(),
+			checker.NodeRunner.Conf,
+			checker.NodeRunner.TransactionPool,
+			promoted,
+		); len(invalids) > 0 {
+			checker.Log.Debug("invalid promoted transactions removed from pool", "transactions", len(invalids))
+		}
+		if height, latestTime, err := getTimeBoundsBasis(checker.NodeRunner.Storage()); err == nil {
+			expired := checker.NodeRunner.TransactionPool.RemoveExpired(height, latestTime)
+			if len(expired) > 0 {
+				checker.Log.Debug("expired transactions removed from pool", "transactions", len(expired))
+			}
+		}
 		checker.NodeRunner.Consensus().RemoveRunningRoundsLowerOrEqualHeight(basis.Height)
 		checker.NodeRunner.RemoveSendRecordsLowerThanOrEqualHeight(basis.Height)
 
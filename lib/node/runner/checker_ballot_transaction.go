@@ -4,6 +4,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"boscoin.io/sebak/lib/ballot"
 	"boscoin.io/sebak/lib/block"
@@ -216,6 +217,162 @@ func BallotTransactionsAllValid(c common.Checker, args ...interface{}) (err erro
 	return
 }
 
+// BallotTransactionsTimeBounds checks the transactions are in their time
+// bounds; the transactions out of bounds are marked as invalid.
+func BallotTransactionsTimeBounds(c common.Checker, args ...interface{}) (err error) {
+	checker := c.(*BallotTransactionChecker)
+
+	var height uint64
+	var latestTime time.Time
+	if height, latestTime, err = getTimeBoundsBasis(checker.NodeRunner.Storage()); err != nil {
+		return
+	}
+
+	var validTransactions []string
+	for _, hash := range checker.ValidTransactions {
+		var tx transaction.Transaction
+		var found bool
+		if tx, found, err = checker.transactionCache.Get(hash); err != nil {
+			return
+		} else if !found {
+			continue
+		}
+		if tx.CheckTimeBounds(height, latestTime) != nil {
+			continue
+		}
+		validTransactions = append(validTransactions, hash)
+	}
+
+	checker.setValidTransactions(validTransactions)
+
+	return
+}
+
+// BallotTransactionsInflationPF checks the congress votings of
+// `operation.CongressVotingResult` and `operation.InflationPF` have only one
+// result and one funding; the transactions, which fund the funded voting
+// result or make the second result or funding of the same congress voting in
+// ballot, are marked as invalid.
+func BallotTransactionsInflationPF(c common.Checker, args ...interface{}) (err error) {
+	checker := c.(*BallotTransactionChecker)
+	st := checker.NodeRunner.Storage()
+
+	results := map[string]bool{}  // congress voting hash
+	fundings := map[string]bool{} // congress voting hash
+
+	var validTransactions []string
+	for _, hash := range checker.ValidTransactions {
+		var tx transaction.Transaction
+		var found bool
+		if tx, found, err = checker.transactionCache.Get(hash); err != nil {
+			return
+		} else if !found {
+			continue
+		}
+
+		var invalid bool
+		for _, op := range tx.B.Operations {
+			var congressVotingHash string
+			var seen map[string]bool
+
+			switch op.H.Type {
+			case operation.TypeCongressVotingResult:
+				opb, ok := op.B.(operation.CongressVotingResult)
+				if !ok {
+					invalid = true
+					break
+				}
+				congressVotingHash, seen = opb.CongressVotingHash, results
+			case operation.TypeInflationPF:
+				opb, ok := op.B.(operation.InflationPF)
+				if !ok {
+					invalid = true
+					break
+				}
+
+				var funded bool
+				if funded, err = block.ExistsCongressVotingFunding(st, opb.VotingResult); err != nil {
+					return
+				} else if funded {
+					invalid = true
+					break
+				}
+
+				var cv *block.CongressVoting
+				if cv, err = block.GetCongressVotingByResult(st, opb.VotingResult); err != nil {
+					err = nil
+					invalid = true
+					break
+				}
+				congressVotingHash, seen = cv.Hash, fundings
+			default:
+				continue
+			}
+
+			if invalid || seen[congressVotingHash] {
+				invalid = true
+				break
+			}
+			seen[congressVotingHash] = true
+		}
+		if invalid {
+			continue
+		}
+		validTransactions = append(validTransactions, hash)
+	}
+
+	checker.setValidTransactions(validTransactions)
+
+	return
+}
+
+// getTimeBoundsBasis returns the height of next block and the proposed time of
+// the latest block, which the time bounds of transaction are checked with.
+func getTimeBoundsBasis(st *storage.LevelDBBackend) (height uint64, latestTime time.Time, err error) {
+	latest := block.GetLatestBlock(st)
+	if latestTime, err = common.ParseISO8601(latest.ProposedTime); err != nil {
+		return
+	}
+	height = latest.Height + 1
+
+	return
+}
+
+// BallotTransactionsStateRoot checks the state root of ballot is matched with
+// the state root, which is made by applying the transactions to the latest
+// block.
+func BallotTransactionsStateRoot(c common.Checker, args ...interface{}) (err error) {
+	checker := c.(*BallotTransactionChecker)
+
+	if checker.VotingHole == voting.NO {
+		return
+	}
+
+	var txs []*transaction.Transaction
+	for _, hash := range checker.Transactions {
+		var tx transaction.Transaction
+		var found bool
+		if tx, found, err = checker.transactionCache.Get(hash); err != nil {
+			return
+		} else if !found {
+			err = errors.TransactionNotFound
+			return
+		}
+		txs = append(txs, &tx)
+	}
+
+	var root common.Hash
+	if root, err = MakeStateRoot(checker.NodeRunner.Storage(), txs, checker.Ballot.ProposerTransaction()); err != nil {
+		return
+	}
+
+	if root != checker.Ballot.StateRoot() {
+		checker.VotingHole = voting.NO
+	}
+
+	return
+}
+
 //
 // Validate the entirety of a transaction
 //
@@ -236,6 +393,19 @@ func BallotTransactionsAllValid(c common.Checker, args ...interface{}) (err erro
 //   tx = Transaction to check
 //
 func ValidateTx(st *storage.LevelDBBackend, config common.Config, tx transaction.Transaction) (err error) {
+	return validateTx(st, config, tx, false)
+}
+
+// ValidateQueuedTx validates the transaction, which follows the pending
+// transactions of same source in `transaction.Pool`; the sequence ID is
+// checked only to be later than the latest one of source account, and the
+// queued transaction is validated again by `ValidateTx()` before it is
+// proposed.
+func ValidateQueuedTx(st *storage.LevelDBBackend, config common.Config, tx transaction.Transaction) (err error) {
+	return validateTx(st, config, tx, true)
+}
+
+func validateTx(st *storage.LevelDBBackend, config common.Config, tx transaction.Transaction, queued bool) (err error) {
 	// check, source exists
 	var ba *block.BlockAccount
 	if ba, err = block.GetBlockAccount(st, tx.B.Source); err != nil {
@@ -249,11 +419,43 @@ func ValidateTx(st *storage.LevelDBBackend, config common.Config, tx transaction
 	}
 
 	// check, sequenceID is based on latest sequenceID
-	if !tx.IsValidSequenceID(ba.SequenceID) {
+	if queued {
+		if tx.B.SequenceID <= ba.SequenceID {
+			err = errors.TransactionInvalidSequenceID
+			return
+		}
+	} else if !tx.IsValidSequenceID(ba.SequenceID) {
 		err = errors.TransactionInvalidSequenceID
 		return
 	}
 
+	// check, the transaction can be included in the next block; the
+	// transaction, which is not yet valid, is checked by the other rules and
+	// `errors.TransactionNotYetValid` is returned at last.
+	var notYetValid bool
+	if tx.B.TimeBounds != nil {
+		var height uint64
+		var latestTime time.Time
+		if height, latestTime, err = getTimeBoundsBasis(st); err != nil {
+			return
+		}
+		if err = tx.CheckTimeBounds(height, latestTime); err == errors.TransactionNotYetValid {
+			notYetValid = true
+			err = nil
+		} else if err != nil {
+			return
+		}
+	}
+
+	// check, the weight of signers reaches the threshold of source account;
+	// the single signature of source is already verified by `IsWellFormed()`.
+	if ba.IsMultiSig() || len(tx.H.Signatures) > 0 {
+		if ba.SignatureWeight(tx.Signers()) < ba.Threshold(tx.ThresholdLevel()) {
+			err = errors.NotEnoughSignatureWeight
+			return
+		}
+	}
+
 	totalAmount := tx.TotalAmount(true)
 
 	// check, have enough balance at sequenceID
@@ -268,9 +470,58 @@ func ValidateTx(st *storage.LevelDBBackend, config common.Config, tx transaction
 		}
 	}
 
+	// check, the frozen account can withdraw the amount of all operations
+	if ba.IsFrozen() && len(tx.B.Operations) > 1 {
+		if amount := tx.TotalAmount(false); amount > 0 {
+			if err = validateFrozenWithdrawal(st, ba, amount); err != nil {
+				return
+			}
+		}
+	}
+
+	if notYetValid {
+		err = errors.TransactionNotYetValid
+	}
+
 	return
 }
 
+// validateFrozenWithdrawal checks the frozen account can withdraw the
+// `amount`; the `amount` can be withdrawn from the unfreezing requests, which
+// pass `common.UnfreezingPeriod`.
+func validateFrozenWithdrawal(st *storage.LevelDBBackend, source *block.BlockAccount, amount common.Amount) error {
+	requests := block.GetAllUnfreezingRequests(st, source.Address)
+	// Before unfreezing payment, unfreezing request shoud be saved
+	if len(requests) < 1 {
+		return errors.UnfreezingRequestNotRequested
+	}
+
+	latest := block.GetLatestBlock(st)
+
+	var matured bool
+	var available common.Amount
+	for _, r := range requests {
+		// unfreezing period is 241920.
+		if !r.IsMatured(latest.Height) {
+			continue
+		}
+		if r.IsWhole() {
+			return nil
+		}
+		matured = true
+		available = available.MustAdd(r.Remaining())
+	}
+
+	if !matured {
+		return errors.UnfreezingNotReachedExpiration
+	}
+	if amount > available {
+		return errors.UnfreezingAmountNotMatured
+	}
+
+	return nil
+}
+
 //
 // Validate an operation
 //
@@ -286,23 +537,6 @@ func ValidateTx(st *storage.LevelDBBackend, config common.Config, tx transaction
 //
 func ValidateOp(st *storage.LevelDBBackend, config common.Config, source *block.BlockAccount, op operation.Operation) (err error) {
 
-	var funcIsFrozenPayable = func(source *block.BlockAccount) (err error) {
-		// Unfreezing must be done after X period from unfreezing request
-		iterFunc, closeFunc := block.GetBlockOperationsBySource(st, source.Address, nil)
-		bo, _, _ := iterFunc() //Get the first operation submitted by the source(frozen) account
-		closeFunc()
-		// Before unfreezing payment, unfreezing request shoud be saved
-		if bo.Type != operation.TypeUnfreezingRequest {
-			return errors.UnfreezingRequestNotRequested
-		}
-		lastblock := block.GetLatestBlock(st)
-		// unfreezing period is 241920.
-		if lastblock.Height-bo.Height < common.UnfreezingPeriod {
-			return errors.UnfreezingNotReachedExpiration
-		}
-		return nil
-	}
-
 	switch op.H.Type {
 	case operation.TypeCreateAccount:
 		var ok bool
@@ -316,7 +550,7 @@ func ValidateOp(st *storage.LevelDBBackend, config common.Config, source *block.
 		}
 
 		if source.IsFrozen() {
-			if err = funcIsFrozenPayable(source); err != nil {
+			if err = validateFrozenWithdrawal(st, source, casted.Amount); err != nil {
 				return err
 			}
 		}
@@ -340,24 +574,44 @@ func ValidateOp(st *storage.LevelDBBackend, config common.Config, source *block.
 
 		// The source account is frozen account
 		if source.IsFrozen() {
-			if err = funcIsFrozenPayable(source); err != nil {
+			if err = validateFrozenWithdrawal(st, source, casted.Amount); err != nil {
 				return err
 			}
 		}
 	case operation.TypeUnfreezingRequest:
-		if _, ok := op.B.(operation.UnfreezeRequest); !ok {
+		var ok bool
+		var casted operation.UnfreezeRequest
+		if casted, ok = op.B.(operation.UnfreezeRequest); !ok {
 			return errors.TypeOperationBodyNotMatched
 		}
 		// Unfreezing should be done from a frozen account
 		if !source.IsFrozen() {
 			return errors.UnfreezingFromInvalidAccount
 		}
-		// Repeated unfreeze request shoud be blocked after unfreeze request saved
-		iterFunc, closeFunc := block.GetBlockOperationsBySource(st, source.Address, nil)
-		bo, _, _ := iterFunc()
-		closeFunc()
-		if bo.Type == operation.TypeUnfreezingRequest {
-			return errors.UnfreezingRequestAlreadyReceived
+
+		var outstanding common.Amount
+		for _, r := range block.GetAllUnfreezingRequests(st, source.Address) {
+			// Repeated unfreeze request shoud be blocked after the unfreeze
+			// request for the whole balance saved
+			if r.IsWhole() {
+				return errors.UnfreezingRequestAlreadyReceived
+			}
+			outstanding = outstanding.MustAdd(r.Remaining())
+		}
+
+		// the outstanding requests can not exceed the balance
+		if !casted.IsWhole() {
+			if total, err := outstanding.Add(casted.Amount); err != nil || total > source.Balance {
+				return errors.UnfreezingAmountExceeded
+			}
+		}
+	case operation.TypeSetOptions:
+		if _, ok := op.B.(operation.SetOptions); !ok {
+			return errors.TypeOperationBodyNotMatched
+		}
+		// Frozen account is controlled by it's linked account
+		if source.IsFrozen() {
+			return errors.InvalidOperation
 		}
 	case operation.TypeInflationPF:
 		var ok bool
@@ -378,6 +632,14 @@ func ValidateOp(st *storage.LevelDBBackend, config common.Config, source *block.
 			return errors.InvalidOperation
 		}
 
+		// the voting result can be funded only once
+		var funded bool
+		if funded, err = block.ExistsCongressVotingFunding(st, inflationPF.VotingResult); err != nil {
+			return
+		} else if funded {
+			return errors.InflationPFAlreadyFunded
+		}
+
 		var congressVotingHash string
 		{
 			var bo block.BlockOperation
@@ -413,45 +675,29 @@ func ValidateOp(st *storage.LevelDBBackend, config common.Config, source *block.
 			congressVotingHash = o.CongressVotingHash
 		}
 
-		var congressVoting operation.CongressVoting
-		{
-			var bo block.BlockOperation
-			var err error
-			var opIndex int
-			parsedCongressVotingHash := strings.Split(congressVotingHash, "-") //0:TxHash, 1:Index
-			if len(parsedCongressVotingHash) != 2 {
-				return errors.InvalidOperation
-			}
-			txHash := parsedCongressVotingHash[0]
-			if opIndex, err = strconv.Atoi(parsedCongressVotingHash[1]); err != nil {
-				return errors.InvalidOperation
-			}
-
-			if bo, err = block.GetBlockOperationByIndex(st, txHash, opIndex); err != nil {
-				return err
-			}
-
-			if bo.Type != operation.TypeCongressVoting {
-				return errors.InvalidOperation
-			}
-			var operationBody operation.Body
-			if operationBody, err = operation.UnmarshalBodyJSON(bo.Type, bo.Body); err != nil {
-				return err
-			}
-
-			var o operation.CongressVoting
-			var ok bool
-			if o, ok = operationBody.(operation.CongressVoting); !ok {
-				return errors.TypeOperationBodyNotMatched
-			}
-			congressVoting = o
+		// only the passed voting can be funded; the result is decided by the
+		// votes, which are tallied by node.
+		var congressVoting *block.CongressVoting
+		if congressVoting, err = block.GetCongressVoting(st, congressVotingHash); err != nil {
+			return errors.CongressVotingNotFound
+		}
+		if !congressVoting.Closed {
+			return errors.CongressVotingNotClosed
+		}
+		if !congressVoting.Passed {
+			return errors.CongressVotingNotPassed
+		}
+		// the other result of the same voting is already funded
+		if len(congressVoting.Funding) > 0 {
+			return errors.InflationPFAlreadyFunded
 		}
 
-		if congressVoting.Amount != inflationPF.Amount {
+		// the funded amount can not exceed the requested amount
+		if inflationPF.Amount > congressVoting.Proposal.Amount {
 			return errors.InflationPFAmountMissMatched
 		}
 
-		if congressVoting.FundingAddress != inflationPF.FundingAddress {
+		if congressVoting.Proposal.FundingAddress != inflationPF.FundingAddress {
 			return errors.InflationPFFundingAddressMissMatched
 		}
 
@@ -461,6 +707,18 @@ func ValidateOp(st *storage.LevelDBBackend, config common.Config, source *block.
 		if source.Address != config.CongressAccountAddress {
 			return errors.CongressAddressMisMatched
 		}
+
+		var ok bool
+		var congressVoting operation.CongressVoting
+		if congressVoting, ok = op.B.(operation.CongressVoting); !ok {
+			return errors.TypeOperationBodyNotMatched
+		}
+
+		// the votes are tallied at the block of `Voting.End`, so the voting
+		// must end after the block, which includes it.
+		if congressVoting.Voting.End <= block.GetLatestBlock(st).Height+1 {
+			return errors.CongressVotingNotInPeriod
+		}
 	case operation.TypeCongressVotingResult:
 		//the CongressAddress is owned by blockchainOS. It is temporally check.
 		//TODO: When a node of BosNet is operated by anonymous then it will be removed.
@@ -474,18 +732,83 @@ func ValidateOp(st *storage.LevelDBBackend, config common.Config, source *block.
 			return errors.TypeOperationBodyNotMatched
 		}
 
-		var opIndex int
-		parsedCongressVotingResultHash := strings.Split(cvResult.CongressVotingHash, "-") //0:TxHash, 1:Index
-		if len(parsedCongressVotingResultHash) != 2 {
-			return errors.InvalidOperation
+		// the result must be same with the votes, which are tallied by node
+		// until the voting is closed.
+		var congressVoting *block.CongressVoting
+		if congressVoting, err = block.GetCongressVoting(st, cvResult.CongressVotingHash); err != nil {
+			return errors.CongressVotingNotFound
 		}
-		txHash := parsedCongressVotingResultHash[0]
-		if opIndex, err = strconv.Atoi(parsedCongressVotingResultHash[1]); err != nil {
-			return errors.InvalidOperation
+		if !congressVoting.Closed {
+			return errors.CongressVotingNotClosed
+		}
+		// one voting has only one result, so it can not be funded twice
+		if len(congressVoting.Results) > 0 {
+			return errors.CongressVotingResultAlreadyExists
 		}
 
-		if _, err = block.GetBlockOperationByIndex(st, txHash, opIndex); err != nil {
-			return err
+		tally := congressVoting.Tally
+		if cvResult.Result.Count != tally.Count ||
+			cvResult.Result.Yes != tally.Yes ||
+			cvResult.Result.No != tally.No ||
+			cvResult.Result.ABS != tally.ABS {
+			return errors.CongressVotingResultMissMatched
+		}
+
+	case operation.TypeCongressVote:
+		var ok bool
+		var vote operation.CongressVote
+		if vote, ok = op.B.(operation.CongressVote); !ok {
+			return errors.TypeOperationBodyNotMatched
+		}
+
+		// the membership of congress is derived from the frozen accounts,
+		// which are linked to the general accounts; the votes of the frozen
+		// accounts linked to same general account are counted as one vote.
+		if !source.IsFrozen() {
+			return errors.CongressVoteFromInvalidAccount
+		}
+
+		var congressVoting *block.CongressVoting
+		if congressVoting, err = block.GetCongressVoting(st, vote.CongressVotingHash); err != nil {
+			return errors.CongressVotingNotFound
+		}
+
+		if !congressVoting.IsVotable(block.GetLatestBlock(st).Height + 1) {
+			return errors.CongressVotingNotInPeriod
+		}
+
+	case operation.TypeDelegate:
+		if _, ok := op.B.(operation.Delegate); !ok {
+			return errors.TypeOperationBodyNotMatched
+		}
+
+		// only the balance of frozen account can be delegated to validator.
+		if !source.IsFrozen() {
+			return errors.DelegateFromInvalidAccount
+		}
+
+	case operation.TypeAddValidator, operation.TypeRemoveValidator:
+		//the CongressAddress is owned by blockchainOS. It is temporally check.
+		//TODO: When a node of BosNet is operated by anonymous then it will be removed.
+		if source.Address != config.CongressAccountAddress {
+			return errors.CongressAddressMisMatched
+		}
+
+		var change *block.ValidatorSetChange
+		if change, err = block.NewValidatorSetChange("", 0, 0, op); err != nil {
+			return
+		}
+
+		// the validator set of the next block is already decided, so the
+		// change must take effect after the block, which includes it.
+		if change.Height <= block.GetLatestBlock(st).Height+1 {
+			return errors.ValidatorSetChangeNotInFuture
+		}
+
+		for _, c := range block.GetValidatorSetChangesByHeight(st, change.Height) {
+			if c.Validator.Address == change.Validator.Address {
+				return errors.ValidatorSetChangeAlreadyExists
+			}
 		}
 
 	default:
@@ -13,6 +13,7 @@ package runner
 
 import (
 	"testing"
+	"time"
 
 	"boscoin.io/sebak/lib/block"
 	"boscoin.io/sebak/lib/common"
@@ -124,6 +125,140 @@ func TestValidateTxWrongSequenceID(t *testing.T) {
 	require.Nil(t, ValidateTx(st, common.Config{}, tx))
 }
 
+// Check the weight of signers of multi-signature account
+func TestValidateTxMultiSig(t *testing.T) {
+	conf := common.NewTestConfig()
+	kps := keypair.Random()
+	kpt := keypair.Random()
+	signer0 := keypair.Random()
+	signer1 := keypair.Random()
+
+	st := storage.NewTestStorage()
+	defer st.Close()
+	bas := block.BlockAccount{
+		Address: kps.Address(),
+		Balance: common.Amount(1 * common.AmountPerCoin),
+	}
+	bat := block.BlockAccount{
+		Address: kpt.Address(),
+		Balance: common.Amount(1 * common.AmountPerCoin),
+	}
+	bas.MustSave(st)
+	bat.MustSave(st)
+
+	makeTx := func(opb operation.Body, signers ...keypair.KP) transaction.Transaction {
+		op, err := operation.NewOperation(opb)
+		require.NoError(t, err)
+		tx, err := transaction.NewTransaction(kps.Address(), 0, op)
+		require.NoError(t, err)
+		for _, kp := range signers {
+			tx.AddSignature(kp, conf.NetworkID)
+		}
+		require.NoError(t, tx.IsWellFormed(conf))
+		return tx
+	}
+
+	payment := operation.NewPayment(kpt.Address(), common.Amount(10000))
+
+	{ // without signers, only the source can sign
+		tx := makeTx(payment, signer0)
+		require.Equal(t, errors.NotEnoughSignatureWeight, ValidateTx(st, conf, tx))
+
+		tx = makeTx(payment, kps)
+		require.Nil(t, ValidateTx(st, conf, tx))
+	}
+
+	// set signers
+	st.Close()
+	st = storage.NewTestStorage()
+	defer st.Close()
+	bas.Signers = []operation.Signer{
+		operation.Signer{Address: signer0.Address(), Weight: 1},
+		operation.Signer{Address: signer1.Address(), Weight: 1},
+	}
+	bas.Thresholds = operation.Thresholds{Low: 1, Medium: 1, High: 2}
+	bas.MustSave(st)
+	bat.MustSave(st)
+
+	{ // the source is not the signer anymore
+		tx := makeTx(payment, kps)
+		require.Equal(t, errors.NotEnoughSignatureWeight, ValidateTx(st, conf, tx))
+	}
+
+	{ // payment needs the medium threshold
+		tx := makeTx(payment, signer0)
+		require.Nil(t, ValidateTx(st, conf, tx))
+	}
+
+	{ // set-options needs the high threshold
+		setOptions := operation.NewSetOptions(nil, operation.Thresholds{})
+		tx := makeTx(setOptions, signer0)
+		require.Equal(t, errors.NotEnoughSignatureWeight, ValidateTx(st, conf, tx))
+
+		tx = makeTx(setOptions, signer0, signer1)
+		require.Nil(t, ValidateTx(st, conf, tx))
+	}
+}
+
+// Check the time bounds of transaction with the latest block
+func TestValidateTxTimeBounds(t *testing.T) {
+	conf := common.NewTestConfig()
+	kps := keypair.Random()
+	kpt := keypair.Random()
+
+	st := block.InitTestBlockchain()
+	defer st.Close()
+	bas := block.BlockAccount{
+		Address: kps.Address(),
+		Balance: common.Amount(1 * common.AmountPerCoin),
+	}
+	bat := block.BlockAccount{
+		Address: kpt.Address(),
+		Balance: common.Amount(1 * common.AmountPerCoin),
+	}
+	bas.MustSave(st)
+	bat.MustSave(st)
+
+	latest := block.GetLatestBlock(st)
+	latestTime, err := common.ParseISO8601(latest.ProposedTime)
+	require.NoError(t, err)
+
+	makeTx := func(tb transaction.TimeBounds) transaction.Transaction {
+		op, err := operation.NewOperation(operation.NewPayment(kpt.Address(), common.Amount(10000)))
+		require.NoError(t, err)
+		tx, err := transaction.NewTransaction(kps.Address(), 0, op)
+		require.NoError(t, err)
+		tx.B.TimeBounds = &tb
+		tx.Sign(kps, conf.NetworkID)
+		return tx
+	}
+
+	{ // height
+		tx := makeTx(transaction.TimeBounds{MaxHeight: latest.Height})
+		require.Equal(t, errors.TransactionExpired, ValidateTx(st, conf, tx))
+
+		tx = makeTx(transaction.TimeBounds{MinHeight: latest.Height + 2})
+		require.Equal(t, errors.TransactionNotYetValid, ValidateTx(st, conf, tx))
+
+		tx = makeTx(transaction.TimeBounds{MinHeight: latest.Height + 1, MaxHeight: latest.Height + 1})
+		require.Nil(t, ValidateTx(st, conf, tx))
+	}
+
+	{ // time
+		tx := makeTx(transaction.TimeBounds{MaxTime: common.FormatISO8601(latestTime.Add(-time.Second))})
+		require.Equal(t, errors.TransactionExpired, ValidateTx(st, conf, tx))
+
+		tx = makeTx(transaction.TimeBounds{MinTime: common.FormatISO8601(latestTime.Add(time.Second))})
+		require.Equal(t, errors.TransactionNotYetValid, ValidateTx(st, conf, tx))
+
+		tx = makeTx(transaction.TimeBounds{
+			MinTime: common.FormatISO8601(latestTime),
+			MaxTime: common.FormatISO8601(latestTime.Add(time.Minute)),
+		})
+		require.Nil(t, ValidateTx(st, conf, tx))
+	}
+}
+
 // Check sending the whole balance
 func TestValidateTxOverBalance(t *testing.T) {
 	kps := keypair.Random()
@@ -84,15 +84,31 @@ func HasTransaction(c common.Checker, args ...interface{}) (err error) {
 }
 
 // SameSource checks there are transactions which has same source in the
-// `Pool`.
+// `Pool`. The transaction, which can replace the existing one by fee, is
+// allowed; see `Transaction.CanReplace()`. The transaction, which follows the
+// existing one, is queued in the `Pool`.
 func MessageHasSameSource(c common.Checker, args ...interface{}) (err error) {
 	checker := c.(*MessageChecker)
 
-	if checker.TransactionPool.IsSameSource(checker.Transaction.Source()) {
+	old, found := checker.TransactionPool.GetFromSource(checker.Transaction.Source())
+	if !found {
+		return
+	}
+
+	if checker.Transaction.B.SequenceID > old.B.SequenceID {
+		return
+	}
+
+	if !checker.Transaction.IsValidSequenceID(old.B.SequenceID) {
 		err = errors.TransactionSameSourceInPool
 		return
 	}
 
+	if !checker.Transaction.CanReplace(old) {
+		err = errors.TransactionReplacementFeeTooLow
+		return
+	}
+
 	return
 }
 
@@ -100,7 +116,13 @@ func MessageHasSameSource(c common.Checker, args ...interface{}) (err error) {
 func MessageValidate(c common.Checker, args ...interface{}) (err error) {
 	checker := c.(*MessageChecker)
 
-	if err = ValidateTx(checker.Storage, checker.Conf, checker.Transaction); err != nil {
+	tx := checker.Transaction
+	if old, found := checker.TransactionPool.GetFromSource(tx.Source()); found && tx.B.SequenceID > old.B.SequenceID {
+		err = ValidateQueuedTx(checker.Storage, checker.Conf, tx)
+		return
+	}
+
+	if err = ValidateTx(checker.Storage, checker.Conf, tx); err != nil {
 		return
 	}
 
@@ -114,7 +136,7 @@ func PushIntoTransactionPool(c common.Checker, args ...interface{}) error {
 
 	tx := checker.Transaction
 	err := checker.TransactionPool.Add(tx)
-	if err == errors.TransactionPoolFull {
+	if err != nil && err != errors.TransactionAlreadyExistsInPool {
 		return err
 	}
 
@@ -133,7 +155,7 @@ func PushIntoTransactionPoolFromClient(c common.Checker, args ...interface{}) er
 
 	tx := checker.Transaction
 	err := checker.TransactionPool.AddFromClient(tx)
-	if err == errors.TransactionPoolFull {
+	if err != nil && err != errors.TransactionAlreadyExistsInPool {
 		return err
 	}
 
@@ -152,7 +174,7 @@ func PushIntoTransactionPoolFromNode(c common.Checker, args ...interface{}) erro
 
 	tx := checker.Transaction
 	err := checker.TransactionPool.AddFromNode(tx)
-	if err == errors.TransactionPoolFull {
+	if err != nil && err != errors.TransactionAlreadyExistsInPool {
 		return err
 	}
 
@@ -5,7 +5,9 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"boscoin.io/sebak/lib/block"
 	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
 	"boscoin.io/sebak/lib/errors"
 	"boscoin.io/sebak/lib/transaction"
 )
@@ -63,6 +65,48 @@ func TestMessageChecker(t *testing.T) {
 	require.Equal(t, err, errors.NewButKnownMessage)
 }
 
+func TestMessageHasSameSource(t *testing.T) {
+	nodeRunner, localNode := MakeNodeRunner()
+	checker := &MessageChecker{
+		LocalNode:       localNode,
+		Consensus:       nodeRunner.Consensus(),
+		Storage:         nodeRunner.Storage(),
+		TransactionPool: nodeRunner.TransactionPool,
+		Log:             nodeRunner.Log(),
+		Conf:            nodeRunner.Conf,
+	}
+
+	kp, tx := transaction.TestMakeTransaction(networkID, 1)
+	require.NoError(t, nodeRunner.TransactionPool.Add(tx))
+
+	makeTx := func(sequenceID uint64, fee common.Amount) transaction.Transaction {
+		newTx, err := transaction.NewTransactionWithFee(kp.Address(), sequenceID, fee, tx.B.Operations...)
+		require.NoError(t, err)
+		newTx.Sign(kp, networkID)
+		return newTx
+	}
+
+	{ // following sequence ID is queued
+		checker.Transaction = makeTx(tx.B.SequenceID+1, tx.B.Fee)
+		require.NoError(t, MessageHasSameSource(checker))
+	}
+
+	{ // not enough fee to replace
+		checker.Transaction = makeTx(tx.B.SequenceID, tx.B.Fee+common.BaseFee-1)
+		require.Equal(t, errors.TransactionReplacementFeeTooLow, MessageHasSameSource(checker))
+	}
+
+	{ // replace-by-fee
+		checker.Transaction = makeTx(tx.B.SequenceID, tx.B.Fee+common.BaseFee)
+		require.NoError(t, MessageHasSameSource(checker))
+		require.NoError(t, PushIntoTransactionPool(checker))
+
+		require.False(t, nodeRunner.TransactionPool.Has(tx.GetHash()))
+		require.True(t, nodeRunner.TransactionPool.Has(checker.Transaction.GetHash()))
+		require.Equal(t, 1, nodeRunner.TransactionPool.Len())
+	}
+}
+
 func TestMessageCheckerWithInvalidHash(t *testing.T) {
 	_, invalidTx := transaction.TestMakeTransaction(networkID, 1)
 	invalidTx.H.Hash = "wrong hash"
@@ -94,3 +138,51 @@ func TestMessageCheckerWithInvalidHash(t *testing.T) {
 	require.EqualError(t, err, "unexpected end of JSON input")
 	require.NotEqual(t, checker.Transaction, invalidTx)
 }
+
+func TestMessageValidateQueuedTransaction(t *testing.T) {
+	nodeRunner, localNode := MakeNodeRunner()
+	checker := &MessageChecker{
+		LocalNode:       localNode,
+		Consensus:       nodeRunner.Consensus(),
+		Storage:         nodeRunner.Storage(),
+		TransactionPool: nodeRunner.TransactionPool,
+		Log:             nodeRunner.Log(),
+		Conf:            nodeRunner.Conf,
+	}
+
+	kp := keypair.Random()
+	ba := block.NewBlockAccount(kp.Address(), common.BaseReserve.MustMult(10))
+	ba.MustSave(nodeRunner.Storage())
+
+	target := keypair.Random().Address()
+	block.NewBlockAccount(target, common.BaseReserve).MustSave(nodeRunner.Storage())
+	first, _ := GetPaymentTransaction(kp, target, ba.SequenceID, 1)
+	second, _ := GetPaymentTransaction(kp, target, ba.SequenceID+1, 1)
+	third, _ := GetPaymentTransaction(kp, target, ba.SequenceID+2, 1)
+
+	{ // without the previous transaction in pool
+		checker.Transaction = second
+		require.Equal(t, errors.TransactionInvalidSequenceID, MessageValidate(checker))
+	}
+
+	for _, tx := range []transaction.Transaction{first, second} {
+		checker.Transaction = tx
+		require.NoError(t, MessageHasSameSource(checker))
+		require.NoError(t, MessageValidate(checker))
+		require.NoError(t, PushIntoTransactionPool(checker))
+	}
+	require.Equal(t, 2, nodeRunner.TransactionPool.Len())
+	require.Equal(t, []string{first.GetHash()}, nodeRunner.TransactionPool.AvailableTransactions(10))
+
+	{ // skipped sequence ID can not be queued
+		skipped, _ := GetPaymentTransaction(kp, target, ba.SequenceID+3, 1)
+		checker.Transaction = skipped
+		require.NoError(t, MessageValidate(checker))
+		require.Equal(t, errors.TransactionInvalidSequenceID, PushIntoTransactionPool(checker))
+	}
+
+	checker.Transaction = third
+	require.NoError(t, MessageValidate(checker))
+	require.NoError(t, PushIntoTransactionPool(checker))
+	require.Equal(t, 3, nodeRunner.TransactionPool.Len())
+}
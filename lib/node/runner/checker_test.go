@@ -552,3 +552,60 @@ func TestIrregularIncomingBallots(t *testing.T) {
 	require.Equal(t, initBallot.H.ProposerSignature, received.H.ProposerSignature)
 	require.Equal(t, ballot.StateSIGN, received.State())
 }
+
+// TestBallotCheckEquivocation checks the conflicting ballot of the same
+// validator is stored as evidence and it is not voted.
+func TestBallotCheckEquivocation(t *testing.T) {
+	conf := common.NewTestConfig()
+	nr, nodes, _ := createNodeRunnerForTesting(5, conf, nil)
+	tx, _ := GetTransaction()
+
+	proposer := nr.localNode
+	nr.TransactionPool.Add(tx)
+
+	round := uint64(0)
+	_, err := nr.proposeNewBallot(round)
+	require.NoError(t, err)
+
+	b := nr.Consensus().LatestBlock()
+	votingBasis := voting.Basis{
+		Round:     round,
+		Height:    b.Height,
+		BlockHash: b.Hash,
+		TotalTxs:  b.TotalTxs,
+	}
+
+	ballotSIGN := GenerateBallot(nr.Storage(), proposer, votingBasis, tx, ballot.StateSIGN, nodes[1], conf)
+	require.NoError(t, ReceiveBallot(nr, ballotSIGN))
+
+	// same ballot again
+	require.Equal(t, errors.BallotAlreadyVoted, ReceiveBallot(nr, ballotSIGN))
+
+	conflicted := GenerateEmptyTxBallot(nr.Storage(), proposer, votingBasis, ballot.StateSIGN, nodes[1], conf)
+	require.Equal(t, errors.BallotEquivocation, ReceiveBallot(nr, conflicted))
+
+	rr := nr.Consensus().RunningRounds[votingBasis.Index()]
+	voted := rr.Voted[proposer.Address()].GetResult(ballot.StateSIGN)[nodes[1].Address()]
+	require.Equal(t, ballotSIGN.GetHash(), voted.GetHash())
+
+	evidence, err := ballot.NewEvidence(*ballotSIGN, *conflicted)
+	require.NoError(t, err)
+	found, err := ballot.GetEvidence(nr.Storage(), evidence.Hash)
+	require.NoError(t, err)
+	require.Equal(t, nodes[1].Address(), found.Address)
+	require.NoError(t, found.Verify(networkID))
+
+	// the evidence is stored only once
+	require.Equal(t, errors.BallotEquivocation, ReceiveBallot(nr, conflicted))
+	iterFunc, closeFunc := ballot.GetEvidences(nr.Storage(), nil)
+	var evidences []*ballot.Evidence
+	for {
+		e, hasNext, _ := iterFunc()
+		if !hasNext {
+			break
+		}
+		evidences = append(evidences, e)
+	}
+	closeFunc()
+	require.Equal(t, 1, len(evidences))
+}
@@ -0,0 +1,221 @@
+package runner
+
+import (
+	"fmt"
+
+	logging "github.com/inconshreveable/log15"
+
+	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/storage"
+	"boscoin.io/sebak/lib/transaction"
+	"boscoin.io/sebak/lib/transaction/operation"
+)
+
+// FinishCongressVoting stores the `operation.CongressVoting` and
+// `operation.CongressVote` of the transactions in block and tallies the votes.
+// The congress votings, which end at the block, are closed with the result.
+// The `operation.CongressVotingResult` and `operation.InflationPF` are linked
+// to their congress voting.
+func FinishCongressVoting(st *storage.LevelDBBackend, blk block.Block, transactions []*transaction.Transaction, log logging.Logger) (err error) {
+	for _, tx := range transactions {
+		for i, op := range tx.B.Operations {
+			switch op.H.Type {
+			case operation.TypeCongressVoting:
+				opb, ok := op.B.(operation.CongressVoting)
+				if !ok {
+					return errors.TypeOperationBodyNotMatched
+				}
+				cv := block.NewCongressVoting(tx.GetHash(), i, tx.B.Source, blk.Height, opb)
+				if err = cv.Save(st); err != nil {
+					return
+				}
+			case operation.TypeCongressVote:
+				opb, ok := op.B.(operation.CongressVote)
+				if !ok {
+					return errors.TypeOperationBodyNotMatched
+				}
+				if err = finishCongressVote(st, blk, *tx, opb); err != nil {
+					return
+				}
+			case operation.TypeCongressVotingResult:
+				opb, ok := op.B.(operation.CongressVotingResult)
+				if !ok {
+					return errors.TypeOperationBodyNotMatched
+				}
+				if err = finishCongressVotingResult(st, fmt.Sprintf("%s-%d", tx.GetHash(), i), opb); err != nil {
+					return
+				}
+			case operation.TypeInflationPF:
+				opb, ok := op.B.(operation.InflationPF)
+				if !ok {
+					return errors.TypeOperationBodyNotMatched
+				}
+				if err = finishCongressVotingFunding(st, blk, fmt.Sprintf("%s-%d", tx.GetHash(), i), opb); err != nil {
+					return
+				}
+			}
+		}
+	}
+
+	var closed []*block.CongressVoting
+	iterFunc, closeFunc := block.GetCongressVotingsByEnd(st, blk.Height, nil)
+	for {
+		cv, hasNext, _ := iterFunc()
+		if !hasNext {
+			break
+		}
+		closed = append(closed, cv)
+	}
+	closeFunc()
+
+	for _, cv := range closed {
+		cv.Close()
+		if err = cv.Save(st); err != nil {
+			return
+		}
+
+		log.Debug(
+			"congress voting closed",
+			"congress-voting", cv.Hash,
+			"passed", cv.Passed,
+			"yes", cv.Tally.Yes,
+			"no", cv.Tally.No,
+			"abs", cv.Tally.ABS,
+		)
+	}
+
+	return
+}
+
+// finishCongressVote counts the vote; the voter is the general account linked
+// by the frozen source account, so the frozen accounts of same owner have
+// only one vote. When the voter already voted, the previous vote is replaced.
+func finishCongressVote(st *storage.LevelDBBackend, blk block.Block, tx transaction.Transaction, opb operation.CongressVote) (err error) {
+	var cv *block.CongressVoting
+	if cv, err = block.GetCongressVoting(st, opb.CongressVotingHash); err != nil {
+		return errors.CongressVotingNotFound
+	}
+
+	var source *block.BlockAccount
+	if source, err = block.GetBlockAccount(st, tx.B.Source); err != nil {
+		return
+	}
+	voter := source.Linked
+	if len(voter) < 1 {
+		voter = source.Address
+	}
+
+	var exists bool
+	if exists, err = block.ExistsCongressVote(st, cv.Hash, voter); err != nil {
+		return
+	} else if exists {
+		var previous *block.CongressVote
+		if previous, err = block.GetCongressVote(st, cv.Hash, voter); err != nil {
+			return
+		}
+		cv.Tally.Remove(previous.Answer)
+	}
+	cv.Tally.Add(opb.Answer)
+
+	if err = block.NewCongressVote(tx.GetHash(), voter, tx.B.Source, blk.Height, opb).Save(st); err != nil {
+		return
+	}
+
+	return cv.Save(st)
+}
+
+// finishCongressVotingResult links the `operation.CongressVotingResult` to the
+// congress voting; the congress voting, which is not stored, is ignored.
+func finishCongressVotingResult(st *storage.LevelDBBackend, resultHash string, opb operation.CongressVotingResult) (err error) {
+	var exists bool
+	if exists, err = block.ExistsCongressVoting(st, opb.CongressVotingHash); err != nil || !exists {
+		return
+	}
+
+	var cv *block.CongressVoting
+	if cv, err = block.GetCongressVoting(st, opb.CongressVotingHash); err != nil {
+		return
+	}
+
+	return cv.AddResult(st, resultHash)
+}
+
+// finishCongressVotingFunding marks the voting result of
+// `operation.InflationPF` as funded and links it to the congress voting of the
+// voting result.
+func finishCongressVotingFunding(st *storage.LevelDBBackend, blk block.Block, fundingHash string, opb operation.InflationPF) (err error) {
+	if err = block.NewCongressVotingFunding(opb.VotingResult, fundingHash, blk.Height).Save(st); err != nil {
+		return
+	}
+
+	var exists bool
+	if exists, err = st.Has(block.GetCongressVotingKeyByResult(opb.VotingResult)); err != nil || !exists {
+		return
+	}
+
+	var cv *block.CongressVoting
+	if cv, err = block.GetCongressVotingByResult(st, opb.VotingResult); err != nil {
+		return
+	}
+	cv.Funding = fundingHash
+
+	return cv.Save(st)
+}
+
+func getCongressVotingsIndexedKey() string {
+	return fmt.Sprintf("%s-congress-votings-indexed", common.InternalPrefix)
+}
+
+// InitCongressVotings stores the congress votings of the stored blocks, which
+// are included before the congress votings are stored by
+// `FinishCongressVoting()`, like the blocks of the previous version; the
+// transactions of the blocks are finished again by the order of height. It
+// runs only once, after that the stored height is marked.
+func InitCongressVotings(st *storage.LevelDBBackend, log logging.Logger) (err error) {
+	var exists bool
+	if exists, err = st.Has(getCongressVotingsIndexedKey()); err != nil || exists {
+		return
+	}
+
+	latest := block.GetLatestBlock(st)
+
+	// the congress votings are already stored
+	iterFunc, closeFunc := block.GetCongressVotings(st, storage.NewDefaultListOptions(false, nil, 1))
+	_, exists, _ = iterFunc()
+	closeFunc()
+
+	if !exists {
+		var finished int
+		for height := common.GenesisBlockHeight + 1; height <= latest.Height; height++ {
+			var blk block.Block
+			if blk, err = block.GetBlockByHeight(st, height); err != nil {
+				// the blocks before the state snapshot are not stored
+				if err == errors.StorageRecordDoesNotExist {
+					err = nil
+					continue
+				}
+				return
+			}
+
+			var transactions []*transaction.Transaction
+			for _, hash := range blk.Transactions {
+				var tx transaction.Transaction
+				if tx, _, err = block.GetTransactionInBlock(st, hash); err != nil {
+					return
+				}
+				transactions = append(transactions, &tx)
+			}
+
+			if err = FinishCongressVoting(st, blk, transactions, log); err != nil {
+				return
+			}
+			finished++
+		}
+
+		log.Info("congress votings are stored from blocks", "blocks", finished, "height", latest.Height)
+	}
+
+	return st.New(getCongressVotingsIndexedKey(), latest.Height)
+}
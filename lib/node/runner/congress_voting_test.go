@@ -0,0 +1,333 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/transaction"
+	"boscoin.io/sebak/lib/transaction/operation"
+)
+
+func TestFinishCongressVoting(t *testing.T) {
+	conf := common.NewTestConfig()
+	st := block.InitTestBlockchain()
+	defer st.Close()
+
+	kpCongress := keypair.Random()
+	conf.CongressAccountAddress = kpCongress.Address()
+	block.NewBlockAccount(kpCongress.Address(), common.BaseReserve).MustSave(st)
+
+	kpCommon := keypair.Random()
+	conf.CommonAccountAddress = kpCommon.Address()
+	block.NewBlockAccount(kpCommon.Address(), common.BaseReserve).MustSave(st)
+
+	kpGeneral := keypair.Random()
+	block.NewBlockAccount(kpGeneral.Address(), common.BaseReserve).MustSave(st)
+
+	// the frozen accounts are the members of congress; the owners of the
+	// members are different general accounts.
+	var members []*keypair.Full
+	var owners []*keypair.Full
+	for i := 0; i < 3; i++ {
+		owner := keypair.Random()
+		block.NewBlockAccount(owner.Address(), common.BaseReserve).MustSave(st)
+		owners = append(owners, owner)
+
+		kp := keypair.Random()
+		block.NewBlockAccountLinked(kp.Address(), common.BaseReserve, owner.Address()).MustSave(st)
+		members = append(members, kp)
+	}
+
+	// the other frozen account of the owner of `members[0]`
+	sibling := keypair.Random()
+	block.NewBlockAccountLinked(sibling.Address(), common.BaseReserve, owners[0].Address()).MustSave(st)
+
+	makeTx := func(kp *keypair.Full, opb operation.Body) transaction.Transaction {
+		op, err := operation.NewOperation(opb)
+		require.NoError(t, err)
+		tx, err := transaction.NewTransaction(kp.Address(), 0, op)
+		require.NoError(t, err)
+		tx.Sign(kp, networkID)
+		return tx
+	}
+
+	validateOp := func(kp *keypair.Full, opb operation.Body) error {
+		ba, err := block.GetBlockAccount(st, kp.Address())
+		require.NoError(t, err)
+		op, err := operation.NewOperation(opb)
+		require.NoError(t, err)
+		return ValidateOp(st, conf, ba, op)
+	}
+
+	nextBlock := func(txs ...transaction.Transaction) {
+		var hashes []string
+		var proposed []*transaction.Transaction
+		for i := range txs {
+			hashes = append(hashes, txs[i].GetHash())
+			proposed = append(proposed, &txs[i])
+		}
+
+		blk := block.TestMakeNewBlockWithPrevBlock(block.GetLatestBlock(st), hashes)
+		blk.MustSave(st)
+		for _, tx := range txs {
+			bt := block.NewBlockTransactionFromTransaction(blk.Hash, blk.Height, blk.ProposedTime, tx)
+			bt.MustSave(st)
+			require.NoError(t, bt.SaveBlockOperations(st))
+		}
+		require.NoError(t, FinishCongressVoting(st, blk, proposed, common.NopLogger()))
+	}
+
+	// the latest block is 1; the voting must end after the next block
+	{
+		opb := operation.NewCongressVoting("dummy contract", 2, 2, common.Amount(1000000), kpGeneral.Address())
+		require.Equal(t, errors.CongressVotingNotInPeriod, validateOp(kpCongress, opb))
+	}
+
+	votingOpb := operation.NewCongressVoting("dummy contract", 4, 5, common.Amount(1000000), kpGeneral.Address())
+	require.NoError(t, validateOp(kpCongress, votingOpb))
+	votingTx := makeTx(kpCongress, votingOpb)
+
+	// `failedVotingTx` closes at the next block without votes and
+	// `openVotingTx` is not closed until the end of test
+	failedVotingTx := makeTx(kpCongress, operation.NewCongressVoting("failed contract", 3, 3, common.Amount(1000000), kpGeneral.Address()))
+	openVotingTx := makeTx(kpCongress, operation.NewCongressVoting("open contract", 4, 100, common.Amount(1000000), kpGeneral.Address()))
+	nextBlock(votingTx, failedVotingTx, openVotingTx) // 2
+	votingHash := votingTx.GetHash() + "-0"
+	failedVotingHash := failedVotingTx.GetHash() + "-0"
+	openVotingHash := openVotingTx.GetHash() + "-0"
+
+	makeResult := func(count, yes, no, abs uint64, congressVotingHash string) operation.CongressVotingResult {
+		return operation.NewCongressVotingResult(
+			common.MustMakeObjectHashString("ballots"), []string{"http://www.boscoin.io/1"},
+			common.MustMakeObjectHashString("voters"), []string{"http://www.boscoin.io/2"},
+			common.MustMakeObjectHashString("membership"), []string{"http://www.boscoin.io/3"},
+			count, yes, no, abs,
+			congressVotingHash,
+		)
+	}
+
+	cv, err := block.GetCongressVoting(st, votingHash)
+	require.NoError(t, err)
+	require.Equal(t, votingOpb, cv.Proposal)
+	require.Equal(t, uint64(2), cv.Height)
+
+	{ // not yet started
+		require.Equal(t, errors.CongressVotingNotInPeriod, validateOp(members[0], operation.NewCongressVote(votingHash, operation.VoteYes)))
+	}
+	nextBlock() // 3
+
+	{ // not member
+		require.Equal(t, errors.CongressVoteFromInvalidAccount, validateOp(kpGeneral, operation.NewCongressVote(votingHash, operation.VoteYes)))
+	}
+
+	{ // unknown voting
+		unknown := operation.NewCongressVote(kpGeneral.Address()+"-0", operation.VoteYes)
+		require.Equal(t, errors.CongressVotingNotFound, validateOp(members[0], unknown))
+	}
+
+	yes := operation.NewCongressVote(votingHash, operation.VoteYes)
+	no := operation.NewCongressVote(votingHash, operation.VoteNo)
+	abs := operation.NewCongressVote(votingHash, operation.VoteABS)
+	require.NoError(t, validateOp(members[0], yes))
+	require.NoError(t, validateOp(members[1], no))
+	nextBlock(makeTx(members[0], yes), makeTx(members[1], no)) // 4
+
+	cv, err = block.GetCongressVoting(st, votingHash)
+	require.NoError(t, err)
+	require.Equal(t, block.CongressVotingTally{Count: 2, Yes: 1, No: 1}, cv.Tally)
+	require.False(t, cv.Closed)
+
+	{ // the result of the voting, which is not yet closed
+		require.Equal(t, errors.CongressVotingNotClosed, validateOp(kpCongress, makeResult(2, 1, 1, 0, votingHash)))
+	}
+
+	// `members[1]` changes the vote; the vote of `sibling` replaces the vote
+	// of `members[0]`, because they have same owner.
+	require.NoError(t, validateOp(sibling, yes))
+	nextBlock(makeTx(members[1], yes), makeTx(members[2], abs), makeTx(sibling, yes)) // 5
+
+	cv, err = block.GetCongressVoting(st, votingHash)
+	require.NoError(t, err)
+	require.Equal(t, block.CongressVotingTally{Count: 3, Yes: 2, ABS: 1}, cv.Tally)
+	require.True(t, cv.Closed)
+	require.True(t, cv.Passed)
+
+	{ // the vote of the owner is the vote of `sibling`
+		vote, err := block.GetCongressVote(st, votingHash, owners[0].Address())
+		require.NoError(t, err)
+		require.Equal(t, sibling.Address(), vote.Account)
+		require.Equal(t, operation.VoteYes, vote.Answer)
+
+		exists, err := block.ExistsCongressVote(st, votingHash, members[0].Address())
+		require.NoError(t, err)
+		require.False(t, exists)
+	}
+
+	{ // closed
+		require.Equal(t, errors.CongressVotingNotInPeriod, validateOp(members[2], yes))
+	}
+
+	{ // the result does not match with the votes
+		require.Equal(t, errors.CongressVotingResultMissMatched, validateOp(kpCongress, makeResult(3, 3, 0, 0, votingHash)))
+	}
+
+	resultOpb := makeResult(3, 2, 0, 1, votingHash)
+	require.NoError(t, validateOp(kpCongress, resultOpb))
+	resultTx := makeTx(kpCongress, resultOpb)
+	nextBlock(resultTx) // 6
+	resultHash := resultTx.GetHash() + "-0"
+
+	cv, err = block.GetCongressVoting(st, votingHash)
+	require.NoError(t, err)
+	require.Equal(t, []string{resultHash}, cv.Results)
+	require.Empty(t, cv.Funding)
+
+	{ // over the requested amount
+		opb := operation.NewInflationPF(kpGeneral.Address(), common.Amount(1000001), resultHash)
+		require.Equal(t, errors.InflationPFAmountMissMatched, validateOp(kpCommon, opb))
+	}
+
+	fundingOpb := operation.NewInflationPF(kpGeneral.Address(), common.Amount(1000000), resultHash)
+	require.NoError(t, validateOp(kpCommon, fundingOpb))
+	fundingTx := makeTx(kpCommon, fundingOpb)
+	nextBlock(fundingTx) // 7
+
+	cv, err = block.GetCongressVotingByResult(st, resultHash)
+	require.NoError(t, err)
+	require.Equal(t, votingHash, cv.Hash)
+	require.Equal(t, fundingTx.GetHash()+"-0", cv.Funding)
+
+	funding, err := block.GetCongressVotingFunding(st, resultHash)
+	require.NoError(t, err)
+	require.Equal(t, cv.Funding, funding.Funding)
+	require.Equal(t, uint64(7), funding.Height)
+
+	{ // already funded
+		require.Equal(t, errors.InflationPFAlreadyFunded, validateOp(kpCommon, fundingOpb))
+
+		opb := operation.NewInflationPF(kpGeneral.Address(), common.Amount(1), resultHash)
+		require.Equal(t, errors.InflationPFAlreadyFunded, validateOp(kpCommon, opb))
+	}
+
+	{ // the second result of the funded voting
+		secondOpb := makeResult(3, 2, 0, 1, votingHash)
+		secondOpb.BallotStamps.Hash = common.MustMakeObjectHashString("the other ballots")
+		require.Equal(t, errors.CongressVotingResultAlreadyExists, validateOp(kpCongress, secondOpb))
+
+		// even if the second result is stored, it can not be funded
+		secondTx := makeTx(kpCongress, secondOpb)
+		nextBlock(secondTx) // 8
+		secondHash := secondTx.GetHash() + "-0"
+
+		cv, err = block.GetCongressVoting(st, votingHash)
+		require.NoError(t, err)
+		require.Equal(t, []string{resultHash, secondHash}, cv.Results)
+
+		opb := operation.NewInflationPF(kpGeneral.Address(), common.Amount(1000000), secondHash)
+		require.Equal(t, errors.InflationPFAlreadyFunded, validateOp(kpCommon, opb))
+	}
+
+	cv, err = block.GetCongressVoting(st, failedVotingHash)
+	require.NoError(t, err)
+	require.True(t, cv.Closed)
+	require.False(t, cv.Passed)
+
+	failedResultOpb := makeResult(0, 0, 0, 0, failedVotingHash)
+	require.NoError(t, validateOp(kpCongress, failedResultOpb))
+	failedResultTx := makeTx(kpCongress, failedResultOpb)
+
+	{ // the result of the open voting is not valid, but it is stored
+		require.Equal(t, errors.CongressVotingNotClosed, validateOp(kpCongress, makeResult(0, 0, 0, 0, openVotingHash)))
+	}
+	openResultTx := makeTx(kpCongress, makeResult(0, 0, 0, 0, openVotingHash))
+	nextBlock(failedResultTx, openResultTx) // 9
+
+	{ // the failed voting can not be funded
+		opb := operation.NewInflationPF(kpGeneral.Address(), common.Amount(1), failedResultTx.GetHash()+"-0")
+		require.Equal(t, errors.CongressVotingNotPassed, validateOp(kpCommon, opb))
+	}
+
+	{ // the open voting can not be funded
+		opb := operation.NewInflationPF(kpGeneral.Address(), common.Amount(1), openResultTx.GetHash()+"-0")
+		require.Equal(t, errors.CongressVotingNotClosed, validateOp(kpCommon, opb))
+	}
+}
+
+func TestInitCongressVotings(t *testing.T) {
+	st := block.InitTestBlockchain()
+	defer st.Close()
+
+	kpCongress := keypair.Random()
+	block.NewBlockAccount(kpCongress.Address(), common.BaseReserve).MustSave(st)
+
+	kpGeneral := keypair.Random()
+	block.NewBlockAccount(kpGeneral.Address(), common.BaseReserve).MustSave(st)
+
+	kpMember := keypair.Random()
+	block.NewBlockAccountLinked(kpMember.Address(), common.BaseReserve, kpGeneral.Address()).MustSave(st)
+
+	makeTx := func(kp *keypair.Full, opb operation.Body) transaction.Transaction {
+		op, err := operation.NewOperation(opb)
+		require.NoError(t, err)
+		tx, err := transaction.NewTransaction(kp.Address(), 0, op)
+		require.NoError(t, err)
+		tx.Sign(kp, networkID)
+		return tx
+	}
+
+	// the blocks are stored without storing the congress votings
+	nextBlock := func(txs ...transaction.Transaction) {
+		var hashes []string
+		for _, tx := range txs {
+			hashes = append(hashes, tx.GetHash())
+		}
+
+		blk := block.TestMakeNewBlockWithPrevBlock(block.GetLatestBlock(st), hashes)
+		blk.MustSave(st)
+		for _, tx := range txs {
+			bt := block.NewBlockTransactionFromTransaction(blk.Hash, blk.Height, blk.ProposedTime, tx)
+			bt.MustSave(st)
+			_, err := block.SaveTransactionPool(st, tx)
+			require.NoError(t, err)
+			require.NoError(t, bt.SaveBlockOperations(st))
+		}
+	}
+
+	votingTx := makeTx(kpCongress, operation.NewCongressVoting("dummy contract", 3, 4, common.Amount(1000000), kpGeneral.Address()))
+	nextBlock(votingTx) // 2
+	votingHash := votingTx.GetHash() + "-0"
+	voteTx := makeTx(kpMember, operation.NewCongressVote(votingHash, operation.VoteYes))
+	nextBlock(voteTx) // 3
+	nextBlock()       // 4
+	nextBlock()       // 5
+
+	// the `TransactionPool` of the vote is pruned; the vote is found from the
+	// `BlockOperation`s
+	require.NoError(t, block.DeleteTransactionPool(st, voteTx.GetHash()))
+
+	exists, err := block.ExistsCongressVoting(st, votingHash)
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	require.NoError(t, InitCongressVotings(st, common.NopLogger()))
+
+	cv, err := block.GetCongressVoting(st, votingHash)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), cv.Height)
+	require.Equal(t, block.CongressVotingTally{Count: 1, Yes: 1}, cv.Tally)
+	require.True(t, cv.Closed)
+	require.True(t, cv.Passed)
+
+	{ // the next time, the blocks are not finished again
+		require.NoError(t, InitCongressVotings(st, common.NopLogger()))
+
+		cv, err := block.GetCongressVoting(st, votingHash)
+		require.NoError(t, err)
+		require.Equal(t, block.CongressVotingTally{Count: 1, Yes: 1}, cv.Tally)
+	}
+}
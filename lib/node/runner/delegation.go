@@ -0,0 +1,37 @@
+package runner
+
+import (
+	logging "github.com/inconshreveable/log15"
+
+	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/storage"
+	"boscoin.io/sebak/lib/transaction"
+	"boscoin.io/sebak/lib/transaction/operation"
+)
+
+// FinishDelegation stores the `operation.Delegate` of the transactions in
+// block; the delegation indexes by validator are updated.
+func FinishDelegation(st *storage.LevelDBBackend, blk block.Block, transactions []*transaction.Transaction, log logging.Logger) (err error) {
+	for _, tx := range transactions {
+		for _, op := range tx.B.Operations {
+			if op.H.Type != operation.TypeDelegate {
+				continue
+			}
+
+			opb, ok := op.B.(operation.Delegate)
+			if !ok {
+				return errors.TypeOperationBodyNotMatched
+			}
+
+			d := block.NewDelegation(tx.B.Source, opb.Validator, tx.GetHash(), blk.Height)
+			if err = d.Save(st); err != nil {
+				return
+			}
+
+			log.Debug("delegated", "delegator", d.Delegator, "validator", d.Validator)
+		}
+	}
+
+	return
+}
@@ -0,0 +1,58 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/transaction"
+	"boscoin.io/sebak/lib/transaction/operation"
+)
+
+func TestDelegation(t *testing.T) {
+	conf := common.NewTestConfig()
+	st := block.InitTestBlockchain()
+	defer st.Close()
+
+	kpLinked := keypair.Random()
+	linked := block.NewBlockAccount(kpLinked.Address(), common.BaseReserve)
+	linked.MustSave(st)
+
+	kpFrozen := keypair.Random()
+	block.NewBlockAccountLinked(kpFrozen.Address(), common.Unit*3, kpLinked.Address()).MustSave(st)
+
+	validator := keypair.Random().Address()
+	opb := operation.NewDelegate(validator)
+	op, err := operation.NewOperation(opb)
+	require.NoError(t, err)
+
+	{ // only frozen account can delegate
+		require.Equal(t, errors.DelegateFromInvalidAccount, ValidateOp(st, conf, linked, op))
+	}
+
+	frozen, err := block.GetBlockAccount(st, kpFrozen.Address())
+	require.NoError(t, err)
+	require.NoError(t, ValidateOp(st, conf, frozen, op))
+
+	tx, err := transaction.NewTransaction(kpFrozen.Address(), 0, op)
+	require.NoError(t, err)
+	tx.Sign(kpFrozen, networkID)
+
+	blk := block.TestMakeNewBlockWithPrevBlock(block.GetLatestBlock(st), []string{tx.GetHash()})
+	blk.MustSave(st)
+	require.NoError(t, FinishDelegation(st, blk, []*transaction.Transaction{&tx}, common.NopLogger()))
+
+	d, err := block.GetDelegation(st, kpFrozen.Address())
+	require.NoError(t, err)
+	require.Equal(t, validator, d.Validator)
+	require.Equal(t, tx.GetHash(), d.TxHash)
+	require.Equal(t, blk.Height, d.Height)
+
+	amount, err := block.GetDelegatedAmount(st, validator)
+	require.NoError(t, err)
+	require.Equal(t, common.Unit*3, amount)
+}
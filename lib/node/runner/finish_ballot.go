@@ -5,9 +5,12 @@ import (
 
 	"boscoin.io/sebak/lib/ballot"
 	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/common"
 	"boscoin.io/sebak/lib/errors"
 	"boscoin.io/sebak/lib/metrics"
 	"boscoin.io/sebak/lib/storage"
+	"boscoin.io/sebak/lib/storage/statedb"
+	"boscoin.io/sebak/lib/storage/statedb/trie"
 	"boscoin.io/sebak/lib/transaction"
 	"boscoin.io/sebak/lib/transaction/operation"
 )
@@ -42,6 +45,12 @@ func finishBallot(nr *NodeRunner, b ballot.Ballot, log logging.Logger) (*block.B
 		return nil, nil, err
 	}
 
+	if err = saveCertificate(nr, bs, b, *blk, log); err != nil {
+		bs.Discard()
+		log.Error("failed to save certificate", "error", err)
+		return nil, nil, err
+	}
+
 	if err = bs.Commit(); err != nil {
 		if err != errors.NotCommittable {
 			bs.Discard()
@@ -49,9 +58,35 @@ func finishBallot(nr *NodeRunner, b ballot.Ballot, log logging.Logger) (*block.B
 		}
 	}
 
+	// the validator set can be changed from the next block
+	if err = nr.UpdateValidators(); err != nil {
+		log.Error("failed to update the validators", "error", err)
+	}
+
+	// the balances of the delegators can be changed by the new block
+	if err = nr.UpdateDelegated(); err != nil {
+		log.Error("failed to update the delegated amounts", "error", err)
+	}
+
+	if _, err = nr.StateSnapshotter().Take(*blk); err != nil {
+		log.Error("failed to take the state snapshot", "error", err, "height", blk.Height)
+	}
+
 	return blk, proposedTxs, nil
 }
 
+// saveCertificate stores the ACCEPT ballots, which agreed the block, as the
+// certificate of the block.
+func saveCertificate(nr *NodeRunner, st *storage.LevelDBBackend, b ballot.Ballot, blk block.Block, log logging.Logger) error {
+	ballots := nr.Consensus().AcceptedBallots(b)
+	if len(ballots) < 1 {
+		log.Error("accepted ballots not found; certificate can not be stored", "block", blk.Hash)
+		return errors.CertificateNotFound
+	}
+
+	return ballot.NewCertificate(blk.Hash, blk.Height, ballots...).Save(st)
+}
+
 func finishBallotWithProposedTxs(st *storage.LevelDBBackend, b ballot.Ballot, proposedTransactions []*transaction.Transaction, log logging.Logger) (*block.Block, error) {
 	var err error
 	var isValid bool
@@ -69,11 +104,24 @@ func finishBallotWithProposedTxs(st *storage.LevelDBBackend, b ballot.Ballot, pr
 	r.TotalTxs += uint64(len(b.Transactions()) + 1) // + 1 for ProposerTransaction
 	r.TotalOps += uint64(nOps + len(b.ProposerTransaction().B.Operations))
 
+	var sdb *statedb.StateDB
+	var stateRoot common.Hash
+	sdb, stateRoot, err = ApplyBlockState(st, block.GetLatestBlock(st), proposedTransactions, b.ProposerTransaction(), log)
+	if err != nil {
+		log.Error("failed to apply transactions to state", "error", err)
+		return nil, err
+	}
+	if b.StateRoot() != stateRoot {
+		log.Error("state root does not match", "in-ballot", b.StateRoot().Hex(), "computed", stateRoot.Hex())
+		return nil, errors.StateRootDoesNotMatch
+	}
+
 	blk := block.NewBlock(
 		b.Proposer(),
 		r,
 		b.ProposerTransaction().GetHash(),
 		b.Transactions(),
+		stateRoot,
 		b.ProposerConfirmed(),
 	)
 
@@ -82,6 +130,11 @@ func finishBallotWithProposedTxs(st *storage.LevelDBBackend, b ballot.Ballot, pr
 		return nil, err
 	}
 
+	if err = sdb.CommitDB(stateRoot); err != nil {
+		log.Error("failed to commit state", "block", blk.Hash, "error", err)
+		return nil, err
+	}
+
 	log.Info("NewBlock created",
 		"height", blk.Height,
 		"round", blk.Round,
@@ -90,6 +143,7 @@ func finishBallotWithProposedTxs(st *storage.LevelDBBackend, b ballot.Ballot, pr
 		"total-txs", blk.TotalTxs,
 		"total-ops", blk.TotalOps,
 		"proposer", blk.Proposer,
+		"state-root", blk.StateRoot.Hex(),
 	)
 	metrics.Consensus.SetHeight(blk.Height)
 	metrics.Consensus.SetRounds(blk.Round)
@@ -100,6 +154,26 @@ func finishBallotWithProposedTxs(st *storage.LevelDBBackend, b ballot.Ballot, pr
 		return nil, err
 	}
 
+	if err = FinishCongressVoting(st, *blk, proposedTransactions, log); err != nil {
+		log.Error("failed to finish congress voting", "block", blk.Hash, "error", err)
+		return nil, err
+	}
+
+	if err = FinishUnfreezing(st, *blk, proposedTransactions, log); err != nil {
+		log.Error("failed to finish unfreezing", "block", blk.Hash, "error", err)
+		return nil, err
+	}
+
+	if err = FinishDelegation(st, *blk, proposedTransactions, log); err != nil {
+		log.Error("failed to finish delegation", "block", blk.Hash, "error", err)
+		return nil, err
+	}
+
+	if err = FinishValidatorSetChange(st, *blk, proposedTransactions, log); err != nil {
+		log.Error("failed to finish validator set change", "block", blk.Hash, "error", err)
+		return nil, err
+	}
+
 	if err = FinishProposerTransaction(st, *blk, b.ProposerTransaction(), log); err != nil {
 		log.Error("failed to finish proposer transaction", "block", blk.Hash, "ptx", b.ProposerTransaction(), "error", err)
 		return nil, err
@@ -108,6 +182,43 @@ func finishBallotWithProposedTxs(st *storage.LevelDBBackend, b ballot.Ballot, pr
 	return blk, nil
 }
 
+// ApplyBlockState applies the account changes of the transactions and
+// `ProposerTransaction` on top of the state of `prevBlock` and returns the
+// new state root. The changes are not stored until `StateDB.CommitDB()` is
+// called.
+func ApplyBlockState(
+	st *storage.LevelDBBackend,
+	prevBlock block.Block,
+	transactions []*transaction.Transaction,
+	ptx ballot.ProposerTransaction,
+	log logging.Logger,
+) (sdb *statedb.StateDB, root common.Hash, err error) {
+	if root, err = block.GetStateRoot(st, prevBlock); err != nil {
+		return
+	}
+	sdb = statedb.New(root, trie.NewEthDatabase(st))
+
+	if err = ApplyTransactions(sdb, transactions); err != nil {
+		return
+	}
+
+	if err = ProcessProposerTransaction(sdb, ptx, log); err != nil {
+		return
+	}
+
+	root, err = sdb.CommitTrie()
+
+	return
+}
+
+// MakeStateRoot returns the state root after the transactions and
+// `ProposerTransaction` are applied to the latest block; nothing is written to
+// storage.
+func MakeStateRoot(st *storage.LevelDBBackend, transactions []*transaction.Transaction, ptx ballot.ProposerTransaction) (root common.Hash, err error) {
+	_, root, err = ApplyBlockState(st, block.GetLatestBlock(st), transactions, ptx, log)
+	return
+}
+
 func getProposedTransactions(st *storage.LevelDBBackend, pTxHashes []string, transactionPool *transaction.Pool) ([]*transaction.Transaction, error) {
 	proposedTransactions := make([]*transaction.Transaction, 0, len(pTxHashes))
 	var err error
@@ -125,21 +236,31 @@ func getProposedTransactions(st *storage.LevelDBBackend, pTxHashes []string, tra
 	return proposedTransactions, nil
 }
 
+// FinishTransactions saves the transactions of block. The account changes of
+// transactions are applied by `ApplyTransactions`.
 func FinishTransactions(blk block.Block, transactions []*transaction.Transaction, st *storage.LevelDBBackend) (err error) {
 	for _, tx := range transactions {
 		bt := block.NewBlockTransactionFromTransaction(blk.Hash, blk.Height, blk.ProposedTime, *tx)
 		if err = bt.Save(st); err != nil {
 			return
 		}
+	}
+
+	return
+}
+
+// ApplyTransactions applies the account changes of transactions to the state.
+func ApplyTransactions(sdb *statedb.StateDB, transactions []*transaction.Transaction) (err error) {
+	for _, tx := range transactions {
 		for _, op := range tx.B.Operations {
-			if err = finishOperation(st, tx.B.Source, op, log); err != nil {
-				log.Error("failed to finish operation", "block", blk.Hash, "BlockTransaction", bt.Hash, "operation", op, "error", err)
+			if err = finishOperation(sdb, tx.B.Source, op, log); err != nil {
+				log.Error("failed to finish operation", "transaction", tx.GetHash(), "operation", op, "error", err)
 				return err
 			}
 		}
 
 		var baSource *block.BlockAccount
-		if baSource, err = block.GetBlockAccount(st, tx.B.Source); err != nil {
+		if baSource, err = sdb.GetBlockAccount(tx.B.Source); err != nil {
 			err = errors.BlockAccountDoesNotExists
 			return
 		}
@@ -149,31 +270,29 @@ func FinishTransactions(blk block.Block, transactions []*transaction.Transaction
 		}
 
 		baSource.IncreaseSequenceID()
-
-		if err = baSource.Save(st); err != nil {
-			return
-		}
+		sdb.SetBlockAccount(baSource)
 	}
 
 	return
 }
 
 // finishOperation do finish the task after consensus by the type of each operation.
-func finishOperation(st *storage.LevelDBBackend, source string, op operation.Operation, log logging.Logger) (err error) {
+func finishOperation(sdb *statedb.StateDB, source string, op operation.Operation, log logging.Logger) (err error) {
 	switch op.H.Type {
 	case operation.TypeCreateAccount:
 		pop, ok := op.B.(operation.CreateAccount)
 		if !ok {
 			return errors.UnknownOperationType
 		}
-		return finishCreateAccount(st, source, pop, log)
+		return finishCreateAccount(sdb, source, pop, log)
 	case operation.TypePayment:
 		pop, ok := op.B.(operation.Payment)
 		if !ok {
 			return errors.UnknownOperationType
 		}
-		return finishPayment(st, source, pop, log)
-	case operation.TypeCongressVoting, operation.TypeCongressVotingResult:
+		return finishPayment(sdb, source, pop, log)
+	case operation.TypeCongressVoting, operation.TypeCongressVotingResult, operation.TypeCongressVote,
+		operation.TypeDelegate, operation.TypeAddValidator, operation.TypeRemoveValidator:
 		//Nothing to do
 		return
 	case operation.TypeUnfreezingRequest:
@@ -181,13 +300,19 @@ func finishOperation(st *storage.LevelDBBackend, source string, op operation.Ope
 		if !ok {
 			return errors.UnknownOperationType
 		}
-		return finishUnfreezeRequest(st, source, pop, log)
+		return finishUnfreezeRequest(sdb, source, pop, log)
 	case operation.TypeInflationPF:
 		pop, ok := op.B.(operation.InflationPF)
 		if !ok {
 			return errors.UnknownOperationType
 		}
-		return finishInflationPF(st, source, pop, log)
+		return finishInflationPF(sdb, source, pop, log)
+	case operation.TypeSetOptions:
+		pop, ok := op.B.(operation.SetOptions)
+		if !ok {
+			return errors.UnknownOperationType
+		}
+		return finishSetOptions(sdb, source, pop, log)
 
 	default:
 		err = errors.UnknownOperationType
@@ -195,40 +320,35 @@ func finishOperation(st *storage.LevelDBBackend, source string, op operation.Ope
 	}
 }
 
-func finishCreateAccount(st *storage.LevelDBBackend, source string, op operation.CreateAccount, log logging.Logger) (err error) {
-	if _, err = block.GetBlockAccount(st, source); err != nil {
+func finishCreateAccount(sdb *statedb.StateDB, source string, op operation.CreateAccount, log logging.Logger) (err error) {
+	if !sdb.ExistAccount(source) {
 		err = errors.BlockAccountDoesNotExists
 		return
 	}
 
-	var baTarget *block.BlockAccount
-	if baTarget, err = block.GetBlockAccount(st, op.TargetAddress()); err == nil {
+	if sdb.ExistAccount(op.TargetAddress()) {
 		err = errors.BlockAccountAlreadyExists
 		return
-	} else {
-		err = nil
 	}
 
-	baTarget = block.NewBlockAccountLinked(
+	baTarget := block.NewBlockAccountLinked(
 		op.TargetAddress(),
 		op.GetAmount(),
 		op.Linked,
 	)
-	if err = baTarget.Save(st); err != nil {
-		return
-	}
+	sdb.SetBlockAccount(baTarget)
 
 	return
 }
 
-func finishPayment(st *storage.LevelDBBackend, source string, op operation.Payment, log logging.Logger) (err error) {
-	if _, err = block.GetBlockAccount(st, source); err != nil {
+func finishPayment(sdb *statedb.StateDB, source string, op operation.Payment, log logging.Logger) (err error) {
+	if !sdb.ExistAccount(source) {
 		err = errors.BlockAccountDoesNotExists
 		return
 	}
 
 	var baTarget *block.BlockAccount
-	if baTarget, err = block.GetBlockAccount(st, op.TargetAddress()); err != nil {
+	if baTarget, err = sdb.GetBlockAccount(op.TargetAddress()); err != nil {
 		err = errors.BlockAccountDoesNotExists
 		return
 	}
@@ -236,44 +356,55 @@ func finishPayment(st *storage.LevelDBBackend, source string, op operation.Payme
 	if err = baTarget.Deposit(op.GetAmount()); err != nil {
 		return
 	}
-	if err = baTarget.Save(st); err != nil {
-		return
-	}
+	sdb.SetBlockAccount(baTarget)
 
 	return
 }
 
-func finishUnfreezeRequest(st *storage.LevelDBBackend, source string, opb operation.UnfreezeRequest, log logging.Logger) (err error) {
+func finishUnfreezeRequest(sdb *statedb.StateDB, source string, opb operation.UnfreezeRequest, log logging.Logger) (err error) {
 	return
 }
 
-func finishInflationPF(st *storage.LevelDBBackend, source string, opb operation.InflationPF, log logging.Logger) (err error) {
+func finishInflationPF(sdb *statedb.StateDB, source string, opb operation.InflationPF, log logging.Logger) (err error) {
 
 	if opb.Amount < 1 {
 		return
 	}
 
 	var commonAccount *block.BlockAccount
-	if commonAccount, err = block.GetBlockAccount(st, opb.FundingAddress); err != nil {
+	if commonAccount, err = sdb.GetBlockAccount(opb.FundingAddress); err != nil {
 		return
 	}
 
 	if err = commonAccount.Deposit(opb.GetAmount()); err != nil {
 		return
 	}
+	sdb.SetBlockAccount(commonAccount)
 
-	if err = commonAccount.Save(st); err != nil {
+	return
+}
+
+func finishSetOptions(sdb *statedb.StateDB, source string, opb operation.SetOptions, log logging.Logger) (err error) {
+	var baSource *block.BlockAccount
+	if baSource, err = sdb.GetBlockAccount(source); err != nil {
+		err = errors.BlockAccountDoesNotExists
 		return
 	}
 
+	baSource.Signers = nil
+	baSource.Thresholds = operation.Thresholds{}
+	if len(opb.Signers) > 0 {
+		baSource.Signers = append([]operation.Signer{}, opb.Signers...)
+		baSource.Thresholds = opb.Thresholds
+	}
+	sdb.SetBlockAccount(baSource)
+
 	return
 }
 
+// FinishProposerTransaction saves the `ProposerTransaction` of block. The
+// account changes of it are applied by `ProcessProposerTransaction`.
 func FinishProposerTransaction(st *storage.LevelDBBackend, blk block.Block, ptx ballot.ProposerTransaction, log logging.Logger) (err error) {
-	if err = ProcessProposerTransaction(st, blk, ptx, log); err != nil {
-		return err
-	}
-
 	bt := block.NewBlockTransactionFromTransaction(blk.Hash, blk.Height, blk.ProposedTime, ptx.Transaction)
 	if err = bt.Save(st); err != nil {
 		return
@@ -285,13 +416,13 @@ func FinishProposerTransaction(st *storage.LevelDBBackend, blk block.Block, ptx
 	return
 }
 
-func ProcessProposerTransaction(st *storage.LevelDBBackend, blk block.Block, ptx ballot.ProposerTransaction, log logging.Logger) (err error) {
+func ProcessProposerTransaction(sdb *statedb.StateDB, ptx ballot.ProposerTransaction, log logging.Logger) (err error) {
 	{
 		var opb operation.CollectTxFee
 		if opb, err = ptx.CollectTxFee(); err != nil {
 			return
 		}
-		if err = finishCollectTxFee(st, opb, log); err != nil {
+		if err = finishCollectTxFee(sdb, opb, log); err != nil {
 			return
 		}
 	}
@@ -301,7 +432,7 @@ func ProcessProposerTransaction(st *storage.LevelDBBackend, blk block.Block, ptx
 		if opb, err = ptx.Inflation(); err != nil {
 			return
 		}
-		if err = finishInflation(st, opb, log); err != nil {
+		if err = finishInflation(sdb, opb, log); err != nil {
 			return
 		}
 	}
@@ -309,44 +440,38 @@ func ProcessProposerTransaction(st *storage.LevelDBBackend, blk block.Block, ptx
 	return
 }
 
-func finishCollectTxFee(st *storage.LevelDBBackend, opb operation.CollectTxFee, log logging.Logger) (err error) {
+func finishCollectTxFee(sdb *statedb.StateDB, opb operation.CollectTxFee, log logging.Logger) (err error) {
 	if opb.Amount < 1 {
 		return
 	}
 
 	var commonAccount *block.BlockAccount
-	if commonAccount, err = block.GetBlockAccount(st, opb.TargetAddress()); err != nil {
+	if commonAccount, err = sdb.GetBlockAccount(opb.TargetAddress()); err != nil {
 		return
 	}
 
 	if err = commonAccount.Deposit(opb.GetAmount()); err != nil {
 		return
 	}
-
-	if err = commonAccount.Save(st); err != nil {
-		return
-	}
+	sdb.SetBlockAccount(commonAccount)
 
 	return
 }
 
-func finishInflation(st *storage.LevelDBBackend, opb operation.Inflation, log logging.Logger) (err error) {
+func finishInflation(sdb *statedb.StateDB, opb operation.Inflation, log logging.Logger) (err error) {
 	if opb.Amount < 1 {
 		return
 	}
 
 	var commonAccount *block.BlockAccount
-	if commonAccount, err = block.GetBlockAccount(st, opb.TargetAddress()); err != nil {
+	if commonAccount, err = sdb.GetBlockAccount(opb.TargetAddress()); err != nil {
 		return
 	}
 
 	if err = commonAccount.Deposit(opb.GetAmount()); err != nil {
 		return
 	}
-
-	if err = commonAccount.Save(st); err != nil {
-		return
-	}
+	sdb.SetBlockAccount(commonAccount)
 
 	return
 }
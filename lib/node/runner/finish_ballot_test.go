@@ -13,9 +13,12 @@ import (
 	"boscoin.io/sebak/lib/common"
 	"boscoin.io/sebak/lib/common/keypair"
 	"boscoin.io/sebak/lib/consensus"
+	"boscoin.io/sebak/lib/errors"
 	"boscoin.io/sebak/lib/network"
 	"boscoin.io/sebak/lib/node"
 	"boscoin.io/sebak/lib/storage"
+	"boscoin.io/sebak/lib/storage/statedb"
+	"boscoin.io/sebak/lib/storage/statedb/trie"
 	"boscoin.io/sebak/lib/transaction"
 	"boscoin.io/sebak/lib/transaction/operation"
 	"boscoin.io/sebak/lib/voting"
@@ -141,19 +144,50 @@ func testFinishBallot(withBatch bool, numberOfTransactions, numberOfOperations i
 		ptx, _ := ballot.NewProposerTransactionFromBallot(*blt, opc, opi)
 
 		blt.SetProposerTransaction(ptx)
+
+		var proposedTxs []*transaction.Transaction
+		for i := range txs {
+			proposedTxs = append(proposedTxs, &txs[i])
+		}
+		stateRoot, err := MakeStateRoot(nr.Storage(), proposedTxs, ptx)
+		if err != nil {
+			return err
+		}
+		blt.SetStateRoot(stateRoot)
+
 		blt.SetVote(ballot.StateINIT, voting.YES)
 		blt.Sign(proposerNode.Keypair(), conf.NetworkID)
 	}
 
-	_, _, err := finishBallot(
+	accepted, err := acceptBallot(nr, *blt, proposerNode)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = finishBallot(
 		nr,
-		*blt,
+		accepted,
 		nr.Log(),
 	)
 
 	return err
 }
 
+// acceptBallot votes the ACCEPT ballots of `nodes` for the proposed ballot, so
+// the certificate of the block can be stored by `finishBallot()`.
+func acceptBallot(nr *NodeRunner, blt ballot.Ballot, nodes ...*node.LocalNode) (accepted ballot.Ballot, err error) {
+	for _, n := range nodes {
+		accepted = blt
+		accepted.SetVote(ballot.StateACCEPT, voting.YES)
+		accepted.Sign(n.Keypair(), nr.Conf.NetworkID)
+		if _, err = nr.Consensus().Vote(accepted); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
 func TestFinishBallot(t *testing.T) {
 	var err error
 
@@ -163,3 +197,127 @@ func TestFinishBallot(t *testing.T) {
 	err = testFinishBallot(true, 100, 100)
 	require.NoError(t, err)
 }
+
+// TestFinishBallotStateRoot checks the state root of new block is same with
+// the state root in ballot and the changed accounts can be found in state trie.
+func TestFinishBallotStateRoot(t *testing.T) {
+	p := &ballotCheckerProposedTransaction{}
+	p.Prepare()
+
+	blt := p.MakeBallot(3)
+	require.NotEqual(t, common.Hash{}, blt.StateRoot())
+
+	accepted, err := acceptBallot(p.nr, *blt, p.proposerNode)
+	require.NoError(t, err)
+
+	blk, _, err := finishBallot(p.nr, accepted, p.nr.Log())
+	require.NoError(t, err)
+	require.Equal(t, blt.StateRoot(), blk.StateRoot)
+
+	latest := block.GetLatestBlock(p.nr.Storage())
+	require.Equal(t, blt.StateRoot(), latest.StateRoot)
+
+	sdb := statedb.New(latest.StateRoot, trie.NewEthDatabase(p.nr.Storage()))
+	for _, tx := range p.txs {
+		ba, err := block.GetBlockAccount(p.nr.Storage(), tx.B.Source)
+		require.NoError(t, err)
+		require.Equal(t, uint64(1), ba.SequenceID)
+
+		inTrie, err := sdb.GetBlockAccount(tx.B.Source)
+		require.NoError(t, err)
+		require.Equal(t, ba.Balance, inTrie.Balance)
+		require.Equal(t, ba.SequenceID, inTrie.SequenceID)
+	}
+
+	{ // the ballot with different state root can not be finished
+		p.genesisBlock = latest
+		blt := p.MakeBallot(1)
+		blt.SetStateRoot(common.BytesToHash(common.MakeHash([]byte("wrong"))))
+		blt.Sign(p.proposerNode.Keypair(), networkID)
+
+		_, _, err := finishBallot(p.nr, *blt, p.nr.Log())
+		require.Equal(t, errors.StateRootDoesNotMatch, err)
+	}
+}
+
+// TestFinishBallotCertificate checks the ACCEPT ballots, which agreed the
+// block, are stored as the certificate of the block.
+func TestFinishBallotCertificate(t *testing.T) {
+	p := &ballotCheckerProposedTransaction{}
+	p.Prepare()
+
+	blt := p.MakeBallot(3)
+
+	accepted, err := acceptBallot(p.nr, *blt, p.nr.Node(), p.proposerNode)
+	require.NoError(t, err)
+	validators := []string{p.nr.Node().Address(), p.proposerNode.Address()}
+
+	blk, _, err := finishBallot(p.nr, accepted, p.nr.Log())
+	require.NoError(t, err)
+
+	c, err := ballot.GetCertificate(p.nr.Storage(), blk.Hash)
+	require.NoError(t, err)
+	require.Equal(t, blk.Height, c.Height)
+	require.Equal(t, 2, len(c.Ballots))
+	require.Equal(t, blk.ProposedTime, c.Proposed().Confirmed)
+	require.NoError(t, c.Verify(networkID, validators, 2))
+}
+
+// TestFinishBallotWithoutCertificate checks the block is not stored without
+// the ACCEPT ballots, which agreed the block.
+func TestFinishBallotWithoutCertificate(t *testing.T) {
+	p := &ballotCheckerProposedTransaction{}
+	p.Prepare()
+
+	blt := p.MakeBallot(3)
+	previous := block.GetLatestBlock(p.nr.Storage())
+
+	_, _, err := finishBallot(p.nr, *blt, p.nr.Log())
+	require.Equal(t, errors.CertificateNotFound, err)
+	require.Equal(t, previous.Hash, block.GetLatestBlock(p.nr.Storage()).Hash)
+}
+
+func TestFinishSetOptions(t *testing.T) {
+	st := storage.NewTestStorage()
+	defer st.Close()
+
+	kp := keypair.Random()
+	ba := block.NewBlockAccount(kp.Address(), common.Amount(1*common.AmountPerCoin))
+	ba.MustSave(st)
+	root, err := block.PutStateAccounts(st, common.Hash{}, *ba)
+	require.NoError(t, err)
+
+	signers := []operation.Signer{
+		operation.Signer{Address: keypair.Random().Address(), Weight: 1},
+		operation.Signer{Address: keypair.Random().Address(), Weight: 2},
+	}
+	thresholds := operation.Thresholds{Low: 1, Medium: 2, High: 3}
+
+	apply := func(opb operation.SetOptions, sequenceID uint64) *block.BlockAccount {
+		op, err := operation.NewOperation(opb)
+		require.NoError(t, err)
+		tx, err := transaction.NewTransaction(kp.Address(), sequenceID, op)
+		require.NoError(t, err)
+
+		sdb := statedb.New(root, trie.NewEthDatabase(st))
+		require.NoError(t, ApplyTransactions(sdb, []*transaction.Transaction{&tx}))
+		root, err = sdb.CommitTrie()
+		require.NoError(t, err)
+		require.NoError(t, sdb.CommitDB(root))
+
+		updated, err := block.GetBlockAccount(st, kp.Address())
+		require.NoError(t, err)
+		return updated
+	}
+
+	updated := apply(operation.NewSetOptions(signers, thresholds), 0)
+	require.Equal(t, signers, updated.Signers)
+	require.Equal(t, thresholds, updated.Thresholds)
+	require.Equal(t, ba.Balance.MustSub(common.BaseFee), updated.Balance)
+	require.Equal(t, uint64(1), updated.SequenceID)
+
+	// reset signers
+	updated = apply(operation.NewSetOptions(nil, operation.Thresholds{}), 1)
+	require.False(t, updated.IsMultiSig())
+	require.Equal(t, operation.Thresholds{}, updated.Thresholds)
+}
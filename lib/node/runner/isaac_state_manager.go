@@ -13,6 +13,12 @@ import (
 	"boscoin.io/sebak/lib/voting"
 )
 
+// ISAACTimer is the timer for the timeout of the current `ISAACState`;
+// `time.Timer` is used by default.
+type ISAACTimer interface {
+	Reset(time.Duration) bool
+}
+
 // ISAACStateManager manages the ISAACState.
 // The most important function `Start()` is called in startStateManager() function in node_runner.go by goroutine.
 type ISAACStateManager struct {
@@ -21,6 +27,8 @@ type ISAACStateManager struct {
 	nr                     *NodeRunner
 	state                  consensus.ISAACState
 	stateTransit           chan consensus.ISAACState
+	sendTransit            func(consensus.ISAACState) // the function delivers the target state to `stateTransit`.
+	timeout                *ISAACTimeout
 	stop                   chan struct{}
 	blockTimeBuffer        time.Duration              // the time to wait to adjust the block creation time.
 	transitSignal          func(consensus.ISAACState) // the function is called when the ISAACState is changed.
@@ -38,12 +46,18 @@ func NewISAACStateManager(nr *NodeRunner, conf common.Config) *ISAACStateManager
 			BallotState: ballot.StateINIT,
 		},
 		stateTransit:    make(chan consensus.ISAACState),
+		timeout:         NewISAACTimeout(conf),
 		stop:            make(chan struct{}),
 		blockTimeBuffer: 2 * time.Second,
 		transitSignal:   func(consensus.ISAACState) {},
 
 		Conf: conf,
 	}
+	p.sendTransit = func(t consensus.ISAACState) {
+		go func() {
+			p.stateTransit <- t
+		}()
+	}
 
 	p.setTheFirstProposedBlockTime()
 
@@ -167,9 +181,7 @@ func (sm *ISAACStateManager) TransitISAACState(height uint64, round uint64, ball
 			"current", current,
 			"target", target,
 		)
-		go func(t consensus.ISAACState) {
-			sm.stateTransit <- t
-		}(target)
+		sm.sendTransit(target)
 	}
 }
 
@@ -198,52 +210,11 @@ func (sm *ISAACStateManager) Start() {
 		for {
 			select {
 			case <-timer.C:
-				sm.nr.Log().Debug("timeout", "ISAACState", sm.State())
-				switch sm.State().BallotState {
-				case ballot.StateINIT:
-					sm.setBallotState(ballot.StateSIGN)
-					sm.transitSignal(sm.State())
-					sm.resetTimer(timer, ballot.StateSIGN)
-				case ballot.StateSIGN:
-					if sm.nr.localNode.State() == node.StateCONSENSUS {
-						if sm.nr.BallotSendRecord().Sent(sm.State()) {
-							sm.nr.Log().Debug("break; BallotSendRecord().Sent(sm.State) == true", "ISAACState", sm.State())
-							break
-						}
-						go sm.broadcastExpiredBallot(sm.State().Round, ballot.StateSIGN)
-					}
-				case ballot.StateACCEPT:
-					if sm.nr.localNode.State() == node.StateCONSENSUS {
-						if sm.nr.BallotSendRecord().Sent(sm.State()) {
-							sm.nr.Log().Debug("break; BallotSendRecord().Sent(sm.State) == true", "ISAACState", sm.State())
-							break
-						}
-						go sm.broadcastExpiredBallot(sm.State().Round, ballot.StateACCEPT)
-					}
-				case ballot.StateALLCONFIRM:
-					sm.nr.Log().Error("timeout", "ISAACState", sm.State())
-					sm.NextRound()
+				if round, ballotState, expired := sm.expire(timer); expired {
+					go sm.broadcastExpiredBallot(round, ballotState)
 				}
-
 			case state := <-sm.stateTransit:
-				current := sm.State()
-				if !current.IsLater(state) {
-					sm.nr.Log().Debug("break; target is before than or equal to current", "current", current, "target", state)
-					break
-				}
-
-				if state.BallotState == ballot.StateINIT {
-					begin = metrics.Consensus.SetBlockIntervalSeconds(begin)
-
-					if sm.nr.localNode.State() == node.StateCONSENSUS {
-						sm.proposeOrWait(timer, state.Round)
-					}
-				} else {
-					sm.resetTimer(timer, state.BallotState)
-				}
-				sm.setState(state)
-				sm.transitSignal(state)
-
+				begin = sm.transit(timer, state, begin)
 			case <-sm.stop:
 				return
 			}
@@ -251,6 +222,68 @@ func (sm *ISAACStateManager) Start() {
 	}()
 }
 
+// expire handles the timeout of the current `ISAACState`. If the expired
+// ballot should be broadcasted, it returns the round and the ballot state of
+// the expired ballot.
+func (sm *ISAACStateManager) expire(timer ISAACTimer) (round uint64, ballotState ballot.State, expired bool) {
+	sm.nr.Log().Debug("timeout", "ISAACState", sm.State())
+	switch sm.State().BallotState {
+	case ballot.StateINIT:
+		sm.setBallotState(ballot.StateSIGN)
+		sm.transitSignal(sm.State())
+		sm.resetTimer(timer, ballot.StateSIGN)
+	case ballot.StateSIGN, ballot.StateACCEPT:
+		if sm.nr.localNode.State() != node.StateCONSENSUS {
+			break
+		}
+		if sm.nr.BallotSendRecord().Sent(sm.State()) {
+			sm.nr.Log().Debug("break; BallotSendRecord().Sent(sm.State) == true", "ISAACState", sm.State())
+			break
+		}
+		return sm.State().Round, sm.State().BallotState, true
+	case ballot.StateALLCONFIRM:
+		sm.nr.Log().Error("timeout", "ISAACState", sm.State())
+		sm.NextRound()
+	}
+
+	return
+}
+
+// transit moves the current `ISAACState` to the target state and resets the
+// timer for the target state. `begin` is the time when the latest block
+// interval began, it returns the updated one.
+func (sm *ISAACStateManager) transit(timer ISAACTimer, state consensus.ISAACState, begin time.Time) time.Time {
+	current := sm.State()
+	if !current.IsLater(state) {
+		sm.nr.Log().Debug("break; target is before than or equal to current", "current", current, "target", state)
+		return begin
+	}
+
+	if sm.timeout.Transit(current, state) {
+		sm.nr.Log().Debug(
+			"timeouts are changed",
+			"current", current,
+			"target", state,
+			"level", sm.timeout.Level(),
+			"timeout-init", sm.timeout.Timeout(ballot.StateINIT),
+		)
+	}
+
+	if state.BallotState == ballot.StateINIT {
+		begin = metrics.Consensus.SetBlockIntervalSeconds(begin)
+
+		if sm.nr.localNode.State() == node.StateCONSENSUS {
+			sm.proposeOrWait(timer, state.Round)
+		}
+	} else {
+		sm.resetTimer(timer, state.BallotState)
+	}
+	sm.setState(state)
+	sm.transitSignal(state)
+
+	return begin
+}
+
 func (sm *ISAACStateManager) broadcastExpiredBallot(round uint64, state ballot.State) {
 	sm.nr.Log().Debug("begin ISAACStateManager.broadcastExpiredBallot", "round", round, "ballotState", state)
 
@@ -273,23 +306,17 @@ func (sm *ISAACStateManager) broadcastExpiredBallot(round uint64, state ballot.S
 	return
 }
 
-func (sm *ISAACStateManager) resetTimer(timer *time.Timer, state ballot.State) {
+func (sm *ISAACStateManager) resetTimer(timer ISAACTimer, state ballot.State) {
 	switch state {
-	case ballot.StateINIT:
-		timer.Reset(sm.Conf.TimeoutINIT)
-	case ballot.StateSIGN:
-		timer.Reset(sm.Conf.TimeoutSIGN)
-	case ballot.StateACCEPT:
-		timer.Reset(sm.Conf.TimeoutACCEPT)
-	case ballot.StateALLCONFIRM:
-		timer.Reset(sm.Conf.TimeoutALLCONFIRM)
+	case ballot.StateINIT, ballot.StateSIGN, ballot.StateACCEPT, ballot.StateALLCONFIRM:
+		timer.Reset(sm.timeout.Timeout(state))
 	}
 }
 
 // In proposeOrWait,
 // if nr.localNode is proposer, it proposes new ballot,
 // but if not, it waits for receiving ballot from the other proposer.
-func (sm *ISAACStateManager) proposeOrWait(timer *time.Timer, round uint64) {
+func (sm *ISAACStateManager) proposeOrWait(timer ISAACTimer, round uint64) {
 	timer.Reset(time.Duration(1 * time.Hour))
 	sm.setBlockTimeBuffer()
 	height := sm.nr.consensus.LatestBlock().Height
@@ -303,9 +330,9 @@ func (sm *ISAACStateManager) proposeOrWait(timer *time.Timer, round uint64) {
 		} else {
 			log.Error("failed to proposeNewBallot", "height", height, "error", err)
 		}
-		timer.Reset(sm.Conf.TimeoutINIT)
+		timer.Reset(sm.timeout.Timeout(ballot.StateINIT))
 	} else {
-		timer.Reset(sm.blockTimeBuffer + sm.Conf.TimeoutINIT)
+		timer.Reset(sm.blockTimeBuffer + sm.timeout.Timeout(ballot.StateINIT))
 	}
 }
 
@@ -320,6 +347,7 @@ func (sm *ISAACStateManager) setState(state consensus.ISAACState) {
 	defer sm.Unlock()
 	sm.nr.Log().Debug("begin ISAACStateManager.setState()", "state", state)
 	sm.state = state
+	sm.writeJournal()
 
 	return
 }
@@ -329,10 +357,17 @@ func (sm *ISAACStateManager) setBallotState(ballotState ballot.State) {
 	defer sm.Unlock()
 	sm.nr.Log().Debug("begin ISAACStateManager.setBallotState()", "state", sm.state)
 	sm.state.BallotState = ballotState
+	sm.writeJournal()
 
 	return
 }
 
+func (sm *ISAACStateManager) writeJournal() {
+	if err := sm.nr.Journal().WriteState(sm.state); err != nil {
+		sm.nr.Log().Error("failed to write ISAACState in journal", "error", err, "state", sm.state)
+	}
+}
+
 func (sm *ISAACStateManager) Stop() {
 	go func() {
 		sm.stop <- struct{}{}
@@ -388,3 +388,54 @@ func TestStateTransitFromTimeoutSignToAccept(t *testing.T) {
 	require.Equal(t, 0, sign)
 	require.Equal(t, 1, accept)
 }
+
+// 1. All 3 Nodes.
+// 2. Not proposer itself.
+// 3. Before the node stopped, it sent B(`SIGN`, `YES`) in round 1 and it was
+//    recorded in journal.
+// 4. When `ISAACStateManager` starts, the node resumes from the `SIGN` state
+//    of round 1.
+// 5. After TimeoutSIGN, the node does not broadcast B(`SIGN`, `EXP`) because
+//    it already voted.
+func TestStateResumeFromJournal(t *testing.T) {
+	conf := common.NewTestConfig()
+	conf.TimeoutINIT = time.Hour
+	conf.TimeoutSIGN = 200 * time.Millisecond
+	conf.TimeoutACCEPT = time.Hour
+
+	nr, nodes, cm := createNodeRunnerForTesting(3, conf, nil)
+	nr.Consensus().SetProposerSelector(OtherSelector{cm: nr.ConnectionManager(), localNode: nr.Node()})
+
+	latestBlock := nr.Consensus().LatestBlock()
+	basis := voting.Basis{
+		Round:     1,
+		Height:    latestBlock.Height,
+		BlockHash: latestBlock.Hash,
+		TotalTxs:  latestBlock.TotalTxs,
+		TotalOps:  latestBlock.TotalOps,
+	}
+	state := consensus.ISAACState{Height: basis.Height, Round: basis.Round, BallotState: ballot.StateSIGN}
+
+	b := GenerateEmptyTxBallot(nr.Storage(), nodes[1], basis, ballot.StateSIGN, nr.localNode, conf)
+	require.NoError(t, nr.Journal().WriteBallot(*b))
+	require.NoError(t, nr.Journal().WriteState(state))
+
+	ballots := nr.replayJournal()
+	require.Equal(t, 1, len(ballots))
+	require.Equal(t, b.GetHash(), ballots[0].GetHash())
+	require.True(t, nr.BallotSendRecord().Sent(state))
+	require.Equal(t, basis.Round, nr.Consensus().LatestVotingBasis().Round)
+
+	recv := make(chan consensus.ISAACState)
+	nr.isaacStateManager.SetTransitSignal(func(state consensus.ISAACState) {
+		recv <- state
+	})
+
+	nr.startStateManager()
+	defer nr.StopStateManager()
+
+	require.Equal(t, state, <-recv)
+
+	time.Sleep(400 * time.Millisecond)
+	require.Equal(t, 0, len(cm.Messages()))
+}
@@ -0,0 +1,123 @@
+package runner
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"boscoin.io/sebak/lib/ballot"
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/consensus"
+)
+
+// ISAACTimeout calculates the timeouts of `ISAACState`. The timeouts grow by
+// `common.Config.TimeoutBackoffFactor` with the consecutive failed rounds in
+// the same height up to `common.Config.TimeoutBackoffLimit`, and shrink back
+// one step with each successful block.
+type ISAACTimeout struct {
+	sync.RWMutex
+
+	level uint64 // how many times the timeouts are increased
+
+	Conf common.Config
+}
+
+func NewISAACTimeout(conf common.Config) *ISAACTimeout {
+	return &ISAACTimeout{Conf: conf}
+}
+
+func (t *ISAACTimeout) Level() uint64 {
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.level
+}
+
+// Transit updates the level of timeouts by the transition of `ISAACState`;
+// the new round in the same height means the previous round failed and the
+// new height means the block is successfully confirmed. It returns true when
+// the level is changed.
+func (t *ISAACTimeout) Transit(current, target consensus.ISAACState) bool {
+	t.Lock()
+	defer t.Unlock()
+
+	level := t.level
+
+	switch {
+	case target.Height > current.Height:
+		if t.level > 0 {
+			t.level--
+		}
+	case target.Height == current.Height && target.Round > current.Round:
+		for i := current.Round; i < target.Round; i++ {
+			if t.isLimited(t.level) {
+				break
+			}
+			t.level++
+		}
+	}
+
+	return level != t.level
+}
+
+// Timeout returns the timeout of the ballot state.
+func (t *ISAACTimeout) Timeout(state ballot.State) time.Duration {
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.timeout(state, t.level)
+}
+
+func (t *ISAACTimeout) base(state ballot.State) time.Duration {
+	switch state {
+	case ballot.StateINIT:
+		return t.Conf.TimeoutINIT
+	case ballot.StateSIGN:
+		return t.Conf.TimeoutSIGN
+	case ballot.StateACCEPT:
+		return t.Conf.TimeoutACCEPT
+	case ballot.StateALLCONFIRM:
+		return t.Conf.TimeoutALLCONFIRM
+	}
+
+	return 0
+}
+
+func (t *ISAACTimeout) timeout(state ballot.State, level uint64) time.Duration {
+	base := t.base(state)
+	if level < 1 || t.Conf.TimeoutBackoffFactor <= 1 {
+		return base
+	}
+
+	d := float64(base) * math.Pow(t.Conf.TimeoutBackoffFactor, float64(level))
+	if d > math.MaxInt64 {
+		d = math.MaxInt64
+	}
+	if t.Conf.TimeoutBackoffLimit > 0 && d > float64(t.Conf.TimeoutBackoffLimit) {
+		if base > t.Conf.TimeoutBackoffLimit {
+			return base
+		}
+		return t.Conf.TimeoutBackoffLimit
+	}
+
+	return time.Duration(d)
+}
+
+// isLimited checks the timeouts of all the ballot states already reach the
+// limit at the `level`, so the level does not need to be increased.
+func (t *ISAACTimeout) isLimited(level uint64) bool {
+	if t.Conf.TimeoutBackoffFactor <= 1 {
+		return true
+	}
+	if t.Conf.TimeoutBackoffLimit < 1 {
+		return false
+	}
+
+	for _, state := range []ballot.State{ballot.StateINIT, ballot.StateSIGN, ballot.StateACCEPT, ballot.StateALLCONFIRM} {
+		if t.timeout(state, level) < t.Conf.TimeoutBackoffLimit {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,77 @@
+package runner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/ballot"
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/consensus"
+)
+
+func TestISAACTimeout(t *testing.T) {
+	conf := common.NewTestConfig()
+	conf.TimeoutINIT = 2 * time.Second
+	conf.TimeoutSIGN = 2 * time.Second
+	conf.TimeoutACCEPT = 2 * time.Second
+	conf.TimeoutALLCONFIRM = 30 * time.Second
+	conf.TimeoutBackoffFactor = 2
+	conf.TimeoutBackoffLimit = 10 * time.Second
+
+	it := NewISAACTimeout(conf)
+
+	newState := func(height, round uint64, ballotState ballot.State) consensus.ISAACState {
+		return consensus.ISAACState{Height: height, Round: round, BallotState: ballotState}
+	}
+
+	require.Equal(t, conf.TimeoutINIT, it.Timeout(ballot.StateINIT))
+	require.Equal(t, conf.TimeoutALLCONFIRM, it.Timeout(ballot.StateALLCONFIRM))
+
+	{ // next state in the same round
+		require.False(t, it.Transit(newState(1, 0, ballot.StateINIT), newState(1, 0, ballot.StateSIGN)))
+		require.Equal(t, uint64(0), it.Level())
+	}
+
+	{ // failed round
+		require.True(t, it.Transit(newState(1, 0, ballot.StateSIGN), newState(1, 1, ballot.StateINIT)))
+		require.Equal(t, uint64(1), it.Level())
+		require.Equal(t, 4*time.Second, it.Timeout(ballot.StateINIT))
+		require.Equal(t, 4*time.Second, it.Timeout(ballot.StateSIGN))
+
+		// the base timeout is already over the limit
+		require.Equal(t, conf.TimeoutALLCONFIRM, it.Timeout(ballot.StateALLCONFIRM))
+	}
+
+	{ // skipped rounds
+		require.True(t, it.Transit(newState(1, 1, ballot.StateINIT), newState(1, 3, ballot.StateINIT)))
+		require.Equal(t, uint64(3), it.Level())
+		require.Equal(t, conf.TimeoutBackoffLimit, it.Timeout(ballot.StateINIT))
+	}
+
+	{ // the level is not increased over the limit
+		require.False(t, it.Transit(newState(1, 3, ballot.StateINIT), newState(1, 10, ballot.StateINIT)))
+		require.Equal(t, uint64(3), it.Level())
+	}
+
+	{ // successful blocks
+		require.True(t, it.Transit(newState(1, 10, ballot.StateALLCONFIRM), newState(2, 0, ballot.StateINIT)))
+		require.Equal(t, uint64(2), it.Level())
+		require.Equal(t, 8*time.Second, it.Timeout(ballot.StateINIT))
+
+		require.True(t, it.Transit(newState(2, 0, ballot.StateALLCONFIRM), newState(3, 0, ballot.StateINIT)))
+		require.True(t, it.Transit(newState(3, 0, ballot.StateALLCONFIRM), newState(4, 0, ballot.StateINIT)))
+		require.Equal(t, uint64(0), it.Level())
+		require.Equal(t, conf.TimeoutINIT, it.Timeout(ballot.StateINIT))
+
+		require.False(t, it.Transit(newState(4, 0, ballot.StateALLCONFIRM), newState(5, 0, ballot.StateINIT)))
+	}
+
+	{ // static timeouts
+		conf.TimeoutBackoffFactor = 1
+		it := NewISAACTimeout(conf)
+		require.False(t, it.Transit(newState(1, 0, ballot.StateSIGN), newState(1, 1, ballot.StateINIT)))
+		require.Equal(t, conf.TimeoutINIT, it.Timeout(ballot.StateINIT))
+	}
+}
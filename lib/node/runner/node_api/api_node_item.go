@@ -19,6 +19,10 @@ const (
 	NodeItemBlockTransaction NodeItemDataType = "block-transaction"
 	NodeItemTransaction      NodeItemDataType = "transaction"
 	NodeItemBallot           NodeItemDataType = "ballot"
+	NodeItemCertificate      NodeItemDataType = "certificate"
+	NodeItemStateSnapshot    NodeItemDataType = "state-snapshot"
+	NodeItemStateChunk       NodeItemDataType = "state-chunk"
+	NodeItemStateTrieNode    NodeItemDataType = "state-trie-node"
 	NodeItemError            NodeItemDataType = "error"
 )
 
@@ -59,6 +63,22 @@ func UnmarshalNodeItemResponse(d []byte) (itemType NodeItemDataType, b interface
 		var t ballot.Ballot
 		err = unmarshal(&t)
 		b = t
+	case NodeItemCertificate:
+		var t ballot.Certificate
+		err = unmarshal(&t)
+		b = t
+	case NodeItemStateSnapshot:
+		var t block.StateSnapshot
+		err = unmarshal(&t)
+		b = t
+	case NodeItemStateChunk:
+		var t block.StateSnapshotChunk
+		err = unmarshal(&t)
+		b = t
+	case NodeItemStateTrieNode:
+		var t []byte
+		err = unmarshal(&t)
+		b = t
 	case NodeItemError:
 		var t errors.Error
 		err = unmarshal(&t)
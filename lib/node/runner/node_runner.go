@@ -39,6 +39,7 @@ var DefaultHandleBaseBallotCheckerFuncs = []common.CheckerFunc{
 }
 
 var DefaultHandleINITBallotCheckerFuncs = []common.CheckerFunc{
+	BallotCheckEquivocation,
 	BallotAlreadyVoted,
 	BallotVote,
 	BallotIsSameProposer,
@@ -51,6 +52,7 @@ var DefaultHandleINITBallotCheckerFuncs = []common.CheckerFunc{
 }
 
 var DefaultHandleSIGNBallotCheckerFuncs = []common.CheckerFunc{
+	BallotCheckEquivocation,
 	BallotAlreadyVoted,
 	BallotVote,
 	BallotIsSameProposer,
@@ -62,6 +64,7 @@ var DefaultHandleSIGNBallotCheckerFuncs = []common.CheckerFunc{
 }
 
 var DefaultHandleACCEPTBallotCheckerFuncs = []common.CheckerFunc{
+	BallotCheckEquivocation,
 	BallotAlreadyVoted,
 	BallotVote,
 	BallotIsSameProposer,
@@ -90,6 +93,8 @@ type NodeRunner struct {
 	storage           *storage.LevelDBBackend
 	isaacStateManager *ISAACStateManager
 	ballotSendRecord  *consensus.BallotSendRecord
+	journal           *consensus.Journal
+	validatorsLock    sync.Mutex
 
 	handleBaseBallotCheckerFuncs   []common.CheckerFunc
 	handleINITBallotCheckerFuncs   []common.CheckerFunc
@@ -104,6 +109,8 @@ type NodeRunner struct {
 	nodeInfo              node.NodeInfo
 	savingBlockOperations *SavingBlockOperations
 	jsonrpcServer         *jsonrpcServer
+	stateSnapshotter      *StateSnapshotter
+	storagePruner         *StoragePruner
 }
 
 func NewNodeRunner(
@@ -126,6 +133,8 @@ func NewNodeRunner(
 		Conf:            conf,
 	}
 	nr.ballotSendRecord = consensus.NewBallotSendRecord(localNode.Alias())
+	nr.journal = consensus.NewJournal(storage, localNode.Alias())
+	nr.stateSnapshotter = NewStateSnapshotter(storage, localNode, conf)
 
 	nr.localNode.SetBooting()
 
@@ -133,6 +142,32 @@ func NewNodeRunner(
 
 	nr.policy.SetValidators(len(nr.localNode.GetValidators()))
 
+	if err = InitStateTrie(nr.Storage(), nr.log); err != nil {
+		nr.log.Error("failed to put the accounts into the state trie", "error", err)
+		return
+	}
+
+	if err = InitCongressVotings(nr.Storage(), nr.log); err != nil {
+		nr.log.Error("failed to store the congress votings of blocks", "error", err)
+		return
+	}
+
+	// the validators of local node is the initial validator set; it is
+	// changed by the validator set changes in block.
+	if err = nr.saveInitialValidatorSet(); err != nil {
+		nr.log.Error("failed to save the initial validator set", "error", err)
+		return
+	}
+	if err = nr.UpdateValidators(); err != nil {
+		nr.log.Error("failed to load the validator set", "error", err)
+		return
+	}
+
+	if err = nr.UpdateDelegated(); err != nil {
+		nr.log.Error("failed to load the delegated amounts", "error", err)
+		return
+	}
+
 	nr.connectionManager = c.ConnectionManager()
 	nr.savingBlockOperations = NewSavingBlockOperations(
 		nr.Storage(),
@@ -144,6 +179,10 @@ func NewNodeRunner(
 		return
 	}
 
+	if conf.StorageMode == common.StorageModePruned {
+		nr.storagePruner = NewStoragePruner(nr.Storage(), conf.PruneBlocks, nr.Log())
+	}
+
 	nr.SetHandleBaseBallotCheckerFuncs(DefaultHandleBaseBallotCheckerFuncs...)
 	nr.SetHandleINITBallotCheckerFuncs(DefaultHandleINITBallotCheckerFuncs...)
 	nr.SetHandleSIGNBallotCheckerFuncs(DefaultHandleSIGNBallotCheckerFuncs...)
@@ -159,6 +198,11 @@ func NewNodeRunner(
 		nr.log.Debug("common account found", "address", nr.Conf.CommonAccountAddress)
 	}
 
+	if _, _, err = LoadTransactionPool(nr.storage, nr.Conf, nr.TransactionPool, nr.log); err != nil {
+		nr.log.Error("failed to load transaction pool", "error", err)
+		return
+	}
+
 	nr.nodeInfo = NewNodeInfo(nr)
 	if conf.JSONRPCEndpoint != nil {
 		nr.jsonrpcServer = newJSONRPCServer(conf.JSONRPCEndpoint, nr.storage)
@@ -257,6 +301,7 @@ func (nr *NodeRunner) Ready() {
 		network.UrlPathPrefixNode,
 		nr.Conf,
 	)
+	nodeHandler.SetStateSnapshotter(nr.stateSnapshotter)
 
 	nr.network.AddHandler(nodeHandler.HandlerURLPattern(NodeInfoHandlerPattern), nodeHandler.NodeInfoHandler)
 	nr.network.AddHandler(nodeHandler.HandlerURLPattern(ConnectHandlerPattern), nodeHandler.ConnectHandler).
@@ -279,6 +324,13 @@ func (nr *NodeRunner) Ready() {
 		MatcherFunc(common.PostAndJSONMatcher)
 	nr.network.AddHandler(nodeHandler.HandlerURLPattern(GetBallotPattern), nodeHandler.GetBallotHandler).
 		Methods("GET")
+	nr.network.AddHandler(nodeHandler.HandlerURLPattern(GetStateSnapshotPattern), nodeHandler.GetStateSnapshotHandler).
+		Methods("GET")
+	nr.network.AddHandler(nodeHandler.HandlerURLPattern(GetStateSnapshotChunkPattern), nodeHandler.GetStateSnapshotChunkHandler).
+		Methods("GET")
+	nr.network.AddHandler(nodeHandler.HandlerURLPattern(GetStateTrieNodesPattern), nodeHandler.GetStateTrieNodesHandler).
+		Methods("POST").
+		Headers("Content-Type", "application/json")
 	nr.network.AddHandler(network.UrlPathPrefixMetric, promhttp.Handler().ServeHTTP)
 
 	// api handlers
@@ -295,6 +347,10 @@ func (nr *NodeRunner) Ready() {
 		apiHandler.HandlerURLPattern(api.GetAccountHandlerPattern),
 		baCache.WrapHandlerFunc(apiHandler.GetAccountHandler),
 	).Methods("GET", "OPTIONS")
+	nr.network.AddHandler(
+		apiHandler.HandlerURLPattern(api.GetAccountProofHandlerPattern),
+		apiHandler.GetAccountProofHandler,
+	).Methods("GET", "OPTIONS")
 	nr.network.AddHandler(
 		apiHandler.HandlerURLPattern(api.GetAccountsHandlerPattern),
 		baCache.WrapHandlerFunc(apiHandler.GetAccountsHandler),
@@ -319,6 +375,34 @@ func (nr *NodeRunner) Ready() {
 		apiHandler.HandlerURLPattern(api.GetAccountFrozenAccountHandlerPattern),
 		apiHandler.GetFrozenAccountsByAccountHandler,
 	).Methods("GET")
+	nr.network.AddHandler(
+		apiHandler.HandlerURLPattern(api.GetCongressProposalsHandlerPattern),
+		listCache.WrapHandlerFunc(apiHandler.GetCongressProposalsHandler),
+	).Methods("GET", "OPTIONS")
+	nr.network.AddHandler(
+		apiHandler.HandlerURLPattern(api.GetCongressProposalHandlerPattern),
+		apiHandler.GetCongressProposalHandler,
+	).Methods("GET", "OPTIONS")
+	nr.network.AddHandler(
+		apiHandler.HandlerURLPattern(api.GetCongressResultHandlerPattern),
+		apiHandler.GetCongressResultHandler,
+	).Methods("GET", "OPTIONS")
+	nr.network.AddHandler(
+		apiHandler.HandlerURLPattern(api.GetValidatorSetsHandlerPattern),
+		apiHandler.GetValidatorSetsHandler,
+	).Methods("GET", "OPTIONS")
+	nr.network.AddHandler(
+		apiHandler.HandlerURLPattern(api.GetValidatorSetHandlerPattern),
+		apiHandler.GetValidatorSetHandler,
+	).Methods("GET", "OPTIONS")
+	nr.network.AddHandler(
+		apiHandler.HandlerURLPattern(api.GetEvidencesHandlerPattern),
+		apiHandler.GetEvidencesHandler,
+	).Methods("GET", "OPTIONS")
+	nr.network.AddHandler(
+		apiHandler.HandlerURLPattern(api.GetEvidenceHandlerPattern),
+		apiHandler.GetEvidenceHandler,
+	).Methods("GET", "OPTIONS")
 	nr.network.AddHandler(
 		apiHandler.HandlerURLPattern(api.GetTransactionByHashHandlerPattern),
 		cache.WrapHandlerFunc(apiHandler.GetTransactionByHashHandler),
@@ -327,6 +411,10 @@ func (nr *NodeRunner) Ready() {
 		apiHandler.HandlerURLPattern(api.GetTransactionOperationsHandlerPattern),
 		listCache.WrapHandlerFunc(apiHandler.GetOperationsByTxHandler),
 	).Methods("GET", "OPTIONS")
+	nr.network.AddHandler(
+		apiHandler.HandlerURLPattern(api.GetTransactionProofHandlerPattern),
+		cache.WrapHandlerFunc(apiHandler.GetTransactionProofHandler),
+	).Methods("GET", "OPTIONS")
 	nr.network.AddHandler(
 		apiHandler.HandlerURLPattern(api.GetTransactionStatusHandlerPattern),
 		listCache.WrapHandlerFunc(apiHandler.GetTransactionStatusByHashHandler),
@@ -392,6 +480,9 @@ func (nr *NodeRunner) Start() (err error) {
 	go nr.ConnectValidators()
 	go nr.InitRound()
 	go nr.savingBlockOperations.Start()
+	if nr.storagePruner != nil {
+		go nr.storagePruner.Start()
+	}
 
 	if nr.jsonrpcServer != nil {
 		go func() {
@@ -416,6 +507,10 @@ func (nr *NodeRunner) Stop() {
 	if nr.jsonrpcServer != nil {
 		nr.jsonrpcServer.Stop()
 	}
+	nr.stateSnapshotter.Close()
+	if nr.storagePruner != nil {
+		nr.storagePruner.Stop()
+	}
 }
 
 func (nr *NodeRunner) Node() *node.LocalNode {
@@ -446,6 +541,102 @@ func (nr *NodeRunner) Policy() voting.ThresholdPolicy {
 	return nr.policy
 }
 
+func (nr *NodeRunner) saveInitialValidatorSet() error {
+	return block.SaveInitialValidatorSet(nr.storage, InitialValidatorSet(nr.localNode))
+}
+
+// InitialValidatorSet makes the `block.ValidatorSet` from the validators given
+// to the local node at boot.
+func InitialValidatorSet(localNode *node.LocalNode) block.ValidatorSet {
+	var validators []block.ValidatorSetItem
+	for _, v := range localNode.GetValidators() {
+		var endpoint string
+		if v.Endpoint() != nil {
+			endpoint = v.Endpoint().String()
+		}
+		validators = append(validators, block.ValidatorSetItem{
+			Address:  v.Address(),
+			Endpoint: endpoint,
+			Alias:    v.Alias(),
+		})
+	}
+
+	return block.NewValidatorSet(common.GenesisBlockHeight, validators...)
+}
+
+// UpdateValidators applies the validator set of the next block to the local
+// node, the voting policy and the connection manager at once.
+func (nr *NodeRunner) UpdateValidators() error {
+	nr.validatorsLock.Lock()
+	defer nr.validatorsLock.Unlock()
+
+	vs, err := block.GetValidatorSet(nr.storage, block.GetLatestBlock(nr.storage).Height+1)
+	if err != nil {
+		return err
+	}
+
+	// not yet changed from the initial validator set
+	if vs.Height == common.GenesisBlockHeight {
+		return nil
+	}
+
+	current := nr.localNode.GetValidators()
+
+	changed := len(current) != len(vs.Validators)
+	var validators []*node.Validator
+	for _, item := range vs.Validators {
+		if v, found := current[item.Address]; found {
+			validators = append(validators, v)
+			continue
+		}
+		changed = true
+
+		var endpoint *common.Endpoint
+		if len(item.Endpoint) > 0 {
+			if endpoint, err = common.ParseEndpoint(item.Endpoint); err != nil {
+				return err
+			}
+		}
+
+		var v *node.Validator
+		if v, err = node.NewValidator(item.Address, endpoint, item.Alias); err != nil {
+			return err
+		}
+		validators = append(validators, v)
+	}
+
+	if !changed {
+		return nil
+	}
+
+	nr.localNode.SetValidators(validators...)
+	nr.policy.SetValidators(len(validators))
+	if nr.connectionManager != nil {
+		nr.connectionManager.UpdateValidators()
+	}
+
+	nr.log.Info("validator set changed", "height", vs.Height, "validators", vs.Addresses())
+
+	return nil
+}
+
+// UpdateDelegated sets the delegated amounts of validators to the voting
+// policy.
+func (nr *NodeRunner) UpdateDelegated() error {
+	var validators []string
+	for address := range nr.localNode.GetValidators() {
+		validators = append(validators, address)
+	}
+
+	delegated, err := block.GetDelegatedAmounts(nr.storage, validators...)
+	if err != nil {
+		return err
+	}
+	nr.policy.SetDelegated(delegated)
+
+	return nil
+}
+
 func (nr *NodeRunner) Log() logging.Logger {
 	return nr.log
 }
@@ -539,11 +730,11 @@ func (nr *NodeRunner) handleBallotMessage(message common.NetworkMessage) (err er
 	var checkerFuncs []common.CheckerFunc
 	switch baseChecker.Ballot.State() {
 	case ballot.StateINIT:
-		checkerFuncs = DefaultHandleINITBallotCheckerFuncs
+		checkerFuncs = nr.handleINITBallotCheckerFuncs
 	case ballot.StateSIGN:
-		checkerFuncs = DefaultHandleSIGNBallotCheckerFuncs
+		checkerFuncs = nr.handleSIGNBallotCheckerFuncs
 	case ballot.StateACCEPT:
-		checkerFuncs = DefaultHandleACCEPTBallotCheckerFuncs
+		checkerFuncs = nr.handleACCEPTBallotCheckerFuncs
 	}
 
 	checker := &BallotChecker{
@@ -583,10 +774,54 @@ func (nr *NodeRunner) InitRound() {
 	// get latest blocks
 	nr.consensus.SetLatestVotingBasis(voting.Basis{})
 
+	// replay the journal before the state manager starts, the node should not
+	// vote differently for the rounds, which it already voted before stopped.
+	ballots := nr.replayJournal()
+
 	nr.waitForConnectingEnoughNodes()
+	for _, b := range ballots {
+		nr.broadcastBallot(b)
+	}
 	nr.startStateManager()
 }
 
+// replayJournal restores the `BallotSendRecord` and the latest voting basis
+// from the journal of the unfinished height. It returns the sent ballots of
+// the latest round to send them again, the other validators may not receive
+// them.
+func (nr *NodeRunner) replayJournal() (ballots []ballot.Ballot) {
+	height := nr.consensus.LatestBlock().Height
+	if height > 0 {
+		if err := nr.journal.RemoveLowerThanOrEqualHeight(height - 1); err != nil {
+			nr.log.Error("failed to remove the finished records of journal", "error", err)
+		}
+	}
+
+	var latest voting.Basis
+	for _, b := range nr.journal.Ballots(height) {
+		nr.ballotSendRecord.SetSent(consensus.ISAACState{
+			Height:      b.VotingBasis().Height,
+			Round:       b.VotingBasis().Round,
+			BallotState: b.State(),
+		})
+
+		if b.VotingBasis().Round != latest.Round {
+			ballots = nil
+		}
+		latest = b.VotingBasis()
+		ballots = append(ballots, b)
+	}
+
+	if len(ballots) < 1 {
+		return
+	}
+
+	nr.consensus.SetLatestVotingBasis(latest)
+	nr.log.Debug("replayed journal", "voting-basis", latest, "ballots", len(ballots))
+
+	return
+}
+
 func (nr *NodeRunner) waitForConnectingEnoughNodes() {
 	ticker := time.NewTicker(time.Millisecond * 5)
 	for _ = range ticker.C {
@@ -606,6 +841,14 @@ func (nr *NodeRunner) waitForConnectingEnoughNodes() {
 
 func (nr *NodeRunner) startStateManager() {
 	nr.isaacStateManager.Start()
+
+	// resume from the state recorded in journal
+	if state, found := nr.journal.LatestState(nr.consensus.LatestBlock().Height); found {
+		nr.log.Debug("resume ISAACState from journal", "state", state)
+		nr.isaacStateManager.TransitISAACState(state.Height, state.Round, state.BallotState)
+		return
+	}
+
 	nr.isaacStateManager.NextHeight()
 	return
 }
@@ -625,6 +868,29 @@ func (nr *NodeRunner) NextHeight() {
 
 func (nr *NodeRunner) RemoveSendRecordsLowerThanOrEqualHeight(height uint64) {
 	nr.ballotSendRecord.RemoveLowerThanOrEqualHeight(height)
+	if err := nr.journal.RemoveLowerThanOrEqualHeight(height); err != nil {
+		nr.log.Error("failed to remove the finished records of journal", "error", err, "height", height)
+	}
+}
+
+func (nr *NodeRunner) Journal() *consensus.Journal {
+	return nr.journal
+}
+
+func (nr *NodeRunner) StateSnapshotter() *StateSnapshotter {
+	return nr.stateSnapshotter
+}
+
+// StoragePruner returns the `StoragePruner`; it is nil if the storage mode is
+// not `common.StorageModePruned`.
+func (nr *NodeRunner) StoragePruner() *StoragePruner {
+	return nr.storagePruner
+}
+
+// SetStateSnapshotter replaces the `StateSnapshotter`; it must be called
+// before `Ready()`.
+func (nr *NodeRunner) SetStateSnapshotter(s *StateSnapshotter) {
+	nr.stateSnapshotter = s
 }
 
 var NewBallotTransactionCheckerFuncs = []common.CheckerFunc{
@@ -674,6 +940,11 @@ func (nr *NodeRunner) proposeNewBallot(round uint64) (ballot.Ballot, error) {
 		)
 	}
 
+	height, latestTime, err := getTimeBoundsBasis(nr.Storage())
+	if err != nil {
+		return ballot.Ballot{}, err
+	}
+
 	var validTransactions []transaction.Transaction
 	var validTransactionHashes []string
 	var ops int
@@ -687,6 +958,11 @@ func (nr *NodeRunner) proposeNewBallot(round uint64) (ballot.Ballot, error) {
 			return ballot.Ballot{}, errors.TransactionNotFound
 		}
 
+		// NOTE the transaction, which is not yet valid, is kept in `Pool`
+		if tx.CheckTimeBounds(height, latestTime) != nil {
+			continue
+		}
+
 		if ops+len(tx.B.Operations) > nr.Conf.OpsInBallotLimit {
 			continue
 		}
@@ -720,6 +996,17 @@ func (nr *NodeRunner) proposeNewBallot(round uint64) (ballot.Ballot, error) {
 	}
 
 	blt.SetProposerTransaction(ptx)
+
+	var txs []*transaction.Transaction
+	for i := range validTransactions {
+		txs = append(txs, &validTransactions[i])
+	}
+	stateRoot, err := MakeStateRoot(nr.Storage(), txs, ptx)
+	if err != nil {
+		nr.log.Error("failed to make state root", "error", err)
+		return ballot.Ballot{}, err
+	}
+	blt.SetStateRoot(stateRoot)
 	blt.Sign(nr.localNode.Keypair(), nr.Conf.NetworkID)
 
 	nr.log.Debug(
@@ -728,6 +1015,7 @@ func (nr *NodeRunner) proposeNewBallot(round uint64) (ballot.Ballot, error) {
 		"basis", basis,
 		"valid-transactions", len(validTransactions),
 		"transactionpool", nr.TransactionPool.Len(),
+		"state-root", stateRoot.Hex(),
 	)
 
 	nr.BroadcastBallot(*blt)
@@ -763,8 +1051,19 @@ func (nr *NodeRunner) BroadcastBallot(b ballot.Ballot) {
 		"ballot", b,
 	)
 
+	// the ballot should be written in journal before sent; if not, the node
+	// can send the different ballot for the same state after restarted.
+	if err := nr.journal.WriteBallot(b); err != nil {
+		nr.Log().Error("failed to write ballot in journal", "error", err, "ballot", b)
+		return
+	}
+
 	nr.ballotSendRecord.SetSent(state)
 
+	nr.broadcastBallot(b)
+}
+
+func (nr *NodeRunner) broadcastBallot(b ballot.Ballot) {
 	go func() {
 		encoded, _ := b.Serialize()
 		nr.Network().MessageBroker().Receive(common.NewNetworkMessage(common.BallotMessage, encoded))
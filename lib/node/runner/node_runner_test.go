@@ -267,7 +267,7 @@ func TestExpiredBallotCheckProposer(t *testing.T) {
 	}
 
 	// The createNodeRunnerForTesting has FixedSelector{localNode.Address()} so the proposer is always nr(nodes[0]).
-	validBallot := GenerateEmptyTxBallot(nr.localNode, basis, ballot.StateSIGN, nodes[1], conf)
+	validBallot := GenerateEmptyTxBallot(nr.Storage(), nr.localNode, basis, ballot.StateSIGN, nodes[1], conf)
 	validBallot.SetVote(ballot.StateSIGN, voting.EXP)
 
 	checker := &BallotChecker{
@@ -288,7 +288,7 @@ func TestExpiredBallotCheckProposer(t *testing.T) {
 
 	// The createNodeRunnerForTesting has FixedSelector{localNode.Address()} so the proposer is always nr(nodes[0]).
 	// The invalidBallot has nodes[1] as a proposer so it is invalid.
-	invalidBallot := GenerateEmptyTxBallot(nodes[1], basis, ballot.StateSIGN, nodes[1], common.NewTestConfig())
+	invalidBallot := GenerateEmptyTxBallot(nr.Storage(), nodes[1], basis, ballot.StateSIGN, nodes[1], common.NewTestConfig())
 	invalidBallot.SetVote(ballot.StateSIGN, voting.EXP)
 
 	checker = &BallotChecker{
@@ -309,6 +309,16 @@ func TestExpiredBallotCheckProposer(t *testing.T) {
 	require.Equal(t, voting.NO, checker.VotingHole)
 }
 
+// makeProposableTransaction makes the transaction with `n` payments, which can
+// be applied to the latest block of `nr`.
+func makeProposableTransaction(nr *NodeRunner, n int) transaction.Transaction {
+	kpSource, kpTarget := keypair.Random(), keypair.Random()
+	block.NewBlockAccount(kpSource.Address(), common.MaximumBalance/2).MustSave(nr.Storage())
+	block.NewBlockAccount(kpTarget.Address(), common.BaseReserve).MustSave(nr.Storage())
+
+	return transaction.TestMakeTransactionWithKeypair(networkID, n, kpSource, kpTarget)
+}
+
 // NodeRunner must propose new ballot by common.Config.OpsInBallotLimit.
 func TestProposedBallotByOpsInBallotLimit(t *testing.T) {
 	{ // limit=100 tx0=50, tx1=50; tx0 and tx1 will be in ballot
@@ -320,10 +330,10 @@ func TestProposedBallotByOpsInBallotLimit(t *testing.T) {
 
 		var txs []string
 
-		_, tx0 := transaction.TestMakeTransaction(networkID, 50)
+		tx0 := makeProposableTransaction(nr, 50)
 		txs = append(txs, tx0.GetHash())
 		nr.TransactionPool.Add(tx0)
-		_, tx1 := transaction.TestMakeTransaction(networkID, 50)
+		tx1 := makeProposableTransaction(nr, 50)
 		nr.TransactionPool.Add(tx1)
 		txs = append(txs, tx1.GetHash())
 
@@ -353,10 +363,10 @@ func TestProposedBallotByOpsInBallotLimit(t *testing.T) {
 
 		var txs []string
 
-		_, tx0 := transaction.TestMakeTransaction(networkID, 50)
+		tx0 := makeProposableTransaction(nr, 50)
 		txs = append(txs, tx0.GetHash())
 		nr.TransactionPool.Add(tx0)
-		_, tx1 := transaction.TestMakeTransaction(networkID, 51)
+		tx1 := makeProposableTransaction(nr, 51)
 		nr.TransactionPool.Add(tx1)
 		txs = append(txs, tx1.GetHash())
 
@@ -386,13 +396,13 @@ func TestProposedBallotByOpsInBallotLimit(t *testing.T) {
 
 		var txs []string
 
-		_, tx0 := transaction.TestMakeTransaction(networkID, 50)
+		tx0 := makeProposableTransaction(nr, 50)
 		txs = append(txs, tx0.GetHash())
 		nr.TransactionPool.Add(tx0)
-		_, tx1 := transaction.TestMakeTransaction(networkID, 51)
+		tx1 := makeProposableTransaction(nr, 51)
 		nr.TransactionPool.Add(tx1)
 		txs = append(txs, tx1.GetHash())
-		_, tx2 := transaction.TestMakeTransaction(networkID, 10)
+		tx2 := makeProposableTransaction(nr, 10)
 		nr.TransactionPool.Add(tx2)
 		txs = append(txs, tx2.GetHash())
 
@@ -424,16 +434,16 @@ func TestProposedBallotByOpsInBallotLimit(t *testing.T) {
 
 		var txs []string
 
-		_, tx0 := transaction.TestMakeTransaction(networkID, 50)
+		tx0 := makeProposableTransaction(nr, 50)
 		txs = append(txs, tx0.GetHash())
 		nr.TransactionPool.Add(tx0)
-		_, tx1 := transaction.TestMakeTransaction(networkID, 51)
+		tx1 := makeProposableTransaction(nr, 51)
 		nr.TransactionPool.Add(tx1)
 		txs = append(txs, tx1.GetHash())
-		_, tx2 := transaction.TestMakeTransaction(networkID, 10)
+		tx2 := makeProposableTransaction(nr, 10)
 		nr.TransactionPool.Add(tx2)
 		txs = append(txs, tx2.GetHash())
-		_, tx3 := transaction.TestMakeTransaction(networkID, 40)
+		tx3 := makeProposableTransaction(nr, 40)
 		nr.TransactionPool.Add(tx3)
 		txs = append(txs, tx3.GetHash())
 
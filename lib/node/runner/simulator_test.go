@@ -0,0 +1,98 @@
+package runner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/common"
+)
+
+func requireSameChain(t *testing.T, nodes []*SimulatedNode) {
+	height := nodes[0].Consensus().LatestBlock().Height
+	for _, n := range nodes[1:] {
+		if h := n.Consensus().LatestBlock().Height; h < height {
+			height = h
+		}
+	}
+
+	for h := uint64(common.GenesisBlockHeight); h <= height; h++ {
+		expected, err := block.GetBlockByHeight(nodes[0].Storage(), h)
+		require.NoError(t, err)
+		for _, n := range nodes[1:] {
+			blk, err := block.GetBlockByHeight(n.Storage(), h)
+			require.NoError(t, err)
+			require.Equal(t, expected.Hash, blk.Hash, "height=%d node=%d", h, n.Index)
+		}
+	}
+}
+
+func TestSimulatorConsensus(t *testing.T) {
+	s := NewSimulator(4, 1, common.NewTestConfig())
+	s.SetDelay(10*time.Millisecond, 200*time.Millisecond)
+	s.Start()
+
+	require.True(t, s.RunUntil(reachHeight(s.Nodes(), 5), time.Minute))
+	requireSameChain(t, s.Nodes())
+}
+
+// TestSimulatorDeterministic checks the simulators with the same seed make the
+// same rounds and proposers, even though the ballots are reordered and
+// dropped.
+func TestSimulatorDeterministic(t *testing.T) {
+	type history struct {
+		height   uint64
+		round    uint64
+		proposer string
+	}
+
+	run := func(seed int64) (histories [][]history) {
+		s := NewSimulator(4, seed, common.NewTestConfig())
+		s.SetDelay(10*time.Millisecond, 500*time.Millisecond)
+		s.SetDropRate(0.05)
+		s.Start()
+		require.True(t, s.RunUntil(reachHeight(s.Nodes(), 4), 10*time.Minute))
+
+		for _, n := range s.Nodes() {
+			var hs []history
+			for h := uint64(common.GenesisBlockHeight); h <= n.Consensus().LatestBlock().Height; h++ {
+				blk, err := block.GetBlockByHeight(n.Storage(), h)
+				require.NoError(t, err)
+				hs = append(hs, history{height: blk.Height, round: blk.Round, proposer: blk.Proposer})
+			}
+			histories = append(histories, hs)
+		}
+
+		return
+	}
+
+	expected := run(7)
+	require.Equal(t, 4, len(expected[0]))
+	require.Equal(t, expected, run(7))
+}
+
+// TestSimulatorPartition partitions 2 of 7 validators while the others make 3
+// blocks; after the partition is healed, all the validators have the same
+// chain.
+func TestSimulatorPartition(t *testing.T) {
+	s := NewSimulator(7, 3, common.NewTestConfig())
+	s.SetDelay(50*time.Millisecond, 100*time.Millisecond)
+	s.Start()
+
+	require.True(t, s.RunUntil(reachHeight(s.Nodes(), 2), time.Minute))
+
+	s.Partition([]int{0, 1, 2, 3, 4}, []int{5, 6})
+	majority, minority := s.Nodes()[:5], s.Nodes()[5:]
+	height := minority[0].Consensus().LatestBlock().Height
+
+	require.True(t, s.RunUntil(reachHeight(majority, height+3), time.Minute))
+	for _, n := range minority {
+		require.Equal(t, height, n.Consensus().LatestBlock().Height)
+	}
+
+	s.Heal()
+	require.True(t, s.RunUntil(reachHeight(s.Nodes(), height+5), 5*time.Minute))
+	requireSameChain(t, s.Nodes())
+}
@@ -0,0 +1,318 @@
+package runner
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+
+	logging "github.com/inconshreveable/log15"
+
+	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/node"
+	"boscoin.io/sebak/lib/storage"
+	"boscoin.io/sebak/lib/storage/statedb/trie"
+)
+
+// StateSnapshotRecordPrefixes are the prefixes of the records, which are
+// exported in the chunks of `block.StateSnapshot`; they are the state, which
+// is not in the state trie.
+var StateSnapshotRecordPrefixes = []string{
+	common.CongressVotingPrefixHash,
+	common.CongressVotingPrefixEnd,
+	common.CongressVotePrefixVoting,
+	common.CongressVotingPrefixHeight,
+	common.CongressVotingPrefixResult,
+	common.CongressVotingPrefixFunding,
+	common.UnfreezingRequestPrefixAddress,
+	common.DelegationPrefixDelegator,
+	common.DelegationPrefixValidator,
+	common.ValidatorSetChangePrefixHeight,
+}
+
+// MaxStateSnapshots is the number of the latest snapshots, which are kept to
+// be served; the node, which is downloading the older one, can continue
+// while the new one is taken.
+const MaxStateSnapshots = 2
+
+type takenStateSnapshot struct {
+	snapshot *block.StateSnapshot
+	chunks   []block.StateSnapshotChunk
+	st       *storage.LevelDBBackend // storage snapshot for the trie nodes
+}
+
+// StateSnapshotter takes the `block.StateSnapshot` at every `interval`
+// blocks and serves the taken snapshots to the other nodes.
+type StateSnapshotter struct {
+	sync.RWMutex
+
+	st        *storage.LevelDBBackend
+	localNode *node.LocalNode
+	networkID []byte
+	interval  uint64
+	snapshots []takenStateSnapshot // the latest one is the last
+
+	log logging.Logger
+}
+
+func NewStateSnapshotter(st *storage.LevelDBBackend, localNode *node.LocalNode, conf common.Config) *StateSnapshotter {
+	return &StateSnapshotter{
+		st:        st,
+		localNode: localNode,
+		networkID: conf.NetworkID,
+		interval:  conf.StateSnapshotInterval,
+		log:       log.New(logging.Ctx{"node": localNode.Alias()}),
+	}
+}
+
+// Take takes the snapshot of state after `blk` is stored; nothing is taken
+// if the height of `blk` is not on the interval.
+func (s *StateSnapshotter) Take(blk block.Block) (*block.StateSnapshot, error) {
+	if s.interval < 1 || blk.Height%s.interval != 0 {
+		return nil, nil
+	}
+
+	st, err := s.st.OpenSnapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	var chunks []block.StateSnapshotChunk
+	if chunks, err = MakeStateSnapshotChunks(st); err != nil {
+		st.Release()
+		return nil, err
+	}
+
+	snapshot := block.NewStateSnapshot(blk, chunks...)
+	snapshot.Sign(s.localNode.Keypair(), s.networkID)
+
+	s.Lock()
+	s.snapshots = append(s.snapshots, takenStateSnapshot{snapshot: snapshot, chunks: chunks, st: st})
+	for len(s.snapshots) > MaxStateSnapshots {
+		s.snapshots[0].st.Release()
+		s.snapshots = s.snapshots[1:]
+	}
+	s.Unlock()
+
+	s.log.Debug("state snapshot taken", "height", blk.Height, "chunks", len(chunks))
+
+	return snapshot, nil
+}
+
+// Latest returns the latest snapshot.
+func (s *StateSnapshotter) Latest() (*block.StateSnapshot, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	if len(s.snapshots) < 1 {
+		return nil, errors.StateSnapshotNotFound
+	}
+
+	return s.snapshots[len(s.snapshots)-1].snapshot, nil
+}
+
+// Snapshot returns the snapshot at `height`.
+func (s *StateSnapshotter) Snapshot(height uint64) (*block.StateSnapshot, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	taken, found := s.get(height)
+	if !found {
+		return nil, errors.StateSnapshotNotFound
+	}
+
+	return taken.snapshot, nil
+}
+
+// Chunk returns the chunk of snapshot at `height` by the index in
+// `StateSnapshotBody.Chunks`.
+func (s *StateSnapshotter) Chunk(height uint64, index int) (block.StateSnapshotChunk, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	taken, found := s.get(height)
+	if !found || index < 0 || index >= len(taken.chunks) {
+		return nil, errors.StateSnapshotNotFound
+	}
+
+	return taken.chunks[index], nil
+}
+
+// TrieNodes returns the nodes of state trie of the snapshot at `height` by
+// their hashes.
+func (s *StateSnapshotter) TrieNodes(height uint64, hashes []common.Hash) ([][]byte, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	taken, found := s.get(height)
+	if !found {
+		return nil, errors.StateSnapshotNotFound
+	}
+
+	db := trie.NewEthDatabase(taken.st)
+
+	var nodes [][]byte
+	for _, hash := range hashes {
+		b, err := db.Get(hash.Bytes())
+		if err != nil {
+			return nil, errors.StorageRecordDoesNotExist
+		}
+		nodes = append(nodes, b)
+	}
+
+	return nodes, nil
+}
+
+// Close releases the taken snapshots.
+func (s *StateSnapshotter) Close() {
+	s.Lock()
+	defer s.Unlock()
+
+	for _, taken := range s.snapshots {
+		taken.st.Release()
+	}
+	s.snapshots = nil
+}
+
+func (s *StateSnapshotter) get(height uint64) (takenStateSnapshot, bool) {
+	for _, taken := range s.snapshots {
+		if taken.snapshot.B.Height == height {
+			return taken, true
+		}
+	}
+
+	return takenStateSnapshot{}, false
+}
+
+// MakeStateSnapshotChunks exports the records of `StateSnapshotRecordPrefixes`
+// by the order of key. The `BlockTransaction`s and `BlockOperation`s, which
+// are referred by the congress votings, are also exported, because they are
+// needed to validate the congress operations.
+func MakeStateSnapshotChunks(st *storage.LevelDBBackend) (chunks []block.StateSnapshotChunk, err error) {
+	var records []block.StateSnapshotRecord
+	txHashes := map[string]bool{}
+
+	for _, prefix := range StateSnapshotRecordPrefixes {
+		iterFunc, closeFunc := st.GetIterator(prefix, nil)
+		for {
+			item, hasNext := iterFunc()
+			if !hasNext {
+				break
+			}
+			// the key and value of iterator are reused by the next item
+			records = append(records, block.StateSnapshotRecord{
+				Key:   append([]byte{}, item.Key...),
+				Value: append([]byte{}, item.Value...),
+			})
+
+			if prefix != common.CongressVotingPrefixHash {
+				continue
+			}
+
+			var cv block.CongressVoting
+			if err = json.Unmarshal(item.Value, &cv); err != nil {
+				closeFunc()
+				return
+			}
+			txHashes[cv.TxHash] = true
+			for _, hash := range append(cv.Results, cv.Funding) {
+				if i := strings.LastIndex(hash, "-"); i > 0 {
+					txHashes[hash[:i]] = true
+				}
+			}
+		}
+		closeFunc()
+	}
+
+	var referred []block.StateSnapshotRecord
+	if referred, err = makeReferredTransactionRecords(st, txHashes); err != nil {
+		return
+	}
+	records = append(records, referred...)
+
+	for len(records) > 0 {
+		n := common.StateSnapshotChunkSize
+		if len(records) < n {
+			n = len(records)
+		}
+		chunks = append(chunks, block.StateSnapshotChunk(records[:n]))
+		records = records[n:]
+	}
+
+	return
+}
+
+// makeReferredTransactionRecords returns the records of `BlockTransaction`s
+// and their `BlockOperation`s. The `BlockOperation`s are made from the
+// `TransactionPool` instead of storage, because they are saved
+// asynchronously.
+func makeReferredTransactionRecords(st *storage.LevelDBBackend, txHashes map[string]bool) (records []block.StateSnapshotRecord, err error) {
+	var hashes []string
+	for hash := range txHashes {
+		hashes = append(hashes, hash)
+	}
+	sort.Strings(hashes)
+
+	for _, hash := range hashes {
+		var b []byte
+		if b, err = st.GetRaw(block.GetBlockTransactionKey(hash)); err != nil {
+			if err == errors.StorageRecordDoesNotExist {
+				err = nil
+				continue
+			}
+			return
+		}
+
+		var bt block.BlockTransaction
+		var tp block.TransactionPool
+		var blk block.Block
+		if err = json.Unmarshal(b, &bt); err != nil {
+			return
+		}
+		records = append(records, block.StateSnapshotRecord{Key: []byte(block.GetBlockTransactionKey(hash)), Value: b})
+
+		if tp, err = block.GetTransactionPool(st, hash); err == errors.StorageRecordDoesNotExist {
+			// the `TransactionPool` of old block is removed by
+			// `StoragePruner`, but the `BlockOperation`s are already saved.
+			var saved []block.StateSnapshotRecord
+			if saved, err = makeSavedOperationRecords(st, bt); err != nil {
+				return
+			}
+			records = append(records, saved...)
+			continue
+		} else if err != nil {
+			return
+		}
+		if blk, err = block.GetBlock(st, bt.Block); err != nil {
+			return
+		}
+
+		tx := tp.Transaction()
+		for _, op := range tx.B.Operations {
+			var bo block.BlockOperation
+			if bo, err = block.NewBlockOperationFromOperation(op, tx, blk.Height); err != nil {
+				return
+			}
+			records = append(records, block.StateSnapshotRecord{
+				Key:   []byte(block.GetBlockOperationKey(bo.Hash)),
+				Value: common.MustMarshalJSON(bo),
+			})
+		}
+	}
+
+	return
+}
+
+func makeSavedOperationRecords(st *storage.LevelDBBackend, bt block.BlockTransaction) (records []block.StateSnapshotRecord, err error) {
+	for _, hash := range bt.Operations {
+		var b []byte
+		if b, err = st.GetRaw(block.GetBlockOperationKey(hash)); err != nil {
+			return
+		}
+		records = append(records, block.StateSnapshotRecord{Key: []byte(block.GetBlockOperationKey(hash)), Value: b})
+	}
+
+	return
+}
@@ -0,0 +1,129 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/node"
+	"boscoin.io/sebak/lib/storage/statedb"
+	"boscoin.io/sebak/lib/storage/statedb/trie"
+	"boscoin.io/sebak/lib/transaction"
+	"boscoin.io/sebak/lib/transaction/operation"
+)
+
+func TestStateSnapshotter(t *testing.T) {
+	conf := common.NewTestConfig()
+	conf.StateSnapshotInterval = 2
+	st := block.InitTestBlockchain()
+	defer st.Close()
+
+	kp := keypair.Random()
+	localNode := node.NewTestLocalNode(kp, common.MustParseEndpoint("http://localhost:12345"))
+	snapshotter := NewStateSnapshotter(st, localNode, conf)
+	defer snapshotter.Close()
+
+	{ // nothing is taken yet
+		_, err := snapshotter.Latest()
+		require.Equal(t, errors.StateSnapshotNotFound, err)
+	}
+
+	{ // genesis is not on the interval
+		s, err := snapshotter.Take(block.GetLatestBlock(st))
+		require.NoError(t, err)
+		require.Nil(t, s)
+	}
+
+	// the congress voting refers its transaction
+	kpCongress := keypair.Random()
+	block.NewBlockAccount(kpCongress.Address(), common.BaseReserve).MustSave(st)
+
+	op, err := operation.NewOperation(operation.NewCongressVoting("dummy contract", 10, 20, common.Amount(100), kpCongress.Address()))
+	require.NoError(t, err)
+	tx, err := transaction.NewTransaction(kpCongress.Address(), 0, op)
+	require.NoError(t, err)
+	tx.Sign(kpCongress, networkID)
+
+	// state trie of the block
+	sdb := statedb.New(common.Hash{}, trie.NewEthDatabase(st))
+	sdb.CreateAccount(kpCongress.Address())
+	require.NoError(t, sdb.AddBalance(kpCongress.Address(), common.BaseReserve))
+	root, err := sdb.CommitTrie()
+	require.NoError(t, err)
+	require.NoError(t, sdb.CommitDB(root))
+
+	blk := block.TestMakeNewBlockWithPrevBlock(block.GetLatestBlock(st), []string{tx.GetHash()})
+	blk.StateRoot = root
+	blk.MustSave(st)
+	bt := block.NewBlockTransactionFromTransaction(blk.Hash, blk.Height, blk.ProposedTime, tx)
+	bt.MustSave(st)
+	_, err = block.SaveTransactionPool(st, tx)
+	require.NoError(t, err)
+	require.NoError(t, FinishCongressVoting(st, blk, []*transaction.Transaction{&tx}, common.NopLogger()))
+
+	s, err := snapshotter.Take(blk)
+	require.NoError(t, err)
+	require.NotNil(t, s)
+	require.NoError(t, s.Verify(networkID))
+	require.Equal(t, kp.Address(), s.H.Source)
+	require.Equal(t, blk.Height, s.B.Height)
+	require.Equal(t, blk.Hash, s.B.Block)
+	require.Equal(t, root, s.B.StateRoot)
+	require.Equal(t, 1, len(s.B.Chunks))
+
+	latest, err := snapshotter.Latest()
+	require.NoError(t, err)
+	require.Equal(t, s.H.Hash, latest.H.Hash)
+
+	{ // the chunk has the congress voting and the referred transaction
+		chunk, err := snapshotter.Chunk(blk.Height, 0)
+		require.NoError(t, err)
+		require.Equal(t, s.B.Chunks[0], chunk.MakeHashString())
+
+		keys := map[string]bool{}
+		for _, r := range chunk {
+			keys[string(r.Key)] = true
+		}
+		require.True(t, keys[block.GetCongressVotingKey(tx.GetHash()+"-0")])
+		require.True(t, keys[block.GetBlockTransactionKey(tx.GetHash())])
+
+		// the `BlockOperation` is exported, even though it is not saved yet
+		opHash := block.NewBlockOperationKey(common.MustMakeObjectHashString(op), tx.GetHash())
+		require.True(t, keys[block.GetBlockOperationKey(opHash)])
+
+		_, err = snapshotter.Chunk(blk.Height, 1)
+		require.Equal(t, errors.StateSnapshotNotFound, err)
+	}
+
+	{ // trie nodes
+		nodes, err := snapshotter.TrieNodes(blk.Height, []common.Hash{root})
+		require.NoError(t, err)
+		require.Equal(t, 1, len(nodes))
+		require.Equal(t, root, crypto.Keccak256Hash(nodes[0]))
+
+		_, err = snapshotter.TrieNodes(blk.Height, []common.Hash{common.BytesToHash([]byte("unknown"))})
+		require.Equal(t, errors.StorageRecordDoesNotExist, err)
+
+		_, err = snapshotter.TrieNodes(blk.Height+1, []common.Hash{root})
+		require.Equal(t, errors.StateSnapshotNotFound, err)
+	}
+
+	{ // only the latest snapshots are kept
+		for i := 0; i < MaxStateSnapshots; i++ {
+			next := block.TestMakeNewBlockWithPrevBlock(block.GetLatestBlock(st), nil)
+			next.MustSave(st)
+			next = block.TestMakeNewBlockWithPrevBlock(next, nil)
+			next.MustSave(st)
+			_, err := snapshotter.Take(next)
+			require.NoError(t, err)
+		}
+
+		_, err := snapshotter.Snapshot(blk.Height)
+		require.Equal(t, errors.StateSnapshotNotFound, err)
+	}
+}
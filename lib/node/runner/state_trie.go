@@ -0,0 +1,74 @@
+package runner
+
+import (
+	"bytes"
+
+	logging "github.com/inconshreveable/log15"
+
+	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/storage"
+	"boscoin.io/sebak/lib/storage/statedb"
+	"boscoin.io/sebak/lib/storage/statedb/trie"
+)
+
+// InitStateTrie puts the accounts, which are not in the state of the latest
+// block, like the accounts stored before the state trie, into the state trie.
+// The new state root is stored by `block.SaveStateRoot()`, so the next block is
+// applied to the state, which has all the accounts.
+func InitStateTrie(st *storage.LevelDBBackend, log logging.Logger) (err error) {
+	latest := block.GetLatestBlock(st)
+
+	var root common.Hash
+	if root, err = block.GetStateRoot(st, latest); err != nil {
+		return
+	}
+	sdb := statedb.New(root, trie.NewEthDatabase(st))
+
+	var accounts []*block.BlockAccount
+	iterFunc, closeFunc := st.GetIterator(common.BlockAccountPrefixAddress, nil)
+	for {
+		item, hasNext := iterFunc()
+		if !hasNext {
+			break
+		}
+
+		var ba block.BlockAccount
+		common.MustUnmarshalJSON(item.Value, &ba)
+
+		if current, err := sdb.GetBlockAccount(ba.Address); err == nil {
+			if bytes.Equal(common.MustMarshalJSON(current), item.Value) {
+				continue
+			}
+		}
+		accounts = append(accounts, &ba)
+	}
+	closeFunc()
+
+	if len(accounts) < 1 {
+		return
+	}
+
+	for _, ba := range accounts {
+		sdb.SetBlockAccount(ba)
+	}
+
+	if root, err = sdb.CommitTrie(); err != nil {
+		return
+	}
+	if err = sdb.CommitDB(root); err != nil {
+		return
+	}
+	if err = block.SaveStateRoot(st, latest.Hash, root); err != nil {
+		return
+	}
+
+	log.Info(
+		"accounts are put into the state trie",
+		"accounts", len(accounts),
+		"height", latest.Height,
+		"state-root", root.Hex(),
+	)
+
+	return
+}
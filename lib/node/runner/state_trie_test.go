@@ -0,0 +1,62 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/storage/statedb"
+	"boscoin.io/sebak/lib/storage/statedb/trie"
+)
+
+func TestInitStateTrie(t *testing.T) {
+	st := block.InitTestBlockchain()
+	defer st.Close()
+
+	genesis := block.GetLatestBlock(st)
+
+	{ // the genesis accounts are in the state of genesis block
+		sdb := statedb.New(genesis.StateRoot, trie.NewEthDatabase(st))
+		require.True(t, sdb.ExistAccount(block.GenesisKP.Address()))
+		require.True(t, sdb.ExistAccount(block.CommonKP.Address()))
+	}
+
+	{ // nothing to put
+		require.NoError(t, InitStateTrie(st, common.NopLogger()))
+		root, err := block.GetStateRoot(st, genesis)
+		require.NoError(t, err)
+		require.Equal(t, genesis.StateRoot, root)
+	}
+
+	// the account, which is stored outside of the state trie
+	kp := keypair.Random()
+	ba := block.NewBlockAccount(kp.Address(), common.BaseReserve)
+	require.NoError(t, ba.Save(st))
+
+	{
+		sdb := statedb.New(genesis.StateRoot, trie.NewEthDatabase(st))
+		require.False(t, sdb.ExistAccount(kp.Address()))
+	}
+
+	require.NoError(t, InitStateTrie(st, common.NopLogger()))
+
+	root, err := block.GetStateRoot(st, genesis)
+	require.NoError(t, err)
+	require.NotEqual(t, genesis.StateRoot, root)
+
+	sdb := statedb.New(root, trie.NewEthDatabase(st))
+	require.True(t, sdb.ExistAccount(block.GenesisKP.Address()))
+	stored, err := sdb.GetBlockAccount(kp.Address())
+	require.NoError(t, err)
+	require.Equal(t, ba.Balance, stored.Balance)
+
+	{ // the next time, nothing is changed
+		require.NoError(t, InitStateTrie(st, common.NopLogger()))
+		again, err := block.GetStateRoot(st, genesis)
+		require.NoError(t, err)
+		require.Equal(t, root, again)
+	}
+}
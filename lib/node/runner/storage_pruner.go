@@ -0,0 +1,277 @@
+package runner
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	logging "github.com/inconshreveable/log15"
+
+	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/metrics"
+	"boscoin.io/sebak/lib/storage"
+)
+
+const (
+	// PruneInterval is the interval to check the blocks to be pruned.
+	PruneInterval = 1 * time.Minute
+	// PruneBatchSize is the maximum number of blocks pruned in one batch.
+	PruneBatchSize uint64 = 100
+)
+
+// StoragePruner removes the history of the blocks, which are older than the
+// latest `keep` blocks, in `common.StorageModePruned`:
+//   - `TransactionPool` records of the transactions in block
+//   - `BlockAccountSequenceID`s, which are replaced by the transactions in block
+//
+// The blocks, `BlockTransaction`s, `BlockOperation`s and the current state are
+// kept. The blocks, whose `BlockOperation`s are not saved yet, are not
+// pruned, because `SavingBlockOperations` needs the `TransactionPool`.
+type StoragePruner struct {
+	st       *storage.LevelDBBackend
+	keep     uint64
+	interval time.Duration
+
+	prunedHeight uint64
+	stop         chan struct{}
+	stopOnce     sync.Once
+
+	log logging.Logger
+}
+
+func NewStoragePruner(st *storage.LevelDBBackend, keep uint64, logger logging.Logger) *StoragePruner {
+	if logger == nil {
+		logger = log
+	}
+
+	p := &StoragePruner{
+		st:       st,
+		keep:     keep,
+		interval: PruneInterval,
+		stop:     make(chan struct{}),
+		log:      logger.New(logging.Ctx{"m": "StoragePruner"}),
+	}
+	p.prunedHeight = p.getPrunedHeight()
+
+	return p
+}
+
+func getPrunedBlockKey() string {
+	return fmt.Sprintf("%s-last-pruned-block", common.InternalPrefix)
+}
+
+func (p *StoragePruner) getPrunedHeight() uint64 {
+	var pruned uint64
+	if err := p.st.Get(getPrunedBlockKey(), &pruned); err != nil {
+		if err != errors.StorageRecordDoesNotExist {
+			p.log.Error("failed to get pruned height", "error", err)
+		}
+		// the transactions of genesis block are kept
+		return common.GenesisBlockHeight
+	}
+
+	return pruned
+}
+
+// PrunedHeight returns the height of the last pruned block.
+func (p *StoragePruner) PrunedHeight() uint64 {
+	return p.prunedHeight
+}
+
+func (p *StoragePruner) Start() {
+	p.log.Debug("start pruning", "keep", p.keep, "pruned", p.prunedHeight)
+
+	for {
+		if err := p.Prune(); err != nil {
+			p.log.Error("failed to prune", "error", err)
+		}
+
+		select {
+		case <-p.stop:
+			return
+		case <-time.After(p.interval):
+		}
+	}
+}
+
+func (p *StoragePruner) Stop() {
+	p.stopOnce.Do(func() {
+		close(p.stop)
+	})
+}
+
+// Prune removes the history of the blocks until the latest `keep` blocks.
+func (p *StoragePruner) Prune() (err error) {
+	to := p.prunableHeight()
+	for p.prunedHeight < to {
+		end := p.prunedHeight + PruneBatchSize
+		if end > to {
+			end = to
+		}
+
+		begin := time.Now()
+		if err = p.pruneBlocks(p.prunedHeight+1, end); err != nil {
+			return
+		}
+		metrics.Storage.ObservePruneDurationSeconds(begin)
+		metrics.Storage.SetPrunedHeight(end)
+
+		p.log.Debug("pruned", "from", p.prunedHeight+1, "to", end)
+		p.prunedHeight = end
+	}
+
+	return
+}
+
+// prunableHeight returns the highest height, which can be pruned.
+func (p *StoragePruner) prunableHeight() uint64 {
+	latest := block.GetLatestBlock(p.st).Height
+	if latest <= p.keep {
+		return p.prunedHeight
+	}
+	height := latest - p.keep
+
+	var checked uint64
+	if err := p.st.Get(getCheckedBlockKey(), &checked); err != nil {
+		return p.prunedHeight
+	}
+	if checked < height {
+		height = checked
+	}
+
+	return height
+}
+
+func (p *StoragePruner) pruneBlocks(from, to uint64) (err error) {
+	var tpKeys []string
+	sequenceIDKeys := map[ /* address */ string]map[string]bool{}
+
+	for height := from; height <= to; height++ {
+		var blk block.Block
+		if blk, err = block.GetBlockByHeight(p.st, height); err != nil {
+			if err == errors.StorageRecordDoesNotExist {
+				// the blocks before the state snapshot are not stored
+				err = nil
+				continue
+			}
+			return
+		}
+
+		tpKeys = append(tpKeys, block.GetTransactionPoolKey(blk.ProposerTransaction))
+		for _, hash := range blk.Transactions {
+			tpKeys = append(tpKeys, block.GetTransactionPoolKey(hash))
+
+			var bt block.BlockTransaction
+			if bt, err = block.GetBlockTransaction(p.st, hash); err != nil {
+				return
+			}
+
+			// the sequence id of transaction is replaced by the transaction,
+			// but the current one must be kept.
+			var ba *block.BlockAccount
+			if ba, err = block.GetBlockAccount(p.st, bt.Source); err != nil {
+				if err == errors.StorageRecordDoesNotExist {
+					err = nil
+					continue
+				}
+				return
+			}
+			if ba.SequenceID <= bt.SequenceID {
+				continue
+			}
+
+			if _, found := sequenceIDKeys[bt.Source]; !found {
+				sequenceIDKeys[bt.Source] = map[string]bool{}
+			}
+			sequenceIDKeys[bt.Source][block.GetBlockAccountSequenceIDKey(bt.Source, bt.SequenceID)] = true
+		}
+	}
+
+	var bs *storage.LevelDBBackend
+	if bs, err = p.st.OpenBatch(); err != nil {
+		return
+	}
+
+	var prunedTxs, prunedSequenceIDs int
+	if prunedTxs, err = p.remove(bs, tpKeys); err != nil {
+		bs.Discard()
+		return
+	}
+
+	for address, keys := range sequenceIDKeys {
+		var n int
+		if n, err = p.removeSequenceIDs(bs, address, keys); err != nil {
+			bs.Discard()
+			return
+		}
+		prunedSequenceIDs += n
+	}
+
+	if err = bs.Core.Put([]byte(getPrunedBlockKey()), common.MustMarshalJSON(to), nil); err != nil {
+		bs.Discard()
+		return
+	}
+
+	if err = bs.Commit(); err != nil {
+		bs.Discard()
+		return
+	}
+
+	metrics.Storage.AddPruned(metrics.StoragePruneTransactionPool, prunedTxs)
+	metrics.Storage.AddPruned(metrics.StoragePruneSequenceID, prunedSequenceIDs)
+
+	return
+}
+
+// remove removes the existing records of keys.
+func (p *StoragePruner) remove(bs *storage.LevelDBBackend, keys []string) (removed int, err error) {
+	for _, key := range keys {
+		var exists bool
+		if exists, err = p.st.Has(key); err != nil {
+			return
+		} else if !exists {
+			continue
+		}
+
+		if err = bs.Core.Delete([]byte(key), nil); err != nil {
+			return
+		}
+		removed++
+	}
+
+	return
+}
+
+// removeSequenceIDs removes the `BlockAccountSequenceID`s of keys and their
+// index by address.
+func (p *StoragePruner) removeSequenceIDs(bs *storage.LevelDBBackend, address string, keys map[string]bool) (removed int, err error) {
+	var indexKeys []string
+
+	iterFunc, closeFunc := p.st.GetIterator(block.GetBlockAccountSequenceIDByAddressKeyPrefix(address), nil)
+	for {
+		item, hasNext := iterFunc()
+		if !hasNext {
+			break
+		}
+
+		var key string
+		common.MustUnmarshalJSON(item.Value, &key)
+		if keys[key] {
+			indexKeys = append(indexKeys, string(item.Key))
+		}
+	}
+	closeFunc()
+
+	if _, err = p.remove(bs, indexKeys); err != nil {
+		return
+	}
+
+	var sequenceIDKeys []string
+	for key := range keys {
+		sequenceIDKeys = append(sequenceIDKeys, key)
+	}
+
+	return p.remove(bs, sequenceIDKeys)
+}
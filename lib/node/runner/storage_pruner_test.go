@@ -0,0 +1,137 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/storage"
+	"boscoin.io/sebak/lib/transaction"
+	"boscoin.io/sebak/lib/transaction/operation"
+)
+
+func TestStoragePruner(t *testing.T) {
+	st := block.InitTestBlockchain()
+	defer st.Close()
+
+	genesis := block.GetLatestBlock(st)
+	genesisTxExists, err := st.Has(block.GetTransactionPoolKey(genesis.Transactions[0]))
+	require.NoError(t, err)
+
+	kp := keypair.Random()
+	ba := block.NewBlockAccount(kp.Address(), common.BaseReserve)
+	ba.MustSave(st)
+
+	// every block has the transaction, which increases the sequence id of
+	// account
+	var txs []transaction.Transaction
+	for i := 0; i < 5; i++ {
+		op, err := operation.NewOperation(operation.NewPayment(keypair.Random().Address(), common.Amount(1)))
+		require.NoError(t, err)
+		tx, err := transaction.NewTransaction(kp.Address(), ba.SequenceID, op)
+		require.NoError(t, err)
+		tx.Sign(kp, networkID)
+
+		blk := block.TestMakeNewBlockWithPrevBlock(block.GetLatestBlock(st), []string{tx.GetHash()})
+		blk.MustSave(st)
+		bt := block.NewBlockTransactionFromTransaction(blk.Hash, blk.Height, blk.ProposedTime, tx)
+		bt.MustSave(st)
+		_, err = block.SaveTransactionPool(st, tx)
+		require.NoError(t, err)
+
+		ba.IncreaseSequenceID()
+		ba.MustSave(st)
+
+		txs = append(txs, tx)
+	}
+	require.Equal(t, uint64(6), block.GetLatestBlock(st).Height)
+
+	existsTx := func(tx transaction.Transaction) bool {
+		exists, err := block.ExistsTransactionPool(st, tx.GetHash())
+		require.NoError(t, err)
+		return exists
+	}
+	existsSequenceID := func(sequenceID uint64) bool {
+		_, err := block.GetBlockAccountSequenceID(st, kp.Address(), sequenceID)
+		if err == errors.StorageRecordDoesNotExist {
+			return false
+		}
+		require.NoError(t, err)
+		return true
+	}
+
+	pruner := NewStoragePruner(st, 2, nil)
+	require.Equal(t, common.GenesisBlockHeight, pruner.PrunedHeight())
+
+	{ // the block operations are not saved yet
+		require.NoError(t, pruner.Prune())
+		require.Equal(t, common.GenesisBlockHeight, pruner.PrunedHeight())
+		require.True(t, existsTx(txs[0]))
+	}
+
+	{ // the blocks, whose block operations are not saved, are not pruned
+		require.NoError(t, SetBlockOperationsCheckedHeight(st, 2))
+		require.NoError(t, pruner.Prune())
+		require.Equal(t, uint64(2), pruner.PrunedHeight())
+		require.False(t, existsTx(txs[0]))
+		require.True(t, existsTx(txs[1]))
+		require.False(t, existsSequenceID(0))
+		require.True(t, existsSequenceID(1))
+	}
+
+	{ // the latest 2 blocks are kept
+		require.NoError(t, SetBlockOperationsCheckedHeight(st, 6))
+		require.NoError(t, pruner.Prune())
+		require.Equal(t, uint64(4), pruner.PrunedHeight())
+
+		for i, tx := range txs {
+			require.Equal(t, i >= 3, existsTx(tx), "transaction %d", i)
+		}
+		for i := uint64(0); i <= 5; i++ {
+			require.Equal(t, i >= 3, existsSequenceID(i), "sequence id %d", i)
+		}
+
+		// the index by address refers only the kept ones
+		var sequenceIDs []uint64
+		iterFunc, closeFunc := block.GetBlockAccountSequenceIDByAddress(st, kp.Address(), storage.NewDefaultListOptions(false, nil, 10))
+		for {
+			s, hasNext, _ := iterFunc()
+			if !hasNext {
+				break
+			}
+			sequenceIDs = append(sequenceIDs, s.SequenceID)
+		}
+		closeFunc()
+		require.ElementsMatch(t, []uint64{3, 4, 5}, sequenceIDs)
+	}
+
+	{ // the blocks, genesis and the current state are kept
+		for height := common.GenesisBlockHeight; height <= 6; height++ {
+			_, err := block.GetBlockByHeight(st, height)
+			require.NoError(t, err)
+		}
+		for _, tx := range txs {
+			_, err := block.GetBlockTransaction(st, tx.GetHash())
+			require.NoError(t, err)
+		}
+
+		exists, err := st.Has(block.GetTransactionPoolKey(genesis.Transactions[0]))
+		require.NoError(t, err)
+		require.Equal(t, genesisTxExists, exists)
+
+		current, err := block.GetBlockAccount(st, kp.Address())
+		require.NoError(t, err)
+		require.Equal(t, uint64(5), current.SequenceID)
+	}
+
+	{ // the pruned height is kept after restart
+		pruner = NewStoragePruner(st, 2, nil)
+		require.Equal(t, uint64(4), pruner.PrunedHeight())
+		require.NoError(t, pruner.Prune())
+		require.Equal(t, uint64(4), pruner.PrunedHeight())
+	}
+}
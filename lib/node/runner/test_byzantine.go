@@ -0,0 +1,297 @@
+package runner
+
+import (
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"boscoin.io/sebak/lib/ballot"
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/consensus"
+	"boscoin.io/sebak/lib/voting"
+)
+
+// ByzantineScenario describes the simulation with the faulty validators. It
+// is written in yaml, for example,
+//
+//	seed: 1
+//	validators: 7
+//	delay-min: 10ms
+//	delay-max: 200ms
+//	height: 5
+//	limit: 5m
+//	byzantine:
+//	  - node: 5
+//	    vote-no: 0.5
+//	    double-vote: 0.5
+//	  - node: 6
+//	    withhold: 0.3
+//	    invalid-proposer-transaction: true
+type ByzantineScenario struct {
+	Seed       int64               `yaml:"seed"`
+	Validators int                 `yaml:"validators"`
+	Threshold  int                 `yaml:"threshold"` // percentage of voting threshold policy; default is 66
+	DelayMin   time.Duration       `yaml:"delay-min"`
+	DelayMax   time.Duration       `yaml:"delay-max"`
+	DropRate   float64             `yaml:"drop-rate"`
+	Height     uint64              `yaml:"height"` // the honest validators should reach
+	Limit      time.Duration       `yaml:"limit"`  // in virtual time
+	Byzantine  []ByzantineBehavior `yaml:"byzantine"`
+}
+
+// ByzantineBehavior is the faults of the validator, `Node` is the index of
+// validator in `Simulator`. The probabilities are decided for each ballot.
+type ByzantineBehavior struct {
+	Node int `yaml:"node"`
+
+	// VoteNO votes NO in SIGN and ACCEPT with the probability, regardless of
+	// the result of checking ballot.
+	VoteNO float64 `yaml:"vote-no"`
+
+	// InvalidProposerTransaction makes the `ProposerTransaction` of the
+	// proposed ballot have the wrong inflation amount.
+	InvalidProposerTransaction bool `yaml:"invalid-proposer-transaction"`
+
+	// DoubleVote sends the conflicting ballot, which has the opposite vote,
+	// to the half of validators with the probability.
+	DoubleVote float64 `yaml:"double-vote"`
+
+	// Withhold does not send the ballot to the other validators with the
+	// probability.
+	Withhold float64 `yaml:"withhold"`
+}
+
+func LoadByzantineScenario(path string) (*ByzantineScenario, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewByzantineScenarioFromYAML(b)
+}
+
+func NewByzantineScenarioFromYAML(b []byte) (sc *ByzantineScenario, err error) {
+	sc = &ByzantineScenario{Threshold: 66}
+	if err = yaml.UnmarshalStrict(b, sc); err != nil {
+		return nil, err
+	}
+
+	if err = sc.IsWellFormed(); err != nil {
+		return nil, err
+	}
+
+	return
+}
+
+func (sc *ByzantineScenario) IsWellFormed() error {
+	if sc.Validators < 1 {
+		return fmt.Errorf("validators must be greater than 0")
+	}
+	if sc.Threshold < 1 || sc.Threshold > 100 {
+		return fmt.Errorf("threshold must be in 1-100: %d", sc.Threshold)
+	}
+	if sc.Height <= common.GenesisBlockHeight {
+		return fmt.Errorf("height must be greater than %d", common.GenesisBlockHeight)
+	}
+	if sc.Limit <= 0 {
+		return fmt.Errorf("limit must be greater than 0")
+	}
+	if !isProbability(sc.DropRate) {
+		return fmt.Errorf("drop-rate must be in 0-1: %v", sc.DropRate)
+	}
+
+	nodes := map[int]bool{}
+	for _, b := range sc.Byzantine {
+		if b.Node < 0 || b.Node >= sc.Validators {
+			return fmt.Errorf("unknown node: %d", b.Node)
+		}
+		if nodes[b.Node] {
+			return fmt.Errorf("duplicated node: %d", b.Node)
+		}
+		nodes[b.Node] = true
+
+		if !isProbability(b.VoteNO) || !isProbability(b.DoubleVote) || !isProbability(b.Withhold) {
+			return fmt.Errorf("probability must be in 0-1: node=%d", b.Node)
+		}
+	}
+
+	return nil
+}
+
+// Tolerance returns the number of faulty validators, which the voting
+// threshold policy claims to tolerate.
+func (sc *ByzantineScenario) Tolerance() int {
+	policy, _ := consensus.NewDefaultVotingThresholdPolicy(sc.Threshold)
+	policy.SetValidators(sc.Validators)
+
+	return sc.Validators - policy.Threshold()
+}
+
+// NewSimulator creates the `Simulator` of the scenario; the byzantine
+// behaviors are applied to the nodes.
+func (sc *ByzantineScenario) NewSimulator(conf common.Config) *Simulator {
+	s := NewSimulator(sc.Validators, sc.Seed, conf)
+	s.SetDelay(sc.DelayMin, sc.DelayMax)
+	s.SetDropRate(sc.DropRate)
+
+	for _, b := range sc.Byzantine {
+		s.SetByzantine(b)
+	}
+
+	return s
+}
+
+// Run runs the scenario until the honest nodes reach the height of scenario;
+// it returns false if the height is not reached in the limit.
+func (sc *ByzantineScenario) Run(conf common.Config) (*Simulator, bool) {
+	s := sc.NewSimulator(conf)
+	s.Start()
+
+	return s, s.RunUntil(reachHeight(s.Honest(), sc.Height), sc.Limit)
+}
+
+// SetByzantine makes the node behave as `b`; the voting is changed by the
+// ballot checker funcs and the sending ballots are changed in
+// `simulatedConnectionManager`.
+func (s *Simulator) SetByzantine(b ByzantineBehavior) {
+	n := s.nodes[b.Node]
+	n.byzantine = &b
+
+	if b.VoteNO > 0 {
+		n.SetHandleINITBallotCheckerFuncs(
+			insertCheckerFuncBefore(DefaultHandleINITBallotCheckerFuncs, SIGNBallotBroadcast, n.byzantineVoteNOInSIGN)...,
+		)
+		n.SetHandleSIGNBallotCheckerFuncs(
+			insertCheckerFuncBefore(DefaultHandleSIGNBallotCheckerFuncs, ACCEPTBallotBroadcast, n.byzantineVoteNOInACCEPT)...,
+		)
+	}
+}
+
+// Honest returns the nodes without byzantine behavior.
+func (s *Simulator) Honest() (nodes []*SimulatedNode) {
+	for _, n := range s.nodes {
+		if n.byzantine == nil {
+			nodes = append(nodes, n)
+		}
+	}
+
+	return
+}
+
+func (n *SimulatedNode) byzantineVoteNOInSIGN(c common.Checker, args ...interface{}) (err error) {
+	checker := c.(*BallotChecker)
+	if n.s.chance(n.byzantine.VoteNO) {
+		checker.VotingHole = voting.NO
+	}
+
+	return
+}
+
+func (n *SimulatedNode) byzantineVoteNOInACCEPT(c common.Checker, args ...interface{}) (err error) {
+	checker := c.(*BallotChecker)
+	if checker.VotingFinished && n.s.chance(n.byzantine.VoteNO) {
+		checker.FinishedVotingHole = voting.NO
+	}
+
+	return
+}
+
+// broadcastByzantine sends the ballot to the validators by the byzantine
+// behavior. The ballot is always delivered to the node itself.
+func (n *SimulatedNode) broadcastByzantine(b ballot.Ballot, validators []string) {
+	if n.byzantine.InvalidProposerTransaction && b.State() == ballot.StateINIT && b.IsFromProposer() {
+		b = n.invalidProposerTransaction(b)
+	}
+
+	withhold := n.s.chance(n.byzantine.Withhold)
+
+	var conflicted *ballot.Ballot
+	if n.s.chance(n.byzantine.DoubleVote) {
+		conflicted = n.conflictingBallot(b)
+	}
+
+	for i, address := range validators {
+		to := n.s.nodeByAddress(address)
+		if to == nil {
+			continue
+		}
+		if to == n {
+			n.send(to, b)
+			continue
+		}
+		if withhold {
+			continue
+		}
+
+		n.send(to, b)
+		if conflicted != nil && i%2 == 0 {
+			n.send(to, *conflicted)
+		}
+	}
+}
+
+// invalidProposerTransaction increases the inflation amount of the proposed
+// ballot; the other validators will vote NO to the ballot.
+func (n *SimulatedNode) invalidProposerTransaction(b ballot.Ballot) ballot.Ballot {
+	ptx := b.ProposerTransaction()
+
+	opc, err := ptx.CollectTxFee()
+	if err != nil {
+		return b
+	}
+	opi, err := ptx.Inflation()
+	if err != nil {
+		return b
+	}
+	opi.Amount = opi.Amount + 1
+
+	if ptx, err = ballot.NewProposerTransactionFromBallot(b, opc, opi); err != nil {
+		return b
+	}
+
+	b.SetProposerTransaction(ptx)
+	b.Sign(n.localNode.Keypair(), n.Conf.NetworkID)
+
+	return b
+}
+
+// conflictingBallot makes the ballot, which has the opposite vote of `b`;
+// the ballot of INIT and the expired ballot are not changed.
+func (n *SimulatedNode) conflictingBallot(b ballot.Ballot) *ballot.Ballot {
+	if b.State() == ballot.StateINIT {
+		return nil
+	}
+
+	switch b.Vote() {
+	case voting.YES:
+		b.SetVote(b.State(), voting.NO)
+	case voting.NO:
+		b.SetVote(b.State(), voting.YES)
+	default:
+		return nil
+	}
+	b.Sign(n.localNode.Keypair(), n.Conf.NetworkID)
+
+	return &b
+}
+
+// insertCheckerFuncBefore returns the copy of `funcs`, which `f` is inserted
+// in front of `target`.
+func insertCheckerFuncBefore(funcs []common.CheckerFunc, target, f common.CheckerFunc) []common.CheckerFunc {
+	var inserted []common.CheckerFunc
+	for _, c := range funcs {
+		if reflect.ValueOf(c).Pointer() == reflect.ValueOf(target).Pointer() {
+			inserted = append(inserted, f)
+		}
+		inserted = append(inserted, c)
+	}
+
+	return inserted
+}
+
+func isProbability(p float64) bool {
+	return p >= 0 && p <= 1
+}
@@ -0,0 +1,562 @@
+package runner
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"boscoin.io/sebak/lib/ballot"
+	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/consensus"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/network"
+	"boscoin.io/sebak/lib/node"
+	"boscoin.io/sebak/lib/storage"
+	"boscoin.io/sebak/lib/transaction"
+	"boscoin.io/sebak/lib/voting"
+)
+
+// Simulator runs the multiple `NodeRunner`s in one process by the virtual
+// clock. The ballots between the nodes, the timeouts and the state
+// transitions of `ISAACStateManager` and the sync requests are the events of
+// one queue, which are processed one by one in order of the virtual time, so
+// with the same seed the nodes always behave in the same order.
+//
+// The delivery of ballots can be controlled by `SetDelay()`, `SetDropRate()`
+// and `Partition()`; with the random delays the ballots are reordered.
+//
+// NOTE the ballots and blocks still have the wall-clock timestamps, so the
+// hashes of them are different in each run.
+type Simulator struct {
+	sync.Mutex
+
+	now    time.Duration // virtual time since the simulator is created
+	seq    uint64        // keeps the order of the events in the same time
+	events simulatorEvents
+	rand   *rand.Rand
+	nodes  []*SimulatedNode
+
+	delayMin  time.Duration
+	delayMax  time.Duration
+	dropRate  float64
+	partition map[ /* node.Address() */ string]int // group of node; empty means no partition
+}
+
+// SimulatedNode is the `NodeRunner` controlled by `Simulator`.
+type SimulatedNode struct {
+	*NodeRunner
+
+	Index int
+
+	s          *Simulator
+	timer      *simulatedTimer
+	blockBegin time.Time          // measure for block interval time
+	byzantine  *ByzantineBehavior // nil for the honest node
+}
+
+// NewSimulator creates `n` validators from `seed`; the keypairs of validators
+// and the random decisions of the simulator are derived from `seed`.
+func NewSimulator(n int, seed int64, conf common.Config) *Simulator {
+	s := &Simulator{
+		rand:      rand.New(rand.NewSource(seed)),
+		partition: map[string]int{},
+	}
+
+	// the block time is not adjusted in simulation; the proposer does not
+	// wait before proposing new ballot.
+	conf.BlockTime = 0
+	conf.BlockTimeDelta = 0
+
+	var net *network.MemoryNetwork
+	var networks []*network.MemoryNetwork
+	var localNodes []*node.LocalNode
+	for i := 0; i < n; i++ {
+		var rawSeed [32]byte
+		s.rand.Read(rawSeed[:])
+		kp, err := keypair.FromRawSeed(rawSeed)
+		if err != nil {
+			panic(err)
+		}
+
+		net = net.NewMemoryNetwork()
+		localNode := node.NewTestLocalNode(kp, net.Endpoint())
+		net.SetLocalNode(localNode)
+		net.SetMessageBroker(simulatedMessageBroker{})
+
+		networks = append(networks, net)
+		localNodes = append(localNodes, localNode)
+	}
+
+	for _, localNode := range localNodes {
+		for _, v := range localNodes {
+			localNode.AddValidators(v.ConvertToValidator())
+		}
+	}
+
+	for i, localNode := range localNodes {
+		policy, _ := consensus.NewDefaultVotingThresholdPolicy(66)
+		sn := &SimulatedNode{Index: i, s: s}
+		sn.timer = &simulatedTimer{node: sn}
+
+		cm := &simulatedConnectionManager{
+			ConnectionManager: network.NewValidatorConnectionManager(localNode, networks[i], policy, conf),
+			node:              sn,
+		}
+
+		st := block.InitTestBlockchain()
+		is, err := consensus.NewISAAC(localNode, policy, cm, st, conf, &simulatedSyncer{node: sn})
+		if err != nil {
+			panic(err)
+		}
+
+		if sn.NodeRunner, err = NewNodeRunner(localNode, policy, networks[i], is, st, transaction.NewPool(conf), conf); err != nil {
+			panic(err)
+		}
+		sn.isaacStateManager.sendTransit = sn.sendTransit
+		sn.isaacStateManager.blockTimeBuffer = 0
+
+		s.nodes = append(s.nodes, sn)
+	}
+
+	return s
+}
+
+// Start starts the consensus of all the nodes at the current virtual time.
+func (s *Simulator) Start() {
+	for _, n := range s.nodes {
+		n.start()
+	}
+}
+
+func (s *Simulator) Nodes() []*SimulatedNode {
+	return s.nodes
+}
+
+func (s *Simulator) Node(i int) *SimulatedNode {
+	return s.nodes[i]
+}
+
+// Now returns the virtual time since the simulator is created.
+func (s *Simulator) Now() time.Duration {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.now
+}
+
+// SetDelay sets the range of delay of ballots; each ballot is delayed randomly
+// in the range, so the ballots can arrive in the different order.
+func (s *Simulator) SetDelay(min, max time.Duration) {
+	s.Lock()
+	defer s.Unlock()
+
+	if max < min {
+		max = min
+	}
+	s.delayMin, s.delayMax = min, max
+}
+
+// SetDropRate sets the probability to drop each ballot, which is sent to the
+// other nodes.
+func (s *Simulator) SetDropRate(rate float64) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.dropRate = rate
+}
+
+// Partition splits the nodes into the groups by the indices of nodes; the
+// ballots between the different groups are dropped. The nodes, which are not
+// in any group, belong to the same group.
+func (s *Simulator) Partition(groups ...[]int) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.partition = map[string]int{}
+	for i, group := range groups {
+		for _, index := range group {
+			s.partition[s.nodes[index].Node().Address()] = i + 1
+		}
+	}
+}
+
+// Heal removes the partition.
+func (s *Simulator) Heal() {
+	s.Partition()
+}
+
+func (s *Simulator) isConnected(from, to string) bool {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.partition[from] == s.partition[to]
+}
+
+// Step runs the next event; it returns false when there is no event to run.
+func (s *Simulator) Step() bool {
+	s.Lock()
+	if s.events.Len() < 1 {
+		s.Unlock()
+		return false
+	}
+	e := heap.Pop(&s.events).(*simulatorEvent)
+	s.now = e.at
+	s.Unlock()
+
+	e.run()
+
+	return true
+}
+
+// RunFor runs the events until the virtual time passes by `d`.
+func (s *Simulator) RunFor(d time.Duration) {
+	until := s.Now() + d
+	for {
+		s.Lock()
+		if s.events.Len() < 1 || s.events[0].at > until {
+			s.now = until
+			s.Unlock()
+			return
+		}
+		s.Unlock()
+
+		s.Step()
+	}
+}
+
+// RunUntil runs the events until `f` returns true; it returns false if `f`
+// is not satisfied in `limit` of virtual time.
+func (s *Simulator) RunUntil(f func() bool, limit time.Duration) bool {
+	until := s.Now() + limit
+	for !f() {
+		s.Lock()
+		if s.events.Len() < 1 || s.events[0].at > until {
+			s.now = until
+			s.Unlock()
+			return false
+		}
+		s.Unlock()
+
+		s.Step()
+	}
+
+	return true
+}
+
+// reachHeight returns the condition for `RunUntil()`, which all the nodes
+// reach the height.
+func reachHeight(nodes []*SimulatedNode, height uint64) func() bool {
+	return func() bool {
+		for _, n := range nodes {
+			if n.Consensus().LatestBlock().Height < height {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// after schedules `f` to run after `d` of virtual time.
+func (s *Simulator) after(d time.Duration, f func()) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.seq++
+	heap.Push(&s.events, &simulatorEvent{at: s.now + d, seq: s.seq, run: f})
+}
+
+func (s *Simulator) delay() time.Duration {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.delayMax <= s.delayMin {
+		return s.delayMin
+	}
+
+	return s.delayMin + time.Duration(s.rand.Int63n(int64(s.delayMax-s.delayMin)+1))
+}
+
+// chance returns true with the probability, `p`.
+func (s *Simulator) chance(p float64) bool {
+	s.Lock()
+	defer s.Unlock()
+
+	return p > 0 && s.rand.Float64() < p
+}
+
+func (s *Simulator) drop() bool {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.dropRate > 0 && s.rand.Float64() < s.dropRate
+}
+
+// send delivers the message to the node after the random delay; the message
+// to itself is delivered without delay and never dropped.
+func (s *Simulator) send(from, to *SimulatedNode, message common.NetworkMessage) {
+	if from == to {
+		s.after(0, func() { to.handleMessage(message) })
+		return
+	}
+
+	if s.drop() {
+		return
+	}
+
+	s.after(s.delay(), func() {
+		if !s.isConnected(from.Node().Address(), to.Node().Address()) {
+			return
+		}
+		to.handleMessage(message)
+	})
+}
+
+func (s *Simulator) nodeByAddress(address string) *SimulatedNode {
+	for _, n := range s.nodes {
+		if n.Node().Address() == address {
+			return n
+		}
+	}
+
+	return nil
+}
+
+func (n *SimulatedNode) start() {
+	n.localNode.SetConsensus()
+	n.blockBegin = time.Now()
+	n.consensus.SetLatestVotingBasis(voting.Basis{})
+	n.isaacStateManager.NextHeight()
+}
+
+func (n *SimulatedNode) send(to *SimulatedNode, message common.Message) {
+	encoded, err := message.Serialize()
+	if err != nil {
+		n.Log().Error("failed to serialize message", "error", err)
+		return
+	}
+
+	n.s.send(n, to, common.NewNetworkMessage(message.GetType(), encoded))
+}
+
+func (n *SimulatedNode) State() consensus.ISAACState {
+	return n.isaacStateManager.State()
+}
+
+func (n *SimulatedNode) sendTransit(state consensus.ISAACState) {
+	n.s.after(0, func() {
+		n.blockBegin = n.isaacStateManager.transit(n.timer, state, n.blockBegin)
+	})
+}
+
+func (n *SimulatedNode) expire(seq uint64) {
+	if seq != n.timer.seq {
+		return
+	}
+
+	if round, ballotState, expired := n.isaacStateManager.expire(n.timer); expired {
+		n.isaacStateManager.broadcastExpiredBallot(round, ballotState)
+	}
+}
+
+// sync copies the blocks until `height` from one of `nodeAddrs`, which is
+// connected to the node.
+func (n *SimulatedNode) sync(height uint64, nodeAddrs []string) {
+	sort.Strings(nodeAddrs)
+	for _, address := range nodeAddrs {
+		peer := n.s.nodeByAddress(address)
+		if peer == nil || peer == n || !n.s.isConnected(n.Node().Address(), address) {
+			continue
+		}
+
+		for h := n.consensus.LatestBlock().Height + 1; h <= height; h++ {
+			if err := n.copyBlock(peer, h); err != nil {
+				n.Log().Debug("failed to sync block", "height", h, "peer", peer.Node().Alias(), "error", err)
+				break
+			}
+		}
+
+		if n.consensus.LatestBlock().Height >= height {
+			break
+		}
+	}
+}
+
+// copyBlock stores the block of peer by finishing the ballot in the
+// certificate of the block.
+func (n *SimulatedNode) copyBlock(peer *SimulatedNode, height uint64) (err error) {
+	var blk block.Block
+	if blk, err = block.GetBlockByHeight(peer.Storage(), height); err != nil {
+		return
+	}
+
+	var c *ballot.Certificate
+	if c, err = ballot.GetCertificate(peer.Storage(), blk.Hash); err != nil {
+		return
+	} else if len(c.Ballots) < 1 {
+		return errors.BlockNotFound
+	}
+
+	var bs *storage.LevelDBBackend
+	if bs, err = n.Storage().OpenBatch(); err != nil {
+		return
+	}
+
+	var txs []*transaction.Transaction
+	for _, hash := range blk.Transactions {
+		var bt block.BlockTransaction
+		if bt, err = block.GetBlockTransaction(peer.Storage(), hash); err != nil {
+			bs.Discard()
+			return
+		}
+		tx := bt.Transaction()
+		if _, err = block.SaveTransactionPool(bs, tx); err != nil {
+			bs.Discard()
+			return
+		}
+		txs = append(txs, &tx)
+	}
+
+	var synced *block.Block
+	if synced, err = finishBallotWithProposedTxs(bs, c.Ballots[0], txs, n.Log()); err != nil {
+		bs.Discard()
+		return
+	} else if synced.Hash != blk.Hash {
+		bs.Discard()
+		return fmt.Errorf("synced block does not match; %s != %s", synced.Hash, blk.Hash)
+	}
+
+	if err = c.Save(bs); err != nil {
+		bs.Discard()
+		return
+	}
+
+	if err = bs.Commit(); err != nil {
+		bs.Discard()
+		return
+	}
+
+	n.TransactionPool.RemoveIncluded(blk.Transactions...)
+	n.consensus.RemoveRunningRoundsLowerOrEqualHeight(height - 1)
+	n.RemoveSendRecordsLowerThanOrEqualHeight(height - 1)
+
+	if err = n.UpdateValidators(); err != nil {
+		return
+	}
+
+	return n.UpdateDelegated()
+}
+
+// simulatedTimer replaces `time.Timer` of `ISAACStateManager`; the timeout is
+// scheduled in the virtual time and the previous one is ignored when reset.
+type simulatedTimer struct {
+	node *SimulatedNode
+	seq  uint64
+}
+
+func (t *simulatedTimer) Reset(d time.Duration) bool {
+	t.seq++
+	seq := t.seq
+	t.node.s.after(d, func() { t.node.expire(seq) })
+
+	return true
+}
+
+// simulatedConnectionManager sends the ballots through `Simulator`; all the
+// validators are considered to be connected.
+type simulatedConnectionManager struct {
+	network.ConnectionManager
+
+	node *SimulatedNode
+}
+
+func (c *simulatedConnectionManager) Broadcast(message common.Message) {
+	if b, ok := message.(ballot.Ballot); ok && c.node.byzantine != nil {
+		c.node.broadcastByzantine(b, c.AllConnected())
+		return
+	}
+
+	for _, address := range c.AllConnected() {
+		if to := c.node.s.nodeByAddress(address); to != nil {
+			c.node.send(to, message)
+		}
+	}
+}
+
+func (c *simulatedConnectionManager) AllConnected() []string {
+	validators := c.AllValidators()
+	sort.Strings(validators)
+
+	return validators
+}
+
+func (c *simulatedConnectionManager) CountConnected() int {
+	return len(c.AllValidators())
+}
+
+func (c *simulatedConnectionManager) IsReady() bool {
+	return true
+}
+
+// simulatedMessageBroker ignores the messages from the node itself; they are
+// delivered by `simulatedConnectionManager` in order with the others.
+type simulatedMessageBroker struct{}
+
+func (r simulatedMessageBroker) Response(w io.Writer, o []byte) error {
+	_, err := w.Write(o)
+	return err
+}
+
+func (r simulatedMessageBroker) Receive(common.NetworkMessage) {}
+
+// simulatedSyncer copies the missing blocks from the other nodes in
+// `Simulator`.
+type simulatedSyncer struct {
+	node *SimulatedNode
+}
+
+func (s *simulatedSyncer) SetSyncTargetBlock(_ context.Context, height uint64, nodeAddrs []string) error {
+	addrs := make([]string, len(nodeAddrs))
+	copy(addrs, nodeAddrs)
+
+	s.node.s.after(s.node.s.delay(), func() { s.node.sync(height, addrs) })
+
+	return nil
+}
+
+type simulatorEvent struct {
+	at  time.Duration
+	seq uint64
+	run func()
+}
+
+type simulatorEvents []*simulatorEvent
+
+func (e simulatorEvents) Len() int { return len(e) }
+
+func (e simulatorEvents) Less(i, j int) bool {
+	if e[i].at == e[j].at {
+		return e[i].seq < e[j].seq
+	}
+	return e[i].at < e[j].at
+}
+
+func (e simulatorEvents) Swap(i, j int) { e[i], e[j] = e[j], e[i] }
+
+func (e *simulatorEvents) Push(x interface{}) {
+	*e = append(*e, x.(*simulatorEvent))
+}
+
+func (e *simulatorEvents) Pop() interface{} {
+	old := *e
+	n := len(old)
+	x := old[n-1]
+	*e = old[:n-1]
+	return x
+}
@@ -0,0 +1,128 @@
+package runner
+
+import (
+	"sort"
+
+	logging "github.com/inconshreveable/log15"
+
+	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/storage"
+	"boscoin.io/sebak/lib/transaction"
+)
+
+// LoadTransactionPool rebuilds `transaction.Pool` from the unconfirmed
+// `block.TransactionPool`, which is still valid, so the pending transactions
+// are not lost by restarting node. The stale `block.TransactionPool`, which
+// can not be confirmed anymore, is removed; the confirmed ones are kept.
+func LoadTransactionPool(st *storage.LevelDBBackend, conf common.Config, pool *transaction.Pool, log logging.Logger) (loaded, removed int, err error) {
+	var txs []transaction.Transaction
+
+	var confirmed []string
+	iterFunc, closeFunc := block.GetUnconfirmedTransactionPools(st, nil)
+	for {
+		tp, hasNext, _ := iterFunc()
+		if !hasNext {
+			break
+		}
+
+		var exists bool
+		if exists, err = block.ExistsBlockTransaction(st, tp.Hash); err != nil {
+			closeFunc()
+			return
+		} else if exists {
+			confirmed = append(confirmed, tp.Hash)
+			continue
+		}
+
+		txs = append(txs, tp.Transaction())
+	}
+	closeFunc()
+
+	for _, hash := range confirmed {
+		if err = block.RemoveUnconfirmedTransactionPool(st, hash); err != nil {
+			return
+		}
+	}
+
+	// the transactions of same source are added by the order of sequence ID,
+	// so the later ones can be queued; for the same sequence ID, the one
+	// paying more fee is added first.
+	sort.SliceStable(txs, func(i, j int) bool {
+		if txs[i].B.Source != txs[j].B.Source {
+			return txs[i].B.Source < txs[j].B.Source
+		}
+		if txs[i].B.SequenceID != txs[j].B.SequenceID {
+			return txs[i].B.SequenceID < txs[j].B.SequenceID
+		}
+		return txs[i].FeePerOperation() > txs[j].FeePerOperation()
+	})
+
+	var stales []string
+	for _, tx := range txs {
+		if !isLoadableTransaction(st, conf, pool, tx) {
+			stales = append(stales, tx.GetHash())
+			continue
+		}
+
+		if err := pool.Add(tx); err == nil {
+			loaded++
+		} else if err != errors.TransactionAlreadyExistsInPool {
+			stales = append(stales, tx.GetHash())
+		}
+	}
+
+	for _, hash := range stales {
+		if err = block.DeleteTransactionPool(st, hash); err != nil {
+			return
+		}
+		removed++
+	}
+
+	log.Debug("transaction pool loaded", "loaded", loaded, "removed", removed)
+
+	return
+}
+
+// isLoadableTransaction checks the transaction can be included in the next
+// blocks; the transaction, which is not yet in time bounds, will be valid
+// later. `errors.TransactionNotYetValid` is returned only after the other
+// rules are passed.
+func isLoadableTransaction(st *storage.LevelDBBackend, conf common.Config, pool *transaction.Pool, tx transaction.Transaction) bool {
+	if tx.IsEmpty() || tx.IsWellFormed(conf) != nil {
+		return false
+	}
+
+	validate := ValidateTx
+	if old, found := pool.GetFromSource(tx.Source()); found && tx.B.SequenceID > old.B.SequenceID {
+		validate = ValidateQueuedTx
+	}
+
+	if err := validate(st, conf, tx); err != nil && err != errors.TransactionNotYetValid {
+		return false
+	}
+
+	return true
+}
+
+// RemoveInvalidPromotedTransactions validates the transactions, which are
+// promoted in `transaction.Pool` after the previous transactions of same
+// source are included in block; they were queued without the state of the
+// previous ones. With the invalid one, the later queued transactions of same
+// source are also removed, because they can not be valid anymore.
+func RemoveInvalidPromotedTransactions(st *storage.LevelDBBackend, conf common.Config, pool *transaction.Pool, promoted []string) (removed []string) {
+	var sources []string
+	for _, hash := range promoted {
+		tx, found := pool.Get(hash)
+		if !found {
+			continue
+		}
+
+		if err := ValidateTx(st, conf, tx); err != nil && err != errors.TransactionNotYetValid {
+			sources = append(sources, tx.Source())
+		}
+	}
+
+	return pool.RemoveAllFromSources(sources...)
+}
@@ -0,0 +1,119 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/transaction"
+)
+
+func TestLoadTransactionPool(t *testing.T) {
+	conf := common.NewTestConfig()
+	st := block.InitTestBlockchain()
+	defer st.Close()
+
+	genesisAccount, err := block.GetBlockAccount(st, block.GenesisKP.Address())
+	require.NoError(t, err)
+
+	kpA := keypair.Random()
+	kpB := keypair.Random()
+	for _, kp := range []*keypair.Full{kpA, kpB} {
+		ba := block.NewBlockAccount(kp.Address(), common.BaseReserve)
+		ba.MustSave(st)
+	}
+
+	// valid
+	valid, _, _ := GetCreateAccountTransaction(genesisAccount.SequenceID, uint64(common.BaseReserve))
+
+	// stale; the sequence ID is already passed
+	stale, _ := GetPaymentTransaction(kpA, kpB.Address(), 10, 1)
+
+	// not yet valid, but it can be valid later
+	notYet, _ := GetPaymentTransaction(kpB, kpA.Address(), 0, 1)
+	notYet.B.TimeBounds = &transaction.TimeBounds{MinHeight: 100}
+	notYet.Sign(kpB, networkID)
+
+	// not yet valid, but the source can not pay the amount
+	notYetStale, _ := GetPaymentTransaction(kpA, kpB.Address(), 0, uint64(common.BaseReserve)*10)
+	notYetStale.B.TimeBounds = &transaction.TimeBounds{MinHeight: 100}
+	notYetStale.Sign(kpA, networkID)
+
+	// queued after the previous one of same source
+	queued, _ := GetPaymentTransaction(kpB, kpA.Address(), 1, 1)
+
+	// confirmed
+	confirmed, _, _ := GetCreateAccountTransaction(genesisAccount.SequenceID, uint64(common.BaseReserve))
+	blk := block.TestMakeNewBlockWithPrevBlock(block.GetLatestBlock(st), []string{confirmed.GetHash()})
+	bt := block.NewBlockTransactionFromTransaction(blk.Hash, blk.Height, blk.ProposedTime, confirmed)
+	bt.MustSave(st)
+
+	for _, tx := range []transaction.Transaction{valid, stale, notYet, notYetStale, queued, confirmed} {
+		_, err := block.SaveTransactionPool(st, tx)
+		require.NoError(t, err)
+	}
+
+	pool := transaction.NewPool(conf)
+	loaded, removed, err := LoadTransactionPool(st, conf, pool, common.NopLogger())
+	require.NoError(t, err)
+	require.Equal(t, 3, loaded)
+	require.Equal(t, 2, removed)
+
+	require.True(t, pool.Has(valid.GetHash()))
+	require.True(t, pool.Has(notYet.GetHash()))
+	require.True(t, pool.Has(queued.GetHash()))
+	require.False(t, pool.Has(stale.GetHash()))
+	require.False(t, pool.Has(notYetStale.GetHash()))
+	require.False(t, pool.Has(confirmed.GetHash()))
+
+	for _, tx := range []transaction.Transaction{valid, notYet, confirmed} {
+		exists, err := block.ExistsTransactionPool(st, tx.GetHash())
+		require.NoError(t, err)
+		require.True(t, exists)
+	}
+	for _, tx := range []transaction.Transaction{stale, notYetStale} {
+		exists, err := block.ExistsTransactionPool(st, tx.GetHash())
+		require.NoError(t, err)
+		require.False(t, exists)
+	}
+}
+
+func TestRemoveInvalidPromotedTransactions(t *testing.T) {
+	conf := common.NewTestConfig()
+	st := block.InitTestBlockchain()
+	defer st.Close()
+
+	kpA := keypair.Random()
+	kpB := keypair.Random()
+	baA := block.NewBlockAccount(kpA.Address(), common.BaseReserve.MustMult(2))
+	baA.MustSave(st)
+	block.NewBlockAccount(kpB.Address(), common.BaseReserve).MustSave(st)
+
+	pool := transaction.NewPool(conf)
+
+	first, _ := GetPaymentTransaction(kpA, kpB.Address(), baA.SequenceID, uint64(common.BaseReserve))
+	// `second` can not be paid after `first` is included in block
+	second, _ := GetPaymentTransaction(kpA, kpB.Address(), baA.SequenceID+1, uint64(common.BaseReserve))
+	third, _ := GetPaymentTransaction(kpA, kpB.Address(), baA.SequenceID+2, 1)
+	for _, tx := range []transaction.Transaction{first, second, third} {
+		require.NoError(t, pool.Add(tx))
+	}
+
+	{ // `first` is included in block
+		baA.Balance = baA.Balance.MustSub(first.TotalAmount(true))
+		baA.IncreaseSequenceID()
+		baA.MustSave(st)
+	}
+
+	promoted := pool.RemoveFromSources(kpA.Address())
+	require.Equal(t, []string{second.GetHash()}, promoted)
+	require.Equal(t, 2, pool.Len())
+
+	removed := RemoveInvalidPromotedTransactions(st, conf, pool, promoted)
+	require.Equal(t, 2, len(removed))
+	require.Equal(t, 0, pool.Len())
+	require.False(t, pool.IsSameSource(kpA.Address()))
+}
@@ -0,0 +1,77 @@
+package runner
+
+import (
+	logging "github.com/inconshreveable/log15"
+
+	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/storage"
+	"boscoin.io/sebak/lib/transaction"
+	"boscoin.io/sebak/lib/transaction/operation"
+)
+
+// FinishUnfreezing stores the `operation.UnfreezeRequest` of the transactions
+// in block. The amount, which is paid from the frozen account, is withdrawn
+// from the matured unfreezing requests by the order of request.
+func FinishUnfreezing(st *storage.LevelDBBackend, blk block.Block, transactions []*transaction.Transaction, log logging.Logger) (err error) {
+	for _, tx := range transactions {
+		var withdrawal common.Amount
+		for _, op := range tx.B.Operations {
+			switch op.H.Type {
+			case operation.TypeUnfreezingRequest:
+				opb, ok := op.B.(operation.UnfreezeRequest)
+				if !ok {
+					return errors.TypeOperationBodyNotMatched
+				}
+				r := block.NewUnfreezingRequest(tx.B.Source, common.MustMakeObjectHashString(op), tx.GetHash(), blk.Height, opb)
+				if err = r.Save(st); err != nil {
+					return
+				}
+			case operation.TypePayment, operation.TypeCreateAccount:
+				if pop, ok := op.B.(operation.Payable); ok {
+					withdrawal = withdrawal.MustAdd(pop.GetAmount())
+				}
+			}
+		}
+
+		if withdrawal < 1 {
+			continue
+		}
+		if err = finishFrozenWithdrawal(st, blk, tx.B.Source, withdrawal, log); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func finishFrozenWithdrawal(st *storage.LevelDBBackend, blk block.Block, source string, amount common.Amount, log logging.Logger) (err error) {
+	// `blk` is not yet the latest block, so the request is matured at the
+	// previous block.
+	for _, r := range block.GetAllUnfreezingRequests(st, source) {
+		if amount < 1 {
+			break
+		}
+		if !r.IsMatured(blk.Height - 1) {
+			continue
+		}
+		if !r.IsWhole() && r.Remaining() < 1 {
+			continue
+		}
+
+		amount = r.Withdraw(amount)
+		if err = r.Save(st); err != nil {
+			return
+		}
+
+		log.Debug(
+			"withdrawn from unfreezing request",
+			"address", source,
+			"unfreezing-request", r.OpHash,
+			"withdrawn", r.Withdrawn,
+		)
+	}
+
+	return
+}
@@ -0,0 +1,133 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/transaction"
+	"boscoin.io/sebak/lib/transaction/operation"
+)
+
+func TestPartialUnfreezing(t *testing.T) {
+	conf := common.NewTestConfig()
+	st := block.InitTestBlockchain()
+	defer st.Close()
+
+	kpLinked := keypair.Random()
+	block.NewBlockAccount(kpLinked.Address(), common.BaseReserve).MustSave(st)
+
+	kpFrozen := keypair.Random()
+	block.NewBlockAccountLinked(kpFrozen.Address(), common.Unit*3, kpLinked.Address()).MustSave(st)
+
+	makeTx := func(kp *keypair.Full, opbs ...operation.Body) transaction.Transaction {
+		var ops []operation.Operation
+		for _, opb := range opbs {
+			op, err := operation.NewOperation(opb)
+			require.NoError(t, err)
+			ops = append(ops, op)
+		}
+		tx, err := transaction.NewTransaction(kp.Address(), 0, ops...)
+		require.NoError(t, err)
+		tx.Sign(kp, networkID)
+		return tx
+	}
+
+	validateOp := func(opb operation.Body) error {
+		ba, err := block.GetBlockAccount(st, kpFrozen.Address())
+		require.NoError(t, err)
+		op, err := operation.NewOperation(opb)
+		require.NoError(t, err)
+		return ValidateOp(st, conf, ba, op)
+	}
+
+	// nextBlock makes new block after `skip` blocks
+	nextBlock := func(skip uint64, txs ...transaction.Transaction) {
+		var hashes []string
+		var proposed []*transaction.Transaction
+		for i := range txs {
+			hashes = append(hashes, txs[i].GetHash())
+			proposed = append(proposed, &txs[i])
+		}
+
+		prev := block.GetLatestBlock(st)
+		prev.Height += skip
+		blk := block.TestMakeNewBlockWithPrevBlock(prev, hashes)
+		blk.MustSave(st)
+		require.NoError(t, FinishUnfreezing(st, blk, proposed, common.NopLogger()))
+	}
+
+	payment := func(amount common.Amount) operation.Payment {
+		return operation.NewPayment(kpLinked.Address(), amount)
+	}
+
+	{ // not yet requested
+		require.Equal(t, errors.UnfreezingRequestNotRequested, validateOp(payment(common.Unit)))
+	}
+
+	{ // over the balance
+		require.Equal(t, errors.UnfreezingAmountExceeded, validateOp(operation.NewPartialUnfreezeRequest(common.Unit*4)))
+	}
+
+	first := operation.NewPartialUnfreezeRequest(common.Unit)
+	require.NoError(t, validateOp(first))
+	nextBlock(0, makeTx(kpFrozen, first)) // 2
+
+	second := operation.NewPartialUnfreezeRequest(common.Unit)
+	require.NoError(t, validateOp(second))
+	nextBlock(0, makeTx(kpFrozen, second)) // 3
+
+	requests := block.GetAllUnfreezingRequests(st, kpFrozen.Address())
+	require.Equal(t, 2, len(requests))
+	require.Equal(t, uint64(2), requests[0].Height)
+	require.Equal(t, common.Unit, requests[0].Amount)
+	require.Equal(t, uint64(3), requests[1].Height)
+
+	{ // the outstanding requests are over the balance
+		require.Equal(t, errors.UnfreezingAmountExceeded, validateOp(operation.NewPartialUnfreezeRequest(common.Unit*2)))
+	}
+
+	{ // not yet matured
+		require.Equal(t, errors.UnfreezingNotReachedExpiration, validateOp(payment(common.Unit)))
+	}
+
+	// the first request is matured
+	nextBlock(common.UnfreezingPeriod - 2) // 2 + UnfreezingPeriod
+
+	{ // over the matured amount
+		require.Equal(t, errors.UnfreezingAmountNotMatured, validateOp(payment(common.Unit*2)))
+	}
+
+	{ // the total amount of operations is over the matured amount
+		ba, err := block.GetBlockAccount(st, kpFrozen.Address())
+		require.NoError(t, err)
+		tx := makeTx(kpFrozen, payment(common.Unit/2), operation.NewPayment(keypair.Random().Address(), common.Unit))
+		tx.B.SequenceID = ba.SequenceID
+		tx.Sign(kpFrozen, networkID)
+		block.NewBlockAccount(tx.B.Operations[1].B.(operation.Payment).Target, common.BaseReserve).MustSave(st)
+		require.Equal(t, errors.UnfreezingAmountNotMatured, ValidateTx(st, conf, tx))
+	}
+
+	require.NoError(t, validateOp(payment(common.Unit)))
+	nextBlock(0, makeTx(kpFrozen, payment(common.Unit))) // 3 + UnfreezingPeriod
+
+	requests = block.GetAllUnfreezingRequests(st, kpFrozen.Address())
+	require.Equal(t, common.Unit, requests[0].Withdrawn)
+	require.Equal(t, common.Amount(0), requests[1].Withdrawn)
+
+	// the second request is matured with the block
+	require.NoError(t, validateOp(payment(common.Unit)))
+
+	{ // request the whole balance
+		whole := operation.NewUnfreezeRequest()
+		require.NoError(t, validateOp(whole))
+		nextBlock(0, makeTx(kpFrozen, whole))
+
+		require.Equal(t, errors.UnfreezingRequestAlreadyReceived, validateOp(operation.NewUnfreezeRequest()))
+		require.Equal(t, errors.UnfreezingRequestAlreadyReceived, validateOp(operation.NewPartialUnfreezeRequest(common.Unit)))
+	}
+}
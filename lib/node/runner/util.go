@@ -126,6 +126,8 @@ func NewNodeInfo(nr *NodeRunner) node.NodeInfo {
 		TimeoutSIGN:               nr.Conf.TimeoutSIGN,
 		TimeoutACCEPT:             nr.Conf.TimeoutACCEPT,
 		TimeoutALLCONFIRM:         nr.Conf.TimeoutALLCONFIRM,
+		TimeoutBackoffFactor:      nr.Conf.TimeoutBackoffFactor,
+		TimeoutBackoffLimit:       nr.Conf.TimeoutBackoffLimit,
 		RateLimitRuleAPI:          nr.Conf.RateLimitRuleAPI.Default.Formatted,
 		RateLimitRuleNode:         nr.Conf.RateLimitRuleNode.Default.Formatted,
 		OperationsLimit:           nr.Conf.OpsLimit,
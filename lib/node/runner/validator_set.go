@@ -0,0 +1,37 @@
+package runner
+
+import (
+	logging "github.com/inconshreveable/log15"
+
+	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/storage"
+	"boscoin.io/sebak/lib/transaction"
+	"boscoin.io/sebak/lib/transaction/operation"
+)
+
+// FinishValidatorSetChange stores the `operation.AddValidator` and
+// `operation.RemoveValidator` of the transactions in block; they are applied
+// to the validator set at the block of their height.
+func FinishValidatorSetChange(st *storage.LevelDBBackend, blk block.Block, transactions []*transaction.Transaction, log logging.Logger) (err error) {
+	for _, tx := range transactions {
+		for i, op := range tx.B.Operations {
+			switch op.H.Type {
+			case operation.TypeAddValidator, operation.TypeRemoveValidator:
+			default:
+				continue
+			}
+
+			var c *block.ValidatorSetChange
+			if c, err = block.NewValidatorSetChange(tx.GetHash(), i, blk.Height, op); err != nil {
+				return
+			}
+			if err = c.Save(st); err != nil {
+				return
+			}
+
+			log.Debug("validator set change stored", "change", c)
+		}
+	}
+
+	return
+}
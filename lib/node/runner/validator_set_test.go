@@ -0,0 +1,106 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/transaction"
+	"boscoin.io/sebak/lib/transaction/operation"
+)
+
+func TestValidatorSetChange(t *testing.T) {
+	nr, localNode := MakeNodeRunner()
+	st := nr.Storage()
+	defer st.Close()
+
+	conf := nr.Conf
+	kpCongress := keypair.Random()
+	conf.CongressAccountAddress = kpCongress.Address()
+	block.NewBlockAccount(kpCongress.Address(), common.BaseReserve).MustSave(st)
+
+	kpGeneral := keypair.Random()
+	block.NewBlockAccount(kpGeneral.Address(), common.BaseReserve).MustSave(st)
+
+	makeTx := func(kp *keypair.Full, opb operation.Body) transaction.Transaction {
+		op, err := operation.NewOperation(opb)
+		require.NoError(t, err)
+		tx, err := transaction.NewTransaction(kp.Address(), 0, op)
+		require.NoError(t, err)
+		tx.Sign(kp, networkID)
+		return tx
+	}
+
+	validateOp := func(kp *keypair.Full, opb operation.Body) error {
+		ba, err := block.GetBlockAccount(st, kp.Address())
+		require.NoError(t, err)
+		op, err := operation.NewOperation(opb)
+		require.NoError(t, err)
+		return ValidateOp(st, conf, ba, op)
+	}
+
+	nextBlock := func(txs ...transaction.Transaction) {
+		var hashes []string
+		var proposed []*transaction.Transaction
+		for i := range txs {
+			hashes = append(hashes, txs[i].GetHash())
+			proposed = append(proposed, &txs[i])
+		}
+
+		blk := block.TestMakeNewBlockWithPrevBlock(block.GetLatestBlock(st), hashes)
+		blk.MustSave(st)
+		require.NoError(t, FinishValidatorSetChange(st, blk, proposed, common.NopLogger()))
+		require.NoError(t, nr.UpdateValidators())
+	}
+
+	newValidator := keypair.Random().Address()
+	latestHeight := block.GetLatestBlock(st).Height // 1
+
+	{ // not from congress
+		opb := operation.NewAddValidator(newValidator, "", "", latestHeight+3)
+		require.Equal(t, errors.CongressAddressMisMatched, validateOp(kpGeneral, opb))
+	}
+
+	{ // the validator set of the next block can not be changed
+		opb := operation.NewAddValidator(newValidator, "", "", latestHeight+1)
+		require.Equal(t, errors.ValidatorSetChangeNotInFuture, validateOp(kpCongress, opb))
+	}
+
+	add := operation.NewAddValidator(newValidator, "", "new", latestHeight+3) // 4
+	require.NoError(t, validateOp(kpCongress, add))
+	nextBlock(makeTx(kpCongress, add)) // 2
+
+	{ // already exists at the height
+		require.Equal(t, errors.ValidatorSetChangeAlreadyExists, validateOp(kpCongress, operation.NewRemoveValidator(newValidator, latestHeight+3)))
+	}
+
+	require.Equal(t, 1, len(localNode.GetValidators()))
+	require.Equal(t, 1, nr.Policy().Validators())
+
+	nextBlock() // 3; the validator set of the block 4 is applied
+	require.Equal(t, 2, len(localNode.GetValidators()))
+	require.True(t, localNode.HasValidators(newValidator))
+	require.Equal(t, "new", localNode.Validator(newValidator).Alias())
+	require.Equal(t, 2, nr.Policy().Validators())
+
+	remove := operation.NewRemoveValidator(newValidator, block.GetLatestBlock(st).Height+3) // 6
+	require.NoError(t, validateOp(kpCongress, remove))
+	nextBlock(makeTx(kpCongress, remove)) // 4
+	require.Equal(t, 2, nr.Policy().Validators())
+
+	nextBlock() // 5; the validator set of the block 6 is applied
+	require.Equal(t, 1, len(localNode.GetValidators()))
+	require.False(t, localNode.HasValidators(newValidator))
+	require.True(t, localNode.HasValidators(localNode.Address()))
+	require.Equal(t, 1, nr.Policy().Validators())
+
+	history, err := block.GetValidatorSetHistory(st)
+	require.NoError(t, err)
+	require.Equal(t, 3, len(history))
+	require.Equal(t, uint64(4), history[1].Height)
+	require.Equal(t, uint64(6), history[2].Height)
+}
@@ -47,7 +47,7 @@ func (so *stateObject) Serialize() ([]byte, error) {
 }
 
 func (so *stateObject) Deserialize(encoded []byte) error {
-	return json.Unmarshal(encoded, so.data)
+	return json.Unmarshal(encoded, &so.data)
 }
 
 /* GETTERS */
@@ -78,6 +78,10 @@ func (so *stateObject) Code() []byte {
 	}
 }
 
+func (so *stateObject) BlockAccount() block.BlockAccount {
+	return so.data
+}
+
 func (so *stateObject) SequenceID() uint64 {
 	return so.data.SequenceID
 }
@@ -162,6 +166,16 @@ func (so *stateObject) SetSequenceID(sequenceID uint64) {
 	}
 }
 
+func (so *stateObject) SetBlockAccount(ba block.BlockAccount) {
+	ba.CodeHash = so.data.CodeHash
+	ba.RootHash = so.data.RootHash
+	so.data = ba
+	if so.onDirty != nil {
+		so.onDirty(so.Address())
+		so.onDirty = nil
+	}
+}
+
 func (so *stateObject) SetCode(codeHash, code []byte) {
 	so.code = code
 	so.data.CodeHash = codeHash
@@ -202,33 +216,10 @@ func (so *stateObject) CommitDB(root common.Hash) (err error) {
 	return nil
 }
 
+// Save stores the account data into the backend storage, so the account can be
+// also found by `block.GetBlockAccount()`.
 func (so *stateObject) Save() (err error) {
-	st := so.db.BackEnd()
-	key := block.GetBlockAccountKey(so.Address())
-
-	var exists bool
-	exists, err = st.Has(key)
-	if err != nil {
-		return
-	}
-
-	if exists {
-		err = st.Set(key, so.data)
-	} else {
-		// TODO consider to use, [`Transaction`](https://godoc.org/github.com/syndtr/goleveldb/leveldb#DB.OpenTransaction)
-		err = st.New(key, so.data)
-		createdKey := block.GetBlockAccountCreatedKey(common.GetUniqueIDFromUUID())
-		err = st.New(createdKey, so.Address())
-	}
-
-	bac := block.BlockAccountSequenceID{
-		SequenceID: so.data.SequenceID,
-		Address:    so.Address(),
-		Balance:    so.data.Balance,
-	}
-	err = bac.Save(st)
-
-	return
+	return so.data.Save(so.db.BackEnd())
 }
 
 /*
@@ -6,6 +6,7 @@ import (
 
 	"boscoin.io/sebak/lib/block"
 	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/errors"
 	"boscoin.io/sebak/lib/storage/statedb/trie"
 )
 
@@ -97,32 +98,36 @@ func (stateDB *StateDB) SetSequenceID(addr string, sequenceID uint64) {
 	}
 }
 
-func (stateDB *StateDB) AddBalance(addr string, amount common.Amount) {
+func (stateDB *StateDB) AddBalance(addr string, amount common.Amount) error {
 	stateObject := stateDB.GetOrNewStateObject(addr)
 	if stateObject != nil {
-		stateObject.AddBalance(amount)
+		return stateObject.AddBalance(amount)
 	}
+	return nil
 }
 
-func (stateDB *StateDB) AddBalanceWithSequenceID(addr string, amount common.Amount, sequenceID uint64) {
+func (stateDB *StateDB) AddBalanceWithSequenceID(addr string, amount common.Amount, sequenceID uint64) error {
 	stateObject := stateDB.GetOrNewStateObject(addr)
 	if stateObject != nil {
-		stateObject.AddBalanceWithSequenceID(amount, sequenceID)
+		return stateObject.AddBalanceWithSequenceID(amount, sequenceID)
 	}
+	return nil
 }
 
-func (stateDB *StateDB) SubBalance(addr string, amount common.Amount) {
+func (stateDB *StateDB) SubBalance(addr string, amount common.Amount) error {
 	stateObject := stateDB.GetOrNewStateObject(addr)
 	if stateObject != nil {
-		stateObject.SubBalance(amount)
+		return stateObject.SubBalance(amount)
 	}
+	return nil
 }
 
-func (stateDB *StateDB) SubBalanceWithSequenceID(addr string, amount common.Amount, sequenceID uint64) {
+func (stateDB *StateDB) SubBalanceWithSequenceID(addr string, amount common.Amount, sequenceID uint64) error {
 	stateObject := stateDB.GetOrNewStateObject(addr)
 	if stateObject != nil {
-		stateObject.SubBalanceWithSequenceID(amount, sequenceID)
+		return stateObject.SubBalanceWithSequenceID(amount, sequenceID)
 	}
+	return nil
 }
 
 func (stateDB *StateDB) SetCode(addr string, code []byte) {
@@ -139,6 +144,61 @@ func (stateDB *StateDB) SetState(addr string, key, value common.Hash) {
 	}
 }
 
+// GetBlockAccount returns the copy of account data; to apply the changes of it,
+// use `SetBlockAccount()`.
+func (stateDB *StateDB) GetBlockAccount(addr string) (*block.BlockAccount, error) {
+	stateObject := stateDB.getStateObject(addr)
+	if stateObject == nil {
+		return nil, errors.BlockAccountDoesNotExists
+	}
+	ba := stateObject.BlockAccount()
+	return &ba, nil
+}
+
+// SetBlockAccount updates the account data; if the account does not exist, it
+// will be created.
+func (stateDB *StateDB) SetBlockAccount(ba *block.BlockAccount) {
+	stateObject := stateDB.GetOrNewStateObject(ba.Address)
+	if stateObject != nil {
+		stateObject.SetBlockAccount(*ba)
+	}
+}
+
+// GetAccountProof returns the merkle proof of the account against the root of
+// state trie.
+func (stateDB *StateDB) GetAccountProof(addr string) (proof trie.ProofList, err error) {
+	var enc []byte
+	if enc, err = stateDB.trie.TryGet([]byte(addr)); err != nil {
+		return
+	} else if len(enc) == 0 {
+		err = errors.BlockAccountDoesNotExists
+		return
+	}
+
+	return stateDB.trie.ProveKey([]byte(addr))
+}
+
+// VerifyAccountProof checks the proof from `GetAccountProof()` against the
+// state root of `block.Header` and returns the proved account.
+func VerifyAccountProof(root common.Hash, addr string, proof trie.ProofList) (*block.BlockAccount, error) {
+	enc, err := trie.VerifyProof(root, []byte(addr), proof)
+	if err != nil {
+		return nil, errors.InvalidAccountProof.Clone().SetData("error", err.Error())
+	} else if len(enc) == 0 {
+		return nil, errors.BlockAccountDoesNotExists
+	}
+
+	var ba block.BlockAccount
+	if err = json.Unmarshal(enc, &ba); err != nil {
+		return nil, errors.InvalidAccountProof.Clone().SetData("error", err.Error())
+	}
+	if ba.Address != addr {
+		return nil, errors.InvalidAccountProof
+	}
+
+	return &ba, nil
+}
+
 func (stateDB *StateDB) getStateObject(addr string) (stateObject *stateObject) {
 	if obj := stateDB.stateObjects[addr]; obj != nil {
 		return obj
@@ -151,7 +211,6 @@ func (stateDB *StateDB) getStateObject(addr string) (stateObject *stateObject) {
 		return nil
 	}
 	var data block.BlockAccount
-
 	if err := json.Unmarshal(enc, &data); err != nil {
 		return nil
 	}
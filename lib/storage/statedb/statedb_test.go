@@ -3,7 +3,9 @@ package statedb
 import (
 	"testing"
 
+	"boscoin.io/sebak/lib/block"
 	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/errors"
 	"boscoin.io/sebak/lib/storage"
 	"boscoin.io/sebak/lib/storage/statedb/trie"
 
@@ -36,3 +38,54 @@ func TestStateDB(t *testing.T) {
 		require.Equal(t, gotValueHash, valueHash)
 	}
 }
+
+func TestStateDBAccountProof(t *testing.T) {
+	st := storage.NewTestStorage()
+
+	var root common.Hash
+	var err error
+
+	ba := block.TestMakeBlockAccount()
+	{
+		stateDB := New(root, trie.NewEthDatabase(st))
+		stateDB.SetBlockAccount(ba)
+		stateDB.SetBlockAccount(block.TestMakeBlockAccount())
+		root, err = stateDB.CommitTrie()
+		require.NoError(t, err)
+		require.NoError(t, stateDB.CommitDB(root))
+	}
+
+	stateDB := New(root, trie.NewEthDatabase(st))
+	proof, err := stateDB.GetAccountProof(ba.Address)
+	require.NoError(t, err)
+	require.NotEmpty(t, proof)
+
+	{ // valid proof
+		proved, err := VerifyAccountProof(root, ba.Address, proof)
+		require.NoError(t, err)
+		require.Equal(t, ba.Address, proved.Address)
+		require.Equal(t, ba.Balance, proved.Balance)
+		require.Equal(t, ba.SequenceID, proved.SequenceID)
+	}
+
+	{ // with different state root
+		_, err := VerifyAccountProof(common.Hash{0x01}, ba.Address, proof)
+		require.Equal(t, errors.InvalidAccountProof.Code, err.(*errors.Error).Code)
+	}
+
+	{ // with tampered proof
+		tampered := make(trie.ProofList, len(proof))
+		copy(tampered, proof)
+		node := append([]byte{}, tampered[len(tampered)-1]...)
+		node[len(node)-1] ^= 0xff
+		tampered[len(tampered)-1] = node
+
+		_, err := VerifyAccountProof(root, ba.Address, tampered)
+		require.Equal(t, errors.InvalidAccountProof.Code, err.(*errors.Error).Code)
+	}
+
+	{ // unknown account
+		_, err := stateDB.GetAccountProof(block.TestMakeBlockAccount().Address)
+		require.Equal(t, errors.BlockAccountDoesNotExists, err)
+	}
+}
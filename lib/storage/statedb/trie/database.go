@@ -1,12 +1,17 @@
 package trie
 
 import (
-	"boscoin.io/sebak/lib/storage"
+	"sync"
+
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/syndtr/goleveldb/leveldb"
-	"sync"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/storage"
 )
 
+// EthDatabase stores the trie nodes under `common.StateTriePrefix`, so they
+// can not be mixed up with the other records of storage.
 type EthDatabase struct {
 	ldbBackend *storage.LevelDBBackend
 	quitLock   sync.Mutex // Mutex protecting the quit channel access
@@ -18,16 +23,20 @@ func NewEthDatabase(ldb *storage.LevelDBBackend) *EthDatabase {
 	}
 }
 
+func makeKey(key []byte) []byte {
+	return append([]byte(common.StateTriePrefix), key...)
+}
+
 func (db *EthDatabase) Put(key []byte, value []byte) error {
-	return db.ldbBackend.Core.Put(key, value, nil)
+	return db.ldbBackend.Core.Put(makeKey(key), value, nil)
 }
 
 func (db *EthDatabase) Has(key []byte) (bool, error) {
-	return db.ldbBackend.Core.Has(key, nil)
+	return db.ldbBackend.Core.Has(makeKey(key), nil)
 }
 
 func (db *EthDatabase) Get(key []byte) ([]byte, error) {
-	dat, err := db.ldbBackend.Core.Get(key, nil)
+	dat, err := db.ldbBackend.Core.Get(makeKey(key), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -35,7 +44,7 @@ func (db *EthDatabase) Get(key []byte) ([]byte, error) {
 }
 
 func (db *EthDatabase) Delete(key []byte) error {
-	return db.ldbBackend.Core.Delete(key, nil)
+	return db.ldbBackend.Core.Delete(makeKey(key), nil)
 }
 
 func (db *EthDatabase) Close() {
@@ -59,19 +68,26 @@ type ldbBatch struct {
 }
 
 func (b *ldbBatch) Put(key, value []byte) error {
-	b.b.Put(key, value)
+	b.b.Put(makeKey(key), value)
 	b.size += len(value)
 	return nil
 }
 
 func (b *ldbBatch) Delete(key []byte) error {
-	b.b.Delete(key)
+	b.b.Delete(makeKey(key))
 	b.size += 1
 	return nil
 }
 
+// Write replays the batch into the core of backend; if the backend is
+// `storage.BatchCore`, the trie nodes will be written together with the other
+// records when it is committed.
 func (b *ldbBatch) Write() error {
-	return b.db.Core.Write(b.b, nil)
+	r := &coreReplayer{core: b.db.Core}
+	if err := b.b.Replay(r); err != nil {
+		return err
+	}
+	return r.err
 }
 
 func (b *ldbBatch) ValueSize() int {
@@ -82,3 +98,32 @@ func (b *ldbBatch) Reset() {
 	b.b.Reset()
 	b.size = 0
 }
+
+type coreReplayer struct {
+	core storage.LevelDBCore
+	err  error
+}
+
+func (r *coreReplayer) Put(key, value []byte) {
+	if r.err != nil {
+		return
+	}
+	// NOTE `key` and `value` are the part of batch data, which will be reused
+	// after `Reset()`.
+	k := make([]byte, len(key))
+	copy(k, key)
+	v := make([]byte, len(value))
+	copy(v, value)
+
+	r.err = r.core.Put(k, v, nil)
+}
+
+func (r *coreReplayer) Delete(key []byte) {
+	if r.err != nil {
+		return
+	}
+	k := make([]byte, len(key))
+	copy(k, key)
+
+	r.err = r.core.Delete(k, nil)
+}
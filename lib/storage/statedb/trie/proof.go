@@ -0,0 +1,60 @@
+package trie
+
+import (
+	"errors"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/trie"
+
+	"boscoin.io/sebak/lib/common"
+)
+
+// ProofList collects the trie nodes of merkle proof, from the root node to
+// the node which has the value.
+type ProofList [][]byte
+
+func (l *ProofList) Put(key []byte, value []byte) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// proofReader serves the nodes of `ProofList` by the node hash for
+// `trie.VerifyProof`.
+type proofReader map[string][]byte
+
+func newProofReader(proof ProofList) proofReader {
+	r := proofReader{}
+	for _, node := range proof {
+		r[string(crypto.Keccak256(node))] = node
+	}
+	return r
+}
+
+func (r proofReader) Get(key []byte) ([]byte, error) {
+	node, found := r[string(key)]
+	if !found {
+		return nil, errors.New("proof node not found")
+	}
+	return node, nil
+}
+
+func (r proofReader) Has(key []byte) (bool, error) {
+	_, found := r[string(key)]
+	return found, nil
+}
+
+// ProveKey returns the merkle proof of the given key. If the key does not
+// exist in trie, the proof shows the absence of the key.
+func (t *Trie) ProveKey(key []byte) (proof ProofList, err error) {
+	err = t.Prove(key, 0, &proof)
+	return
+}
+
+// VerifyProof checks the merkle proof against the given root hash and
+// returns the value of key. If the proof shows the absence of key, the
+// returned value is nil.
+func VerifyProof(root common.Hash, key []byte, proof ProofList) (value []byte, err error) {
+	value, _, err = trie.VerifyProof(ethcommon.Hash(root), key, newProofReader(proof))
+	return
+}
@@ -13,12 +13,14 @@ import (
 )
 
 const (
-	SyncPoolSize             uint64 = 300
+	SyncPoolSize             uint64 = 10
+	SyncBatchSize            uint64 = 100
 	FetchTimeout                    = 1 * time.Minute
 	RetryInterval                   = 10 * time.Second
 	CheckBlockHeightInterval        = 30 * time.Second
 	CheckPrevBlockInterval          = 30 * time.Second
 	WatchInterval                   = 5 * time.Second
+	VotingThreshold                 = 67
 )
 
 type Config struct {
@@ -29,13 +31,18 @@ type Config struct {
 	nodelist          *NodeList
 	logger            log15.Logger
 	commonCfg         common.Config
+	stateSnapshotter  *runner.StateSnapshotter
+	updateValidators  func() error
+	updateDelegated   func() error
 
 	SyncPoolSize             uint64
+	SyncBatchSize            uint64
 	FetchTimeout             time.Duration
 	RetryInterval            time.Duration
 	CheckBlockHeightInterval time.Duration
 	CheckPrevBlockInterval   time.Duration
 	WatchInterval            time.Duration
+	VotingThreshold          int
 }
 
 func NewConfig(localNode *node.LocalNode,
@@ -53,9 +60,11 @@ func NewConfig(localNode *node.LocalNode,
 		nodelist:          &NodeList{},
 
 		SyncPoolSize:             SyncPoolSize,
+		SyncBatchSize:            SyncBatchSize,
 		FetchTimeout:             FetchTimeout,
 		RetryInterval:            RetryInterval,
 		CheckBlockHeightInterval: CheckBlockHeightInterval,
+		VotingThreshold:          VotingThreshold,
 	}
 	commonAccountAddress, err := c.commonAccountAddress()
 	if err != nil {
@@ -71,7 +80,9 @@ func (c *Config) NewSyncer() *Syncer {
 	s := NewSyncer(f, v, c.storage, func(s *Syncer) {
 		s.nodelist = c.nodelist
 		s.poolSize = c.SyncPoolSize
+		s.batchSize = c.SyncBatchSize
 		s.checkInterval = c.CheckBlockHeightInterval
+		s.retryInterval = c.RetryInterval
 		s.logger = c.logger.New("submodule", "syncer")
 	})
 
@@ -80,6 +91,10 @@ func (c *Config) NewSyncer() *Syncer {
 }
 
 func (c *Config) NewFetcher() Fetcher {
+	return c.newBlockFetcher()
+}
+
+func (c *Config) newBlockFetcher() *BlockFetcher {
 	client := c.NewHTTP2Client()
 
 	f := NewBlockFetcher(
@@ -90,6 +105,7 @@ func (c *Config) NewFetcher() Fetcher {
 		func(f *BlockFetcher) {
 			f.fetchTimeout = c.FetchTimeout
 			f.retryInterval = c.RetryInterval
+			f.batchSize = c.SyncBatchSize
 			f.logger = c.logger.New("submodule", "fetcher")
 		},
 	)
@@ -97,17 +113,71 @@ func (c *Config) NewFetcher() Fetcher {
 }
 
 func (c *Config) NewValidator() Validator {
+	return c.newBlockValidator()
+}
+
+func (c *Config) newBlockValidator() *BlockValidator {
 	v := NewBlockValidator(
 		c.storage,
 		c.tp,
 		c.commonCfg,
 		func(v *BlockValidator) {
 			v.prevBlockWaitTimeout = c.CheckPrevBlockInterval
+			v.threshold = c.VotingThreshold
+			v.snapshotter = c.stateSnapshotter
+			v.updateValidators = func() error {
+				if c.updateValidators == nil {
+					return nil
+				}
+				return c.updateValidators()
+			}
+			v.updateDelegated = func() error {
+				if c.updateDelegated == nil {
+					return nil
+				}
+				return c.updateDelegated()
+			}
 			v.logger = c.logger.New("submodule", "validator")
 		})
 	return v
 }
 
+// NewStateSyncer makes the `StateSyncer`; it should be run before the
+// `Syncer` is started.
+func (c *Config) NewStateSyncer() *StateSyncer {
+	return NewStateSyncer(
+		c.newBlockFetcher(),
+		c.newBlockValidator(),
+		c.NewHTTP2Client(),
+		c.storage,
+		c.localNode,
+		c.commonCfg,
+		func(s *StateSyncer) {
+			s.threshold = c.VotingThreshold
+			s.logger = c.logger.New("submodule", "state-syncer")
+		},
+	)
+}
+
+// SetStateSnapshotter sets the `runner.StateSnapshotter`, which takes the
+// snapshot of the synced blocks.
+func (c *Config) SetStateSnapshotter(s *runner.StateSnapshotter) {
+	c.stateSnapshotter = s
+}
+
+// SetValidatorsUpdater sets the function, which applies the validator set
+// changes of the synced blocks; usually it is `runner.NodeRunner.UpdateValidators`,
+// so it can be set after the `Syncer` is made.
+func (c *Config) SetValidatorsUpdater(f func() error) {
+	c.updateValidators = f
+}
+
+// SetDelegatedUpdater sets the function, which applies the delegated amounts
+// of the synced blocks; usually it is `runner.NodeRunner.UpdateDelegated`.
+func (c *Config) SetDelegatedUpdater(f func() error) {
+	c.updateDelegated = f
+}
+
 func (c *Config) NewWatcher(s SyncController) *Watcher {
 	c.logger.Info("watcher config", "watchInterval", c.WatchInterval)
 
@@ -137,10 +207,12 @@ func (c *Config) NewHTTP2Client() *common.HTTP2Client {
 func (c *Config) LoggingConfig() {
 	c.logger.Info("syncer config",
 		"poolSize", c.SyncPoolSize,
+		"batchSize", c.SyncBatchSize,
 		"fetchTimeout", c.FetchTimeout,
 		"retryInterval", c.RetryInterval,
 		"checkInterval", c.CheckBlockHeightInterval,
 		"checkPrevBlockInterval", c.CheckPrevBlockInterval,
+		"votingThreshold", c.VotingThreshold,
 	)
 }
 
@@ -5,9 +5,9 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"math/rand"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 
 	"boscoin.io/sebak/lib/ballot"
@@ -20,18 +20,24 @@ import (
 	"boscoin.io/sebak/lib/node/runner"
 	api "boscoin.io/sebak/lib/node/runner/node_api"
 	"boscoin.io/sebak/lib/storage"
+	"boscoin.io/sebak/lib/voting"
 
 	"github.com/inconshreveable/log15"
 )
 
+// BlockFetcher fetches the headers and blocks by the range of heights. The
+// node to request is picked by `PeerScores`, so the faster node is requested
+// first and the node, which sent the invalid data, is avoided.
 type BlockFetcher struct {
 	connectionManager network.ConnectionManager
 	apiClient         Doer
 	storage           *storage.LevelDBBackend
 	localNode         *node.LocalNode
+	peers             *PeerScores
 
 	fetchTimeout  time.Duration
 	retryInterval time.Duration
+	batchSize     uint64 // maximum number of blocks in one request
 
 	logger log15.Logger
 }
@@ -50,10 +56,12 @@ func NewBlockFetcher(
 		apiClient:         client,
 		storage:           st,
 		localNode:         localNode,
+		peers:             NewPeerScores(),
 		logger:            common.NopLogger(),
 
 		fetchTimeout:  1 * time.Minute,
 		retryInterval: 30 * time.Second,
+		batchSize:     SyncBatchSize,
 	}
 
 	for _, opt := range opts {
@@ -63,177 +71,321 @@ func NewBlockFetcher(
 	return f
 }
 
-func (f *BlockFetcher) Fetch(ctx context.Context, syncInfo *SyncInfo) (*SyncInfo, error) {
-	height := syncInfo.Height
+// Peers returns the scores of nodes.
+func (f *BlockFetcher) Peers() *PeerScores {
+	return f.peers
+}
 
-	TryForever(func(attempt int) (bool, error) {
-		select {
-		case <-ctx.Done():
-			return false, ctx.Err()
-		default:
-			f.logger.Debug("try to fetch", "height", height, "attempt", attempt)
-			if err := f.fetch(ctx, syncInfo); err != nil {
-				if err == context.Canceled {
-					return false, ctx.Err()
-				}
-				f.logger.Error("fetch err", "err", err, "height", height)
-				metrics.Sync.AddFetchError()
-				c := time.After(f.retryInterval) //afterFunc?
-				select {
-				case <-ctx.Done():
-					return false, ctx.Err()
-				case <-c:
-					return true, err
+func (f *BlockFetcher) FetchHeaders(ctx context.Context, prev block.Block, to uint64, nodeAddrs []string) ([]SyncHeader, error) {
+	var headers []SyncHeader
+	for prev.Height < to {
+		from, end := prev.Height+1, prev.Height+f.batchSize
+		if end > to {
+			end = to
+		}
+
+		var fetched []SyncHeader
+		err := f.tryNodes(ctx, nodeAddrs, func(n node.Node) error {
+			fetched = fetched[:0]
+
+			items, err := f.fetchRange(ctx, n, from, end, runner.GetBlocksOptionsModeBlock)
+			if err != nil {
+				return err
+			}
+
+			blocks := items[api.NodeItemBlock]
+			if uint64(len(blocks)) != end-from+1 {
+				return errors.BlockNotFound
+			}
+
+			p := prev
+			for _, b := range blocks {
+				blk := b.(block.Block)
+				if err := checkHeader(p, blk); err != nil {
+					f.logger.Error("invalid header", "node", n.Address(), "height", blk.Height, "hash", blk.Hash)
+					return err
 				}
+				fetched = append(fetched, SyncHeader{Block: blk, Source: n.Address()})
+				p = blk
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		headers = append(headers, fetched...)
+		prev = fetched[len(fetched)-1].Block
+	}
+
+	return headers, nil
+}
+
+func (f *BlockFetcher) Fetch(ctx context.Context, headers []SyncHeader, nodeAddrs []string) ([]*SyncInfo, error) {
+	if len(headers) < 1 {
+		return nil, nil
+	}
+
+	var infos []*SyncInfo
+	err := f.tryNodes(ctx, nodeAddrs, func(n node.Node) (err error) {
+		from, to := headers[0].Block.Height, headers[len(headers)-1].Block.Height
+		if infos, err = f.fetchBlocks(ctx, n, from, to); err != nil {
+			return
+		}
+
+		for i, si := range infos {
+			if si.Block.Hash != headers[i].Block.Hash {
+				f.logger.Error("block does not match with header", "node", n.Address(), "height", si.Height, "hash", si.Block.Hash, "header", headers[i].Block.Hash)
+				return errors.SyncInvalidData
 			}
-			return false, nil
 		}
+
+		return
 	})
 
-	return syncInfo, nil
+	return infos, err
 }
 
-func (f *BlockFetcher) fetch(ctx context.Context, si *SyncInfo) error {
-	var (
-		height    = si.Height
-		nodeAddrs = si.NodeAddrs()
-	)
-	si.Bts = si.Bts[:0]
-	f.logger.Debug("start fetch", "height", height, "nodes", nodeAddrs)
+func (f *BlockFetcher) Invalid(nodeAddr string) {
+	f.peers.Invalid(nodeAddr)
+	metrics.Sync.AddInvalidData()
+}
 
-	if len(nodeAddrs) <= 0 {
-		f.logger.Error("Node addrs are nil!", "height", height, "nodes", nodeAddrs)
-		return errors.NodeNotFound
-	}
+// tryNodes calls `fn` with the nodes by the order of score until it succeeds;
+// if all the nodes fail, it retries after `retryInterval`.
+func (f *BlockFetcher) tryNodes(ctx context.Context, nodeAddrs []string, fn func(node.Node) error) error {
+	for {
+		addrs := f.peers.Sort(f.candidates(nodeAddrs))
+		if len(addrs) < 1 {
+			f.logger.Error("Alive Node addrs not exists", "nodes", nodeAddrs)
+		}
+
+		for _, addr := range addrs {
+			n := f.localNode.Validator(addr)
+			if n == nil {
+				continue
+			}
 
-	n := f.pickRandomNode(nodeAddrs)
-	if n == nil {
-		f.logger.Error("Alive Node addrs not exists", "height", height, "nodes", nodeAddrs)
-		return errors.NodeNotFound
+			f.peers.Begin(addr)
+			begin := time.Now()
+			err := fn(n)
+			if err == nil {
+				f.peers.Succeed(addr, time.Since(begin))
+				return nil
+			}
+
+			if ctx.Err() != nil {
+				f.peers.Fail(addr)
+				return ctx.Err()
+			}
+
+			f.logger.Error("fetch err", "err", err, "node", addr)
+			metrics.Sync.AddFetchError()
+			if err == errors.SyncInvalidData {
+				f.Invalid(addr)
+			} else {
+				f.peers.Fail(addr)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(f.retryInterval):
+		}
 	}
-	f.logger.Debug("fetching items from node", "fetching_node", n, "height", height)
+}
 
-	apiURL := apiClientURL(n, height)
-	f.logger.Debug("apiClient", "url", apiURL.String())
+// candidates returns the connected nodes in `nodeAddrs` except local node;
+// with empty `nodeAddrs`, all the connected nodes are returned.
+func (f *BlockFetcher) candidates(nodeAddrs []string) []string {
+	var nodeMap = make(map[string]struct{})
+	for _, addr := range nodeAddrs {
+		nodeMap[addr] = struct{}{}
+	}
 
-	req, err := http.NewRequest("GET", apiURL.String(), nil)
-	if err != nil {
-		err := errors.Wrap(err, "api request")
-		f.logger.Error("request err", "err", err, "height", height)
-		return err
+	var addressList []string
+	for _, a := range f.connectionManager.AllConnected() {
+		if f.localNode.Address() == a {
+			continue
+		}
+		if len(nodeAddrs) > 0 {
+			if _, ok := nodeMap[a]; !ok {
+				continue
+			}
+		}
+		addressList = append(addressList, a)
 	}
-	req = req.WithContext(ctx)
 
-	resp, err := f.apiClient.Do(req)
+	return addressList
+}
+
+// fetchFromNode fetches the block of `SyncInfo.Height` and it's transactions
+// and certificate from the node.
+func (f *BlockFetcher) fetchFromNode(ctx context.Context, n node.Node, si *SyncInfo) error {
+	infos, err := f.fetchBlocks(ctx, n, si.Height, si.Height)
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode == http.StatusNotFound {
-		return errors.New("fetch: block not found")
-	}
-	if resp.StatusCode == http.StatusTooManyRequests {
-		return errors.New("fetch: too many requests")
-	}
+	fetched := infos[0]
+	si.Block = fetched.Block
+	si.Bts = fetched.Bts
+	si.Ptx = fetched.Ptx
+	si.Certificate = fetched.Certificate
+	si.Source = fetched.Source
 
-	items, err := f.unmarshalResp(resp.Body)
+	return nil
+}
+
+// fetchBlocks fetches the blocks from `from` to `to` with their transactions
+// and certificates.
+func (f *BlockFetcher) fetchBlocks(ctx context.Context, n node.Node, from, to uint64) ([]*SyncInfo, error) {
+	items, err := f.fetchRange(ctx, n, from, to, runner.GetBlocksOptionsModeFull)
 	if err != nil {
-		err := errors.Wrap(err, "response failed to unmarshal")
-		code := resp.StatusCode
-		f.logger.Debug("unmarshalResp err", "err", err, "height", height, "statusCode", code)
-		return err
+		return nil, err
 	}
 
-	f.logger.Debug("fetch get items", "items", len(items), "height", height)
+	blocks := items[api.NodeItemBlock]
+	if uint64(len(blocks)) != to-from+1 {
+		return nil, errors.New("fetch: block not found in response")
+	}
 
-	blocks, ok := items[api.NodeItemBlock]
-	if !ok || len(blocks) <= 0 {
-		err := errors.New("fetch: block not found in response")
-		return err
+	// the pruned node can not serve the transactions of old blocks
+	if errs, found := items[api.NodeItemError]; found {
+		f.logger.Debug("node failed to serve blocks", "node", n.Address(), "from", from, "to", to, "error", errs[0])
+		return nil, errors.New("fetch: node failed to serve blocks")
 	}
 
-	bts, ok := items[api.NodeItemBlockTransaction]
-	if !ok {
-		err := errors.New("fetch: block transactions not found in response")
-		return err
+	btmap := make(map[string]*block.BlockTransaction) // For ordering txs by block.Transactions
+	for _, bt := range items[api.NodeItemBlockTransaction] {
+		bt, ok := bt.(block.BlockTransaction)
+		if !ok {
+			return nil, errors.InvalidTransaction
+		}
+		btmap[bt.Hash] = &bt
 	}
 
-	blk := blocks[0].(block.Block)
-	si.Block = &blk
+	certificates := map[string]*ballot.Certificate{}
+	for _, c := range items[api.NodeItemCertificate] {
+		certificate := c.(ballot.Certificate)
+		certificates[certificate.Block] = &certificate
+	}
 
-	{
-		btmap := make(map[string]*block.BlockTransaction) // For ordering txs by block.Transactions
+	var infos []*SyncInfo
+	for i, b := range blocks {
+		blk := b.(block.Block)
+		if blk.Height != from+uint64(i) {
+			f.logger.Error("unexpected block height", "node", n.Address(), "height", blk.Height, "expected", from+uint64(i))
+			return nil, errors.SyncInvalidData
+		}
 
-		for _, bt := range bts {
-			bt, ok := bt.(block.BlockTransaction)
-			if !ok {
-				return errors.InvalidTransaction
-			}
-			btmap[bt.Hash] = &bt
+		si := &SyncInfo{
+			Height:      blk.Height,
+			Block:       &blk,
+			Certificate: certificates[blk.Hash],
+			Source:      n.Address(),
 		}
 
 		for _, hash := range blk.Transactions {
 			bt, ok := btmap[hash]
 			if !ok {
-				return errors.Wrapf(errors.TransactionNotFound, "block hash: %s height: %d", hash, blk.Height)
+				f.logger.Error("tx in block not found", "node", n.Address(), "height", blk.Height, "tx", hash)
+				return nil, errors.SyncInvalidData
 			}
 			if bt.Transaction().IsEmpty() {
-				return errors.Wrapf(errors.TransactionNotFound, "tx in btx not found: tx %s not found of height %s", hash, blk.Height)
+				return nil, f.prunedError(n, blk, hash)
 			}
 			si.Bts = append(si.Bts, bt)
 		}
 
 		if blk.ProposerTransaction != "" {
-			if bt, ok := btmap[blk.ProposerTransaction]; ok {
-				if bt.Transaction().IsEmpty() {
-					return errors.Wrapf(errors.TransactionNotFound, "proposer tx in btx not found: tx %s not found of height %s", blk.ProposerTransaction, blk.Height)
-				}
-				ptx := &ballot.ProposerTransaction{Transaction: bt.Transaction()}
-				si.Ptx = ptx
-			} else {
-				return errors.Wrapf(errors.TransactionNotFound, "proposer transaction block hash: %v", blk.ProposerTransaction)
+			bt, ok := btmap[blk.ProposerTransaction]
+			if !ok {
+				f.logger.Error("proposer tx in block not found", "node", n.Address(), "height", blk.Height, "tx", blk.ProposerTransaction)
+				return nil, errors.SyncInvalidData
 			}
+			if bt.Transaction().IsEmpty() {
+				return nil, f.prunedError(n, blk, blk.ProposerTransaction)
+			}
+			si.Ptx = &ballot.ProposerTransaction{Transaction: bt.Transaction()}
 		}
+
+		infos = append(infos, si)
 	}
 
-	f.logger.Debug("end fetch", "height", height)
-	return nil
+	return infos, nil
 }
 
-// pickRandomNode choose one node by random. It is very protype for choosing fetching which node
-func (f *BlockFetcher) pickRandomNode(nodeAddrs []string) node.Node {
-	ac := f.connectionManager.AllConnected()
-	if len(ac) <= 0 {
-		return nil
+// prunedError is returned when the body of transaction in block is not
+// served; the pruned node serves the transactions of old blocks without body.
+func (f *BlockFetcher) prunedError(n node.Node, blk block.Block, hash string) error {
+	f.logger.Debug("body of tx in block is pruned", "node", n.Address(), "height", blk.Height, "tx", hash)
+	return errors.SyncBlocksPruned
+}
+
+// fetchRange requests the blocks from `from` to `to` to the node.
+func (f *BlockFetcher) fetchRange(ctx context.Context, n node.Node, from, to uint64, mode runner.GetBlocksOptionsMode) (map[api.NodeItemDataType][]interface{}, error) {
+	f.logger.Debug("fetching items from node", "fetching_node", n, "from", from, "to", to, "mode", mode)
+
+	apiURL := apiClientURL(n, from, to, mode)
+	f.logger.Debug("apiClient", "url", apiURL.String())
+
+	req, err := http.NewRequest("GET", apiURL.String(), nil)
+	if err != nil {
+		err := errors.Wrap(err, "api request")
+		f.logger.Error("request err", "err", err, "from", from, "to", to)
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+
+	resp, err := f.apiClient.Do(req)
+	if err != nil {
+		return nil, err
 	}
+	defer resp.Body.Close()
 
-	var nodeMap = make(map[string]struct{})
-	for _, addr := range nodeAddrs {
-		nodeMap[addr] = struct{}{}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errors.New("fetch: block not found")
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, errors.New("fetch: too many requests")
 	}
 
-	var addressList []string
-	for _, a := range ac {
-		if f.localNode.Address() == a {
-			continue
-		}
-		if len(nodeAddrs) > 0 {
-			if _, ok := nodeMap[a]; ok {
-				addressList = append(addressList, a)
-			}
-		} else {
-			addressList = append(addressList, a)
-		}
+	items, err := f.unmarshalResp(resp.Body)
+	if err != nil {
+		err := errors.Wrap(err, "response failed to unmarshal")
+		code := resp.StatusCode
+		f.logger.Debug("unmarshalResp err", "err", err, "from", from, "to", to, "statusCode", code)
+		return nil, err
 	}
 
-	if len(addressList) <= 0 {
-		return nil
+	f.logger.Debug("fetch get items", "items", len(items), "from", from, "to", to)
+
+	return items, nil
+}
+
+// checkHeader checks the block is the next block of `prev` and it's hash is
+// made from it's fields.
+func checkHeader(prev block.Block, blk block.Block) error {
+	if blk.Height != prev.Height+1 || blk.PrevBlockHash != prev.Hash {
+		return errors.SyncInvalidData
 	}
 
-	idx := rand.Intn(len(addressList))
-	node := f.localNode.Validator(addressList[idx])
-	return node
+	basis := voting.Basis{
+		Round:     blk.Round,
+		Height:    blk.Height,
+		BlockHash: prev.Hash,
+		TotalTxs:  blk.TotalTxs,
+		TotalOps:  blk.TotalOps,
+	}
+	expected := block.NewBlock(blk.Proposer, basis, blk.ProposerTransaction, blk.Transactions, blk.StateRoot, blk.ProposedTime)
+	if expected.Hash != blk.Hash {
+		return errors.SyncInvalidData
+	}
+
+	return nil
 }
 
 func (f *BlockFetcher) existsBlockHeight(height uint64) bool {
@@ -275,13 +427,14 @@ func (f *BlockFetcher) unmarshalResp(body io.Reader) (map[api.NodeItemDataType][
 	return items, nil
 }
 
-func apiClientURL(n node.Node, height uint64) *url.URL {
+func apiClientURL(n node.Node, from, to uint64, mode runner.GetBlocksOptionsMode) *url.URL {
 	ep := n.Endpoint()
 	u := url.URL(*ep)
 	u.Path = network.UrlPathPrefixNode + runner.GetBlocksPattern
 	q := u.Query()
-	q.Set("height-range", fmt.Sprintf("%d-%d", height, height+1))
-	q.Set("mode", "full")
+	q.Set("height-range", fmt.Sprintf("%d-%d", from, to+1))
+	q.Set("limit", strconv.FormatUint(to-from+1, 10))
+	q.Set("mode", string(mode))
 	u.RawQuery = q.Encode()
 
 	return &u
@@ -11,6 +11,7 @@ import (
 	"boscoin.io/sebak/lib/block"
 	"boscoin.io/sebak/lib/common"
 	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/errors"
 	"boscoin.io/sebak/lib/node"
 	api "boscoin.io/sebak/lib/node/runner/node_api"
 
@@ -73,14 +74,123 @@ func TestBlockFetcher(t *testing.T) {
 	f.logger = log
 
 	ctx := context.Background()
-	nodelist := &NodeList{}
-	nodelist.SetLatestNodeAddrs([]string{kp.Address()})
-	si, err := f.Fetch(ctx, &SyncInfo{Height: 1, NodeList: nodelist})
+	infos, err := f.Fetch(ctx, []SyncHeader{{Block: bk}}, []string{kp.Address()})
 	require.NoError(t, err)
+	require.Equal(t, 1, len(infos))
+	si := infos[0]
+	require.Equal(t, kp.Address(), si.Source)
 	require.Equal(t, bk.Hash, si.Block.Hash)
 	require.Equal(t, bk.TransactionsRoot, si.Block.TransactionsRoot)
 }
 
+// TestBlockFetcherPruned checks the blocks, whose transactions are served
+// without body by the pruned node, are not fetched.
+func TestBlockFetcherPruned(t *testing.T) {
+	st := block.InitTestBlockchain()
+	defer st.Close()
+
+	localNode := node.NewTestLocalNode0()
+
+	kp := keypair.Random()
+	ep := common.MustParseEndpoint("https://node1?NodeName=n1")
+	v, _ := node.NewValidator(kp.Address(), ep, "n1")
+
+	localNode.AddValidators(v)
+	cm := &mockConnectionManager{
+		allConnected: []string{kp.Address()},
+	}
+
+	bk := block.GetLatestBlock(st)
+	bt, err := block.GetBlockTransaction(st, bk.Transactions[0])
+	require.NoError(t, err)
+
+	apiHandlerFunc := func(req *http.Request) (*http.Response, error) {
+		w := httptest.NewRecorder()
+		renderNodeItem(w, api.NodeItemBlock, bk)
+		// the `TransactionPool` of the transaction is pruned
+		renderNodeItem(w, api.NodeItemBlockTransaction, bt)
+		return w.Result(), nil
+	}
+
+	f := NewBlockFetcher(cm, mockDoer{handleFunc: apiHandlerFunc}, st, localNode)
+	f.logger = log
+
+	_, err = f.fetchBlocks(context.Background(), v, bk.Height, bk.Height)
+	require.Equal(t, errors.SyncBlocksPruned, err)
+}
+
+func TestBlockFetcherHeaders(t *testing.T) {
+	st := block.InitTestBlockchain()
+	defer st.Close()
+
+	localNode := node.NewTestLocalNode0()
+
+	var addrs []string
+	hosts := map[string]string{}
+	for _, name := range []string{"n1", "n2"} {
+		kp := keypair.Random()
+		v, _ := node.NewValidator(kp.Address(), common.MustParseEndpoint("https://"+name), name)
+		localNode.AddValidators(v)
+		addrs = append(addrs, kp.Address())
+		hosts[name] = kp.Address()
+	}
+	cm := &mockConnectionManager{
+		allConnected: addrs,
+	}
+
+	genesis := block.GetLatestBlock(st)
+	var blocks []block.Block
+	prev := genesis
+	for i := 0; i < 3; i++ {
+		prev = block.TestMakeNewBlockWithPrevBlock(prev, []string{})
+		blocks = append(blocks, prev)
+	}
+
+	// n1 sends the block, which is modified after hashing
+	invalid := make([]block.Block, len(blocks))
+	copy(invalid, blocks)
+	invalid[1].TotalTxs = 100
+
+	var requested []string
+	cli := mockDoer{
+		handleFunc: func(req *http.Request) (*http.Response, error) {
+			requested = append(requested, req.URL.Hostname())
+			require.Equal(t, "block", req.URL.Query().Get("mode"))
+			require.Equal(t, "2-5", req.URL.Query().Get("height-range"))
+
+			w := httptest.NewRecorder()
+			bs := blocks
+			if req.URL.Hostname() == "n1" {
+				bs = invalid
+			}
+			for _, b := range bs {
+				renderNodeItem(w, api.NodeItemBlock, b)
+			}
+			return w.Result(), nil
+		},
+	}
+
+	f := NewBlockFetcher(cm, cli, st, localNode)
+	f.logger = log
+
+	ctx := context.Background()
+	headers, err := f.FetchHeaders(ctx, genesis, genesis.Height+3, addrs)
+	require.NoError(t, err)
+	require.Equal(t, []string{"n1", "n2"}, requested)
+	require.Equal(t, 3, len(headers))
+	for i, h := range headers {
+		require.Equal(t, blocks[i].Hash, h.Block.Hash)
+		require.Equal(t, hosts["n2"], h.Source)
+	}
+	require.Equal(t, uint64(1), f.Peers().Get(hosts["n1"]).Invalids)
+
+	// n1 is banned
+	requested = nil
+	_, err = f.FetchHeaders(ctx, genesis, genesis.Height+3, addrs)
+	require.NoError(t, err)
+	require.Equal(t, []string{"n2"}, requested)
+}
+
 func TestLargeFetch(t *testing.T) {
 	f := &BlockFetcher{}
 	f.logger = log
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net/http"
 
+	"boscoin.io/sebak/lib/block"
 	"boscoin.io/sebak/lib/common"
 	"boscoin.io/sebak/lib/network"
 	"boscoin.io/sebak/lib/node"
@@ -47,6 +48,8 @@ func (m *mockConnectionManager) Discovery(network.DiscoveryMessage) error {
 	return nil
 }
 
+func (m *mockConnectionManager) UpdateValidators() {}
+
 type mockDoer struct {
 	handleFunc func(*http.Request) (*http.Response, error)
 }
@@ -59,11 +62,23 @@ func (d mockDoer) Do(req *http.Request) (*http.Response, error) {
 }
 
 type mockFetcher struct {
-	fetchFunc func(context.Context, *SyncInfo) (*SyncInfo, error)
+	headerFunc  func(context.Context, block.Block, uint64, []string) ([]SyncHeader, error)
+	fetchFunc   func(context.Context, []SyncHeader, []string) ([]*SyncInfo, error)
+	invalidFunc func(string)
+}
+
+func (f mockFetcher) FetchHeaders(ctx context.Context, prev block.Block, to uint64, nodeAddrs []string) ([]SyncHeader, error) {
+	return f.headerFunc(ctx, prev, to, nodeAddrs)
 }
 
-func (f mockFetcher) Fetch(ctx context.Context, si *SyncInfo) (*SyncInfo, error) {
-	return f.fetchFunc(ctx, si)
+func (f mockFetcher) Fetch(ctx context.Context, headers []SyncHeader, nodeAddrs []string) ([]*SyncInfo, error) {
+	return f.fetchFunc(ctx, headers, nodeAddrs)
+}
+
+func (f mockFetcher) Invalid(nodeAddr string) {
+	if f.invalidFunc != nil {
+		f.invalidFunc(nodeAddr)
+	}
 }
 
 type mockValidator struct {
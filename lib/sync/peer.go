@@ -0,0 +1,160 @@
+package sync
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultPeerLatency is the latency of the node, which is not requested
+	// yet.
+	DefaultPeerLatency = 1 * time.Second
+	// PeerLatencyWeight is the weight of the new latency in the moving
+	// average of latency.
+	PeerLatencyWeight = 0.3
+	// PeerBanDuration is how long the node, which sent the invalid data, is
+	// not picked; it is multiplied by the number of invalid data.
+	PeerBanDuration = 1 * time.Minute
+)
+
+// PeerScore is the record of responses of node.
+type PeerScore struct {
+	Latency     time.Duration // moving average of latency
+	Successes   uint64
+	Failures    uint64 // failures after the last success
+	Invalids    uint64 // number of invalid data
+	InFlight    uint64 // number of the running requests
+	BannedUntil time.Time
+}
+
+// PeerScores scores the nodes, which the blocks are fetched from, by the
+// latency and correctness of responses. The node with lower latency is picked
+// first; the failures increase the score and the node, which sent the
+// invalid data, is banned for a while.
+type PeerScores struct {
+	sync.RWMutex
+
+	peers map[ /* node.Address() */ string]*PeerScore
+	now   func() time.Time
+}
+
+func NewPeerScores() *PeerScores {
+	return &PeerScores{
+		peers: map[string]*PeerScore{},
+		now:   time.Now,
+	}
+}
+
+func (p *PeerScores) get(address string) *PeerScore {
+	s, found := p.peers[address]
+	if !found {
+		s = &PeerScore{Latency: DefaultPeerLatency}
+		p.peers[address] = s
+	}
+
+	return s
+}
+
+// Get returns the copy of score of node.
+func (p *PeerScores) Get(address string) PeerScore {
+	p.RLock()
+	defer p.RUnlock()
+
+	if s, found := p.peers[address]; found {
+		return *s
+	}
+
+	return PeerScore{Latency: DefaultPeerLatency}
+}
+
+// Begin marks the request to node is started; the node, which has more
+// running requests, is picked later, so the requests are spread over nodes.
+func (p *PeerScores) Begin(address string) {
+	p.Lock()
+	defer p.Unlock()
+
+	p.get(address).InFlight++
+}
+
+func (p *PeerScores) end(s *PeerScore) {
+	if s.InFlight > 0 {
+		s.InFlight--
+	}
+}
+
+// Succeed records the successful response of node.
+func (p *PeerScores) Succeed(address string, latency time.Duration) {
+	p.Lock()
+	defer p.Unlock()
+
+	s := p.get(address)
+	p.end(s)
+	s.Successes++
+	s.Failures = 0
+	s.Latency = time.Duration(float64(s.Latency)*(1-PeerLatencyWeight) + float64(latency)*PeerLatencyWeight)
+}
+
+// Fail records the failed request, like timeout or not found.
+func (p *PeerScores) Fail(address string) {
+	p.Lock()
+	defer p.Unlock()
+
+	s := p.get(address)
+	p.end(s)
+	s.Failures++
+}
+
+// Invalid records the node sent the invalid data; the node is banned.
+func (p *PeerScores) Invalid(address string) {
+	p.Lock()
+	defer p.Unlock()
+
+	s := p.get(address)
+	p.end(s)
+	s.Invalids++
+	s.BannedUntil = p.now().Add(PeerBanDuration * time.Duration(s.Invalids))
+}
+
+// Score returns the score of node; the lower is the better.
+func (p *PeerScores) Score(address string) float64 {
+	p.RLock()
+	defer p.RUnlock()
+
+	return p.score(address)
+}
+
+func (p *PeerScores) score(address string) float64 {
+	s, found := p.peers[address]
+	if !found {
+		return DefaultPeerLatency.Seconds()
+	}
+
+	return s.Latency.Seconds() * float64(1+s.Failures) * float64(1+s.InFlight)
+}
+
+func (p *PeerScores) isBanned(address string) bool {
+	s, found := p.peers[address]
+	return found && p.now().Before(s.BannedUntil)
+}
+
+// Sort returns the addresses by the order of score; the banned nodes are
+// placed at last, so they are tried only when the others fail.
+func (p *PeerScores) Sort(addresses []string) []string {
+	p.RLock()
+	defer p.RUnlock()
+
+	sorted := make([]string, len(addresses))
+	copy(sorted, addresses)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		bi, bj := p.isBanned(sorted[i]), p.isBanned(sorted[j])
+		if bi != bj {
+			return bj
+		}
+
+		return p.score(sorted[i]) < p.score(sorted[j])
+	})
+
+	return sorted
+}
@@ -0,0 +1,64 @@
+package sync
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeerScores(t *testing.T) {
+	now := time.Now()
+	p := NewPeerScores()
+	p.now = func() time.Time { return now }
+
+	addrs := []string{"a", "b", "c"}
+
+	{ // not requested yet; the order is kept
+		require.Equal(t, addrs, p.Sort(addrs))
+		require.Equal(t, DefaultPeerLatency, p.Get("a").Latency)
+	}
+
+	{ // faster node first
+		p.Begin("a")
+		p.Succeed("a", 2*time.Second)
+		p.Begin("b")
+		p.Succeed("b", 100*time.Millisecond)
+		require.Equal(t, []string{"b", "c", "a"}, p.Sort(addrs))
+		require.Equal(t, uint64(1), p.Get("b").Successes)
+		require.Equal(t, uint64(0), p.Get("b").InFlight)
+	}
+
+	{ // running requests are spread
+		p.Begin("b")
+		p.Begin("b")
+		p.Begin("b")
+		require.Equal(t, []string{"c", "a", "b"}, p.Sort(addrs))
+		p.Fail("b")
+		p.Fail("b")
+		p.Fail("b")
+		require.Equal(t, uint64(0), p.Get("b").InFlight)
+		require.Equal(t, uint64(3), p.Get("b").Failures)
+	}
+
+	{ // the node sent the invalid data is placed at last
+		p.Begin("c")
+		p.Invalid("c")
+		require.Equal(t, "c", p.Sort(addrs)[2])
+		require.Equal(t, uint64(1), p.Get("c").Invalids)
+
+		// ban is released
+		now = now.Add(PeerBanDuration)
+		require.Equal(t, "c", p.Sort(addrs)[0])
+
+		// ban grows with the invalid data
+		p.Invalid("c")
+		require.Equal(t, now.Add(2*PeerBanDuration), p.Get("c").BannedUntil)
+	}
+
+	{ // success resets failures
+		p.Succeed("b", 100*time.Millisecond)
+		require.Equal(t, uint64(0), p.Get("b").Failures)
+		require.Equal(t, []string{"b", "a", "c"}, p.Sort(addrs))
+	}
+}
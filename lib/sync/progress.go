@@ -0,0 +1,110 @@
+package sync
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/storage"
+)
+
+// syncTarget is the highest block, which is requested to sync; it is stored,
+// so the sync continues after restart.
+type syncTarget struct {
+	Height    uint64   `json:"height"`
+	NodeAddrs []string `json:"node_addrs"`
+}
+
+func getSyncTargetKey() string {
+	return common.SyncPrefixTarget
+}
+
+func getSyncHeaderKey(height uint64) string {
+	return fmt.Sprintf("%s%020d", common.SyncPrefixHeader, height)
+}
+
+func saveSyncTarget(st *storage.LevelDBBackend, height uint64, nodeAddrs []string) (err error) {
+	key := getSyncTargetKey()
+	target := syncTarget{Height: height, NodeAddrs: nodeAddrs}
+
+	var exists bool
+	if exists, err = st.Has(key); err != nil {
+		return
+	}
+
+	if exists {
+		return st.Set(key, target)
+	}
+
+	return st.New(key, target)
+}
+
+func getSyncTarget(st *storage.LevelDBBackend) (target syncTarget, err error) {
+	err = st.Get(getSyncTargetKey(), &target)
+	return
+}
+
+// saveSyncHeaders stores the fetched headers; the stored headers are not
+// fetched again.
+func saveSyncHeaders(st *storage.LevelDBBackend, headers []SyncHeader) (err error) {
+	if len(headers) < 1 {
+		return
+	}
+
+	var bs *storage.LevelDBBackend
+	if bs, err = st.OpenBatch(); err != nil {
+		return
+	}
+
+	for _, h := range headers {
+		if err = bs.Core.Put([]byte(getSyncHeaderKey(h.Block.Height)), common.MustMarshalJSON(h), nil); err != nil {
+			bs.Discard()
+			return
+		}
+	}
+
+	if err = bs.Commit(); err != nil {
+		bs.Discard()
+	}
+
+	return
+}
+
+func getSyncHeader(st *storage.LevelDBBackend, height uint64) (header SyncHeader, err error) {
+	err = st.Get(getSyncHeaderKey(height), &header)
+	return
+}
+
+// removeSyncHeaders removes the stored headers from `from` to `to`.
+func removeSyncHeaders(st *storage.LevelDBBackend, from, to uint64) (err error) {
+	var keys []string
+
+	iterFunc, closeFunc := st.GetIterator(common.SyncPrefixHeader, nil)
+	for {
+		item, hasNext := iterFunc()
+		if !hasNext {
+			break
+		}
+
+		key := string(item.Key)
+		var height uint64
+		if height, err = strconv.ParseUint(strings.TrimPrefix(key, common.SyncPrefixHeader), 10, 64); err != nil {
+			closeFunc()
+			return
+		}
+		if height >= from && height <= to {
+			keys = append(keys, key)
+		}
+	}
+	closeFunc()
+
+	for _, key := range keys {
+		if err = st.Remove(key); err != nil && err != errors.StorageRecordDoesNotExist {
+			return
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,534 @@
+package sync
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	ethtrie "github.com/ethereum/go-ethereum/trie"
+	"github.com/inconshreveable/log15"
+
+	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/consensus"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/network"
+	"boscoin.io/sebak/lib/node"
+	"boscoin.io/sebak/lib/node/runner"
+	api "boscoin.io/sebak/lib/node/runner/node_api"
+	"boscoin.io/sebak/lib/storage"
+	"boscoin.io/sebak/lib/storage/statedb/trie"
+	"boscoin.io/sebak/lib/transaction"
+)
+
+// emptyStateRoot is the root hash of the empty trie.
+var emptyStateRoot = crypto.Keccak256Hash([]byte{0x80})
+
+// StateSyncer downloads the `block.StateSnapshot`, which the enough
+// validators signed, instead of the all the blocks from genesis. The state
+// trie is downloaded from the state root of the snapshot block, and the other
+// records of state are downloaded by the chunks. After the snapshot block is
+// stored, the `Syncer` continues from the next block.
+//
+// NOTE The blocks and transactions before the snapshot block are not stored.
+type StateSyncer struct {
+	storage   *storage.LevelDBBackend
+	localNode *node.LocalNode
+	apiClient Doer
+	fetcher   *BlockFetcher
+	validator *BlockValidator
+	commonCfg common.Config
+	threshold int // percent of validators, which must sign the same snapshot
+
+	logger log15.Logger
+}
+
+type StateSyncerOption = func(s *StateSyncer)
+
+func NewStateSyncer(
+	fetcher *BlockFetcher,
+	validator *BlockValidator,
+	client Doer,
+	st *storage.LevelDBBackend,
+	localNode *node.LocalNode,
+	cfg common.Config,
+	opts ...StateSyncerOption) *StateSyncer {
+
+	s := &StateSyncer{
+		storage:   st,
+		localNode: localNode,
+		apiClient: client,
+		fetcher:   fetcher,
+		validator: validator,
+		commonCfg: cfg,
+		threshold: VotingThreshold,
+		logger:    common.NopLogger(),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Sync downloads the latest snapshot and stores it; it returns the block of
+// snapshot. The storage must have only the genesis block.
+func (s *StateSyncer) Sync(ctx context.Context) (*block.Block, error) {
+	if latest := block.GetLatestBlock(s.storage); latest.Height > common.GenesisBlockHeight {
+		s.logger.Info("blocks already exist; state sync is skipped", "height", latest.Height)
+		return nil, nil
+	}
+
+	snapshot, sources, err := s.findSnapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Info("state snapshot found", "height", snapshot.B.Height, "block", snapshot.B.Block, "sources", sources)
+
+	chunks, err := s.fetchChunks(ctx, snapshot, sources)
+	if err != nil {
+		return nil, err
+	}
+
+	si, err := s.fetchBlock(ctx, snapshot, sources)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.validate(ctx, snapshot, chunks, si); err != nil {
+		return nil, err
+	}
+
+	bs, err := s.storage.OpenBatch()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.save(ctx, bs, snapshot, chunks, si, sources); err != nil {
+		bs.Discard()
+		return nil, err
+	}
+
+	if err := bs.Commit(); err != nil {
+		bs.Discard()
+		return nil, err
+	}
+
+	s.logger.Info("state snapshot stored", "height", snapshot.B.Height, "block", snapshot.B.Block)
+
+	return si.Block, nil
+}
+
+// findSnapshot returns the highest snapshot, which is signed by the enough
+// validators, and the validators, which signed it.
+func (s *StateSyncer) findSnapshot(ctx context.Context) (*block.StateSnapshot, []string, error) {
+	validators := s.localNode.GetValidators()
+
+	policy, err := consensus.NewDefaultVotingThresholdPolicy(s.threshold)
+	if err != nil {
+		return nil, nil, err
+	}
+	policy.SetValidators(len(validators))
+
+	latest := map[string]*block.StateSnapshot{}
+	var heights []uint64
+	for address, v := range validators {
+		if address == s.localNode.Address() {
+			continue
+		}
+
+		snapshot, err := s.fetchSnapshot(ctx, v, 0)
+		if err != nil {
+			s.logger.Debug("failed to fetch state snapshot", "node", address, "err", err)
+			continue
+		}
+		latest[address] = snapshot
+		heights = append(heights, snapshot.B.Height)
+	}
+
+	// the validators can take the next snapshot at the different time, so the
+	// lower heights are also tried.
+	sort.Slice(heights, func(i, j int) bool { return heights[i] > heights[j] })
+	for i, height := range heights {
+		if i > 0 && heights[i-1] == height {
+			continue
+		}
+
+		signed := map[string][]string{}
+		snapshots := map[string]*block.StateSnapshot{}
+		for address, snapshot := range latest {
+			if snapshot.B.Height < height {
+				continue
+			} else if snapshot.B.Height > height {
+				if snapshot, err = s.fetchSnapshot(ctx, validators[address], height); err != nil {
+					continue
+				}
+			}
+
+			signed[snapshot.H.Hash] = append(signed[snapshot.H.Hash], address)
+			snapshots[snapshot.H.Hash] = snapshot
+		}
+
+		for hash, sources := range signed {
+			if len(sources) >= policy.Threshold() {
+				sort.Strings(sources)
+				return snapshots[hash], sources, nil
+			}
+		}
+	}
+
+	return nil, nil, errors.StateSnapshotNotFound
+}
+
+// fetchSnapshot fetches the snapshot of validator at `height`; with 0 height,
+// the latest one is fetched.
+func (s *StateSyncer) fetchSnapshot(ctx context.Context, v *node.Validator, height uint64) (*block.StateSnapshot, error) {
+	u := stateSnapshotURL(v, runner.GetStateSnapshotPattern)
+	if height > 0 {
+		q := u.Query()
+		q.Set("height", strconv.FormatUint(height, 10))
+		u.RawQuery = q.Encode()
+	}
+
+	items, err := s.request(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	found, ok := items[api.NodeItemStateSnapshot]
+	if !ok || len(found) != 1 {
+		return nil, errors.StateSnapshotNotFound
+	}
+
+	snapshot := found[0].(block.StateSnapshot)
+	if snapshot.H.Source != v.Address() || (height > 0 && snapshot.B.Height != height) {
+		return nil, errors.StateSnapshotInvalid
+	}
+	if err := snapshot.Verify(s.commonCfg.NetworkID); err != nil {
+		return nil, err
+	}
+
+	return &snapshot, nil
+}
+
+// fetchChunks fetches the chunks of snapshot from the validators, which
+// signed it, by turns.
+func (s *StateSyncer) fetchChunks(ctx context.Context, snapshot *block.StateSnapshot, sources []string) ([]block.StateSnapshotChunk, error) {
+	var chunks []block.StateSnapshotChunk
+	for index, hash := range snapshot.B.Chunks {
+		var chunk block.StateSnapshotChunk
+		err := s.tryValidators(sources, index, func(v *node.Validator) (err error) {
+			u := stateSnapshotURL(v, runner.GetStateSnapshotChunkPattern)
+			q := u.Query()
+			q.Set("height", strconv.FormatUint(snapshot.B.Height, 10))
+			q.Set("index", strconv.Itoa(index))
+			u.RawQuery = q.Encode()
+
+			var items map[api.NodeItemDataType][]interface{}
+			if items, err = s.request(ctx, "GET", u, nil); err != nil {
+				return
+			}
+
+			found, ok := items[api.NodeItemStateChunk]
+			if !ok || len(found) != 1 {
+				return errors.StateSnapshotNotFound
+			}
+			chunk = found[0].(block.StateSnapshotChunk)
+			if chunk.MakeHashString() != hash {
+				return errors.StateSnapshotInvalid
+			}
+
+			return
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		chunks = append(chunks, chunk)
+	}
+
+	return chunks, nil
+}
+
+// fetchBlock fetches the block of snapshot with it's transactions and
+// certificate.
+func (s *StateSyncer) fetchBlock(ctx context.Context, snapshot *block.StateSnapshot, sources []string) (*SyncInfo, error) {
+	si := &SyncInfo{Height: snapshot.B.Height}
+	err := s.tryValidators(sources, 0, func(v *node.Validator) error {
+		return s.fetcher.fetchFromNode(ctx, v, si)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return si, nil
+}
+
+// validate checks the block of `SyncInfo` is the block of snapshot and it is
+// confirmed by the validators of the snapshot.
+func (s *StateSyncer) validate(ctx context.Context, snapshot *block.StateSnapshot, chunks []block.StateSnapshotChunk, si *SyncInfo) error {
+	if si.Block.Hash != snapshot.B.Block || si.Block.StateRoot != snapshot.B.StateRoot {
+		return errors.StateSnapshotInvalid
+	}
+	if si.Certificate == nil {
+		return errors.CertificateNotFound
+	}
+
+	for _, bt := range si.Bts {
+		tx := bt.Transaction()
+		if tx.B.MakeHashString() != tx.H.Hash {
+			return errors.HashDoesNotMatch
+		}
+	}
+
+	// the previous block is not stored, so the hash of it comes from the
+	// certificate; it is checked by the hash of block.
+	prevBlk := &block.Block{
+		Header: block.Header{Height: si.Height - 1},
+		Hash:   si.Certificate.Proposed().VotingBasis.BlockHash,
+	}
+	if err := s.validator.validateBlock(ctx, si, prevBlk); err != nil {
+		return err
+	}
+
+	vs, err := s.validatorSet(chunks, si.Height)
+	if err != nil {
+		return err
+	}
+
+	return s.validator.verifyCertificate(si, prevBlk, vs)
+}
+
+// validatorSet returns the `ValidatorSet` at `height` from the validator set
+// changes in the chunks.
+func (s *StateSyncer) validatorSet(chunks []block.StateSnapshotChunk, height uint64) (vs block.ValidatorSet, err error) {
+	// the initial validator set is stored by `runner.NodeRunner`, which is
+	// not created yet.
+	if vs, err = block.GetInitialValidatorSet(s.storage); err == errors.StorageRecordDoesNotExist {
+		vs, err = runner.InitialValidatorSet(s.localNode), nil
+	} else if err != nil {
+		return
+	}
+
+	for _, chunk := range chunks {
+		for _, r := range chunk {
+			if !strings.HasPrefix(string(r.Key), common.ValidatorSetChangePrefixHeight) {
+				continue
+			}
+
+			var c block.ValidatorSetChange
+			if err = json.Unmarshal(r.Value, &c); err != nil {
+				return
+			}
+			if c.Height > height {
+				return
+			}
+			vs = vs.Apply(&c)
+		}
+	}
+
+	return
+}
+
+// isStateSnapshotRecord checks the key of record is one of
+// `runner.StateSnapshotRecordPrefixes`; the other records must not be
+// overwritten by the snapshot.
+func isStateSnapshotRecord(key []byte) bool {
+	for _, prefix := range runner.StateSnapshotRecordPrefixes {
+		if strings.HasPrefix(string(key), prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// save stores the records of chunks, the state trie and the block of
+// snapshot.
+func (s *StateSyncer) save(ctx context.Context, bs *storage.LevelDBBackend, snapshot *block.StateSnapshot, chunks []block.StateSnapshotChunk, si *SyncInfo, sources []string) error {
+	for _, chunk := range chunks {
+		for _, r := range chunk {
+			if !isStateSnapshotRecord(r.Key) {
+				s.logger.Error("unknown record in state snapshot", "key", string(r.Key))
+				return errors.StateSnapshotInvalid
+			}
+		}
+	}
+
+	for _, chunk := range chunks {
+		for _, r := range chunk {
+			if err := bs.Core.Put(r.Key, r.Value, nil); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := s.syncStateTrie(ctx, bs, snapshot, sources); err != nil {
+		return err
+	}
+
+	blk := *si.Block
+	if err := blk.Save(bs); err != nil {
+		return err
+	}
+
+	var txs []*transaction.Transaction
+	for _, bt := range si.Bts {
+		tx := bt.Transaction()
+		txs = append(txs, &tx)
+	}
+	if err := runner.FinishTransactions(blk, txs, bs); err != nil {
+		return err
+	}
+
+	if err := saveSyncInfo(bs, blk, si); err != nil {
+		return err
+	}
+
+	return runner.SetBlockOperationsCheckedHeight(bs, blk.Height)
+}
+
+// syncStateTrie downloads the nodes of state trie, which are not in storage;
+// the accounts of trie are also stored.
+func (s *StateSyncer) syncStateTrie(ctx context.Context, bs *storage.LevelDBBackend, snapshot *block.StateSnapshot, sources []string) error {
+	db := trie.NewEthDatabase(bs)
+
+	var sched *ethtrie.Sync
+	sched = ethtrie.NewSync(snapshot.B.StateRoot, db, func(leaf []byte, parent ethcommon.Hash) error {
+		var ba block.BlockAccount
+		if err := json.Unmarshal(leaf, &ba); err != nil {
+			return err
+		}
+		if ba.RootHash != (common.Hash{}) && ba.RootHash != emptyStateRoot {
+			sched.AddSubTrie(ba.RootHash, 64, parent, nil)
+		}
+
+		return ba.Save(bs)
+	})
+
+	var n int
+	for i := 0; sched.Pending() > 0; i++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		hashes := sched.Missing(runner.MaxStateTrieNodesRequest)
+		if len(hashes) < 1 {
+			break
+		}
+
+		var results []ethtrie.SyncResult
+		err := s.tryValidators(sources, i, func(v *node.Validator) (err error) {
+			results, err = s.fetchTrieNodes(ctx, v, snapshot.B.Height, hashes)
+			return
+		})
+		if err != nil {
+			return err
+		}
+
+		if _, _, err = sched.Process(results); err != nil {
+			return err
+		}
+		if _, err = sched.Commit(db); err != nil {
+			return err
+		}
+		n += len(results)
+	}
+
+	s.logger.Debug("state trie downloaded", "root", snapshot.B.StateRoot.Hex(), "nodes", n)
+
+	return nil
+}
+
+func (s *StateSyncer) fetchTrieNodes(ctx context.Context, v *node.Validator, height uint64, hashes []common.Hash) ([]ethtrie.SyncResult, error) {
+	body := common.MustMarshalJSON(runner.StateTrieNodesRequest{Height: height, Hashes: hashes})
+
+	items, err := s.request(ctx, "POST", stateSnapshotURL(v, runner.GetStateTrieNodesPattern), body)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := items[api.NodeItemStateTrieNode]
+	if len(nodes) != len(hashes) {
+		return nil, errors.StateSnapshotInvalid
+	}
+
+	var results []ethtrie.SyncResult
+	for i, n := range nodes {
+		data := n.([]byte)
+		if crypto.Keccak256Hash(data) != hashes[i] {
+			return nil, errors.StateSnapshotInvalid
+		}
+		results = append(results, ethtrie.SyncResult{Hash: hashes[i], Data: data})
+	}
+
+	return results, nil
+}
+
+// tryValidators calls `f` with the validators from `start`-th one by turns
+// until it succeeds.
+func (s *StateSyncer) tryValidators(addresses []string, start int, f func(*node.Validator) error) (err error) {
+	for i := range addresses {
+		address := addresses[(start+i)%len(addresses)]
+		v := s.localNode.Validator(address)
+		if v == nil {
+			continue
+		}
+
+		if err = f(v); err == nil {
+			return
+		}
+		s.logger.Debug("failed to fetch from validator", "node", address, "err", err)
+	}
+
+	if err == nil {
+		err = errors.NodeNotFound
+	}
+
+	return
+}
+
+func (s *StateSyncer) request(ctx context.Context, method string, u *url.URL, body []byte) (map[api.NodeItemDataType][]interface{}, error) {
+	req, err := http.NewRequest(method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req = req.WithContext(ctx)
+
+	resp, err := s.apiClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errors.StateSnapshotNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New(fmt.Sprintf("state sync: unexpected status code: %d", resp.StatusCode))
+	}
+
+	return s.fetcher.unmarshalResp(resp.Body)
+}
+
+func stateSnapshotURL(n node.Node, pattern string) *url.URL {
+	ep := n.Endpoint()
+	u := url.URL(*ep)
+	u.Path = network.UrlPathPrefixNode + pattern
+
+	return &u
+}
@@ -0,0 +1,178 @@
+package sync
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/network"
+	"boscoin.io/sebak/lib/node"
+	"boscoin.io/sebak/lib/node/runner"
+	"boscoin.io/sebak/lib/storage/statedb"
+	"boscoin.io/sebak/lib/storage/statedb/trie"
+	"boscoin.io/sebak/lib/transaction"
+)
+
+// stateSyncTest runs the simulated validators, which take the state snapshot
+// at every 2 blocks, and the fresh node, which requests to them.
+func stateSyncTest(t *testing.T, height uint64) (*runner.Simulator, *StateSyncer, *BlockFetcher, *BlockValidator) {
+	conf := common.NewTestConfig()
+	conf.StateSnapshotInterval = 2
+
+	s := runner.NewSimulator(4, 1, conf)
+	s.Start()
+	reached := s.RunUntil(func() bool {
+		for _, n := range s.Nodes() {
+			if n.Consensus().LatestBlock().Height < height {
+				return false
+			}
+		}
+		return true
+	}, time.Minute)
+	require.True(t, reached)
+
+	handlers := map[string]*runner.NetworkHandlerNode{}
+	for _, n := range s.Nodes() {
+		h := runner.NewNetworkHandlerNode(n.Node(), n.Network(), n.Storage(), n.Consensus(), transaction.NewPool(conf), network.UrlPathPrefixNode, conf)
+		h.SetStateSnapshotter(n.StateSnapshotter())
+		handlers[n.Node().Endpoint().Host] = h
+	}
+
+	client := mockDoer{
+		handleFunc: func(req *http.Request) (*http.Response, error) {
+			w := httptest.NewRecorder()
+			h, found := handlers[req.URL.Host]
+			if !found {
+				return nil, errors.NodeNotFound
+			}
+
+			switch req.URL.Path {
+			case network.UrlPathPrefixNode + runner.GetBlocksPattern:
+				h.GetBlocksHandler(w, req)
+			case network.UrlPathPrefixNode + runner.GetStateSnapshotPattern:
+				h.GetStateSnapshotHandler(w, req)
+			case network.UrlPathPrefixNode + runner.GetStateSnapshotChunkPattern:
+				h.GetStateSnapshotChunkHandler(w, req)
+			case network.UrlPathPrefixNode + runner.GetStateTrieNodesPattern:
+				h.GetStateTrieNodesHandler(w, req)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+
+			return w.Result(), nil
+		},
+	}
+
+	st := block.InitTestBlockchain()
+	localNode := node.NewTestLocalNode(keypair.Random(), network.CreateNewMemoryEndpoint())
+	var validators []*node.Validator
+	var addrs []string
+	for _, n := range s.Nodes() {
+		validators = append(validators, n.Node().ConvertToValidator())
+		addrs = append(addrs, n.Node().Address())
+	}
+	localNode.SetValidators(validators...)
+
+	f := NewBlockFetcher(&mockConnectionManager{allConnected: addrs}, client, st, localNode)
+	v := NewBlockValidator(st, transaction.NewPool(conf), conf)
+
+	return s, NewStateSyncer(f, v, client, st, localNode, conf), f, v
+}
+
+func TestStateSyncer(t *testing.T) {
+	s, syncer, f, v := stateSyncTest(t, 5)
+	defer syncer.storage.Close()
+
+	blk, err := syncer.Sync(context.Background())
+	require.NoError(t, err)
+	require.NotNil(t, blk)
+	require.True(t, blk.Height >= 4)
+
+	synced := s.Node(0)
+	expected, err := block.GetBlockByHeight(synced.Storage(), blk.Height)
+	require.NoError(t, err)
+	require.Equal(t, expected.Hash, blk.Hash)
+	require.Equal(t, expected.Hash, block.GetLatestBlock(syncer.storage).Hash)
+
+	{ // the accounts in the state trie are stored
+		sdb := statedb.New(blk.StateRoot, trie.NewEthDatabase(synced.Storage()))
+		expectedAccount, err := sdb.GetBlockAccount(block.CommonKP.Address())
+		require.NoError(t, err)
+		account, err := block.GetBlockAccount(syncer.storage, block.CommonKP.Address())
+		require.NoError(t, err)
+		require.Equal(t, expectedAccount.Balance, account.Balance)
+		require.Equal(t, expectedAccount.SequenceID, account.SequenceID)
+	}
+
+	{ // the next block can be validated and stored on the snapshot
+		// the initial validator set is stored by `runner.NodeRunner`
+		require.NoError(t, block.SaveInitialValidatorSet(syncer.storage, runner.InitialValidatorSet(syncer.localNode)))
+
+		si := &SyncInfo{Height: blk.Height + 1}
+		require.NoError(t, f.fetchFromNode(context.Background(), synced.Node(), si))
+		require.NoError(t, v.Validate(context.Background(), si))
+		require.Equal(t, si.Block.Hash, block.GetLatestBlock(syncer.storage).Hash)
+
+		// the header of block made by consensus is verified
+		headers, err := f.FetchHeaders(context.Background(), *blk, si.Height, nil)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(headers))
+		require.Equal(t, si.Block.Hash, headers[0].Block.Hash)
+	}
+
+	{ // the blocks already exist
+		blk, err := syncer.Sync(context.Background())
+		require.NoError(t, err)
+		require.Nil(t, blk)
+	}
+}
+
+func TestStateSyncerNotEnoughSnapshots(t *testing.T) {
+	s, syncer, _, _ := stateSyncTest(t, 3)
+	defer syncer.storage.Close()
+
+	// only one validator serves the snapshot
+	for _, n := range s.Nodes()[1:] {
+		n.StateSnapshotter().Close()
+	}
+
+	_, err := syncer.Sync(context.Background())
+	require.Equal(t, errors.StateSnapshotNotFound, err)
+	require.Equal(t, common.GenesisBlockHeight, block.GetLatestBlock(syncer.storage).Height)
+}
+
+// TestStateSyncerUnknownRecord checks the records of chunk, which are not the
+// state of snapshot, are not stored.
+func TestStateSyncerUnknownRecord(t *testing.T) {
+	conf := common.NewTestConfig()
+	st := block.InitTestBlockchain()
+	defer st.Close()
+
+	localNode := node.NewTestLocalNode(keypair.Random(), network.CreateNewMemoryEndpoint())
+	syncer := NewStateSyncer(nil, nil, nil, st, localNode, conf)
+
+	genesis := block.GetGenesis(st)
+	key := []byte(common.BlockPrefixHash + genesis.Hash)
+	chunks := []block.StateSnapshotChunk{
+		{{Key: key, Value: []byte("{}")}},
+	}
+
+	bs, err := st.OpenBatch()
+	require.NoError(t, err)
+	defer bs.Discard()
+
+	err = syncer.save(context.Background(), bs, &block.StateSnapshot{}, chunks, &SyncInfo{}, nil)
+	require.Equal(t, errors.StateSnapshotInvalid, err)
+
+	stored, err := block.GetBlock(bs, genesis.Hash)
+	require.NoError(t, err)
+	require.Equal(t, genesis.Hash, stored.Hash)
+}
@@ -2,12 +2,15 @@ package sync
 
 import (
 	"context"
+	"math"
 	"strconv"
+	"sync"
 	"time"
 
 	"boscoin.io/sebak/lib/block"
 	"boscoin.io/sebak/lib/common"
 	"boscoin.io/sebak/lib/common/observer"
+	"boscoin.io/sebak/lib/errors"
 	"boscoin.io/sebak/lib/metrics"
 	"boscoin.io/sebak/lib/storage"
 	"github.com/inconshreveable/log15"
@@ -27,7 +30,12 @@ type Syncer struct {
 	nodelist *NodeList
 
 	poolSize      uint64
+	batchSize     uint64 // number of blocks in one work
 	checkInterval time.Duration
+	retryInterval time.Duration
+
+	headerMu     sync.Mutex
+	headerHeight uint64 // height of the last fetched header
 
 	afterFunc  AfterFunc
 	workPool   *Pool
@@ -56,7 +64,9 @@ func NewSyncer(
 		storage:   st,
 
 		poolSize:      SyncPoolSize,
+		batchSize:     SyncBatchSize,
 		checkInterval: CheckBlockHeightInterval,
+		retryInterval: RetryInterval,
 
 		afterFunc: time.After,
 
@@ -94,6 +104,7 @@ func (s *Syncer) Stop() error {
 func (s *Syncer) Start() error {
 	s.logger.Info("starting syncer")
 	s.workPool = NewPool(s.poolSize)
+	go s.resume()
 	s.loop()
 	return nil
 }
@@ -161,6 +172,9 @@ func (s *Syncer) loop() {
 			s.logger.Info("updated highest height", "height", height, "nodes", nodeAddrs)
 			s.nodelist.SetLatestNodeAddrs(nodeAddrs)
 			if height > syncProgress.CurrentBlock {
+				if err := saveSyncTarget(s.storage, height, nodeAddrs); err != nil {
+					s.logger.Error("failed to save sync target", "err", err, "height", height)
+				}
 				syncProgress.HighestBlock = height
 				s.sync(syncProgress)
 			}
@@ -206,13 +220,18 @@ func (s *Syncer) sync(p *SyncProgress) {
 		return
 	}
 
-	for height := startHeight; height <= highestHeight; height++ {
-		s.logger.Debug("work height", "height", height)
+	for from := startHeight; from <= highestHeight; from += s.batchSize {
+		to := from + s.batchSize - 1
+		if to > highestHeight {
+			to = highestHeight
+		}
+
+		s.logger.Debug("work range", "from", from, "to", to)
 		// TryAdd for unblocking when the pool is full. Just keep syncprogress for next sync
-		if s.work(height) == false {
+		if s.work(from, to) == false {
 			break
 		}
-		currentHeight = height
+		currentHeight = to
 	}
 	p.StartingBlock = startHeight
 	p.CurrentBlock = currentHeight
@@ -221,69 +240,177 @@ func (s *Syncer) sync(p *SyncProgress) {
 		"start", p.StartingBlock, "cur", p.CurrentBlock, "high", p.HighestBlock)
 }
 
-func (s *Syncer) work(height uint64) bool {
-	defer func(begin time.Time) { metrics.Sync.ObserveDurationSeconds(begin, "") }(time.Now())
+// work syncs the blocks from `from` to `to`; the ranges are synced in
+// parallel, but the blocks are stored in order, because
+// `Validator.Validate` waits the previous block.
+func (s *Syncer) work(from, to uint64) bool {
 	ctx := s.ctx
 	work := func() {
-		s.logger.Debug("start work", "height", height, "nodes", s.nodelist.NodeAddrs())
+		defer func(begin time.Time) { metrics.Sync.ObserveDurationSeconds(begin, "") }(time.Now())
+		s.logger.Debug("start work", "from", from, "to", to, "nodes", s.nodelist.NodeAddrs())
 
-		latestHeight := s.latestBlockHeight()
-		if latestHeight > 0 && height <= latestHeight {
-			s.logger.Info("this height has already synced", "height", height)
-			return
-		}
+		for {
+			if latestHeight := s.latestBlockHeight(); latestHeight >= from {
+				from = latestHeight + 1
+			}
+			if from > to {
+				s.logger.Info("this range has already synced", "to", to)
+				return
+			}
 
-		var (
-			syncInfo = &SyncInfo{
-				Height:   height,
-				NodeList: s.nodelist,
+			err := s.syncRange(ctx, from, to)
+			if err == nil {
+				s.logger.Info("done sync work", "from", from, "to", to)
+				return
+			}
+			if ctx.Err() != nil {
+				s.logger.Debug("stop sync work", "from", from, "to", to, "err", err)
+				return
 			}
-			err error
-		)
 
-	L:
-		for {
+			s.logger.Error("sync failure", "from", from, "to", to, "err", err)
 			select {
 			case <-ctx.Done():
-				break L
-			default:
-				begin := time.Now()
-				syncInfo, err = s.fetcher.Fetch(ctx, syncInfo)
-				if err != nil {
-					if err == context.Canceled {
-						break L
-					}
-					s.logger.Error("fetch failure", "err", err, "height", height)
-					continue
-				}
-				metrics.Sync.ObserveDurationSeconds(begin, metrics.SyncFetcher)
-				begin = time.Now()
-				err = s.validator.Validate(ctx, syncInfo)
-				if err != nil {
-					if err == context.Canceled {
-						break L
-					}
-					s.logger.Error("validate failure", "err", err, "height", height)
-					metrics.Sync.AddValidateError()
-					continue
+				return
+			case <-time.After(s.retryInterval):
+			}
+		}
+	}
+	return s.workPool.TryAdd(ctx, work)
+}
+
+// syncRange fetches the blocks of the headers and validates them; if the
+// block is invalid, the nodes, which sent the header and the block, are
+// reported and the headers from the block are fetched again.
+func (s *Syncer) syncRange(ctx context.Context, from, to uint64) error {
+	headers, err := s.headers(ctx, from, to)
+	if err != nil {
+		return err
+	}
+
+	begin := time.Now()
+	infos, err := s.fetcher.Fetch(ctx, headers, s.nodelist.NodeAddrs())
+	if err != nil {
+		return err
+	}
+	metrics.Sync.ObserveDurationSeconds(begin, metrics.SyncFetcher)
+
+	for i, si := range infos {
+		si.NodeList = s.nodelist
+
+		begin = time.Now()
+		if err := s.validator.Validate(ctx, si); err != nil {
+			if ctx.Err() == nil {
+				s.logger.Error("validate failure", "err", err, "height", si.Height, "source", si.Source)
+				metrics.Sync.AddValidateError()
+				s.fetcher.Invalid(si.Source)
+				if headers[i].Source != si.Source {
+					s.fetcher.Invalid(headers[i].Source)
 				}
-				metrics.Sync.ObserveDurationSeconds(begin, metrics.SyncValidator)
-				break L
+				s.resetHeaders(si.Height)
 			}
+			return err
 		}
-		if err != nil {
-			if err != context.Canceled {
-				s.logger.Error("stop sync work", "height", height, "err", err)
-			} else {
-				s.logger.Debug("stop sync work", "height", height, "err", err)
+		metrics.Sync.ObserveDurationSeconds(begin, metrics.SyncValidator)
+		metrics.Sync.SetHeight(si.Height)
+
+		if err := s.storage.Remove(getSyncHeaderKey(si.Height)); err != nil && err != errors.StorageRecordDoesNotExist {
+			s.logger.Error("failed to remove header", "err", err, "height", si.Height)
+		}
+	}
+
+	return nil
+}
+
+// headers returns the headers from `from` to `to`; the missing headers are
+// fetched and stored.
+func (s *Syncer) headers(ctx context.Context, from, to uint64) ([]SyncHeader, error) {
+	s.headerMu.Lock()
+	defer s.headerMu.Unlock()
+
+	if s.headerHeight < to {
+		latest := block.GetLatestBlock(s.storage)
+		prev := latest
+		if s.headerHeight > latest.Height {
+			header, err := getSyncHeader(s.storage, s.headerHeight)
+			if err != nil {
+				return nil, err
 			}
-		} else {
-			s.logger.Info("done sync work", "height", height, "hash", syncInfo.Block.Hash)
-			metrics.Sync.SetHeight(height)
+			prev = header.Block
+		}
+
+		// the headers, which are stored before restart, are not fetched again
+		for prev.Height < to {
+			header, err := getSyncHeader(s.storage, prev.Height+1)
+			if err != nil || header.Block.PrevBlockHash != prev.Hash {
+				break
+			}
+			prev = header.Block
+		}
+		s.headerHeight = prev.Height
+
+		if prev.Height < to {
+			begin := time.Now()
+			fetched, err := s.fetcher.FetchHeaders(ctx, prev, to, s.nodelist.NodeAddrs())
+			if err != nil {
+				return nil, err
+			}
+			if err := saveSyncHeaders(s.storage, fetched); err != nil {
+				return nil, err
+			}
+			s.headerHeight = to
+			metrics.Sync.ObserveDurationSeconds(begin, metrics.SyncHeader)
 		}
-		s.logger.Debug("end work", "height", height)
 	}
-	return s.workPool.TryAdd(ctx, work)
+
+	var headers []SyncHeader
+	for height := from; height <= to; height++ {
+		header, err := getSyncHeader(s.storage, height)
+		if err != nil {
+			return nil, err
+		}
+		headers = append(headers, header)
+	}
+
+	return headers, nil
+}
+
+// resetHeaders removes the stored headers from `height`, so they are fetched
+// again.
+func (s *Syncer) resetHeaders(height uint64) {
+	s.headerMu.Lock()
+	defer s.headerMu.Unlock()
+
+	if err := removeSyncHeaders(s.storage, height, math.MaxUint64); err != nil {
+		s.logger.Error("failed to remove headers", "err", err, "height", height)
+	}
+	if s.headerHeight >= height {
+		s.headerHeight = height - 1
+	}
+}
+
+// resume continues the sync, which is not finished before restart.
+func (s *Syncer) resume() {
+	latestHeight := s.latestBlockHeight()
+	if err := removeSyncHeaders(s.storage, 0, latestHeight); err != nil {
+		s.logger.Error("failed to remove synced headers", "err", err)
+	}
+
+	target, err := getSyncTarget(s.storage)
+	if err != nil {
+		if err != errors.StorageRecordDoesNotExist {
+			s.logger.Error("failed to get sync target", "err", err)
+		}
+		return
+	}
+	if target.Height <= latestHeight {
+		return
+	}
+
+	s.logger.Info("resume sync", "height", target.Height, "nodes", target.NodeAddrs)
+	if err := s.SetSyncTargetBlock(s.ctx, target.Height, target.NodeAddrs); err != nil {
+		s.logger.Debug("failed to resume sync", "err", err)
+	}
 }
 
 func (s *Syncer) latestBlockHeight() uint64 {
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/errors"
 	"boscoin.io/sebak/lib/storage"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -47,10 +48,11 @@ func TestSyncerSetSyncTarget(t *testing.T) {
 		syncer.SetSyncTargetBlock(ctx, height, nodeAddrs)
 
 		var heights []uint64
-		for si := range infoc {
-			heights = append(heights, si.Height)
-			if len(heights) >= 9 {
-				close(infoc)
+		for len(heights) < 9 {
+			select {
+			case si := <-infoc:
+				heights = append(heights, si.Height)
+			case tctx.tickC <- time.Now():
 			}
 		}
 		require.Equal(t, len(heights), 9)
@@ -65,6 +67,120 @@ func TestSyncerSetSyncTarget(t *testing.T) {
 	SyncerTest(t, fn)
 }
 
+func TestSyncerResume(t *testing.T) {
+	fn := func(tctx *SyncerTestContext) {
+		var (
+			ctx    = context.Background()
+			st     = tctx.st
+			syncer = tctx.syncer
+			infoc  = tctx.syncInfoC
+		)
+
+		// stored before restart
+		require.NoError(t, saveSyncTarget(st, 5, []string{"a"}))
+		require.NoError(t, saveSyncHeaders(st, []SyncHeader{{Block: block.GetLatestBlock(st)}}))
+
+		go func() {
+			syncer.Start()
+		}()
+
+		var heights []uint64
+		for len(heights) < 4 {
+			select {
+			case si := <-infoc:
+				heights = append(heights, si.Height)
+			case tctx.tickC <- time.Now():
+			}
+		}
+		require.Equal(t, []uint64{2, 3, 4, 5}, heights)
+		require.Equal(t, []string{"a"}, syncer.nodelist.NodeAddrs())
+
+		{ // the headers of the synced blocks are removed
+			_, err := getSyncHeader(st, 1)
+			require.Equal(t, errors.StorageRecordDoesNotExist, err)
+			_, err = getSyncHeader(st, 4)
+			require.Equal(t, errors.StorageRecordDoesNotExist, err)
+		}
+
+		progress, err := syncer.SyncProgress(ctx)
+		require.NoError(t, err)
+		require.Equal(t, uint64(5), progress.HighestBlock)
+	}
+	SyncerTest(t, fn)
+}
+
+func TestSyncerInvalidBlock(t *testing.T) {
+	fn := func(tctx *SyncerTestContext) {
+		var (
+			ctx    = context.Background()
+			st     = tctx.st
+			syncer = tctx.syncer
+			infoc  = tctx.syncInfoC
+		)
+
+		var invalids []string
+		fetcher := syncer.fetcher.(*mockFetcher)
+		headerFunc := fetcher.headerFunc
+		fetcher.headerFunc = func(ctx context.Context, prev block.Block, to uint64, nodeAddrs []string) ([]SyncHeader, error) {
+			headers, err := headerFunc(ctx, prev, to, nodeAddrs)
+			for i := range headers {
+				headers[i].Source = "header-node"
+			}
+			return headers, err
+		}
+		fetchFunc := fetcher.fetchFunc
+		fetcher.fetchFunc = func(ctx context.Context, headers []SyncHeader, nodeAddrs []string) ([]*SyncInfo, error) {
+			infos, err := fetchFunc(ctx, headers, nodeAddrs)
+			for _, si := range infos {
+				si.Source = "block-node"
+			}
+			return infos, err
+		}
+		fetcher.invalidFunc = func(addr string) {
+			invalids = append(invalids, addr)
+		}
+
+		failed := false
+		syncer.retryInterval = 0
+		syncer.validator = &mockValidator{
+			validateFunc: func(ctx context.Context, si *SyncInfo) error {
+				if si.Height == 3 && !failed {
+					failed = true
+					return errors.InvalidOperation
+				}
+				si.Block.MustSave(st)
+				infoc <- si
+				return nil
+			},
+		}
+
+		go func() {
+			syncer.Start()
+		}()
+
+		syncer.SetSyncTargetBlock(ctx, 5, []string{"a"})
+
+		var hashes []string
+		for len(hashes) < 4 {
+			select {
+			case si := <-infoc:
+				hashes = append(hashes, si.Block.Hash)
+			case tctx.tickC <- time.Now():
+			}
+		}
+
+		// the headers from the invalid block are fetched again
+		require.Equal(t, []string{"block-node", "header-node"}, invalids)
+		prev := block.GetLatestBlock(st)
+		require.Equal(t, uint64(5), prev.Height)
+		for i := len(hashes) - 1; i >= 0; i-- {
+			require.Equal(t, prev.Hash, hashes[i])
+			prev, _ = prev.PreviousBlock(st)
+		}
+	}
+	SyncerTest(t, fn)
+}
+
 func SyncerTest(t *testing.T, fn func(*SyncerTestContext)) {
 	st := block.InitTestBlockchain()
 	defer st.Close()
@@ -73,12 +189,21 @@ func SyncerTest(t *testing.T, fn func(*SyncerTestContext)) {
 	infoc := make(chan *SyncInfo)
 
 	fetcher := &mockFetcher{
-		fetchFunc: func(ctx context.Context, si *SyncInfo) (*SyncInfo, error) {
-			bk := block.TestMakeNewBlock([]string{})
-			bk.Height = si.Height
-			si.Height = bk.Height
-			si.Block = &bk
-			return si, nil
+		headerFunc: func(ctx context.Context, prev block.Block, to uint64, nodeAddrs []string) ([]SyncHeader, error) {
+			var headers []SyncHeader
+			for prev.Height < to {
+				prev = block.TestMakeNewBlockWithPrevBlock(prev, []string{})
+				headers = append(headers, SyncHeader{Block: prev})
+			}
+			return headers, nil
+		},
+		fetchFunc: func(ctx context.Context, headers []SyncHeader, nodeAddrs []string) ([]*SyncInfo, error) {
+			var infos []*SyncInfo
+			for _, h := range headers {
+				bk := h.Block
+				infos = append(infos, &SyncInfo{Height: bk.Height, Block: &bk})
+			}
+			return infos, nil
 		},
 	}
 	validator := &mockValidator{
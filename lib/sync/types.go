@@ -25,11 +25,24 @@ type SyncInfo struct {
 	Bts    []*block.BlockTransaction
 	Ptx    *ballot.ProposerTransaction
 
+	// Certificate is the ACCEPT ballots, which confirmed the block.
+	Certificate *ballot.Certificate
+
+	// Source is the address of node, which the block is fetched from.
+	Source string
+
 	// Fetching target node addresses, NodeList is  the validators which
 	// participated and confirmed the consensus of latest ballot.
 	NodeList *NodeList
 }
 
+// SyncHeader is the block without the bodies of transactions; the headers are
+// fetched before the blocks, and the blocks are checked against them.
+type SyncHeader struct {
+	Block  block.Block `json:"block"`
+	Source string      `json:"source"` // address of node, which the header is fetched from
+}
+
 func (s *SyncInfo) NodeAddrs() []string {
 	return s.NodeList.NodeAddrs()
 }
@@ -41,7 +54,14 @@ type Doer interface {
 type AfterFunc = func(time.Duration) <-chan time.Time
 
 type Fetcher interface {
-	Fetch(ctx context.Context, syncInfo *SyncInfo) (*SyncInfo, error)
+	// FetchHeaders fetches the headers of the next blocks of `prev` until
+	// `to`; the headers are linked to `prev`.
+	FetchHeaders(ctx context.Context, prev block.Block, to uint64, nodeAddrs []string) ([]SyncHeader, error)
+	// Fetch fetches the blocks of headers with their transactions and
+	// certificates; the `SyncInfo`s are returned by the order of headers.
+	Fetch(ctx context.Context, headers []SyncHeader, nodeAddrs []string) ([]*SyncInfo, error)
+	// Invalid reports the node sent the invalid data.
+	Invalid(nodeAddr string)
 }
 
 type Validator interface {
@@ -8,6 +8,7 @@ import (
 	"boscoin.io/sebak/lib/block"
 	"boscoin.io/sebak/lib/common"
 	"boscoin.io/sebak/lib/common/observer"
+	"boscoin.io/sebak/lib/consensus"
 	"boscoin.io/sebak/lib/errors"
 	"boscoin.io/sebak/lib/node/runner"
 	"boscoin.io/sebak/lib/storage"
@@ -23,6 +24,17 @@ type BlockValidator struct {
 	storage   *storage.LevelDBBackend
 	txpool    *transaction.Pool
 	commonCfg common.Config
+	threshold int // percent of validators, which must sign the certificate
+
+	// snapshotter takes the state snapshot of the synced block, if it is set
+	snapshotter *runner.StateSnapshotter
+
+	// updateValidators applies the validator set changes of the synced block
+	// to the node, if it is set
+	updateValidators func() error
+	// updateDelegated applies the delegated amounts of the synced block to
+	// the voting policy, if it is set
+	updateDelegated func() error
 
 	prevBlockWaitTimeout time.Duration // Waiting prev block if is doesn't exist
 	logger               log15.Logger
@@ -36,6 +48,7 @@ func NewBlockValidator(ldb *storage.LevelDBBackend, tp *transaction.Pool, cfg co
 		txpool:               tp,
 		prevBlockWaitTimeout: CheckPrevBlockInterval,
 		commonCfg:            cfg,
+		threshold:            VotingThreshold,
 
 		logger: common.NopLogger(),
 	}
@@ -89,6 +102,10 @@ func (v *BlockValidator) validate(ctx context.Context, syncInfo *SyncInfo) error
 		return err
 	}
 
+	if err := v.validateCertificate(ctx, syncInfo, prevBlk); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -109,6 +126,30 @@ func (v *BlockValidator) finishBlock(ctx context.Context, syncInfo *SyncInfo) er
 	}
 
 	blk := *syncInfo.Block
+
+	var txs []*transaction.Transaction
+	for _, bt := range syncInfo.Bts {
+		tx := bt.Transaction()
+		txs = append(txs, &tx)
+	}
+
+	prevBlk, err := block.GetBlockByHeight(bs, blk.Height-1)
+	if err != nil {
+		bs.Discard()
+		return err
+	}
+
+	sdb, stateRoot, err := runner.ApplyBlockState(bs, prevBlk, txs, *syncInfo.Ptx, v.logger)
+	if err != nil {
+		bs.Discard()
+		return err
+	}
+	if stateRoot != blk.StateRoot {
+		v.logger.Error("state root does not match", "height", blk.Height, "in-block", blk.StateRoot.Hex(), "computed", stateRoot.Hex())
+		bs.Discard()
+		return errors.StateRootDoesNotMatch
+	}
+
 	if err := blk.Save(bs); err != nil {
 		if err == errors.BlockAlreadyExists {
 			return nil
@@ -116,10 +157,9 @@ func (v *BlockValidator) finishBlock(ctx context.Context, syncInfo *SyncInfo) er
 		return err
 	}
 
-	var txs []*transaction.Transaction
-	for _, bt := range syncInfo.Bts {
-		tx := bt.Transaction()
-		txs = append(txs, &tx)
+	if err := sdb.CommitDB(stateRoot); err != nil {
+		bs.Discard()
+		return err
 	}
 
 	if err := runner.FinishTransactions(blk, txs, bs); err != nil {
@@ -127,40 +167,29 @@ func (v *BlockValidator) finishBlock(ctx context.Context, syncInfo *SyncInfo) er
 		return err
 	}
 
-	for _, bt := range syncInfo.Bts {
-		if err := bt.SaveBlockOperations(bs); err != nil {
-			bs.Discard()
-			return err
-		}
-		if _, err := block.SaveTransactionPool(bs, bt.Transaction()); err != nil {
-			bs.Discard()
-			return err
-		}
+	if err := runner.FinishCongressVoting(bs, blk, txs, v.logger); err != nil {
+		bs.Discard()
+		return err
 	}
 
-	// ProposerTx
-	{
-		ptx := syncInfo.Ptx
-		if err := runner.ProcessProposerTransaction(bs, blk, *ptx, v.logger); err != nil {
-			bs.Discard()
-			return err
-		}
+	if err := runner.FinishUnfreezing(bs, blk, txs, v.logger); err != nil {
+		bs.Discard()
+		return err
+	}
 
-		bt := block.NewBlockTransactionFromTransaction(blk.Hash, blk.Height, blk.ProposedTime, ptx.Transaction)
-		if err := bt.Save(bs); err != nil {
-			bs.Discard()
-			return err
-		}
+	if err := runner.FinishDelegation(bs, blk, txs, v.logger); err != nil {
+		bs.Discard()
+		return err
+	}
 
-		if _, err := block.SaveTransactionPool(bs, ptx.Transaction); err != nil {
-			bs.Discard()
-			return err
-		}
+	if err := runner.FinishValidatorSetChange(bs, blk, txs, v.logger); err != nil {
+		bs.Discard()
+		return err
+	}
 
-		if err := bt.SaveBlockOperations(bs); err != nil {
-			bs.Discard()
-			return err
-		}
+	if err := saveSyncInfo(bs, blk, syncInfo); err != nil {
+		bs.Discard()
+		return err
 	}
 
 	v.logger.Debug("finish to sync block height", "height", syncInfo.Height, "hash", blk.Hash)
@@ -170,9 +199,28 @@ func (v *BlockValidator) finishBlock(ctx context.Context, syncInfo *SyncInfo) er
 		return err
 	}
 
+	if v.snapshotter != nil {
+		if _, err := v.snapshotter.Take(blk); err != nil {
+			v.logger.Error("failed to take the state snapshot", "height", blk.Height, "err", err)
+		}
+	}
+
+	if v.updateValidators != nil {
+		if err := v.updateValidators(); err != nil {
+			v.logger.Error("failed to update the validators", "height", blk.Height, "err", err)
+		}
+	}
+
+	// the balances of the delegators can be changed by the synced block
+	if v.updateDelegated != nil {
+		if err := v.updateDelegated(); err != nil {
+			v.logger.Error("failed to update the delegated amounts", "height", blk.Height, "err", err)
+		}
+	}
+
 	//clean up txs of this block in txpool.
-	v.txpool.Remove(blk.Transactions...)
-	v.txpool.Remove(blk.ProposerTransaction)
+	v.txpool.RemoveIncluded(blk.Transactions...)
+	v.txpool.RemoveIncluded(blk.ProposerTransaction)
 
 	select {
 	case <-ctx.Done():
@@ -186,6 +234,38 @@ func (v *BlockValidator) finishBlock(ctx context.Context, syncInfo *SyncInfo) er
 	return nil
 }
 
+// saveSyncInfo stores the operations and the transaction pool of the
+// transactions, the certificate and the proposer transaction of block.
+func saveSyncInfo(bs *storage.LevelDBBackend, blk block.Block, syncInfo *SyncInfo) error {
+	for _, bt := range syncInfo.Bts {
+		if err := bt.SaveBlockOperations(bs); err != nil {
+			return err
+		}
+		if _, err := block.SaveTransactionPool(bs, bt.Transaction()); err != nil {
+			return err
+		}
+	}
+
+	if syncInfo.Certificate != nil {
+		if err := syncInfo.Certificate.Save(bs); err != nil {
+			return err
+		}
+	}
+
+	// ProposerTx
+	ptx := syncInfo.Ptx
+	bt := block.NewBlockTransactionFromTransaction(blk.Hash, blk.Height, blk.ProposedTime, ptx.Transaction)
+	if err := bt.Save(bs); err != nil {
+		return err
+	}
+
+	if _, err := block.SaveTransactionPool(bs, ptx.Transaction); err != nil {
+		return err
+	}
+
+	return bt.SaveBlockOperations(bs)
+}
+
 func (v *BlockValidator) validateBlock(ctx context.Context, si *SyncInfo, prevBlk *block.Block) error {
 	v.logger.Debug("start validate block", "height", si.Height)
 	var txs []string
@@ -201,7 +281,7 @@ func (v *BlockValidator) validateBlock(ctx context.Context, si *SyncInfo, prevBl
 		TotalOps:  si.Block.TotalOps,
 	}
 
-	blk := block.NewBlock(si.Block.Proposer, r, si.Block.ProposerTransaction, txs, si.Block.ProposedTime)
+	blk := block.NewBlock(si.Block.Proposer, r, si.Block.ProposerTransaction, txs, si.Block.StateRoot, si.Block.ProposedTime)
 
 	if blk.Hash != si.Block.Hash {
 		err := errors.HashDoesNotMatch
@@ -213,6 +293,76 @@ func (v *BlockValidator) validateBlock(ctx context.Context, si *SyncInfo, prevBl
 	return nil
 }
 
+// validateCertificate checks the certificate of block is signed by the
+// validators of the block height and it is for the block; the block below
+// `common.Config.CertificateHeight` can be synced without certificate.
+func (v *BlockValidator) validateCertificate(ctx context.Context, si *SyncInfo, prevBlk *block.Block) error {
+	v.logger.Debug("start validate certificate", "height", si.Height)
+
+	if si.Certificate == nil && si.Height < v.commonCfg.CertificateHeight {
+		v.logger.Debug("block before certificate height; skip", "height", si.Height)
+		return nil
+	}
+
+	vs, err := block.GetValidatorSet(v.storage, si.Height)
+	if err != nil {
+		return err
+	}
+
+	if err := v.verifyCertificate(si, prevBlk, vs); err != nil {
+		return err
+	}
+
+	v.logger.Debug("end validate certificate", "height", si.Height)
+
+	return nil
+}
+
+// verifyCertificate checks the certificate of block is signed by the
+// validators of `vs`.
+func (v *BlockValidator) verifyCertificate(si *SyncInfo, prevBlk *block.Block, vs block.ValidatorSet) error {
+	c := si.Certificate
+	if c == nil {
+		return errors.CertificateNotFound
+	}
+
+	if c.Block != si.Block.Hash || c.Height != si.Block.Height {
+		return errors.CertificateInvalid
+	}
+
+	proposed := c.Proposed()
+	basis := proposed.VotingBasis
+	if basis.Height != prevBlk.Height || basis.BlockHash != prevBlk.Hash || basis.Round != si.Block.Round {
+		return errors.CertificateInvalid
+	}
+	if proposed.Proposer != si.Block.Proposer ||
+		proposed.Confirmed != si.Block.ProposedTime ||
+		proposed.ProposerTransaction.GetHash() != si.Block.ProposerTransaction {
+		return errors.CertificateInvalid
+	}
+	if len(proposed.Transactions) != len(si.Block.Transactions) {
+		return errors.CertificateInvalid
+	}
+	for i, hash := range proposed.Transactions {
+		if si.Block.Transactions[i] != hash {
+			return errors.CertificateInvalid
+		}
+	}
+
+	policy, err := consensus.NewDefaultVotingThresholdPolicy(v.threshold)
+	if err != nil {
+		return err
+	}
+	policy.SetValidators(len(vs.Validators))
+
+	if err := c.Verify(v.commonCfg.NetworkID, vs.Addresses(), policy.Threshold()); err != nil {
+		v.logger.Error("invalid certificate", "height", si.Height, "signed", c.Sources(), "err", err)
+		return err
+	}
+
+	return nil
+}
+
 func (v *BlockValidator) validateTxs(ctx context.Context, si *SyncInfo) error {
 	v.logger.Debug("start validate txs", "height", si.Height)
 	// proposer transaction
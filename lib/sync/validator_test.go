@@ -4,9 +4,15 @@ import (
 	"context"
 	"testing"
 
+	"boscoin.io/sebak/lib/ballot"
 	"boscoin.io/sebak/lib/block"
 	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/node/runner"
 	"boscoin.io/sebak/lib/transaction"
+	"boscoin.io/sebak/lib/transaction/operation"
+	"boscoin.io/sebak/lib/voting"
 	"github.com/stretchr/testify/require"
 )
 
@@ -33,3 +39,165 @@ func TestValidator(t *testing.T) {
 		require.NoError(t, err)
 	}
 }
+
+func TestValidatorCertificate(t *testing.T) {
+	conf := common.NewTestConfig()
+	st := block.InitTestBlockchain()
+	defer st.Close()
+	tp := transaction.NewPool(conf)
+
+	v := NewBlockValidator(st, tp, conf)
+	ctx := context.Background()
+
+	var kps []*keypair.Full
+	var validators []block.ValidatorSetItem
+	for i := 0; i < 3; i++ {
+		kp := keypair.Random()
+		kps = append(kps, kp)
+		validators = append(validators, block.ValidatorSetItem{Address: kp.Address()})
+	}
+	require.NoError(t, block.SaveInitialValidatorSet(st, block.NewValidatorSet(common.GenesisBlockHeight, validators...)))
+
+	prevBlk := block.GetLatestBlock(st)
+	basis := voting.Basis{
+		Round:     0,
+		Height:    prevBlk.Height,
+		BlockHash: prevBlk.Hash,
+		TotalTxs:  prevBlk.TotalTxs,
+		TotalOps:  prevBlk.TotalOps,
+	}
+
+	proposed := ballot.NewBallot(kps[0].Address(), kps[0].Address(), basis, []string{})
+	opc, _ := ballot.NewCollectTxFeeFromBallot(*proposed, block.CommonKP.Address())
+	opi, _ := ballot.NewInflationFromBallot(*proposed, block.CommonKP.Address(), common.Amount(1))
+	ptx, _ := ballot.NewProposerTransactionFromBallot(*proposed, opc, opi)
+	proposed.SetProposerTransaction(ptx)
+	proposed.SetVote(ballot.StateINIT, voting.YES)
+	proposed.Sign(kps[0], conf.NetworkID)
+
+	var ballots []ballot.Ballot
+	for _, kp := range kps {
+		b := *proposed
+		b.SetVote(ballot.StateACCEPT, voting.YES)
+		b.Sign(kp, conf.NetworkID)
+		ballots = append(ballots, b)
+	}
+
+	r := basis
+	r.Height++
+	blk := *block.NewBlock(kps[0].Address(), r, ptx.GetHash(), []string{}, common.Hash{}, proposed.ProposerConfirmed())
+
+	si := &SyncInfo{
+		Height: blk.Height,
+		Block:  &blk,
+	}
+
+	{ // without certificate
+		require.Equal(t, errors.CertificateNotFound, v.validateCertificate(ctx, si, &prevBlk))
+	}
+
+	{ // without certificate, but the block is below the certificate height
+		v.commonCfg.CertificateHeight = blk.Height + 1
+		require.NoError(t, v.validateCertificate(ctx, si, &prevBlk))
+
+		v.commonCfg.CertificateHeight = blk.Height
+		require.Equal(t, errors.CertificateNotFound, v.validateCertificate(ctx, si, &prevBlk))
+	}
+
+	{ // not enough ballots
+		si.Certificate = ballot.NewCertificate(blk.Hash, blk.Height, ballots[:2]...)
+		require.Equal(t, errors.CertificateThresholdNotReached, v.validateCertificate(ctx, si, &prevBlk))
+	}
+
+	{ // certificate for the other block
+		si.Certificate = ballot.NewCertificate(prevBlk.Hash, blk.Height, ballots...)
+		require.Equal(t, errors.CertificateInvalid, v.validateCertificate(ctx, si, &prevBlk))
+	}
+
+	{ // forged block
+		forged := blk
+		forged.Proposer = kps[1].Address()
+		si.Block = &forged
+		si.Certificate = ballot.NewCertificate(forged.Hash, forged.Height, ballots...)
+		require.Equal(t, errors.CertificateInvalid, v.validateCertificate(ctx, si, &prevBlk))
+		si.Block = &blk
+	}
+
+	si.Certificate = ballot.NewCertificate(blk.Hash, blk.Height, ballots...)
+	require.NoError(t, v.validateCertificate(ctx, si, &prevBlk))
+}
+
+// TestValidatorDelegated checks the delegated amounts are updated by the
+// synced block, which includes `operation.Delegate`.
+func TestValidatorDelegated(t *testing.T) {
+	conf := common.NewTestConfig()
+	st := block.InitTestBlockchain()
+	defer st.Close()
+	tp := transaction.NewPool(conf)
+
+	validator := keypair.Random()
+
+	// the block without certificate is synced below the certificate height
+	conf.CertificateHeight = 100
+	var delegated map[string]common.Amount
+	v := NewBlockValidator(st, tp, conf, func(v *BlockValidator) {
+		v.updateDelegated = func() (err error) {
+			delegated, err = block.GetDelegatedAmounts(st, validator.Address())
+			return
+		}
+	})
+
+	owner := keypair.Random()
+	block.NewBlockAccount(owner.Address(), common.BaseReserve).MustSave(st)
+	frozen := keypair.Random()
+	frozenBalance := common.Amount(common.BaseReserve) * 10
+	block.NewBlockAccountLinked(frozen.Address(), frozenBalance, owner.Address()).MustSave(st)
+
+	op, err := operation.NewOperation(operation.NewDelegate(validator.Address()))
+	require.NoError(t, err)
+	tx, err := transaction.NewTransaction(frozen.Address(), 0, op)
+	require.NoError(t, err)
+	tx.Sign(frozen, conf.NetworkID)
+
+	prevBlk := block.GetLatestBlock(st)
+	basis := voting.Basis{
+		Round:     0,
+		Height:    prevBlk.Height,
+		BlockHash: prevBlk.Hash,
+		TotalTxs:  prevBlk.TotalTxs,
+		TotalOps:  prevBlk.TotalOps,
+	}
+
+	proposed := ballot.NewBallot(validator.Address(), validator.Address(), basis, []string{tx.GetHash()})
+	opc, _ := ballot.NewCollectTxFeeFromBallot(*proposed, block.CommonKP.Address(), tx)
+	opi, _ := ballot.NewInflationFromBallot(*proposed, block.CommonKP.Address(), common.Amount(1))
+	ptx, _ := ballot.NewProposerTransactionFromBallot(*proposed, opc, opi)
+	proposed.SetProposerTransaction(ptx)
+	proposed.Sign(validator, conf.NetworkID)
+	ptx = proposed.ProposerTransaction()
+
+	stateRoot, err := runner.MakeStateRoot(st, []*transaction.Transaction{&tx}, ptx)
+	require.NoError(t, err)
+
+	r := basis
+	r.Height++
+	r.TotalTxs += 2
+	r.TotalOps += uint64(len(tx.B.Operations) + len(ptx.B.Operations))
+	blk := *block.NewBlock(validator.Address(), r, ptx.GetHash(), []string{tx.GetHash()}, stateRoot, proposed.ProposerConfirmed())
+
+	bt := block.NewBlockTransactionFromTransaction(blk.Hash, blk.Height, blk.ProposedTime, tx)
+	si := &SyncInfo{
+		Height: blk.Height,
+		Block:  &blk,
+		Bts:    []*block.BlockTransaction{&bt},
+		Ptx:    &ptx,
+	}
+
+	require.NoError(t, v.Validate(context.Background(), si))
+	require.Equal(t, blk.Hash, block.GetLatestBlock(st).Hash)
+
+	amount, err := block.GetDelegatedAmount(st, validator.Address())
+	require.NoError(t, err)
+	require.Equal(t, frozenBalance, amount)
+	require.Equal(t, amount, delegated[validator.Address()])
+}
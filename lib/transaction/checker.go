@@ -90,24 +90,84 @@ func CheckOperations(c common.Checker, args ...interface{}) (err error) {
 
 			hashes = append(hashes, u)
 		}
+
+		// the voting result can be funded only once
+		if pop, ok := op.B.(operation.InflationPF); ok {
+			u := fmt.Sprintf("%s-%s", op.H.Type, pop.VotingResult)
+			if _, found := common.InStringArray(hashes, u); found {
+				err = errors.DuplicatedOperation
+				return
+			}
+
+			hashes = append(hashes, u)
+		}
 	}
 
 	return
 }
 
-func CheckVerifySignature(c common.Checker, args ...interface{}) (err error) {
+func CheckTimeBounds(c common.Checker, args ...interface{}) (err error) {
 	checker := c.(*Checker)
 
-	var kp keypair.KP
-	if kp, err = keypair.Parse(checker.Transaction.B.Source); err != nil {
+	if checker.Transaction.B.TimeBounds == nil {
 		return
 	}
-	err = kp.Verify(
-		append(checker.NetworkID, []byte(checker.Transaction.H.Hash)...),
-		base58.Decode(checker.Transaction.H.Signature),
-	)
-	if err != nil {
+
+	return checker.Transaction.B.TimeBounds.IsWellFormed()
+}
+
+func CheckMemo(c common.Checker, args ...interface{}) (err error) {
+	checker := c.(*Checker)
+
+	if checker.Transaction.B.Memo == nil {
 		return
 	}
+
+	return checker.Transaction.B.Memo.IsWellFormed()
+}
+
+// CheckVerifySignature verifies the signature of source and the signatures
+// of the other signers. Whether the signers are enough for the source account
+// is checked with the account data.
+func CheckVerifySignature(c common.Checker, args ...interface{}) (err error) {
+	checker := c.(*Checker)
+	tx := checker.Transaction
+
+	if len(tx.H.Signature) < 1 && len(tx.H.Signatures) < 1 {
+		err = errors.SignatureVerificationFailed
+		return
+	}
+
+	signed := map[string]bool{}
+	if len(tx.H.Signature) > 0 {
+		if err = verifySignature(checker.NetworkID, tx.H.Hash, tx.B.Source, tx.H.Signature); err != nil {
+			return
+		}
+		signed[tx.B.Source] = true
+	}
+
+	for _, s := range tx.H.Signatures {
+		if signed[s.Signer] {
+			err = errors.DuplicatedSignature
+			return
+		}
+		if err = verifySignature(checker.NetworkID, tx.H.Hash, s.Signer, s.Signature); err != nil {
+			return
+		}
+		signed[s.Signer] = true
+	}
+
 	return
 }
+
+func verifySignature(networkID []byte, hash, address, signature string) (err error) {
+	var kp keypair.KP
+	if kp, err = keypair.Parse(address); err != nil {
+		return
+	}
+
+	return kp.Verify(
+		append(networkID, []byte(hash)...),
+		base58.Decode(signature),
+	)
+}
@@ -0,0 +1,54 @@
+package transaction
+
+import (
+	"strconv"
+
+	"github.com/btcsuite/btcutil/base58"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/errors"
+)
+
+type MemoType string
+
+const (
+	// MemoText is the short text, which is not longer than
+	// `common.MaxMemoTextLength` bytes.
+	MemoText MemoType = "text"
+	// MemoID is the unsigned 64 bit integer.
+	MemoID MemoType = "id"
+	// MemoHash is the base58 encoded 32 bytes hash.
+	MemoHash MemoType = "hash"
+)
+
+// Memo is the additional information of transaction, like the identifier of
+// deposit for exchange.
+type Memo struct {
+	Type  MemoType `json:"type"`
+	Value string   `json:"value"`
+}
+
+func NewMemo(memoType MemoType, value string) *Memo {
+	return &Memo{Type: memoType, Value: value}
+}
+
+func (m Memo) IsWellFormed() (err error) {
+	switch m.Type {
+	case MemoText:
+		if len(m.Value) < 1 || len(m.Value) > common.MaxMemoTextLength {
+			return errors.InvalidMemo
+		}
+	case MemoID:
+		if _, err = strconv.ParseUint(m.Value, 10, 64); err != nil {
+			return errors.InvalidMemo
+		}
+	case MemoHash:
+		if len(base58.Decode(m.Value)) != 32 {
+			return errors.InvalidMemo
+		}
+	default:
+		return errors.InvalidMemo
+	}
+
+	return
+}
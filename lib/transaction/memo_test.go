@@ -0,0 +1,63 @@
+package transaction
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/errors"
+)
+
+func TestMemoIsWellFormed(t *testing.T) {
+	require.NoError(t, NewMemo(MemoText, "deposit-1").IsWellFormed())
+	require.NoError(t, NewMemo(MemoText, strings.Repeat("a", common.MaxMemoTextLength)).IsWellFormed())
+	require.Equal(t, errors.InvalidMemo, NewMemo(MemoText, "").IsWellFormed())
+	require.Equal(t, errors.InvalidMemo, NewMemo(MemoText, strings.Repeat("a", common.MaxMemoTextLength+1)).IsWellFormed())
+
+	require.NoError(t, NewMemo(MemoID, "18446744073709551615").IsWellFormed())
+	require.Equal(t, errors.InvalidMemo, NewMemo(MemoID, "-1").IsWellFormed())
+	require.Equal(t, errors.InvalidMemo, NewMemo(MemoID, "showme").IsWellFormed())
+
+	require.NoError(t, NewMemo(MemoHash, base58.Encode(common.MakeHash([]byte("showme")))).IsWellFormed())
+	require.Equal(t, errors.InvalidMemo, NewMemo(MemoHash, base58.Encode([]byte("showme"))).IsWellFormed())
+
+	require.Equal(t, errors.InvalidMemo, NewMemo(MemoType("unknown"), "showme").IsWellFormed())
+}
+
+func TestTransactionWithMemo(t *testing.T) {
+	conf := common.NewTestConfig()
+	kp, tx := TestMakeTransaction(conf.NetworkID, 1)
+	hashWithoutMemo := tx.B.MakeHashString()
+
+	tx.B.Memo = NewMemo(MemoID, "1")
+	tx.Sign(kp, conf.NetworkID)
+	require.NoError(t, tx.IsWellFormed(conf))
+	require.NotEqual(t, hashWithoutMemo, tx.GetHash())
+
+	// memo is included in hash
+	hashWithMemo := tx.GetHash()
+	tx.B.Memo = NewMemo(MemoID, "2")
+	require.NotEqual(t, hashWithMemo, tx.B.MakeHashString())
+
+	// the empty time bounds is same with no time bounds
+	tx.B.Memo = NewMemo(MemoID, "1")
+	tx.B.TimeBounds = &TimeBounds{}
+	require.Equal(t, hashWithMemo, tx.B.MakeHashString())
+	tx.B.TimeBounds = nil
+
+	// memo is kept in JSON
+	b, err := tx.Serialize()
+	require.NoError(t, err)
+	var decoded Transaction
+	require.NoError(t, json.Unmarshal(b, &decoded))
+	require.Equal(t, *tx.B.Memo, *decoded.B.Memo)
+	require.Equal(t, hashWithMemo, decoded.GetHash())
+
+	tx.B.Memo = NewMemo(MemoText, "")
+	tx.Sign(kp, conf.NetworkID)
+	require.Equal(t, errors.InvalidMemo, tx.IsWellFormed(conf))
+}
@@ -0,0 +1,48 @@
+package operation
+
+import (
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/errors"
+)
+
+// AddValidator adds the validator to the validator set from the block of
+// `Height`. `Endpoint` is optional; if it is empty, the endpoint of validator
+// will be found by discovery.
+type AddValidator struct {
+	Address  string `json:"address"`
+	Endpoint string `json:"endpoint"`
+	Alias    string `json:"alias"`
+	Height   uint64 `json:"height"`
+}
+
+func NewAddValidator(address, endpoint, alias string, height uint64) AddValidator {
+	return AddValidator{
+		Address:  address,
+		Endpoint: endpoint,
+		Alias:    alias,
+		Height:   height,
+	}
+}
+
+func (o AddValidator) IsWellFormed(common.Config) (err error) {
+	if _, err = keypair.Parse(o.Address); err != nil {
+		return
+	}
+
+	if len(o.Endpoint) > 0 {
+		if _, err = common.ParseEndpoint(o.Endpoint); err != nil {
+			return errors.InvalidOperation
+		}
+	}
+
+	if o.Height < 1 {
+		return errors.InvalidOperation
+	}
+
+	return
+}
+
+func (o AddValidator) HasFee() bool {
+	return true
+}
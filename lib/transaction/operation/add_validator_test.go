@@ -0,0 +1,48 @@
+package operation
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/errors"
+)
+
+func TestOperationBodyAddValidator(t *testing.T) {
+	conf := common.NewTestConfig()
+	address := keypair.Random().Address()
+
+	opb := NewAddValidator(address, "https://localhost:12345", "v1", 10)
+	op, err := NewOperation(opb)
+	require.NoError(t, err)
+	require.Equal(t, TypeAddValidator, op.H.Type)
+	require.NoError(t, op.IsWellFormed(conf))
+	common.CheckRoundTripRLP(t, op)
+
+	var o Operation
+	require.NoError(t, json.Unmarshal(common.MustMarshalJSON(op), &o))
+	require.Equal(t, opb, o.B)
+
+	{ // without endpoint
+		opb := NewAddValidator(address, "", "", 10)
+		require.NoError(t, opb.IsWellFormed(conf))
+	}
+
+	{ // wrong address
+		opb := NewAddValidator("validator", "", "", 10)
+		require.Error(t, opb.IsWellFormed(conf))
+	}
+
+	{ // wrong endpoint
+		opb := NewAddValidator(address, "://localhost", "", 10)
+		require.Equal(t, errors.InvalidOperation, opb.IsWellFormed(conf))
+	}
+
+	{ // empty height
+		opb := NewAddValidator(address, "", "", 0)
+		require.Equal(t, errors.InvalidOperation, opb.IsWellFormed(conf))
+	}
+}
@@ -0,0 +1,54 @@
+package operation
+
+import (
+	"strconv"
+	"strings"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/errors"
+)
+
+type VoteAnswer string
+
+const (
+	VoteYes VoteAnswer = "yes"
+	VoteNo  VoteAnswer = "no"
+	VoteABS VoteAnswer = "abs"
+)
+
+// CongressVote casts the vote of frozen account to the `CongressVoting`
+// between `Voting.Start` and `Voting.End`. `CongressVotingHash` is
+// `<transaction hash>-<operation index>` of the `CongressVoting`.
+type CongressVote struct {
+	CongressVotingHash string     `json:"congress_voting_hash"`
+	Answer             VoteAnswer `json:"answer"`
+}
+
+func NewCongressVote(congressVotingHash string, answer VoteAnswer) CongressVote {
+	return CongressVote{
+		CongressVotingHash: congressVotingHash,
+		Answer:             answer,
+	}
+}
+
+func (o CongressVote) IsWellFormed(common.Config) (err error) {
+	parsedCongressVotingHash := strings.Split(o.CongressVotingHash, "-") //0:TxHash, 1:Index
+	if len(parsedCongressVotingHash) != 2 {
+		return errors.InvalidOperation
+	}
+	if _, err := strconv.Atoi(parsedCongressVotingHash[1]); err != nil {
+		return errors.InvalidOperation.Clone().SetData("error", err)
+	}
+
+	switch o.Answer {
+	case VoteYes, VoteNo, VoteABS:
+	default:
+		return errors.InvalidOperation
+	}
+
+	return
+}
+
+func (o CongressVote) HasFee() bool {
+	return true
+}
@@ -0,0 +1,37 @@
+package operation
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/errors"
+)
+
+func TestOperationBodyCongressVote(t *testing.T) {
+	conf := common.NewTestConfig()
+	votingHash := common.MustMakeObjectHashString("voting") + "-0"
+
+	opb := NewCongressVote(votingHash, VoteYes)
+	op, err := NewOperation(opb)
+	require.NoError(t, err)
+	require.Equal(t, TypeCongressVote, op.H.Type)
+	require.NoError(t, op.IsWellFormed(conf))
+	common.CheckRoundTripRLP(t, op)
+
+	var o Operation
+	require.NoError(t, json.Unmarshal(common.MustMarshalJSON(op), &o))
+	require.Equal(t, opb, o.B)
+
+	{ // wrong congress voting hash
+		opb := NewCongressVote(common.MustMakeObjectHashString("voting"), VoteNo)
+		require.Equal(t, errors.InvalidOperation, opb.IsWellFormed(conf))
+	}
+
+	{ // unknown answer
+		opb := NewCongressVote(votingHash, VoteAnswer("maybe"))
+		require.Equal(t, errors.InvalidOperation, opb.IsWellFormed(conf))
+	}
+}
@@ -0,0 +1,34 @@
+package operation
+
+import (
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+)
+
+// Delegate binds the balance of frozen account to the `Validator`; the
+// delegated balance is counted as the weight of validator. The later
+// `Delegate` replaces the previous one. Like `UnfreezeRequest`, it does not
+// have fee, because the balance of frozen account can not be spent.
+type Delegate struct {
+	Validator string `json:"validator"`
+}
+
+func NewDelegate(validator string) Delegate {
+	return Delegate{Validator: validator}
+}
+
+func (o Delegate) IsWellFormed(common.Config) (err error) {
+	if _, err = keypair.Parse(o.Validator); err != nil {
+		return
+	}
+
+	return
+}
+
+func (o Delegate) TargetAddress() string {
+	return o.Validator
+}
+
+func (o Delegate) HasFee() bool {
+	return false
+}
@@ -0,0 +1,34 @@
+package operation
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+)
+
+func TestOperationBodyDelegate(t *testing.T) {
+	conf := common.NewTestConfig()
+	validator := keypair.Random().Address()
+
+	opb := NewDelegate(validator)
+	op, err := NewOperation(opb)
+	require.NoError(t, err)
+	require.Equal(t, TypeDelegate, op.H.Type)
+	require.NoError(t, op.IsWellFormed(conf))
+	require.Equal(t, validator, opb.TargetAddress())
+	require.False(t, op.HasFee())
+	common.CheckRoundTripRLP(t, op)
+
+	var o Operation
+	require.NoError(t, json.Unmarshal(common.MustMarshalJSON(op), &o))
+	require.Equal(t, opb, o.B)
+
+	{ // wrong validator address
+		opb := NewDelegate("validator")
+		require.Error(t, opb.IsWellFormed(conf))
+	}
+}
@@ -20,6 +20,11 @@ const (
 	TypeInflation
 	TypeUnfreezingRequest
 	TypeInflationPF
+	TypeSetOptions
+	TypeCongressVote
+	TypeDelegate
+	TypeAddValidator
+	TypeRemoveValidator
 )
 
 var (
@@ -32,6 +37,11 @@ var (
 		"inflation",
 		"unfreezing-request",
 		"inflation-pf",
+		"set-options",
+		"congress-vote",
+		"delegate",
+		"add-validator",
+		"remove-validator",
 	}
 )
 
@@ -60,7 +70,9 @@ func IsNormalOperation(t OperationType) bool {
 	switch t {
 	case TypeCreateAccount, TypePayment,
 		TypeCongressVoting, TypeCongressVotingResult,
-		TypeUnfreezingRequest, TypeInflationPF:
+		TypeUnfreezingRequest, TypeInflationPF,
+		TypeSetOptions, TypeCongressVote,
+		TypeDelegate, TypeAddValidator, TypeRemoveValidator:
 		return true
 	default:
 		return false
@@ -91,6 +103,16 @@ func NewOperation(opb Body) (op Operation, err error) {
 		t = TypeCongressVotingResult
 	case InflationPF:
 		t = TypeInflationPF
+	case SetOptions:
+		t = TypeSetOptions
+	case CongressVote:
+		t = TypeCongressVote
+	case Delegate:
+		t = TypeDelegate
+	case AddValidator:
+		t = TypeAddValidator
+	case RemoveValidator:
+		t = TypeRemoveValidator
 	default:
 		err = errors.UnknownOperationType
 		return
@@ -263,6 +285,16 @@ func newBodyFromType(ty OperationType) (interface{}, error) {
 		return &UnfreezeRequest{}, nil
 	case TypeInflationPF:
 		return &InflationPF{}, nil
+	case TypeSetOptions:
+		return &SetOptions{}, nil
+	case TypeCongressVote:
+		return &CongressVote{}, nil
+	case TypeDelegate:
+		return &Delegate{}, nil
+	case TypeAddValidator:
+		return &AddValidator{}, nil
+	case TypeRemoveValidator:
+		return &RemoveValidator{}, nil
 	default:
 		return nil, errors.InvalidOperation
 	}
@@ -0,0 +1,37 @@
+package operation
+
+import (
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/errors"
+)
+
+// RemoveValidator removes the validator from the validator set from the block
+// of `Height`.
+type RemoveValidator struct {
+	Address string `json:"address"`
+	Height  uint64 `json:"height"`
+}
+
+func NewRemoveValidator(address string, height uint64) RemoveValidator {
+	return RemoveValidator{
+		Address: address,
+		Height:  height,
+	}
+}
+
+func (o RemoveValidator) IsWellFormed(common.Config) (err error) {
+	if _, err = keypair.Parse(o.Address); err != nil {
+		return
+	}
+
+	if o.Height < 1 {
+		return errors.InvalidOperation
+	}
+
+	return
+}
+
+func (o RemoveValidator) HasFee() bool {
+	return true
+}
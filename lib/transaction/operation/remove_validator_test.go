@@ -0,0 +1,37 @@
+package operation
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/errors"
+)
+
+func TestOperationBodyRemoveValidator(t *testing.T) {
+	conf := common.NewTestConfig()
+
+	opb := NewRemoveValidator(keypair.Random().Address(), 10)
+	op, err := NewOperation(opb)
+	require.NoError(t, err)
+	require.Equal(t, TypeRemoveValidator, op.H.Type)
+	require.NoError(t, op.IsWellFormed(conf))
+	common.CheckRoundTripRLP(t, op)
+
+	var o Operation
+	require.NoError(t, json.Unmarshal(common.MustMarshalJSON(op), &o))
+	require.Equal(t, opb, o.B)
+
+	{ // wrong address
+		opb := NewRemoveValidator("validator", 10)
+		require.Error(t, opb.IsWellFormed(conf))
+	}
+
+	{ // empty height
+		opb := NewRemoveValidator(keypair.Random().Address(), 0)
+		require.Equal(t, errors.InvalidOperation, opb.IsWellFormed(conf))
+	}
+}
@@ -0,0 +1,111 @@
+package operation
+
+import (
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/errors"
+)
+
+// ThresholdLevel is the level of thresholds, which the operation requires.
+type ThresholdLevel uint8
+
+const (
+	ThresholdLow ThresholdLevel = iota
+	ThresholdMedium
+	ThresholdHigh
+)
+
+// GetThresholdLevel returns the threshold level of operation type; managing
+// the signers needs the high level and congress voting needs the low level.
+func GetThresholdLevel(t OperationType) ThresholdLevel {
+	switch t {
+	case TypeSetOptions:
+		return ThresholdHigh
+	case TypeCongressVoting, TypeCongressVotingResult, TypeCongressVote:
+		return ThresholdLow
+	default:
+		return ThresholdMedium
+	}
+}
+
+// Signer is the public address which can sign the transaction of account
+// with it's weight.
+type Signer struct {
+	Address string `json:"address"`
+	Weight  uint32 `json:"weight"`
+}
+
+type Thresholds struct {
+	Low    uint32 `json:"low"`
+	Medium uint32 `json:"medium"`
+	High   uint32 `json:"high"`
+}
+
+func (t Thresholds) Get(level ThresholdLevel) uint32 {
+	switch level {
+	case ThresholdLow:
+		return t.Low
+	case ThresholdHigh:
+		return t.High
+	default:
+		return t.Medium
+	}
+}
+
+// SetOptions replaces the signers and thresholds of the source account. With
+// empty `Signers`, the account is controlled by it's own key again.
+type SetOptions struct {
+	Signers    []Signer   `json:"signers"`
+	Thresholds Thresholds `json:"thresholds"`
+}
+
+func NewSetOptions(signers []Signer, thresholds Thresholds) SetOptions {
+	return SetOptions{
+		Signers:    signers,
+		Thresholds: thresholds,
+	}
+}
+
+// Implement transaction/operation : IsWellFormed
+func (o SetOptions) IsWellFormed(common.Config) (err error) {
+	if len(o.Signers) < 1 {
+		if o.Thresholds != (Thresholds{}) {
+			return errors.InvalidThresholds
+		}
+		return
+	}
+
+	if len(o.Signers) > common.MaxSignersInAccount {
+		return errors.InvalidSigners
+	}
+
+	var totalWeight uint64
+	addresses := map[string]bool{}
+	for _, s := range o.Signers {
+		if _, err = keypair.Parse(s.Address); err != nil {
+			return errors.InvalidSigners
+		}
+		if s.Weight < 1 || addresses[s.Address] {
+			return errors.InvalidSigners
+		}
+		addresses[s.Address] = true
+		totalWeight += uint64(s.Weight)
+	}
+
+	t := o.Thresholds
+	if t.Low > t.Medium || t.Medium > t.High || t.High < 1 {
+		return errors.InvalidThresholds
+	}
+
+	// the signers should be able to reach the high threshold, otherwise the
+	// account can not be managed anymore.
+	if totalWeight < uint64(t.High) {
+		return errors.InvalidThresholds
+	}
+
+	return
+}
+
+func (o SetOptions) HasFee() bool {
+	return true
+}
@@ -0,0 +1,79 @@
+package operation
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/errors"
+)
+
+func TestSetOptionsOperation(t *testing.T) {
+	conf := common.NewTestConfig()
+	kp0 := keypair.Random()
+	kp1 := keypair.Random()
+
+	signers := []Signer{
+		Signer{Address: kp0.Address(), Weight: 1},
+		Signer{Address: kp1.Address(), Weight: 2},
+	}
+
+	{ // valid
+		o := NewSetOptions(signers, Thresholds{Low: 1, Medium: 2, High: 3})
+		require.NoError(t, o.IsWellFormed(conf))
+	}
+
+	{ // reset signers
+		o := NewSetOptions(nil, Thresholds{})
+		require.NoError(t, o.IsWellFormed(conf))
+
+		o = NewSetOptions(nil, Thresholds{Low: 1})
+		require.Equal(t, errors.InvalidThresholds, o.IsWellFormed(conf))
+	}
+
+	{ // signers can not reach the high threshold
+		o := NewSetOptions(signers, Thresholds{Low: 1, Medium: 2, High: 4})
+		require.Equal(t, errors.InvalidThresholds, o.IsWellFormed(conf))
+	}
+
+	{ // wrong order of thresholds
+		o := NewSetOptions(signers, Thresholds{Low: 2, Medium: 1, High: 3})
+		require.Equal(t, errors.InvalidThresholds, o.IsWellFormed(conf))
+	}
+
+	{ // duplicated signer
+		o := NewSetOptions(append(signers, signers[0]), Thresholds{Low: 1, Medium: 2, High: 3})
+		require.Equal(t, errors.InvalidSigners, o.IsWellFormed(conf))
+	}
+
+	{ // zero weight
+		o := NewSetOptions([]Signer{Signer{Address: kp0.Address()}}, Thresholds{})
+		require.Equal(t, errors.InvalidSigners, o.IsWellFormed(conf))
+	}
+
+	{ // invalid address
+		o := NewSetOptions([]Signer{Signer{Address: "showme", Weight: 1}}, Thresholds{High: 1})
+		require.Equal(t, errors.InvalidSigners, o.IsWellFormed(conf))
+	}
+}
+
+func TestSerializeSetOptionsOperation(t *testing.T) {
+	opb := NewSetOptions(
+		[]Signer{Signer{Address: keypair.Random().Address(), Weight: 2}},
+		Thresholds{Low: 1, Medium: 1, High: 2},
+	)
+	op, err := NewOperation(opb)
+	require.NoError(t, err)
+	require.Equal(t, TypeSetOptions, op.H.Type)
+	require.Equal(t, ThresholdHigh, GetThresholdLevel(op.H.Type))
+
+	var o Operation
+	require.NoError(t, json.Unmarshal(common.MustMarshalJSON(op), &o))
+	require.Equal(t, opb, o.B)
+
+	// operation is hashed by RLP
+	require.Equal(t, common.MustMakeObjectHashString(op), common.MustMakeObjectHashString(o))
+}
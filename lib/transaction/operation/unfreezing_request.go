@@ -1,19 +1,67 @@
 package operation
 
 import (
+	"io"
+
 	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/errors"
 )
 
-type UnfreezeRequest struct{}
+// UnfreezeRequest requests to unfreeze the `Amount` of frozen account; the
+// `Amount` must be a whole number of `common.Unit`. If `Amount` is zero, the
+// whole balance is requested. After `common.UnfreezingPeriod`, the requested
+// amount can be paid from the frozen account.
+type UnfreezeRequest struct {
+	Amount common.Amount `json:"amount,omitempty"`
+}
 
 func NewUnfreezeRequest() UnfreezeRequest {
 	return UnfreezeRequest{}
 }
 
+func NewPartialUnfreezeRequest(amount common.Amount) UnfreezeRequest {
+	return UnfreezeRequest{Amount: amount}
+}
+
+// Implement `common.Encoder`; `Amount` is encoded only when it is set, so the
+// hash of the request for the whole balance is not changed.
+func (o UnfreezeRequest) EncodeRLP(w io.Writer) error {
+	if o.Amount == 0 {
+		return common.Encode(w, []interface{}{})
+	}
+
+	return common.Encode(w, []interface{}{o.Amount})
+}
+
+// Implement `common.Decoder`
+func (o *UnfreezeRequest) DecodeRLP(s *common.RLPStream) (err error) {
+	var size uint64
+	if size, err = s.List(); err != nil {
+		return
+	}
+
+	if size > 0 {
+		if err = o.Amount.DecodeRLP(s); err != nil {
+			return
+		}
+	}
+
+	return s.ListEnd()
+}
+
 func (o UnfreezeRequest) IsWellFormed(common.Config) (err error) {
+	if o.Amount%common.Unit != 0 {
+		return errors.UnfreezingAmountWholeUnit
+	}
+
 	return
 }
 
+// IsWhole returns true when the whole balance is requested.
+func (o UnfreezeRequest) IsWhole() bool {
+	return o.Amount == 0
+}
+
 func (o UnfreezeRequest) HasFee() bool {
 	return false
 }
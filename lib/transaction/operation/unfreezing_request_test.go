@@ -0,0 +1,43 @@
+package operation
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/errors"
+)
+
+func TestOperationBodyUnfreezeRequest(t *testing.T) {
+	conf := common.NewTestConfig()
+
+	{ // the whole balance
+		op, err := NewOperation(NewUnfreezeRequest())
+		require.NoError(t, err)
+		require.NoError(t, op.IsWellFormed(conf))
+		common.CheckRoundTripRLP(t, op)
+
+		// the hash is same with the request without amount
+		require.Equal(t, common.MustMakeObjectHashString(struct{}{}), common.MustMakeObjectHashString(op.B))
+	}
+
+	{ // partial
+		opb := NewPartialUnfreezeRequest(common.Unit * 2)
+		op, err := NewOperation(opb)
+		require.NoError(t, err)
+		require.NoError(t, op.IsWellFormed(conf))
+		require.False(t, opb.IsWhole())
+		common.CheckRoundTripRLP(t, op)
+
+		var o Operation
+		require.NoError(t, json.Unmarshal(common.MustMarshalJSON(op), &o))
+		require.Equal(t, opb, o.B)
+	}
+
+	{ // not a whole number of units
+		opb := NewPartialUnfreezeRequest(common.Unit + 1)
+		require.Equal(t, errors.UnfreezingAmountWholeUnit, opb.IsWellFormed(conf))
+	}
+}
@@ -3,6 +3,7 @@ package transaction
 import (
 	"container/list"
 	"sync"
+	"time"
 
 	"boscoin.io/sebak/lib/common"
 	"boscoin.io/sebak/lib/errors"
@@ -14,6 +15,7 @@ type Pool struct {
 
 	Pool    map[ /* Transaction.GetHash() */ string]Transaction
 	sources map[ /* Transaction.Source() */ string] /* Transaction.GetHash() */ string
+	queues  map[ /* Transaction.Source() */ string][] /* Transaction.GetHash() */ string
 
 	hashList *list.List // Transaction.GetHash()
 	hashMap  map[ /* Transaction.GetHash() */ string]*list.Element
@@ -25,6 +27,7 @@ func NewPool(cfg common.Config) *Pool {
 	return &Pool{
 		Pool:     map[string]Transaction{},
 		sources:  map[string]string{},
+		queues:   map[string][]string{},
 		hashList: list.New(),
 		hashMap:  make(map[string]*list.Element),
 		cfg:      cfg,
@@ -57,35 +60,175 @@ func (tp *Pool) Get(hash string) (Transaction, bool) {
 func (tp *Pool) GetFromSource(source string) (Transaction, bool) {
 	tp.RLock()
 	defer tp.RUnlock()
+
 	hash, found := tp.sources[source]
 	if !found {
 		return Transaction{}, false
 	}
-	return tp.Get(hash)
+
+	tx, found := tp.Pool[hash]
+	return tx, found
 }
 
+// add puts the transaction into pool. The first transaction of source is
+// available for the next block; the transactions, which have the following
+// sequence IDs of same source, are queued until the previous one is included
+// in block.
 func (tp *Pool) add(tx Transaction, limit int) error {
 	txHash := tx.GetHash()
 	if tp.Has(txHash) {
 		return errors.TransactionAlreadyExistsInPool
 	}
 
-	if limit > 0 && tp.Len() >= limit {
-		return errors.TransactionPoolFull
+	tp.Lock()
+	defer tp.Unlock()
+
+	source := tx.Source()
+	head, found := tp.sources[source]
+	if !found {
+		if limit > 0 && len(tp.Pool) >= limit {
+			return errors.TransactionPoolFull
+		}
+
+		metrics.TxPool.AddSize(1)
+		tp.insert(tx)
+
+		return nil
 	}
 
-	metrics.TxPool.AddSize(1)
+	headSequenceID := tp.Pool[head].B.SequenceID
+	if tx.B.SequenceID < headSequenceID {
+		return errors.TransactionInvalidSequenceID
+	}
 
-	tp.Lock()
-	defer tp.Unlock()
+	queued := tp.queues[source]
+	offset := tx.B.SequenceID - headSequenceID
+	switch {
+	case offset == 0:
+		// replace-by-fee; the transaction of same source and sequence ID,
+		// which pays more fee replaces the existing one.
+		if !tx.CanReplace(tp.Pool[head]) {
+			return errors.TransactionReplacementFeeTooLow
+		}
+		tp.removeFromList(head)
+		tp.insert(tx)
+	case offset <= uint64(len(queued)):
+		old := queued[offset-1]
+		if !tx.CanReplace(tp.Pool[old]) {
+			return errors.TransactionReplacementFeeTooLow
+		}
+		delete(tp.Pool, old)
+		tp.Pool[txHash] = tx
+		queued[offset-1] = txHash
+	case offset == uint64(len(queued))+1:
+		if tp.cfg.TxPoolSourceLimit > 0 && len(queued)+1 >= tp.cfg.TxPoolSourceLimit {
+			return errors.TransactionSourceQueueFull
+		}
+		if limit > 0 && len(tp.Pool) >= limit {
+			return errors.TransactionPoolFull
+		}
+
+		metrics.TxPool.AddSize(1)
+		tp.Pool[txHash] = tx
+		tp.queues[source] = append(queued, txHash)
+	default:
+		return errors.TransactionInvalidSequenceID
+	}
+
+	return nil
+}
+
+// insert puts the transaction into `hashList` by the order of fee per
+// operation; the transactions of same fee are ordered by the arrival.
+func (tp *Pool) insert(tx Transaction) {
+	txHash := tx.GetHash()
+	fee := tx.FeePerOperation()
+
+	var e *list.Element
+	for mark := tp.hashList.Back(); mark != nil; mark = mark.Prev() {
+		if tp.Pool[mark.Value.(string)].FeePerOperation() >= fee {
+			e = tp.hashList.InsertAfter(txHash, mark)
+			break
+		}
+	}
+	if e == nil {
+		e = tp.hashList.PushFront(txHash)
+	}
 
 	tp.Pool[txHash] = tx
 	tp.sources[tx.Source()] = txHash
-
-	e := tp.hashList.PushBack(txHash)
 	tp.hashMap[txHash] = e
+}
 
-	return nil
+// removeFromList removes the first transaction of source from pool.
+func (tp *Pool) removeFromList(hash string) {
+	tx := tp.Pool[hash]
+
+	delete(tp.sources, tx.Source())
+	delete(tp.Pool, hash)
+	if e, ok := tp.hashMap[hash]; ok {
+		tp.hashList.Remove(e)
+		delete(tp.hashMap, hash)
+	}
+}
+
+// remove removes the transaction from pool. When the first transaction of
+// source is included in block, the next queued one is promoted; otherwise the
+// queued ones are also removed, because they can not be valid without it. In
+// the same reason, when the queued one is removed, the later queued ones are
+// also removed.
+func (tp *Pool) remove(hash string, included bool) (removed []string) {
+	tx, found := tp.Pool[hash]
+	if !found {
+		return
+	}
+
+	source := tx.Source()
+	if tp.sources[source] == hash {
+		tp.removeFromList(hash)
+		removed = append(removed, hash)
+
+		if !included {
+			for _, queued := range tp.queues[source] {
+				delete(tp.Pool, queued)
+				removed = append(removed, queued)
+			}
+			delete(tp.queues, source)
+
+			return
+		}
+
+		if queued := tp.queues[source]; len(queued) > 0 {
+			if len(queued) > 1 {
+				tp.queues[source] = queued[1:]
+			} else {
+				delete(tp.queues, source)
+			}
+			tp.insert(tp.Pool[queued[0]])
+		}
+
+		return
+	}
+
+	queued := tp.queues[source]
+	for i, h := range queued {
+		if h != hash {
+			continue
+		}
+
+		for _, later := range queued[i:] {
+			delete(tp.Pool, later)
+			removed = append(removed, later)
+		}
+		if i > 0 {
+			tp.queues[source] = queued[:i]
+		} else {
+			delete(tp.queues, source)
+		}
+		break
+	}
+
+	return
 }
 
 func (tp *Pool) AddFromClient(tx Transaction) error {
@@ -100,6 +243,8 @@ func (tp *Pool) Add(tx Transaction) error {
 	return tp.add(tx, 0)
 }
 
+// Remove removes the transactions, which are not included in block, like
+// the invalid ones; the queued transactions of same sources are also removed.
 func (tp *Pool) Remove(hashes ...string) {
 	if len(hashes) < 1 {
 		return
@@ -110,22 +255,34 @@ func (tp *Pool) Remove(hashes ...string) {
 
 	var num int
 	for _, hash := range hashes {
-		if tx, found := tp.Pool[hash]; found {
-			delete(tp.sources, tx.Source())
-			delete(tp.Pool, hash)
-			if e, ok := tp.hashMap[hash]; ok {
-				tp.hashList.Remove(e)
-				delete(tp.hashMap, hash)
-			}
-			num++
-		}
+		num += len(tp.remove(hash, false))
 	}
 
 	metrics.TxPool.AddSize(-num)
+}
+
+// RemoveIncluded removes the transactions, which are included in block; the
+// next queued transactions of same sources are promoted.
+func (tp *Pool) RemoveIncluded(hashes ...string) {
+	if len(hashes) < 1 {
+		return
+	}
 
+	tp.Lock()
+	defer tp.Unlock()
+
+	var num int
+	for _, hash := range hashes {
+		num += len(tp.remove(hash, true))
+	}
+
+	metrics.TxPool.AddSize(-num)
 }
 
-func (tp *Pool) RemoveFromSources(sources ...string) {
+// RemoveFromSources removes the first transactions of sources, which are
+// included in block; the next queued transactions of sources are promoted and
+// returned.
+func (tp *Pool) RemoveFromSources(sources ...string) (promoted []string) {
 	if len(sources) < 1 {
 		return
 	}
@@ -136,19 +293,66 @@ func (tp *Pool) RemoveFromSources(sources ...string) {
 	var num int
 	for _, source := range sources {
 		if hash, found := tp.sources[source]; found {
-			if _, found := tp.Pool[hash]; found {
-				delete(tp.sources, source)
-				delete(tp.Pool, hash)
-				if e, ok := tp.hashMap[hash]; ok {
-					tp.hashList.Remove(e)
-					delete(tp.hashMap, hash)
-				}
-				num++
+			num += len(tp.remove(hash, true))
+			if next, found := tp.sources[source]; found {
+				promoted = append(promoted, next)
 			}
 		}
 	}
 
 	metrics.TxPool.AddSize(-num)
+
+	return
+}
+
+// RemoveAllFromSources removes all the transactions of sources including the
+// queued ones.
+func (tp *Pool) RemoveAllFromSources(sources ...string) (removed []string) {
+	if len(sources) < 1 {
+		return
+	}
+
+	tp.Lock()
+	defer tp.Unlock()
+
+	for _, source := range sources {
+		hash, found := tp.sources[source]
+		if !found {
+			continue
+		}
+
+		for _, queued := range tp.queues[source] {
+			delete(tp.Pool, queued)
+			removed = append(removed, queued)
+		}
+		delete(tp.queues, source)
+
+		tp.removeFromList(hash)
+		removed = append(removed, hash)
+	}
+
+	metrics.TxPool.AddSize(-len(removed))
+
+	return
+}
+
+// RemoveExpired removes the transactions, which can not be included in the
+// block of `height` and the later blocks by their time bounds.
+func (tp *Pool) RemoveExpired(height uint64, latestTime time.Time) (removed []string) {
+	tp.Lock()
+	defer tp.Unlock()
+
+	for hash, tx := range tp.Pool {
+		if !tx.IsExpired(height, latestTime) {
+			continue
+		}
+
+		removed = append(removed, tp.remove(hash, false)...)
+	}
+
+	metrics.TxPool.AddSize(-len(removed))
+
+	return
 }
 
 func (tp *Pool) AvailableTransactions(transactionLimit int) []string {
@@ -161,7 +365,7 @@ func (tp *Pool) AvailableTransactions(transactionLimit int) []string {
 
 	var ret []string
 	var cnt int
-	// first ouput by order older hash
+	// `hashList` is ordered by the fee per operation and the arrival
 	for e := tp.hashList.Front(); e != nil; e = e.Next() {
 		if cnt >= transactionLimit {
 			return ret
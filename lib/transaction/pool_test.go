@@ -0,0 +1,223 @@
+package transaction
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/transaction/operation"
+)
+
+func makeTestTransactionWithFee(networkID []byte, kp *keypair.Full, sequenceID uint64, fee common.Amount, n int) Transaction {
+	var ops []operation.Operation
+	for i := 0; i < n; i++ {
+		ops = append(ops, operation.MakeTestPayment(-1))
+	}
+
+	tx, err := NewTransactionWithFee(kp.Address(), sequenceID, fee, ops...)
+	if err != nil {
+		panic(err)
+	}
+	tx.Sign(kp, networkID)
+
+	return tx
+}
+
+func TestNewTransactionWithFee(t *testing.T) {
+	conf := common.NewTestConfig()
+	kp := keypair.Random()
+	op := operation.MakeTestPayment(-1)
+
+	_, err := NewTransactionWithFee(kp.Address(), 0, common.BaseFee-1, op)
+	require.Equal(t, errors.InvalidFee, err)
+
+	tx, err := NewTransactionWithFee(kp.Address(), 0, common.BaseFee.MustMult(3), op, operation.MakeTestPayment(-1))
+	require.NoError(t, err)
+	tx.Sign(kp, conf.NetworkID)
+	require.NoError(t, tx.IsWellFormed(conf))
+	require.Equal(t, common.BaseFee.MustMult(3), tx.B.Fee)
+	require.Equal(t, common.BaseFee.MustMult(3)/2, tx.FeePerOperation())
+}
+
+func TestPoolOrderByFee(t *testing.T) {
+	conf := common.NewTestConfig()
+	pool := NewPool(conf)
+
+	base := makeTestTransactionWithFee(conf.NetworkID, keypair.Random(), 0, common.BaseFee, 1)
+	highest := makeTestTransactionWithFee(conf.NetworkID, keypair.Random(), 0, common.BaseFee.MustMult(3), 1)
+	// 2 operations with 4 times fee; the fee per operation is 2 times of base fee
+	higher := makeTestTransactionWithFee(conf.NetworkID, keypair.Random(), 0, common.BaseFee.MustMult(4), 2)
+	anotherBase := makeTestTransactionWithFee(conf.NetworkID, keypair.Random(), 0, common.BaseFee, 1)
+
+	for _, tx := range []Transaction{base, highest, higher, anotherBase} {
+		require.NoError(t, pool.Add(tx))
+	}
+
+	require.Equal(
+		t,
+		[]string{highest.GetHash(), higher.GetHash(), base.GetHash(), anotherBase.GetHash()},
+		pool.AvailableTransactions(10),
+	)
+	require.Equal(t, []string{highest.GetHash(), higher.GetHash()}, pool.AvailableTransactions(2))
+
+	pool.Remove(higher.GetHash())
+	require.Equal(
+		t,
+		[]string{highest.GetHash(), base.GetHash(), anotherBase.GetHash()},
+		pool.AvailableTransactions(10),
+	)
+}
+
+func TestPoolReplaceByFee(t *testing.T) {
+	conf := common.NewTestConfig()
+	pool := NewPool(conf)
+
+	kp := keypair.Random()
+	tx := makeTestTransactionWithFee(conf.NetworkID, kp, 0, common.BaseFee, 1)
+	other := makeTestTransactionWithFee(conf.NetworkID, keypair.Random(), 0, common.BaseFee.MustMult(2), 1)
+	require.NoError(t, pool.Add(tx))
+	require.NoError(t, pool.Add(other))
+
+	{ // not enough fee to replace
+		lower := makeTestTransactionWithFee(conf.NetworkID, kp, 0, common.BaseFee.MustMult(2)-1, 1)
+		require.False(t, lower.CanReplace(tx))
+	}
+
+	replacing := makeTestTransactionWithFee(conf.NetworkID, kp, 0, common.BaseFee.MustMult(3), 1)
+	require.True(t, replacing.CanReplace(tx))
+	require.False(t, replacing.CanReplace(replacing))
+
+	require.NoError(t, pool.Add(replacing))
+	require.Equal(t, 2, pool.Len())
+	require.False(t, pool.Has(tx.GetHash()))
+
+	found, ok := pool.GetFromSource(kp.Address())
+	require.True(t, ok)
+	require.Equal(t, replacing.GetHash(), found.GetHash())
+	require.Equal(t, []string{replacing.GetHash(), other.GetHash()}, pool.AvailableTransactions(10))
+}
+
+func TestPoolRemoveExpired(t *testing.T) {
+	conf := common.NewTestConfig()
+	pool := NewPool(conf)
+
+	_, expired := TestMakeTransaction(conf.NetworkID, 1)
+	expired.B.TimeBounds = &TimeBounds{MaxHeight: 3}
+	expired.H.Hash = expired.B.MakeHashString()
+
+	_, notYet := TestMakeTransaction(conf.NetworkID, 1)
+	notYet.B.TimeBounds = &TimeBounds{MinHeight: 10}
+	notYet.H.Hash = notYet.B.MakeHashString()
+
+	_, unlimited := TestMakeTransaction(conf.NetworkID, 1)
+
+	for _, tx := range []Transaction{expired, notYet, unlimited} {
+		require.NoError(t, pool.Add(tx))
+	}
+
+	// queued after the expired one
+	kp := keypair.Random()
+	expiredHead := makeTestTransactionWithFee(conf.NetworkID, kp, 0, common.BaseFee, 1)
+	expiredHead.B.TimeBounds = &TimeBounds{MaxHeight: 3}
+	expiredHead.H.Hash = expiredHead.B.MakeHashString()
+	queued := makeTestTransactionWithFee(conf.NetworkID, kp, 1, common.BaseFee, 1)
+	for _, tx := range []Transaction{expiredHead, queued} {
+		require.NoError(t, pool.Add(tx))
+	}
+
+	removed := pool.RemoveExpired(4, time.Now())
+	require.ElementsMatch(t, []string{expired.GetHash(), expiredHead.GetHash(), queued.GetHash()}, removed)
+	require.Equal(t, 2, pool.Len())
+	require.False(t, pool.IsSameSource(kp.Address()))
+	require.False(t, pool.Has(expired.GetHash()))
+	require.False(t, pool.IsSameSource(expired.Source()))
+	require.Equal(t, []string{notYet.GetHash(), unlimited.GetHash()}, pool.AvailableTransactions(10))
+}
+
+func TestPoolQueueBySequenceID(t *testing.T) {
+	conf := common.NewTestConfig()
+	conf.TxPoolSourceLimit = 3
+	pool := NewPool(conf)
+
+	kp := keypair.Random()
+	var txs []Transaction
+	for i := 0; i < 4; i++ {
+		txs = append(txs, makeTestTransactionWithFee(conf.NetworkID, kp, uint64(10+i), common.BaseFee, 1))
+	}
+
+	require.NoError(t, pool.Add(txs[0]))
+
+	{ // not following sequence ID
+		require.Equal(t, errors.TransactionInvalidSequenceID, pool.Add(txs[2]))
+		lower := makeTestTransactionWithFee(conf.NetworkID, kp, 9, common.BaseFee, 1)
+		require.Equal(t, errors.TransactionInvalidSequenceID, pool.Add(lower))
+	}
+
+	require.NoError(t, pool.Add(txs[1]))
+	require.NoError(t, pool.Add(txs[2]))
+	require.Equal(t, errors.TransactionSourceQueueFull, pool.Add(txs[3]))
+	require.Equal(t, 3, pool.Len())
+
+	// only the first transaction of source is available
+	require.Equal(t, []string{txs[0].GetHash()}, pool.AvailableTransactions(10))
+
+	{ // replace the queued one by fee
+		lower := makeTestTransactionWithFee(conf.NetworkID, kp, 11, common.BaseFee.MustMult(2), 2)
+		require.Equal(t, errors.TransactionReplacementFeeTooLow, pool.Add(lower))
+
+		replacing := makeTestTransactionWithFee(conf.NetworkID, kp, 11, common.BaseFee.MustMult(2), 1)
+		require.NoError(t, pool.Add(replacing))
+		require.False(t, pool.Has(txs[1].GetHash()))
+		txs[1] = replacing
+	}
+
+	// included in block; the next one is promoted
+	pool.RemoveFromSources(kp.Address())
+	require.Equal(t, 2, pool.Len())
+	require.Equal(t, []string{txs[1].GetHash()}, pool.AvailableTransactions(10))
+	found, ok := pool.GetFromSource(kp.Address())
+	require.True(t, ok)
+	require.Equal(t, txs[1].GetHash(), found.GetHash())
+
+	require.NoError(t, pool.Add(txs[3]))
+	require.Equal(t, 3, pool.Len())
+
+	// removing the queued one removes the later ones
+	pool.Remove(txs[2].GetHash())
+	require.Equal(t, 1, pool.Len())
+	require.False(t, pool.Has(txs[3].GetHash()))
+
+	pool.RemoveFromSources(kp.Address())
+	require.Equal(t, 0, pool.Len())
+	require.False(t, pool.IsSameSource(kp.Address()))
+
+	{ // the first one is included in block by its hash; the next one is promoted
+		require.NoError(t, pool.Add(txs[3]))
+		next := makeTestTransactionWithFee(conf.NetworkID, kp, 14, common.BaseFee, 1)
+		require.NoError(t, pool.Add(next))
+
+		pool.RemoveIncluded(txs[3].GetHash())
+		require.Equal(t, 1, pool.Len())
+		require.Equal(t, []string{next.GetHash()}, pool.AvailableTransactions(10))
+
+		pool.RemoveIncluded(next.GetHash())
+		require.Equal(t, 0, pool.Len())
+	}
+
+	{ // the first one is removed without being included; the queued ones are also removed
+		for _, seq := range []uint64{15, 16, 17} {
+			require.NoError(t, pool.Add(makeTestTransactionWithFee(conf.NetworkID, kp, seq, common.BaseFee, 1)))
+		}
+		found, ok := pool.GetFromSource(kp.Address())
+		require.True(t, ok)
+
+		pool.Remove(found.GetHash())
+		require.Equal(t, 0, pool.Len())
+		require.False(t, pool.IsSameSource(kp.Address()))
+		require.Equal(t, 0, len(pool.AvailableTransactions(10)))
+	}
+}
@@ -0,0 +1,81 @@
+package transaction
+
+import (
+	"time"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/errors"
+)
+
+// TimeBounds limits the block, which the transaction can be included in. The
+// bounds are checked with the height of next block and the proposed time of
+// the latest block. The empty bound is not limited.
+type TimeBounds struct {
+	MinTime   string `json:"min_time,omitempty"` // ISO8601
+	MaxTime   string `json:"max_time,omitempty"` // ISO8601
+	MinHeight uint64 `json:"min_height,omitempty"`
+	MaxHeight uint64 `json:"max_height,omitempty"`
+}
+
+func parseBoundTime(s string) (t time.Time, err error) {
+	if len(s) < 1 {
+		return
+	}
+
+	if t, err = common.ParseISO8601(s); err != nil {
+		err = errors.InvalidTimeBounds
+	}
+
+	return
+}
+
+func (tb TimeBounds) IsWellFormed() (err error) {
+	var minTime, maxTime time.Time
+	if minTime, err = parseBoundTime(tb.MinTime); err != nil {
+		return
+	}
+	if maxTime, err = parseBoundTime(tb.MaxTime); err != nil {
+		return
+	}
+	if !minTime.IsZero() && !maxTime.IsZero() && minTime.After(maxTime) {
+		return errors.InvalidTimeBounds
+	}
+
+	if tb.MinHeight > 0 && tb.MaxHeight > 0 && tb.MinHeight > tb.MaxHeight {
+		return errors.InvalidTimeBounds
+	}
+
+	return
+}
+
+// Check checks the transaction can be included in the block of `height`,
+// which is the next block of the latest block proposed at `latestTime`.
+func (tb TimeBounds) Check(height uint64, latestTime time.Time) (err error) {
+	if tb.MaxHeight > 0 && height > tb.MaxHeight {
+		return errors.TransactionExpired
+	}
+
+	var minTime, maxTime time.Time
+	if maxTime, err = parseBoundTime(tb.MaxTime); err != nil {
+		return
+	} else if !maxTime.IsZero() && latestTime.After(maxTime) {
+		return errors.TransactionExpired
+	}
+
+	if tb.MinHeight > 0 && height < tb.MinHeight {
+		return errors.TransactionNotYetValid
+	}
+	if minTime, err = parseBoundTime(tb.MinTime); err != nil {
+		return
+	} else if !minTime.IsZero() && latestTime.Before(minTime) {
+		return errors.TransactionNotYetValid
+	}
+
+	return
+}
+
+// IsExpired returns true when the transaction can not be included in the
+// block of `height` and the later blocks.
+func (tb TimeBounds) IsExpired(height uint64, latestTime time.Time) bool {
+	return tb.Check(height, latestTime) == errors.TransactionExpired
+}
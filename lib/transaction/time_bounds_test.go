@@ -0,0 +1,86 @@
+package transaction
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/errors"
+)
+
+func TestTimeBoundsIsWellFormed(t *testing.T) {
+	now := time.Now()
+
+	require.NoError(t, TimeBounds{}.IsWellFormed())
+	require.NoError(t, TimeBounds{MinHeight: 3, MaxHeight: 3}.IsWellFormed())
+	require.NoError(t, TimeBounds{
+		MinTime: common.FormatISO8601(now),
+		MaxTime: common.FormatISO8601(now.Add(time.Minute)),
+	}.IsWellFormed())
+
+	require.Equal(t, errors.InvalidTimeBounds, TimeBounds{MinHeight: 4, MaxHeight: 3}.IsWellFormed())
+	require.Equal(t, errors.InvalidTimeBounds, TimeBounds{MaxTime: "showme"}.IsWellFormed())
+	require.Equal(t, errors.InvalidTimeBounds, TimeBounds{
+		MinTime: common.FormatISO8601(now.Add(time.Minute)),
+		MaxTime: common.FormatISO8601(now),
+	}.IsWellFormed())
+}
+
+func TestTimeBoundsCheck(t *testing.T) {
+	now := time.Now()
+
+	{ // height
+		tb := TimeBounds{MinHeight: 3, MaxHeight: 5}
+		require.Equal(t, errors.TransactionNotYetValid, tb.Check(2, now))
+		require.NoError(t, tb.Check(3, now))
+		require.NoError(t, tb.Check(5, now))
+		require.Equal(t, errors.TransactionExpired, tb.Check(6, now))
+
+		require.False(t, tb.IsExpired(2, now))
+		require.True(t, tb.IsExpired(6, now))
+	}
+
+	{ // time
+		tb := TimeBounds{
+			MinTime: common.FormatISO8601(now),
+			MaxTime: common.FormatISO8601(now.Add(time.Minute)),
+		}
+		require.Equal(t, errors.TransactionNotYetValid, tb.Check(1, now.Add(-time.Second)))
+		require.NoError(t, tb.Check(1, now))
+		require.NoError(t, tb.Check(1, now.Add(time.Minute)))
+		require.Equal(t, errors.TransactionExpired, tb.Check(1, now.Add(time.Minute+time.Second)))
+	}
+}
+
+// TestTransactionHashWithoutTimeBounds checks the hash of transaction without
+// `TimeBounds` is not changed.
+func TestTransactionHashWithoutTimeBounds(t *testing.T) {
+	conf := common.NewTestConfig()
+	_, tx := TestMakeTransaction(conf.NetworkID, 1)
+
+	legacy := struct {
+		Source     string
+		Fee        common.Amount
+		SequenceID uint64
+		Operations interface{}
+	}{tx.B.Source, tx.B.Fee, tx.B.SequenceID, tx.B.Operations}
+	require.Equal(t, common.MustMakeObjectHash(legacy), tx.B.MakeHash())
+
+	tx.B.TimeBounds = &TimeBounds{MaxHeight: 10}
+	require.NotEqual(t, common.MustMakeObjectHash(legacy), tx.B.MakeHash())
+}
+
+func TestIsWellFormedTransactionWithTimeBounds(t *testing.T) {
+	conf := common.NewTestConfig()
+	kp, tx := TestMakeTransaction(conf.NetworkID, 1)
+
+	tx.B.TimeBounds = &TimeBounds{MinHeight: 10, MaxHeight: 20}
+	tx.Sign(kp, conf.NetworkID)
+	require.NoError(t, tx.IsWellFormed(conf))
+
+	tx.B.TimeBounds = &TimeBounds{MinHeight: 20, MaxHeight: 10}
+	tx.Sign(kp, conf.NetworkID)
+	require.Equal(t, errors.InvalidTimeBounds, tx.IsWellFormed(conf))
+}
@@ -2,6 +2,8 @@ package transaction
 
 import (
 	"encoding/json"
+	"io"
+	"time"
 
 	"github.com/btcsuite/btcutil/base58"
 
@@ -32,6 +34,14 @@ type Header struct {
 	// has to validate it anyway.
 	Hash      string `json:"-"`
 	Signature string `json:"signature"`
+	// Signatures of the signers of multi-signature account
+	Signatures []Signature `json:"signatures,omitempty"`
+}
+
+// Signature is the signature of `Signer` to the hash of transaction.
+type Signature struct {
+	Signer    string `json:"signer"`
+	Signature string `json:"signature"`
 }
 
 type Body struct {
@@ -39,6 +49,28 @@ type Body struct {
 	Fee        common.Amount         `json:"fee"`
 	SequenceID uint64                `json:"sequence_id"`
 	Operations []operation.Operation `json:"operations"`
+	TimeBounds *TimeBounds           `json:"time_bounds,omitempty"`
+	Memo       *Memo                 `json:"memo,omitempty"`
+}
+
+// Implement `common.Encoder`; the optional fields are encoded only until the
+// last one, which is set, so the hash of the body without the optional fields
+// is not changed.
+func (tb Body) EncodeRLP(w io.Writer) error {
+	fields := []interface{}{tb.Source, tb.Fee, tb.SequenceID, tb.Operations}
+
+	var timeBounds TimeBounds
+	if tb.TimeBounds != nil {
+		timeBounds = *tb.TimeBounds
+	}
+
+	if tb.Memo != nil {
+		fields = append(fields, timeBounds, *tb.Memo)
+	} else if tb.TimeBounds != nil {
+		fields = append(fields, timeBounds)
+	}
+
+	return common.Encode(w, fields)
 }
 
 func (tb Body) MakeHash() []byte {
@@ -62,11 +94,6 @@ func (t *Transaction) UnmarshalJSON(b []byte) (err error) {
 }
 
 func NewTransaction(source string, sequenceID uint64, ops ...operation.Operation) (tx Transaction, err error) {
-	if len(ops) < 1 {
-		err = errors.TransactionEmptyOperations
-		return
-	}
-
 	var opsHaveFee int
 	for _, op := range ops {
 		if op.HasFee() {
@@ -78,6 +105,18 @@ func NewTransaction(source string, sequenceID uint64, ops ...operation.Operation
 		fee = common.BaseFee.MustMult(opsHaveFee)
 	}
 
+	return NewTransactionWithFee(source, sequenceID, fee, ops...)
+}
+
+// NewTransactionWithFee creates new transaction with the given fee, which can
+// be more than the base fee; the transaction, which pays more fee per
+// operation is included in block first.
+func NewTransactionWithFee(source string, sequenceID uint64, fee common.Amount, ops ...operation.Operation) (tx Transaction, err error) {
+	if len(ops) < 1 {
+		err = errors.TransactionEmptyOperations
+		return
+	}
+
 	txBody := Body{
 		Source:     source,
 		Fee:        fee,
@@ -94,6 +133,10 @@ func NewTransaction(source string, sequenceID uint64, ops ...operation.Operation
 		B: txBody,
 	}
 
+	if fee < tx.TotalBaseFee() {
+		return Transaction{}, errors.InvalidFee
+	}
+
 	return
 }
 
@@ -103,6 +146,8 @@ var TransactionWellFormedCheckerFuncs = []common.CheckerFunc{
 	CheckBaseFee,
 	CheckOperationTypes,
 	CheckOperations,
+	CheckTimeBounds,
+	CheckMemo,
 	CheckVerifySignature,
 }
 
@@ -137,6 +182,26 @@ func (tx Transaction) IsValidSequenceID(sequenceID uint64) bool {
 	return tx.B.SequenceID == sequenceID
 }
 
+// CheckTimeBounds checks the transaction can be included in the block of
+// `height`; see `TimeBounds.Check()`.
+func (tx Transaction) CheckTimeBounds(height uint64, latestTime time.Time) error {
+	if tx.B.TimeBounds == nil {
+		return nil
+	}
+
+	return tx.B.TimeBounds.Check(height, latestTime)
+}
+
+// IsExpired returns true when the transaction is out of time bounds and it can
+// not be included in the later blocks.
+func (tx Transaction) IsExpired(height uint64, latestTime time.Time) bool {
+	if tx.B.TimeBounds == nil {
+		return false
+	}
+
+	return tx.B.TimeBounds.IsExpired(height, latestTime)
+}
+
 func (tx Transaction) GetHash() string {
 	return tx.H.Hash
 }
@@ -176,6 +241,30 @@ func (tx Transaction) TotalAmount(withFee bool) common.Amount {
 	return amount
 }
 
+// FeePerOperation returns the fee divided by the number of operations; the
+// transactions in `Pool` are ordered by this.
+func (tx Transaction) FeePerOperation() common.Amount {
+	if len(tx.B.Operations) < 1 {
+		return tx.B.Fee
+	}
+
+	return tx.B.Fee / common.Amount(len(tx.B.Operations))
+}
+
+// CanReplace checks the transaction can replace the `old` transaction in
+// `Pool`; both have same source and sequence ID, and the fee per operation of
+// the new one should be higher at least by `common.BaseFee`.
+func (tx Transaction) CanReplace(old Transaction) bool {
+	if tx.GetHash() == old.GetHash() {
+		return false
+	}
+	if tx.Source() != old.Source() || !tx.IsValidSequenceID(old.B.SequenceID) {
+		return false
+	}
+
+	return tx.FeePerOperation() >= old.FeePerOperation()+common.BaseFee
+}
+
 // TotalBaseFee returns the minimum fee of transaction.
 func (tx Transaction) TotalBaseFee() common.Amount {
 	var opsHaveFee int
@@ -211,6 +300,46 @@ func (tx *Transaction) Sign(kp keypair.KP, networkID []byte) {
 	return
 }
 
+// AddSignature appends the signature of the signer of the multi-signature
+// account; unlike `Sign()`, the source is not changed.
+func (tx *Transaction) AddSignature(kp keypair.KP, networkID []byte) {
+	tx.H.Hash = tx.B.MakeHashString()
+	signature, _ := keypair.MakeSignature(kp, networkID, tx.H.Hash)
+
+	tx.H.Signatures = append(tx.H.Signatures, Signature{
+		Signer:    kp.Address(),
+		Signature: base58.Encode(signature),
+	})
+
+	return
+}
+
+// Signers returns the addresses, which signed this transaction. The
+// signatures should be verified by `CheckVerifySignature` before.
+func (tx Transaction) Signers() []string {
+	var signers []string
+	if len(tx.H.Signature) > 0 {
+		signers = append(signers, tx.B.Source)
+	}
+	for _, s := range tx.H.Signatures {
+		signers = append(signers, s.Signer)
+	}
+
+	return signers
+}
+
+// ThresholdLevel returns the highest threshold level of operations.
+func (tx Transaction) ThresholdLevel() operation.ThresholdLevel {
+	level := operation.ThresholdLow
+	for _, op := range tx.B.Operations {
+		if l := operation.GetThresholdLevel(op.H.Type); l > level {
+			level = l
+		}
+	}
+
+	return level
+}
+
 func (tx Transaction) IsEmpty() bool {
 	return len(tx.GetHash()) < 1
 }
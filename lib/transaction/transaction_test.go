@@ -194,6 +194,68 @@ func (suite *TestSuite) TestIsWellFormedTransactionMaxOperationsInTransactionSui
 	}
 }
 
+func (suite *TestSuite) TestIsWellFormedTransactionWithSignaturesSuite() {
+	kp, tx := TestMakeTransaction(suite.conf.NetworkID, 1)
+	signer0 := keypair.Random()
+	signer1 := keypair.Random()
+
+	{ // source and the other signer
+		tx.AddSignature(signer0, suite.conf.NetworkID)
+		require.Nil(suite.T(), tx.IsWellFormed(suite.conf))
+		require.Equal(suite.T(), []string{kp.Address(), signer0.Address()}, tx.Signers())
+	}
+
+	{ // without the signature of source
+		tx.H.Signature = ""
+		tx.AddSignature(signer1, suite.conf.NetworkID)
+		require.Nil(suite.T(), tx.IsWellFormed(suite.conf))
+		require.Equal(suite.T(), []string{signer0.Address(), signer1.Address()}, tx.Signers())
+	}
+
+	{ // duplicated signer
+		tx.AddSignature(signer1, suite.conf.NetworkID)
+		require.Equal(suite.T(), errors.DuplicatedSignature, tx.IsWellFormed(suite.conf))
+		tx.H.Signatures = tx.H.Signatures[:2]
+	}
+
+	{ // signature of the other signer is wrong
+		tx.H.Signatures[1].Signer = keypair.Random().Address()
+		require.Error(suite.T(), tx.IsWellFormed(suite.conf))
+	}
+
+	{ // without any signature
+		tx.H.Signatures = nil
+		require.Equal(suite.T(), errors.SignatureVerificationFailed, tx.IsWellFormed(suite.conf))
+	}
+}
+
+func (suite *TestSuite) TestIsWellFormedTransactionWithDuplicatedInflationPF() {
+	kp := keypair.Random()
+	votingResult := common.MustMakeObjectHashString("voting result") + "-0"
+
+	var ops []operation.Operation
+	for _, target := range []string{keypair.Random().Address(), keypair.Random().Address()} {
+		op, err := operation.NewOperation(operation.NewInflationPF(target, common.Amount(1000000), votingResult))
+		require.NoError(suite.T(), err)
+		ops = append(ops, op)
+	}
+
+	{ // the voting result can be funded only once
+		tx, err := NewTransaction(kp.Address(), 0, ops...)
+		require.NoError(suite.T(), err)
+		tx.Sign(kp, suite.conf.NetworkID)
+		require.Equal(suite.T(), errors.DuplicatedOperation, tx.IsWellFormed(suite.conf))
+	}
+
+	{ // the other voting result
+		ops[1].B = operation.NewInflationPF(keypair.Random().Address(), common.Amount(1000000), common.MustMakeObjectHashString("other")+"-0")
+		tx, err := NewTransaction(kp.Address(), 0, ops...)
+		require.NoError(suite.T(), err)
+		tx.Sign(kp, suite.conf.NetworkID)
+		require.NoError(suite.T(), tx.IsWellFormed(suite.conf))
+	}
+}
+
 func TestTransaction(t *testing.T) {
 	suite.Run(t, new(TestSuite))
 }
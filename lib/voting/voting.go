@@ -1,5 +1,7 @@
 package voting
 
+import "boscoin.io/sebak/lib/common"
+
 type Hole string
 
 const (
@@ -19,4 +21,13 @@ type ThresholdPolicy interface {
 	// Set the number of currently connected nodes
 	// The parameter must be a strictly positive integer
 	SetConnected(int)
+	// The amount delegated to the validator is used as the weight of
+	// validator's vote
+	Delegated(string) common.Amount
+	TotalDelegated() common.Amount
+	// Set the delegated amount by validator address
+	SetDelegated(map[string]common.Amount)
+	// The delegated amount required for consensus; if nothing is delegated,
+	// it is zero
+	DelegatedThreshold() common.Amount
 }